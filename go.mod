@@ -1,3 +1,10 @@
 module github.com/mkbworks/proteus
 
 go 1.22.1
+
+require (
+	golang.org/x/net v0.35.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/text v0.22.0 // indirect
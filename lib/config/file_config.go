@@ -0,0 +1,214 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/mkbworks/proteus/lib/fs"
+	"gopkg.in/yaml.v3"
+)
+
+// Prefix of the environment variables which override the values present in a configuration file.
+const ENV_PREFIX = "PROTEUS_"
+
+// Structure to represent a static route defined in a configuration file.
+type StaticRoot struct {
+	// Route at which the static files are served.
+	Route string `json:"route" yaml:"route"`
+	// Path of the file or directory in the file system mapped to the route.
+	Directory string `json:"directory" yaml:"directory"`
+}
+
+// Structure to hold the server settings loaded from a JSON or YAML configuration file. Settings which are not present in the file are left empty, so that the server defaults are used for them.
+type FileConfig struct {
+	// Hostname at which the web server listens for incoming requests.
+	Host string `json:"host" yaml:"host"`
+	// Port number at which the web server listens for incoming requests.
+	Port int `json:"port" yaml:"port"`
+	// Maximum duration allowed for reading an entire request, given as a duration string like "30s".
+	ReadTimeout string `json:"read_timeout" yaml:"read_timeout"`
+	// Maximum duration allowed for processing a request and writing its response, given as a duration string.
+	WriteTimeout string `json:"write_timeout" yaml:"write_timeout"`
+	// Maximum duration to wait for the next request on a persistent connection, given as a duration string.
+	IdleTimeout string `json:"idle_timeout" yaml:"idle_timeout"`
+	// Maximum duration allowed for reading the request line and the request headers, given as a duration string.
+	HeaderTimeout string `json:"header_timeout" yaml:"header_timeout"`
+	// Maximum size (in bytes) allowed for a request body.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size"`
+	// Minimum level of the log entries written by the server logger (debug, info, warn or error).
+	LogLevel string `json:"log_level" yaml:"log_level"`
+	// Additional content types supported by the server, with the file extension as key and the media type as value.
+	ContentTypes map[string]string `json:"content_types" yaml:"content_types"`
+	// List of static routes to be defined for the server.
+	StaticRoots []StaticRoot `json:"static_roots" yaml:"static_roots"`
+}
+
+// Loads the server settings from the JSON (.json) or YAML (.yaml or .yml) configuration file at the given path and applies the overrides set in the environment variables.
+// If the path is empty, only the environment variables are considered. Each setting can be overridden by an environment variable named as the setting in upper case with the
+// prefix "PROTEUS_", like PROTEUS_PORT or PROTEUS_READ_TIMEOUT. Static roots are overridden by PROTEUS_STATIC_ROOTS, given as a comma separated list of route=directory pairs.
+func LoadFile(path string) (*FileConfig, error) {
+	fileConfig := new(FileConfig)
+	path = strings.TrimSpace(path)
+	if path != "" {
+		fileContents, err := fs.ReadFileContents(path)
+		if err != nil {
+			ce := new(ConfigError)
+			ce.Message = err.Error()
+			return nil, ce
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			err = json.Unmarshal(fileContents, fileConfig)
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(fileContents, fileConfig)
+		default:
+			ce := new(ConfigError)
+			ce.Message = fmt.Sprintf("Configuration file %s must have a .json, .yaml or .yml extension", path)
+			return nil, ce
+		}
+
+		if err != nil {
+			ce := new(ConfigError)
+			ce.Message = fmt.Sprintf("Error occurred while unmarshalling configuration file contents: %s", err.Error())
+			return nil, ce
+		}
+	}
+
+	err := fileConfig.applyEnvOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	err = fileConfig.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return fileConfig, nil
+}
+
+// Returns the settings present in the configuration as a map of server default values, which contains only the settings that have been set.
+func (fc *FileConfig) GetServerDefaults() map[string]string {
+	serverDefaults := make(map[string]string)
+	setValue := func(key string, value string) {
+		if strings.TrimSpace(value) != "" {
+			serverDefaults[key] = strings.TrimSpace(value)
+		}
+	}
+
+	setValue("hostname", fc.Host)
+	if fc.Port != 0 {
+		setValue("port", strconv.Itoa(fc.Port))
+	}
+	setValue("read_timeout", fc.ReadTimeout)
+	setValue("write_timeout", fc.WriteTimeout)
+	setValue("idle_timeout", fc.IdleTimeout)
+	setValue("header_timeout", fc.HeaderTimeout)
+	if fc.MaxBodySize != 0 {
+		setValue("max_body_size", strconv.FormatInt(fc.MaxBodySize, 10))
+	}
+	setValue("log_level", fc.LogLevel)
+	return serverDefaults
+}
+
+// Replaces the settings in the configuration with the values of the corresponding environment variables, if they have been set.
+func (fc *FileConfig) applyEnvOverrides() error {
+	stringSettings := map[string]*string{
+		"HOST": &fc.Host,
+		"READ_TIMEOUT": &fc.ReadTimeout,
+		"WRITE_TIMEOUT": &fc.WriteTimeout,
+		"IDLE_TIMEOUT": &fc.IdleTimeout,
+		"HEADER_TIMEOUT": &fc.HeaderTimeout,
+		"LOG_LEVEL": &fc.LogLevel,
+	}
+
+	for name, setting := range stringSettings {
+		if value, found := os.LookupEnv(ENV_PREFIX + name); found {
+			*setting = strings.TrimSpace(value)
+		}
+	}
+
+	if value, found := os.LookupEnv(ENV_PREFIX + "PORT"); found {
+		port, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			ce := new(ConfigError)
+			ce.Message = fmt.Sprintf("Environment variable %sPORT must be a number: %s", ENV_PREFIX, value)
+			return ce
+		}
+		fc.Port = port
+	}
+
+	if value, found := os.LookupEnv(ENV_PREFIX + "MAX_BODY_SIZE"); found {
+		maxBodySize, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			ce := new(ConfigError)
+			ce.Message = fmt.Sprintf("Environment variable %sMAX_BODY_SIZE must be a number: %s", ENV_PREFIX, value)
+			return ce
+		}
+		fc.MaxBodySize = maxBodySize
+	}
+
+	if value, found := os.LookupEnv(ENV_PREFIX + "STATIC_ROOTS"); found {
+		fc.StaticRoots = make([]StaticRoot, 0)
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+
+			route, directory, ok := strings.Cut(pair, "=")
+			if !ok {
+				ce := new(ConfigError)
+				ce.Message = fmt.Sprintf("Environment variable %sSTATIC_ROOTS must contain route=directory pairs: %s", ENV_PREFIX, pair)
+				return ce
+			}
+			fc.StaticRoots = append(fc.StaticRoots, StaticRoot{ Route: strings.TrimSpace(route), Directory: strings.TrimSpace(directory) })
+		}
+	}
+
+	return nil
+}
+
+// Checks if the settings in the configuration have valid values.
+func (fc *FileConfig) validate() error {
+	if fc.Port < 0 || fc.Port > 65535 {
+		ce := new(ConfigError)
+		ce.Message = fmt.Sprintf("Port number %d must be between 0 and 65535", fc.Port)
+		return ce
+	}
+
+	timeouts := map[string]string{ "read_timeout": fc.ReadTimeout, "write_timeout": fc.WriteTimeout, "idle_timeout": fc.IdleTimeout, "header_timeout": fc.HeaderTimeout }
+	for name, value := range timeouts {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(strings.TrimSpace(value)); err != nil {
+			ce := new(ConfigError)
+			ce.Message = fmt.Sprintf("Setting %s must be a valid duration: %s", name, value)
+			return ce
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(fc.LogLevel)) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		ce := new(ConfigError)
+		ce.Message = fmt.Sprintf("Setting log_level must be one of debug, info, warn or error: %s", fc.LogLevel)
+		return ce
+	}
+
+	for _, staticRoot := range fc.StaticRoots {
+		if strings.TrimSpace(staticRoot.Route) == "" || strings.TrimSpace(staticRoot.Directory) == "" {
+			ce := new(ConfigError)
+			ce.Message = "Every static root must have both a route and a directory"
+			return ce
+		}
+	}
+
+	return nil
+}
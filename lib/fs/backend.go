@@ -0,0 +1,71 @@
+package fs
+
+import (
+	iofs "io/fs"
+	"os"
+)
+
+// Interface to be implemented by the file systems from which files are read, like the local file system (OSFileSystem), an in-memory file system (MemoryFileSystem) or an io/fs file system
+// (like an embed.FS) adapted using FromFS(). Alternative backends (like zip archives or object stores) can be served by implementing it. Every FileSystem is also an io/fs file system.
+type FileSystem interface {
+	// Opens the file or folder available at the given path. Files which implement io.Seeker and io.ReaderAt can be sent in byte ranges without reading their complete contents.
+	Open(Name string) (iofs.File, error)
+	// Returns the metadata of the file or folder available at the given path.
+	Stat(Name string) (iofs.FileInfo, error)
+	// Returns the entries present in the folder available at the given path, sorted by their names.
+	ReadDir(Name string) ([]iofs.DirEntry, error)
+}
+
+// Structure to represent the local file system. Unlike the file systems of io/fs, its paths are the paths of the operating system (like "/var/www/index.html"), including absolute paths.
+type OSFileSystem struct {}
+
+// Opens the file or folder available at the given path in the local file system.
+func (OSFileSystem) Open(Name string) (iofs.File, error) {
+	file, err := os.Open(Name)
+	if err != nil {
+		// A nil *os.File is not returned as an iofs.File, since the interface value would not be nil.
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Returns the metadata of the file or folder available at the given path in the local file system.
+func (OSFileSystem) Stat(Name string) (iofs.FileInfo, error) {
+	return os.Stat(Name)
+}
+
+// Returns the entries present in the folder available at the given path in the local file system, sorted by their names.
+func (OSFileSystem) ReadDir(Name string) ([]iofs.DirEntry, error) {
+	return os.ReadDir(Name)
+}
+
+// Structure to adapt an io/fs file system, which need not implement fs.StatFS or fs.ReadDirFS (like an embed.FS), to the FileSystem interface.
+type ioFileSystem struct {
+	// File system being adapted.
+	fileSystem iofs.FS
+}
+
+// Opens the file or folder available at the given path in the adapted file system.
+func (ifs ioFileSystem) Open(Name string) (iofs.File, error) {
+	return ifs.fileSystem.Open(Name)
+}
+
+// Returns the metadata of the file or folder available at the given path in the adapted file system.
+func (ifs ioFileSystem) Stat(Name string) (iofs.FileInfo, error) {
+	return iofs.Stat(ifs.fileSystem, Name)
+}
+
+// Returns the entries present in the folder available at the given path in the adapted file system, sorted by their names.
+func (ifs ioFileSystem) ReadDir(Name string) ([]iofs.DirEntry, error) {
+	return iofs.ReadDir(ifs.fileSystem, Name)
+}
+
+// Returns the given io/fs file system as a FileSystem. File systems which already implement FileSystem are returned as they are.
+func FromFS(Source iofs.FS) FileSystem {
+	if fileSystem, ok := Source.(FileSystem); ok {
+		return fileSystem
+	}
+
+	return ioFileSystem{ fileSystem: Source }
+}
@@ -1,9 +1,10 @@
 package fs
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	iofs "io/fs"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -16,6 +17,8 @@ const (
 	FOLDER_TYPE_PATH = "Folder"
 	// Type value for paths pointing to a file in the file system.
 	FILE_TYPE_PATH = "File"
+	// Number of bytes at the start of a file inspected to determine its media type, as defined in the WHATWG MIME Sniffing standard.
+	SNIFF_LENGTH = 512
 )
 
 // Structure to represent a file in the local file system.
@@ -32,91 +35,177 @@ type File struct {
 	Size int64
 }
 
-// Returns the type of the given path i.e., file or folder. An error is returned if the given path is neither a file nor a folder.
+// Returns the type of the given path in the local file system i.e., file or folder. An error is returned if the given path is neither a file nor a folder.
 func GetPathType(TargetPath string) (string, error) {
-	fileStat, err := os.Stat(TargetPath)
+	return GetPathTypeFS(OSFileSystem{}, TargetPath)
+}
+
+// Reads the contents of the file available at the given path in the local file system and returns it as a byte slice.
+func ReadFileContents(CompleteFilePath string) ([]byte, error) {
+	return ReadFileContentsFS(OSFileSystem{}, CompleteFilePath)
+}
+
+// Opens the file available at the given path for reading its contents as a stream. The caller must close the file once its contents have been read.
+func OpenFile(CompleteFilePath string) (*os.File, error) {
+	fileHandler, err := os.Open(CompleteFilePath)
+	if err != nil {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = CompleteFilePath
+		fsfErr.Message = fmt.Sprintf("Error occurred while opening the file: %s", err.Error())
+		return nil, fsfErr
+	}
+
+	return fileHandler, nil
+}
+
+// Returns pointer to a FILE object that contains metadata for file available at the given path in the local file system.
+// The metadata include file contents, last modified time, base name and size in bytes. If the given path does not point to a file, then an error is returned.
+func GetFile(CompleteFilePath string, ContentType string, OnlyMetadata bool) (*File, error) {
+	return GetFileFS(OSFileSystem{}, CompleteFilePath, ContentType, OnlyMetadata)
+}
+
+// Structure to represent an entry (file or folder) present in a folder of the local file system.
+type DirectoryEntry struct {
+	// Base name of the entry.
+	Name string
+	// Is true if the entry is a folder.
+	IsDir bool
+	// Size of the entry in bytes. It is zero for folders.
+	Size int64
+	// Time at which the entry was last modified.
+	LastModifiedAt time.Time
+}
+
+// Returns the collection of entries present in the folder available at the given path in the local file system, sorted by their names. If the given path does not point to a folder, then an error is returned.
+func ListDirectory(FolderPath string) ([]DirectoryEntry, error) {
+	return ListDirectoryFS(OSFileSystem{}, FolderPath)
+}
+
+// Returns the type of the given path in the given file system (like an embed.FS) i.e., file or folder. The path must be a slash-separated path as accepted by io/fs, with "." for the root folder,
+// except for an OSFileSystem which accepts the paths of the operating system. An error is returned if the given path is neither a file nor a folder.
+func GetPathTypeFS(FileSystem iofs.FS, TargetPath string) (string, error) {
+	fileStat, err := iofs.Stat(FileSystem, TargetPath)
 	if err != nil {
 		fsfErr := new(FileSystemError)
 		fsfErr.TargetPath = TargetPath
 		fsfErr.Message = fmt.Sprintf("GetPathType: Error occurred while fetching file stats: %s", err.Error())
 		return "", fsfErr
 	}
+
 	fileMode := fileStat.Mode()
 	if fileMode.IsDir() {
 		return FOLDER_TYPE_PATH, nil
 	} else if fileMode.IsRegular() {
 		return FILE_TYPE_PATH, nil
-	} else {
-		nfErr := new(FileSystemError)
-		nfErr.TargetPath = TargetPath
-		nfErr.Message = "Given path points neither to a file nor to a folder"
-		return "", nfErr
 	}
+
+	nfErr := new(FileSystemError)
+	nfErr.TargetPath = TargetPath
+	nfErr.Message = "GetPathType: Given path points neither to a file nor to a folder"
+	return "", nfErr
 }
 
-// Reads the contents of the file available at the given path and returns it as a byte slice.
-func ReadFileContents(CompleteFilePath string) ([]byte, error) {
-	fileContents := make([]byte, 0)
-	fileHandler, err := os.Open(CompleteFilePath)
+// Reads the contents of the file available at the given path in the given file system and returns it as a byte slice.
+func ReadFileContentsFS(FileSystem iofs.FS, CompleteFilePath string) ([]byte, error) {
+	fileContents, err := iofs.ReadFile(FileSystem, CompleteFilePath)
 	if err != nil {
 		fsfErr := new(FileSystemError)
 		fsfErr.TargetPath = CompleteFilePath
-		fsfErr.Message = fmt.Sprintf("Error occurred while reading file contents: %s", err.Error())
+		fsfErr.Message = fmt.Sprintf("ReadFileContents: Error occurred while reading file contents: %s", err.Error())
 		return nil, fsfErr
 	}
-	defer fileHandler.Close()
-	reader := bufio.NewReader(fileHandler)
-	for {
-		chunk := make([]byte, CHUNK_SIZE)
-		bytesRead, err := reader.Read(chunk)
-		if err != nil {
-			if err != io.EOF {
-				return nil, err
-			}
-			break
-		}
-		if bytesRead < CHUNK_SIZE {
-			newChunk := chunk[0: bytesRead]
-			fileContents = append(fileContents, newChunk...)
-		} else {
-			fileContents = append(fileContents, chunk...)
-		}
-	}
 
 	return fileContents, nil
 }
 
-// Returns pointer to a FILE object that contains metadata for file available at the given path. 
-// The metadata include file contents, last modified time, base name and size in bytes. If the given path does not point to a file, then an error is returned.
-func GetFile(CompleteFilePath string, ContentType string, OnlyMetadata bool) (*File, error) {
-	var file File
-	fileStat, err := os.Stat(CompleteFilePath)
+// Opens the file available at the given path in the given file system for reading its contents as a stream. The caller must close the file once its contents have been read.
+func OpenFileFS(FileSystem iofs.FS, CompleteFilePath string) (iofs.File, error) {
+	fileHandler, err := FileSystem.Open(CompleteFilePath)
+	if err != nil {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = CompleteFilePath
+		fsfErr.Message = fmt.Sprintf("OpenFile: Error occurred while opening the file: %s", err.Error())
+		return nil, fsfErr
+	}
+
+	return fileHandler, nil
+}
+
+// Returns pointer to a FILE object that contains metadata for the file available at the given path in the given file system, like GetFile() does for the local file system.
+// File systems which do not record modification times (like embed.FS) report a zero last modified time. If the given path does not point to a file, then an error is returned.
+func GetFileFS(FileSystem iofs.FS, CompleteFilePath string, ContentType string, OnlyMetadata bool) (*File, error) {
+	fileStat, err := iofs.Stat(FileSystem, CompleteFilePath)
 	if err != nil {
 		fsfErr := new(FileSystemError)
 		fsfErr.TargetPath = CompleteFilePath
-		fsfErr.Message = fmt.Sprintf("Error occurred while fetching file stats: %s", err.Error())
+		fsfErr.Message = fmt.Sprintf("GetFile: Error occurred while fetching file stats: %s", err.Error())
 		return nil, fsfErr
 	}
-	Mode := fileStat.Mode()
-	if Mode.IsRegular() {
-		file.ContentType = strings.TrimSpace(ContentType)
-		if !OnlyMetadata {
-			fileContents, err := ReadFileContents(CompleteFilePath)
-			if err != nil {
-				return nil, err
-			}
-
-			file.Contents = fileContents
-		} 
-	
-		file.LastModifiedAt = fileStat.ModTime()
-		file.Name = fileStat.Name()
-		file.Size = fileStat.Size()
-		return &file, nil
-	} else {
+
+	if !fileStat.Mode().IsRegular() {
 		fsfErr := new(FileSystemError)
 		fsfErr.TargetPath = CompleteFilePath
-		fsfErr.Message = "Given path does not point to a file"
+		fsfErr.Message = "GetFile: Given path does not point to a file"
 		return nil, fsfErr
 	}
-}
\ No newline at end of file
+
+	file := File{ ContentType: strings.TrimSpace(ContentType), LastModifiedAt: fileStat.ModTime(), Name: fileStat.Name(), Size: fileStat.Size() }
+	if !OnlyMetadata {
+		file.Contents, err = ReadFileContentsFS(FileSystem, CompleteFilePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &file, nil
+}
+
+// Returns the collection of entries present in the folder available at the given path in the given file system, sorted by their names. If the given path does not point to a folder, then an error is returned.
+func ListDirectoryFS(FileSystem iofs.FS, FolderPath string) ([]DirectoryEntry, error) {
+	dirEntries, err := iofs.ReadDir(FileSystem, FolderPath)
+	if err != nil {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = FolderPath
+		fsfErr.Message = fmt.Sprintf("ListDirectory: Error occurred while reading the folder contents: %s", err.Error())
+		return nil, fsfErr
+	}
+
+	entries := make([]DirectoryEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		entryInfo, err := dirEntry.Info()
+		if err != nil {
+			// The entry might have been removed after the folder contents were read.
+			continue
+		}
+
+		entry := DirectoryEntry{ Name: dirEntry.Name(), IsDir: dirEntry.IsDir(), LastModifiedAt: entryInfo.ModTime() }
+		if !entry.IsDir {
+			entry.Size = entryInfo.Size()
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Returns the media type of the file available at the given path in the given file system, determined by inspecting the first SNIFF_LENGTH bytes of the file using the content sniffing algorithm of the
+// WHATWG MIME Sniffing standard (as implemented by the net/http package). "application/octet-stream" is returned if the contents do not match any of the known media types.
+func SniffContentType(FileSystem iofs.FS, CompleteFilePath string) (string, error) {
+	fileHandler, err := OpenFileFS(FileSystem, CompleteFilePath)
+	if err != nil {
+		return "", err
+	}
+	defer fileHandler.Close()
+
+	fileHeader := make([]byte, SNIFF_LENGTH)
+	bytesRead, err := io.ReadFull(fileHandler, fileHeader)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = CompleteFilePath
+		fsfErr.Message = fmt.Sprintf("SniffContentType: Error occurred while reading file contents: %s", err.Error())
+		return "", fsfErr
+	}
+
+	return http.DetectContentType(fileHeader[:bytesRead]), nil
+}
@@ -1,8 +1,12 @@
 package fs
 
 import (
+	iofs "io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 // Test case to validate the working of the GetPathType() function to fetch the path type for a given file system path.
@@ -45,4 +49,137 @@ func Test_GetPathType(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Test case to validate the working of the ListDirectory() function to fetch the entries present in a folder.
+func Test_ListDirectory(t *testing.T) {
+	testFolder := t.TempDir()
+	os.Mkdir(filepath.Join(testFolder, "docs"), 0755)
+	os.WriteFile(filepath.Join(testFolder, "index.html"), []byte("<html></html>"), 0644)
+	testCases := []struct {
+		Name string
+		testPath string
+		ExpectedEntries []DirectoryEntry
+		ExpectedErrorType string
+	} {
+		{ "Path pointing to a folder", testFolder, []DirectoryEntry{ { Name: "docs", IsDir: true }, { Name: "index.html", Size: 13 } }, "" },
+		{ "Path pointing to a file", filepath.Join(testFolder, "index.html"), nil, "FileSystemError" },
+		{ "Path that does not exist", filepath.Join(testFolder, "missing"), nil, "FileSystemError" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			entries, err := ListDirectory(testCase.testPath)
+			if testCase.ExpectedErrorType == "FileSystemError" {
+				fsErr, ok := err.(*FileSystemError)
+				if !ok {
+					tt.Errorf("Expected a FileSystemError, but got %v instead", err)
+				} else {
+					tt.Logf("Received a FileSystemError as expected - %v", fsErr)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error, and yet received one - %v", err)
+				return
+			}
+
+			if len(entries) != len(testCase.ExpectedEntries) {
+				tt.Errorf("Expected %d entries, but got %d", len(testCase.ExpectedEntries), len(entries))
+				return
+			}
+
+			for index, entry := range entries {
+				expEntry := testCase.ExpectedEntries[index]
+				if entry.Name != expEntry.Name || entry.IsDir != expEntry.IsDir || entry.Size != expEntry.Size {
+					tt.Errorf("Entry %v does not match the expected entry %v", entry, expEntry)
+				} else {
+					tt.Logf("Entry %s matches the expected entry", entry.Name)
+				}
+			}
+		})
+	}
+}
+
+// Test case to validate the working of the GetFileFS() function to fetch the metadata and contents of a file present in a file system given as an io/fs value.
+func Test_GetFileFS(t *testing.T) {
+	testFS := fstest.MapFS{
+		"assets/site.css": &fstest.MapFile{ Data: []byte("body { margin: 0; }") },
+	}
+	testCases := []struct {
+		Name string
+		testPath string
+		OnlyMetadata bool
+		ExpectedContents string
+		ExpectedErrorType string
+	} {
+		{ "Path pointing to a file", "assets/site.css", false, "body { margin: 0; }", "" },
+		{ "Path pointing to a file for its metadata only", "assets/site.css", true, "", "" },
+		{ "Path pointing to a folder", "assets", false, "", "FileSystemError" },
+		{ "Path that does not exist", "assets/missing.css", false, "", "FileSystemError" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			file, err := GetFileFS(testFS, testCase.testPath, "text/css", testCase.OnlyMetadata)
+			if testCase.ExpectedErrorType == "FileSystemError" {
+				fsErr, ok := err.(*FileSystemError)
+				if !ok {
+					tt.Errorf("Expected a FileSystemError, but got %v instead", err)
+				} else {
+					tt.Logf("Received a FileSystemError as expected - %v", fsErr)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error, and yet received one - %v", err)
+			} else if string(file.Contents) != testCase.ExpectedContents || file.Name != "site.css" || file.Size != 19 || file.ContentType != "text/css" {
+				tt.Errorf("File %v does not match the expected file", file)
+			} else {
+				tt.Logf("File %s matches the expected file", file.Name)
+			}
+		})
+	}
+}
+
+// File system implementing only the Open method of io/fs, like an embed.FS which does not implement fs.StatFS.
+type testOpenOnlyFS struct {
+	// Files present in the file system.
+	files fstest.MapFS
+}
+
+// Opens the file or folder available at the given path in the underlying file system.
+func (ofs testOpenOnlyFS) Open(Name string) (iofs.File, error) {
+	return ofs.files.Open(Name)
+}
+
+// Test case to validate the working of the FromFS() function to adapt io/fs file systems to the FileSystem interface.
+func Test_FromFS(t *testing.T) {
+	mapFS := fstest.MapFS{ "docs/readme.txt": &fstest.MapFile{ Data: []byte("0123456789") } }
+	testCases := []struct {
+		Name string
+		Source iofs.FS
+	} {
+		{ "File system implementing FileSystem", mapFS },
+		{ "File system implementing only Open", testOpenOnlyFS{ mapFS } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			fileSystem := FromFS(testCase.Source)
+			fileStat, err := fileSystem.Stat("docs/readme.txt")
+			if err != nil || fileStat.Size() != 10 {
+				tt.Errorf("Expected the file to be found with a size of 10 bytes, but got %v (error: %v)", fileStat, err)
+				return
+			}
+
+			dirEntries, err := fileSystem.ReadDir("docs")
+			if err != nil || len(dirEntries) != 1 || dirEntries[0].Name() != "readme.txt" {
+				tt.Errorf("Expected the folder to contain readme.txt, but got %v (error: %v)", dirEntries, err)
+			} else {
+				tt.Logf("The adapted file system returns the expected file and folder entries")
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	iofs "io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Structure to represent a file system held in memory, which can be used in place of the local file system in unit tests or to serve generated files. Its paths are slash-separated paths as used by io/fs,
+// with "." for the root folder. The folders containing a file are created when the file is written. It is safe for concurrent use.
+type MemoryFileSystem struct {
+	// Collection of files and folders present in the file system, with their paths as keys. The root folder is not stored.
+	entries map[string]*memoryEntry
+	// Mutex to synchronize access to the files and folders across multiple goroutines.
+	mutex sync.RWMutex
+}
+
+// Structure to represent a single file or folder present in a MemoryFileSystem.
+type memoryEntry struct {
+	// Contents of the file. It is nil for folders.
+	contents []byte
+	// Time at which the file or folder was last modified.
+	modifiedAt time.Time
+	// Is true if the entry is a folder.
+	isDir bool
+}
+
+// Creates and returns pointer to a new MemoryFileSystem containing only the root folder.
+func NewMemoryFileSystem() *MemoryFileSystem {
+	return &MemoryFileSystem{ entries: make(map[string]*memoryEntry) }
+}
+
+// Writes the given contents to the file available at the given path, creating the file (and the folders containing it) if it does not exist. The file is given the current time as its last modified time.
+// An error is returned if the given path is not valid or if the file or one of its folders would replace an existing entry of a different type.
+func (mfs *MemoryFileSystem) WriteFile(Name string, Contents []byte) error {
+	if !iofs.ValidPath(Name) || Name == "." {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = Name
+		fsfErr.Message = "WriteFile: Given path is not a valid file path"
+		return fsfErr
+	}
+
+	mfs.mutex.Lock()
+	defer mfs.mutex.Unlock()
+	if entry, exists := mfs.entries[Name]; exists && entry.isDir {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = Name
+		fsfErr.Message = "WriteFile: Given path points to a folder"
+		return fsfErr
+	}
+
+	if err := mfs.makeFolders(path.Dir(Name)); err != nil {
+		return err
+	}
+
+	// The contents are copied, so that changes made by the caller to the given slice do not change the file.
+	mfs.entries[Name] = &memoryEntry{ contents: append(make([]byte, 0, len(Contents)), Contents...), modifiedAt: time.Now() }
+	return nil
+}
+
+// Creates the folder available at the given path along with the folders containing it, if they do not exist. An error is returned if the given path is not valid or if one of the folders is a file.
+func (mfs *MemoryFileSystem) MkdirAll(Name string) error {
+	if !iofs.ValidPath(Name) {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = Name
+		fsfErr.Message = "MkdirAll: Given path is not a valid folder path"
+		return fsfErr
+	}
+
+	mfs.mutex.Lock()
+	defer mfs.mutex.Unlock()
+	return mfs.makeFolders(Name)
+}
+
+// Creates the folder available at the given path along with the folders containing it, if they do not exist. The caller must hold the lock of the file system.
+func (mfs *MemoryFileSystem) makeFolders(Name string) error {
+	for folderPath := Name; folderPath != "."; folderPath = path.Dir(folderPath) {
+		entry, exists := mfs.entries[folderPath]
+		if !exists {
+			mfs.entries[folderPath] = &memoryEntry{ modifiedAt: time.Now(), isDir: true }
+		} else if !entry.isDir {
+			fsfErr := new(FileSystemError)
+			fsfErr.TargetPath = folderPath
+			fsfErr.Message = "makeFolders: Given path points to a file"
+			return fsfErr
+		}
+	}
+
+	return nil
+}
+
+// Removes the file or folder (along with its contents) available at the given path. An error is returned if the given path does not exist or points to the root folder.
+func (mfs *MemoryFileSystem) Remove(Name string) error {
+	mfs.mutex.Lock()
+	defer mfs.mutex.Unlock()
+	if _, exists := mfs.entries[Name]; !exists {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = Name
+		fsfErr.Message = "Remove: Given path does not exist"
+		return fsfErr
+	}
+
+	for entryPath := range mfs.entries {
+		if entryPath == Name || strings.HasPrefix(entryPath, Name + "/") {
+			delete(mfs.entries, entryPath)
+		}
+	}
+
+	return nil
+}
+
+// Returns the metadata of the file or folder available at the given path, as a value implementing both fs.FileInfo and fs.DirEntry. The caller must hold the lock of the file system.
+func (mfs *MemoryFileSystem) getInfo(Operation string, Name string) (*memoryFileInfo, *memoryEntry, error) {
+	if !iofs.ValidPath(Name) {
+		return nil, nil, &iofs.PathError{ Op: Operation, Path: Name, Err: iofs.ErrInvalid }
+	}
+
+	if Name == "." {
+		return &memoryFileInfo{ name: ".", isDir: true }, &memoryEntry{ isDir: true }, nil
+	}
+
+	entry, exists := mfs.entries[Name]
+	if !exists {
+		return nil, nil, &iofs.PathError{ Op: Operation, Path: Name, Err: iofs.ErrNotExist }
+	}
+
+	return &memoryFileInfo{ name: path.Base(Name), size: int64(len(entry.contents)), modifiedAt: entry.modifiedAt, isDir: entry.isDir }, entry, nil
+}
+
+// Opens the file or folder available at the given path. The files opened implement io.Seeker and io.ReaderAt, and continue to return the contents they had when they were opened.
+func (mfs *MemoryFileSystem) Open(Name string) (iofs.File, error) {
+	mfs.mutex.RLock()
+	defer mfs.mutex.RUnlock()
+	info, entry, err := mfs.getInfo("open", Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.isDir {
+		return &memoryFile{ info: info, Reader: bytes.NewReader(entry.contents) }, nil
+	}
+
+	entries, err := mfs.readDir(Name)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryFolder{ path: Name, info: info, entries: entries }, nil
+}
+
+// Returns the metadata of the file or folder available at the given path.
+func (mfs *MemoryFileSystem) Stat(Name string) (iofs.FileInfo, error) {
+	mfs.mutex.RLock()
+	defer mfs.mutex.RUnlock()
+	info, _, err := mfs.getInfo("stat", Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Returns the entries present in the folder available at the given path, sorted by their names.
+func (mfs *MemoryFileSystem) ReadDir(Name string) ([]iofs.DirEntry, error) {
+	mfs.mutex.RLock()
+	defer mfs.mutex.RUnlock()
+	info, _, err := mfs.getInfo("readdir", Name)
+	if err != nil {
+		return nil, err
+	} else if !info.isDir {
+		return nil, &iofs.PathError{ Op: "readdir", Path: Name, Err: iofs.ErrInvalid }
+	}
+
+	return mfs.readDir(Name)
+}
+
+// Returns the entries present directly in the folder available at the given path, sorted by their names. The caller must hold the lock of the file system.
+func (mfs *MemoryFileSystem) readDir(Name string) ([]iofs.DirEntry, error) {
+	entries := make([]iofs.DirEntry, 0)
+	for entryPath := range mfs.entries {
+		if path.Dir(entryPath) == Name {
+			info, _, err := mfs.getInfo("readdir", entryPath)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, info)
+		}
+	}
+
+	slices.SortFunc(entries, func(first iofs.DirEntry, second iofs.DirEntry) int {
+		return strings.Compare(first.Name(), second.Name())
+	})
+	return entries, nil
+}
+
+// Structure to represent the metadata of a file or folder present in a MemoryFileSystem. It implements both fs.FileInfo and fs.DirEntry.
+type memoryFileInfo struct {
+	// Base name of the file or folder.
+	name string
+	// Size of the file in bytes. It is zero for folders.
+	size int64
+	// Time at which the file or folder was last modified.
+	modifiedAt time.Time
+	// Is true if the entry is a folder.
+	isDir bool
+}
+
+// Returns the base name of the file or folder.
+func (info *memoryFileInfo) Name() string {
+	return info.name
+}
+
+// Returns the size of the file in bytes.
+func (info *memoryFileInfo) Size() int64 {
+	return info.size
+}
+
+// Returns the file mode bits, which are read-only permissions along with fs.ModeDir for folders.
+func (info *memoryFileInfo) Mode() iofs.FileMode {
+	if info.isDir {
+		return iofs.ModeDir | 0555
+	}
+	return 0444
+}
+
+// Returns the time at which the file or folder was last modified.
+func (info *memoryFileInfo) ModTime() time.Time {
+	return info.modifiedAt
+}
+
+// Returns true if the entry is a folder.
+func (info *memoryFileInfo) IsDir() bool {
+	return info.isDir
+}
+
+// Returns nil, as there is no underlying data source.
+func (info *memoryFileInfo) Sys() any {
+	return nil
+}
+
+// Returns the type bits of the entry.
+func (info *memoryFileInfo) Type() iofs.FileMode {
+	return info.Mode().Type()
+}
+
+// Returns the metadata of the entry.
+func (info *memoryFileInfo) Info() (iofs.FileInfo, error) {
+	return info, nil
+}
+
+// Structure to represent a file of a MemoryFileSystem opened for reading.
+type memoryFile struct {
+	// Metadata of the file.
+	info *memoryFileInfo
+	// Reader over the contents of the file, which provides the Read, Seek and ReadAt methods.
+	*bytes.Reader
+}
+
+// Returns the metadata of the file.
+func (file *memoryFile) Stat() (iofs.FileInfo, error) {
+	return file.info, nil
+}
+
+// Closes the file. Files held in memory do not hold any resources, so closing them always succeeds.
+func (file *memoryFile) Close() error {
+	return nil
+}
+
+// Structure to represent a folder of a MemoryFileSystem opened for reading its entries.
+type memoryFolder struct {
+	// Path of the folder in the file system.
+	path string
+	// Metadata of the folder.
+	info *memoryFileInfo
+	// Entries present in the folder when it was opened.
+	entries []iofs.DirEntry
+	// Number of entries already returned by ReadDir().
+	offset int
+}
+
+// Returns the metadata of the folder.
+func (folder *memoryFolder) Stat() (iofs.FileInfo, error) {
+	return folder.info, nil
+}
+
+// Returns an error, as folders cannot be read as a stream of bytes.
+func (folder *memoryFolder) Read(Contents []byte) (int, error) {
+	return 0, &iofs.PathError{ Op: "read", Path: folder.path, Err: iofs.ErrInvalid }
+}
+
+// Closes the folder.
+func (folder *memoryFolder) Close() error {
+	return nil
+}
+
+// Returns the next entries of the folder, as defined by fs.ReadDirFile. If Count is zero or negative, all the remaining entries are returned.
+func (folder *memoryFolder) ReadDir(Count int) ([]iofs.DirEntry, error) {
+	remaining := folder.entries[folder.offset:]
+	if Count <= 0 {
+		folder.offset = len(folder.entries)
+		return remaining, nil
+	} else if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	Count = min(Count, len(remaining))
+	folder.offset += Count
+	return remaining[:Count], nil
+}
@@ -0,0 +1,56 @@
+package fs
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+// Test case to validate that the MemoryFileSystem behaves as an io/fs file system, using the checks of testing/fstest, and that it can be read using the file system functions of this package.
+func Test_MemoryFileSystem(t *testing.T) {
+	testFS := NewMemoryFileSystem()
+	testFS.WriteFile("index.html", []byte("<html></html>"))
+	testFS.WriteFile("assets/css/site.css", []byte("body { margin: 0; }"))
+	testFS.MkdirAll("uploads")
+	if err := fstest.TestFS(testFS, "index.html", "assets/css/site.css", "uploads"); err != nil {
+		t.Fatalf("Was not expecting the file system checks to fail, but got this instead - %v", err)
+	}
+
+	testCases := []struct {
+		Name string
+		Operation func() error
+		ExpectedErrorType string
+	} {
+		{ "Writing a file over a folder", func() error { return testFS.WriteFile("assets", []byte("x")) }, "FileSystemError" },
+		{ "Writing a file inside a file", func() error { return testFS.WriteFile("index.html/about.html", []byte("x")) }, "FileSystemError" },
+		{ "Writing a file at an invalid path", func() error { return testFS.WriteFile("../secret.txt", []byte("x")) }, "FileSystemError" },
+		{ "Removing a path that does not exist", func() error { return testFS.Remove("missing") }, "FileSystemError" },
+		{ "Removing a folder with its contents", func() error { return testFS.Remove("assets") }, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			err := testCase.Operation()
+			if testCase.ExpectedErrorType == "FileSystemError" {
+				fsErr, ok := err.(*FileSystemError)
+				if !ok {
+					tt.Errorf("Expected a FileSystemError, but got %v instead", err)
+				} else {
+					tt.Logf("Received a FileSystemError as expected - %v", fsErr)
+				}
+			} else if err != nil {
+				tt.Errorf("Was not expecting an error, and yet received one - %v", err)
+			} else {
+				tt.Logf("Operation completed without an error as expected")
+			}
+		})
+	}
+
+	if _, err := GetPathTypeFS(testFS, "assets/css/site.css"); err == nil {
+		t.Errorf("Expected the contents of the removed folder to be removed as well")
+	}
+
+	entries, err := ListDirectoryFS(testFS, ".")
+	if err != nil || len(entries) != 2 || entries[0].Name != "index.html" || entries[1].Name != "uploads" || !entries[1].IsDir {
+		t.Errorf("Expected the root folder to contain index.html and uploads, but got %v (error: %v)", entries, err)
+	}
+}
@@ -0,0 +1,190 @@
+package fs
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Structure to represent the state of a file or folder recorded by a Watcher, which is compared across scans to detect changes.
+type watchedState struct {
+	// Time at which the file or folder was last modified, in nanoseconds since the Unix epoch.
+	modifiedAt int64
+	// Size of the file in bytes.
+	size int64
+	// Is true if the entry is a folder.
+	isDir bool
+}
+
+// Structure to represent a watcher which monitors files and folders of the local file system for changes, by scanning them at regular intervals. Folders are watched along with all the files and
+// folders present in them. Changes (files or folders being created, modified or removed) are debounced, so that a burst of changes (like an editor saving several files at once) results in a single
+// invocation of the callback with all the changed paths. Polling is used instead of operating system notifications, so that the watcher behaves the same way on all operating systems.
+type Watcher struct {
+	// Duration between two scans of the watched paths.
+	Interval time.Duration
+	// Duration for which no further changes must be detected before the callback is invoked.
+	Debounce time.Duration
+	// Function invoked (on the goroutine of the watcher) with the sorted paths of the files and folders changed since the previous invocation.
+	OnChange func(ChangedPaths []string)
+	// Collection of paths being watched.
+	paths []string
+	// State of the files and folders found in the last scan, with their complete paths as keys.
+	states map[string]watchedState
+	// Collection of paths changed since the callback was last invoked.
+	pending map[string]bool
+	// Time at which the last change was detected.
+	lastChangeAt time.Time
+	// Channel closed to stop the watcher. It is nil if the watcher is not running.
+	stop chan struct{}
+	// Channel closed once the goroutine of the watcher has returned.
+	stopped chan struct{}
+	// Mutex to synchronize access to the watched paths and the state of the watcher.
+	mutex sync.Mutex
+}
+
+// Creates and returns pointer to a new Watcher which scans the watched paths once every given interval and invokes the given callback once no further changes have been detected for the given debounce duration.
+func NewWatcher(Interval time.Duration, Debounce time.Duration, OnChange func(ChangedPaths []string)) *Watcher {
+	return &Watcher{ Interval: Interval, Debounce: Debounce, OnChange: OnChange, states: make(map[string]watchedState), pending: make(map[string]bool) }
+}
+
+// Adds the file or folder available at the given path to the paths being watched. Changes made to the path before it is added are not reported. An error is returned if the path does not exist.
+func (watcher *Watcher) Add(TargetPath string) error {
+	TargetPath = filepath.Clean(TargetPath)
+	if _, err := os.Stat(TargetPath); err != nil {
+		fsfErr := new(FileSystemError)
+		fsfErr.TargetPath = TargetPath
+		fsfErr.Message = fmt.Sprintf("Add: Error occurred while fetching file stats: %s", err.Error())
+		return fsfErr
+	}
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	if slices.Contains(watcher.paths, TargetPath) {
+		return nil
+	}
+
+	watcher.paths = append(watcher.paths, TargetPath)
+	for entryPath, state := range scanPath(TargetPath) {
+		watcher.states[entryPath] = state
+	}
+	return nil
+}
+
+// Starts watching the paths in a separate goroutine, until Stop() is called. Calling Start() on a watcher which is already running has no effect.
+func (watcher *Watcher) Start() {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	if watcher.stop != nil {
+		return
+	}
+
+	interval := watcher.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	watcher.stop = make(chan struct{})
+	watcher.stopped = make(chan struct{})
+	go watcher.run(interval, watcher.stop, watcher.stopped)
+}
+
+// Stops watching the paths and waits for the goroutine of the watcher to return, which is why it must not be called from the callback. Changes which have not been reported yet are reported once the watcher
+// is started again.
+func (watcher *Watcher) Stop() {
+	watcher.mutex.Lock()
+	stop, stopped := watcher.stop, watcher.stopped
+	watcher.stop, watcher.stopped = nil, nil
+	watcher.mutex.Unlock()
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+}
+
+// Scans the watched paths once every given interval until the given stop channel is closed.
+func (watcher *Watcher) run(interval time.Duration, stop chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if changedPaths := watcher.Scan(); len(changedPaths) > 0 && watcher.OnChange != nil {
+				watcher.OnChange(changedPaths)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Scans the watched paths once and returns the sorted paths changed since the changes were last returned, once no further changes have been detected for the debounce duration. Otherwise, no paths are returned.
+// It is called by the goroutine of the watcher, but can also be called directly to check for changes without starting the watcher.
+func (watcher *Watcher) Scan() []string {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	currentStates := make(map[string]watchedState)
+	for _, watchedPath := range watcher.paths {
+		for entryPath, state := range scanPath(watchedPath) {
+			currentStates[entryPath] = state
+		}
+	}
+
+	now := time.Now()
+	for entryPath, state := range currentStates {
+		if previousState, found := watcher.states[entryPath]; !found || previousState != state {
+			watcher.pending[entryPath] = true
+			watcher.lastChangeAt = now
+		}
+	}
+
+	for entryPath := range watcher.states {
+		if _, found := currentStates[entryPath]; !found {
+			watcher.pending[entryPath] = true
+			watcher.lastChangeAt = now
+		}
+	}
+
+	watcher.states = currentStates
+	if len(watcher.pending) == 0 || now.Sub(watcher.lastChangeAt) < watcher.Debounce {
+		return nil
+	}
+
+	changedPaths := make([]string, 0, len(watcher.pending))
+	for entryPath := range watcher.pending {
+		changedPaths = append(changedPaths, entryPath)
+	}
+
+	slices.Sort(changedPaths)
+	watcher.pending = make(map[string]bool)
+	return changedPaths
+}
+
+// Returns the state of the file or folder available at the given path, along with all the files and folders present in it. Entries which cannot be read are left out, so that they are reported as removed.
+func scanPath(TargetPath string) map[string]watchedState {
+	states := make(map[string]watchedState)
+	filepath.WalkDir(TargetPath, func(entryPath string, dirEntry iofs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		entryInfo, err := dirEntry.Info()
+		if err != nil {
+			return nil
+		}
+
+		state := watchedState{ modifiedAt: entryInfo.ModTime().UnixNano(), isDir: dirEntry.IsDir() }
+		if !state.isDir {
+			state.size = entryInfo.Size()
+		}
+
+		states[entryPath] = state
+		return nil
+	})
+
+	return states
+}
@@ -0,0 +1,91 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Test case to validate the changes reported by the Scan() method of a Watcher, for files being created, modified and removed in a watched folder, along with the debouncing of the changes.
+func Test_Watcher_Scan(t *testing.T) {
+	testFolder := t.TempDir()
+	os.WriteFile(filepath.Join(testFolder, "index.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "old.html"), []byte("old"), 0644)
+	watcher := NewWatcher(time.Second, 0, nil)
+	if err := watcher.Add(testFolder); err != nil {
+		t.Fatalf("Was not expecting an error while watching the folder, but got this instead - %v", err)
+	}
+
+	folderChangedAt := time.Now().Add(time.Hour)
+	testCases := []struct {
+		Name string
+		Change func()
+		Debounce time.Duration
+		ExpectedPaths []string
+	} {
+		{ "Folder without changes", func() {}, 0, nil },
+		{ "File modified", func() { os.WriteFile(filepath.Join(testFolder, "index.html"), []byte("<html>changed</html>"), 0644) }, 0, []string{ "index.html" } },
+		{ "File created and another removed", func() {
+			os.WriteFile(filepath.Join(testFolder, "new.html"), []byte("new"), 0644)
+			os.Remove(filepath.Join(testFolder, "old.html"))
+			os.Chtimes(testFolder, folderChangedAt, folderChangedAt)
+		}, 0, []string{ ".", "new.html", "old.html" } },
+		{ "File modified within the debounce duration", func() { os.WriteFile(filepath.Join(testFolder, "new.html"), []byte("newer"), 0644) }, time.Hour, nil },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testCase.Change()
+			watcher.Debounce = testCase.Debounce
+			changedPaths := make([]string, 0)
+			for _, changedPath := range watcher.Scan() {
+				relativePath, _ := filepath.Rel(testFolder, changedPath)
+				changedPaths = append(changedPaths, filepath.ToSlash(relativePath))
+			}
+
+			if !slices.Equal(changedPaths, testCase.ExpectedPaths) {
+				tt.Errorf("Expected the changed paths %v, but got %v instead", testCase.ExpectedPaths, changedPaths)
+			} else {
+				tt.Logf("The changed paths %v have been reported as expected", changedPaths)
+			}
+		})
+	}
+
+	watcher.Debounce = 0
+	if changedPaths := watcher.Scan(); len(changedPaths) != 1 {
+		t.Errorf("Expected the debounced change to be reported once the debounce duration is over, but got %v", changedPaths)
+	}
+
+	if err := watcher.Add(filepath.Join(testFolder, "missing")); err == nil {
+		t.Errorf("Was expecting an error while watching a path that does not exist")
+	}
+}
+
+// Test case to validate that a running Watcher invokes its callback with the changed paths, and stops invoking it once stopped.
+func Test_Watcher_Start(t *testing.T) {
+	testFolder := t.TempDir()
+	changes := make(chan []string, 10)
+	watcher := NewWatcher(10 * time.Millisecond, 0, func(ChangedPaths []string) { changes <- ChangedPaths })
+	watcher.Add(testFolder)
+	watcher.Start()
+	watcher.Start()
+	os.WriteFile(filepath.Join(testFolder, "config.json"), []byte("{}"), 0644)
+	select {
+	case changedPaths := <-changes:
+		if !slices.Contains(changedPaths, filepath.Join(testFolder, "config.json")) {
+			t.Errorf("Expected config.json to be among the changed paths, but got %v", changedPaths)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the callback to be invoked once the file was created")
+	}
+
+	watcher.Stop()
+	watcher.Stop()
+	os.WriteFile(filepath.Join(testFolder, "other.json"), []byte("{}"), 0644)
+	time.Sleep(50 * time.Millisecond)
+	if len(changes) != 0 {
+		t.Errorf("Was not expecting the callback to be invoked once the watcher was stopped")
+	}
+}
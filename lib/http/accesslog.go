@@ -0,0 +1,135 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Represents the format in which the access log entries are written.
+type AccessLogFormat string
+
+const (
+	// Apache Common Log Format - remote address, identity, user, time, request line, status and response size.
+	CommonAccessLogFormat AccessLogFormat = "common"
+	// Apache Combined Log Format - the common log format followed by the Referer and User-Agent request headers.
+	CombinedAccessLogFormat AccessLogFormat = "combined"
+)
+
+// Date and time format used for the timestamps in the access log entries.
+const ACCESS_LOG_TIME_FORMAT = "02/Jan/2006:15:04:05 -0700"
+
+// Structure to represent an access logger, which records an entry for every request processed by the web server instance.
+type AccessLogger struct {
+	// Format in which the access log entries are written.
+	Format AccessLogFormat
+	// Boolean value to indicate if the time taken to process the request (in microseconds) is appended to each access log entry.
+	IncludeLatency bool
+	// Boolean value to indicate if the ID assigned to the request is appended (within quotes) to each access log entry, after the latency if it is included.
+	IncludeRequestID bool
+	// Optional hook executed before each access log entry is written, with the current writer and the number of bytes written to it so far. If the hook returns a different writer,
+	// the access log entries are written to the returned writer from then on. This can be used to rotate the access log files based on their size or age.
+	RotateFunc func(Current io.Writer, BytesWritten int64) (io.Writer, error)
+	// Writer to which the access log entries are written.
+	output io.Writer
+	// Number of bytes written to the current writer.
+	bytesWritten int64
+	// Mutex to synchronize the writing of access log entries from multiple goroutines.
+	mutex sync.Mutex
+}
+
+// Replaces the writer to which the access log entries are written and returns the previous writer, so that it can be closed by the caller.
+func (alg *AccessLogger) Rotate(Output io.Writer) io.Writer {
+	alg.mutex.Lock()
+	defer alg.mutex.Unlock()
+	previousOutput := alg.output
+	alg.output = Output
+	alg.bytesWritten = 0
+	return previousOutput
+}
+
+// Writes an access log entry for the given request and response, where Latency is the time taken to process the request.
+func (alg *AccessLogger) log(request *HttpRequest, response *HttpResponse, Latency time.Duration) error {
+	entry := alg.formatEntry(request, response, time.Now(), Latency)
+	alg.mutex.Lock()
+	defer alg.mutex.Unlock()
+	if alg.RotateFunc != nil {
+		Output, err := alg.RotateFunc(alg.output, alg.bytesWritten)
+		if err != nil {
+			return err
+		}
+
+		if Output != nil && Output != alg.output {
+			alg.output = Output
+			alg.bytesWritten = 0
+		}
+	}
+
+	bytesWritten, err := io.WriteString(alg.output, entry)
+	alg.bytesWritten += int64(bytesWritten)
+	return err
+}
+
+// Returns the access log entry for the given request and response in the configured format.
+func (alg *AccessLogger) formatEntry(request *HttpRequest, response *HttpResponse, Timestamp time.Time, Latency time.Duration) string {
+	remoteHost := request.RemoteIP()
+
+	requestTarget := request.getRawPath()
+	if request.RawQuery != "" {
+		requestTarget += "?" + request.RawQuery
+	}
+
+	requestLine := fmt.Sprintf("%s %s HTTP/%s", request.Method, requestTarget, request.Version)
+	responseSize := "-"
+	if response.bodySize > 0 {
+		responseSize = fmt.Sprintf("%d", response.bodySize)
+	}
+
+	var entryBuilder strings.Builder
+	entryBuilder.WriteString(fmt.Sprintf("%s - - [%s] %q %d %s", getAccessLogValue(remoteHost), Timestamp.Format(ACCESS_LOG_TIME_FORMAT), requestLine, response.StatusCode, responseSize))
+	if alg.Format == CombinedAccessLogFormat {
+		Referer, _ := request.Headers.Get("Referer")
+		UserAgent, _ := request.Headers.Get("User-Agent")
+		entryBuilder.WriteString(fmt.Sprintf(" %q %q", getAccessLogValue(Referer), getAccessLogValue(UserAgent)))
+	}
+
+	if alg.IncludeLatency {
+		entryBuilder.WriteString(fmt.Sprintf(" %d", Latency.Microseconds()))
+	}
+
+	if alg.IncludeRequestID {
+		entryBuilder.WriteString(fmt.Sprintf(" %q", getAccessLogValue(request.requestID)))
+	}
+
+	entryBuilder.WriteString("\n")
+	return entryBuilder.String()
+}
+
+// Returns the given value trimmed of any leading and trailing whitespaces, or "-" if the value is empty.
+func getAccessLogValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// Enables access logging for the web server instance using the given access logger. An entry is recorded for every request processed by the server, including the requests for which no route was found.
+func (srv *HttpServer) UseAccessLog(accessLogger *AccessLogger) {
+	srv.accessLogger = accessLogger
+}
+
+// Records an access log entry for the given request and response, if access logging is enabled for the web server instance.
+func (srv *HttpServer) logAccess(request *HttpRequest, response *HttpResponse, RequestStartTime time.Time) {
+	if srv.accessLogger == nil {
+		return
+	}
+
+	err := srv.accessLogger.log(request, response, time.Since(RequestStartTime))
+	if err != nil {
+		srv.LogError("Error occurred while writing the access log entry", "error", err)
+	}
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// Test case to validate the access log entries written in the common and combined log formats.
+func Test_AccessLogger_FormatEntry(t *testing.T) {
+	timestamp := time.Date(2024, time.October, 10, 13, 55, 36, 0, time.FixedZone("", -7 * 60 * 60))
+	testCases := []struct {
+		Name string
+		Format AccessLogFormat
+		IncludeLatency bool
+		IncludeRequestID bool
+		RawQuery string
+		BodySize int
+		ExpEntry string
+	} {
+		{ "Common log format", CommonAccessLogFormat, false, false, "", 2326, `127.0.0.1 - - [10/Oct/2024:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326` + "\n" },
+		{ "Common log format with empty body and query string", CommonAccessLogFormat, false, false, "page=2", 0, `127.0.0.1 - - [10/Oct/2024:13:55:36 -0700] "GET /index.html?page=2 HTTP/1.1" 200 -` + "\n" },
+		{ "Combined log format", CombinedAccessLogFormat, false, false, "", 512, `127.0.0.1 - - [10/Oct/2024:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 512 "https://example.com/" "curl/8.0"` + "\n" },
+		{ "Combined log format with latency", CombinedAccessLogFormat, true, false, "", 512, `127.0.0.1 - - [10/Oct/2024:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 512 "https://example.com/" "curl/8.0" 1500` + "\n" },
+		{ "Common log format with request ID", CommonAccessLogFormat, false, true, "", 512, `127.0.0.1 - - [10/Oct/2024:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 512 "req-42"` + "\n" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/index.html"
+			testRequest.RawQuery = testCase.RawQuery
+			testRequest.Version = "1.1"
+			testRequest.ClientAddress = "127.0.0.1:52314"
+			testRequest.requestID = "req-42"
+			testRequest.Headers.Add("Referer", "https://example.com/")
+			testRequest.Headers.Add("User-Agent", "curl/8.0")
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.Status(StatusOK)
+			testResponse.bodySize = testCase.BodySize
+			accessLogger := NewAccessLogger(io.Discard, testCase.Format)
+			accessLogger.IncludeLatency = testCase.IncludeLatency
+			accessLogger.IncludeRequestID = testCase.IncludeRequestID
+			entry := accessLogger.formatEntry(testRequest, testResponse, timestamp, 1500 * time.Microsecond)
+			if entry != testCase.ExpEntry {
+				tt.Errorf("The access log entry [%s] does not match the expected entry [%s]", entry, testCase.ExpEntry)
+			} else {
+				tt.Logf("The access log entry matches the expected entry [%s]", testCase.ExpEntry)
+			}
+		})
+	}
+}
+
+// Test case to validate the rotation of the access log writer using the rotation hook.
+func Test_AccessLogger_Rotate(t *testing.T) {
+	var firstOutput, secondOutput bytes.Buffer
+	accessLogger := NewAccessLogger(&firstOutput, CommonAccessLogFormat)
+	accessLogger.RotateFunc = func(current io.Writer, bytesWritten int64) (io.Writer, error) {
+		if bytesWritten > 0 {
+			return &secondOutput, nil
+		}
+		return current, nil
+	}
+
+	testRequest := newTestRequest(t)
+	testRequest.Method = "GET"
+	testRequest.ResourcePath = "/"
+	testResponse := newTestResponse(t, "1.1")
+	testResponse.Status(StatusOK)
+	for index := 0; index < 2; index++ {
+		err := accessLogger.log(testRequest, testResponse, time.Millisecond)
+		if err != nil {
+			t.Fatalf("Was not expecting an error and yet received one - %v", err)
+		}
+	}
+
+	if bytes.Count(firstOutput.Bytes(), []byte("\n")) != 1 || bytes.Count(secondOutput.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("Expected one access log entry in each writer, but got [%s] and [%s]", firstOutput.String(), secondOutput.String())
+	}
+}
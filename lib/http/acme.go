@@ -0,0 +1,328 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	nethttp "net/http"
+	"time"
+)
+
+// Maximum size (in bytes) of a response read from an ACME server.
+const ACME_MAX_RESPONSE_SIZE = 1 << 20
+
+// Endpoints of an ACME server, as listed in its directory.
+type acmeDirectory struct {
+	// URL to get a fresh nonce for signing the next request.
+	NewNonce string `json:"newNonce"`
+	// URL to create (or look up) the account identified by the key signing the request.
+	NewAccount string `json:"newAccount"`
+	// URL to create a new order for a certificate.
+	NewOrder string `json:"newOrder"`
+}
+
+// Order for a certificate placed with an ACME server, as defined in RFC 8555 (section 7.1.3).
+type acmeOrder struct {
+	// Status of the order, like "pending", "ready", "processing", "valid" or "invalid".
+	Status string `json:"status"`
+	// URLs of the authorizations to be completed for the identifiers in the order.
+	Authorizations []string `json:"authorizations"`
+	// URL to which the certificate signing request is sent once all the authorizations have been completed.
+	Finalize string `json:"finalize"`
+	// URL from which the certificate can be downloaded once the order is valid.
+	Certificate string `json:"certificate"`
+	// Problem which caused the order to become invalid, if any.
+	Error *acmeProblem `json:"error"`
+}
+
+// Authorization of the account to obtain certificates for an identifier, as defined in RFC 8555 (section 7.1.4).
+type acmeAuthorization struct {
+	// Status of the authorization, like "pending", "valid" or "invalid".
+	Status string `json:"status"`
+	// Challenges offered by the ACME server, one of which must be completed to validate the authorization.
+	Challenges []acmeChallenge `json:"challenges"`
+}
+
+// Challenge offered by an ACME server to prove the control of an identifier, as defined in RFC 8555 (section 8).
+type acmeChallenge struct {
+	// Type of the challenge, like "http-01" or "tls-alpn-01".
+	Type string `json:"type"`
+	// URL to which the response to the challenge is sent.
+	URL string `json:"url"`
+	// Token from which the key authorization of the challenge is derived.
+	Token string `json:"token"`
+	// Status of the challenge, like "pending", "processing", "valid" or "invalid".
+	Status string `json:"status"`
+	// Problem which caused the validation of the challenge to fail, if any.
+	Error *acmeProblem `json:"error"`
+}
+
+// Problem document returned by an ACME server for a failed request, as defined in RFC 7807.
+type acmeProblem struct {
+	// Type of the problem, like "urn:ietf:params:acme:error:badNonce".
+	Type string `json:"type"`
+	// Human readable explanation of the problem.
+	Detail string `json:"detail"`
+}
+
+// Minimal client of the ACME protocol (RFC 8555), which signs its requests using the ECDSA P-256 key of the account.
+type acmeClient struct {
+	// URL of the directory of the ACME server.
+	directoryURL string
+	// Private key of the ACME account, which signs all the requests.
+	accountKey *ecdsa.PrivateKey
+	// URL of the ACME account, which identifies the account once it has been registered.
+	accountURL string
+	// Endpoints of the ACME server. It is nil if the directory has not been fetched yet.
+	directory *acmeDirectory
+	// Nonce returned by the last response of the ACME server, which is used to sign the next request.
+	nonce string
+	// Interval between the requests to poll the status of an authorization or an order.
+	pollInterval time.Duration
+	// HTTP client used to send requests to the ACME server.
+	httpClient *nethttp.Client
+}
+
+// Fetches the directory of the ACME server, if it has not been fetched yet.
+func (client *acmeClient) discover(ctx context.Context) error {
+	if client.directory != nil {
+		return nil
+	}
+
+	header, body, err := client.send(ctx, "GET", client.directoryURL, nil)
+	if err != nil {
+		return err
+	}
+
+	directory := new(acmeDirectory)
+	if err := decodeACMEResponse(client.directoryURL, header, body, directory); err != nil {
+		return err
+	} else if directory.NewNonce == "" || directory.NewAccount == "" || directory.NewOrder == "" {
+		return newACMEError(client.directoryURL, 0, "Directory does not contain the endpoints to create accounts and orders")
+	}
+
+	client.directory = directory
+	return nil
+}
+
+// Registers the account with the ACME server, agreeing to its terms of service, and stores the URL of the account. The URL of the existing account is returned by the server if the key has already been registered.
+func (client *acmeClient) register(ctx context.Context, Email string) error {
+	if client.accountURL != "" {
+		return nil
+	}
+
+	payload := map[string]any{ "termsOfServiceAgreed": true }
+	if Email != "" {
+		payload["contact"] = []string{ "mailto:" + Email }
+	}
+
+	header, _, err := client.post(ctx, client.directory.NewAccount, payload)
+	if err != nil {
+		return err
+	}
+
+	client.accountURL = header.Get("Location")
+	if client.accountURL == "" {
+		return newACMEError(client.directory.NewAccount, 0, "Response does not contain the URL of the account")
+	}
+
+	return nil
+}
+
+// Places an order for a certificate for the given domain and returns the order along with its URL.
+func (client *acmeClient) newOrder(ctx context.Context, Domain string) (*acmeOrder, string, error) {
+	payload := map[string]any{ "identifiers": []map[string]string{ { "type": "dns", "value": Domain } } }
+	header, body, err := client.post(ctx, client.directory.NewOrder, payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	order := new(acmeOrder)
+	if err := decodeACMEResponse(client.directory.NewOrder, header, body, order); err != nil {
+		return nil, "", err
+	} else if header.Get("Location") == "" {
+		return nil, "", newACMEError(client.directory.NewOrder, 0, "Response does not contain the URL of the order")
+	}
+
+	return order, header.Get("Location"), nil
+}
+
+// Fetches the resource at the given URL using a POST-as-GET request and decodes it into the given value.
+func (client *acmeClient) fetch(ctx context.Context, URL string, resource any) error {
+	return client.postAndDecode(ctx, URL, nil, resource)
+}
+
+// Posts the given payload to the given URL and decodes the resource returned in the response into the given value.
+func (client *acmeClient) postAndDecode(ctx context.Context, URL string, payload any, resource any) error {
+	header, body, err := client.post(ctx, URL, payload)
+	if err != nil {
+		return err
+	}
+
+	return decodeACMEResponse(URL, header, body, resource)
+}
+
+// Fetches the resource at the given URL, until the given status of the resource is neither "pending" nor "processing". An error is returned if the context is done before that.
+func (client *acmeClient) poll(ctx context.Context, URL string, resource any, status *string) error {
+	for {
+		if err := client.fetch(ctx, URL, resource); err != nil {
+			return err
+		} else if *status != "pending" && *status != "processing" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return newACMEError(URL, 0, fmt.Sprintf("Gave up waiting for the status [%s] to change :: %s", *status, ctx.Err().Error()))
+		case <-time.After(client.pollInterval):
+		}
+	}
+}
+
+// Returns the key authorization of the challenge with the given token, which is the token followed by the thumbprint of the account key (RFC 7638).
+func (client *acmeClient) keyAuthorization(Token string) string {
+	thumbprint := sha256.Sum256([]byte(client.jwk()))
+	return Token + "." + base64.RawURLEncoding.EncodeToString(thumbprint[:])
+}
+
+// Returns the public key of the account as a JSON web key, with its members in the lexicographic order required for computing its thumbprint.
+func (client *acmeClient) jwk() string {
+	publicKey, _ := client.accountKey.PublicKey.ECDH()
+	point := publicKey.Bytes()
+	return fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, base64.RawURLEncoding.EncodeToString(point[1:33]), base64.RawURLEncoding.EncodeToString(point[33:]))
+}
+
+// Sends the given payload to the given URL as a signed request and returns the headers and the body of the response. A nil payload sends a POST-as-GET request. The request is sent again
+// with a fresh nonce if the server rejects the nonce used.
+func (client *acmeClient) post(ctx context.Context, URL string, payload any) (nethttp.Header, []byte, error) {
+	var payloadBytes []byte
+	if payload != nil {
+		var err error
+		if payloadBytes, err = json.Marshal(payload); err != nil {
+			return nil, nil, newACMEError(URL, 0, fmt.Sprintf("Error while encoding the payload :: %s", err.Error()))
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		nonce, err := client.getNonce(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		header, body, err := client.send(ctx, "POST", URL, client.sign(URL, nonce, payloadBytes))
+		if acmeErr, ok := err.(*ACMEError); ok && acmeErr.Type == "urn:ietf:params:acme:error:badNonce" && attempt < 3 {
+			continue
+		}
+
+		return header, body, err
+	}
+}
+
+// Returns the nonce returned by the last response of the ACME server, or fetches a fresh nonce if the last nonce has already been used.
+func (client *acmeClient) getNonce(ctx context.Context) (string, error) {
+	if nonce := client.nonce; nonce != "" {
+		client.nonce = ""
+		return nonce, nil
+	}
+
+	header, _, err := client.send(ctx, "HEAD", client.directory.NewNonce, nil)
+	if err != nil {
+		return "", err
+	} else if header.Get("Replay-Nonce") == "" {
+		return "", newACMEError(client.directory.NewNonce, 0, "Response does not contain a nonce")
+	}
+
+	client.nonce = ""
+	return header.Get("Replay-Nonce"), nil
+}
+
+// Returns the given payload signed as a JSON web signature, in the flattened JSON serialization required by ACME. The account URL identifies the key once the account has been registered,
+// while the public key itself is embedded before that.
+func (client *acmeClient) sign(URL string, Nonce string, payload []byte) []byte {
+	protected := map[string]any{ "alg": "ES256", "nonce": Nonce, "url": URL }
+	if client.accountURL != "" {
+		protected["kid"] = client.accountURL
+	} else {
+		protected["jwk"] = json.RawMessage(client.jwk())
+	}
+
+	protectedBytes, _ := json.Marshal(protected)
+	encodedProtected := base64.RawURLEncoding.EncodeToString(protectedBytes)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(encodedProtected + "." + encodedPayload))
+	r, s, _ := ecdsa.Sign(rand.Reader, client.accountKey, digest[:])
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	signedRequest, _ := json.Marshal(map[string]string{ "protected": encodedProtected, "payload": encodedPayload, "signature": base64.RawURLEncoding.EncodeToString(signature) })
+	return signedRequest
+}
+
+// Sends a request with the given method and body to the given URL and returns the headers and the body of the response. The nonce returned in the response is stored for signing the next request.
+// An error is returned if the request fails or the server responds with an error status.
+func (client *acmeClient) send(ctx context.Context, Method string, URL string, body []byte) (nethttp.Header, []byte, error) {
+	request, err := nethttp.NewRequestWithContext(ctx, Method, URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, newACMEError(URL, 0, fmt.Sprintf("Error while creating the request :: %s", err.Error()))
+	} else if body != nil {
+		request.Header.Set("Content-Type", "application/jose+json")
+	}
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, nil, newACMEError(URL, 0, fmt.Sprintf("Error while sending the request :: %s", err.Error()))
+	}
+
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, ACME_MAX_RESPONSE_SIZE))
+	if err != nil {
+		return nil, nil, newACMEError(URL, response.StatusCode, fmt.Sprintf("Error while reading the response :: %s", err.Error()))
+	}
+
+	if nonce := response.Header.Get("Replay-Nonce"); nonce != "" {
+		client.nonce = nonce
+	}
+
+	if response.StatusCode >= 400 {
+		acmeErr := newACMEError(URL, response.StatusCode, "Request has been rejected by the ACME server")
+		problem := new(acmeProblem)
+		if json.Unmarshal(responseBody, problem) == nil && problem.Type != "" {
+			acmeErr.Type = problem.Type
+			acmeErr.Message = problem.Detail
+		}
+
+		return nil, nil, acmeErr
+	}
+
+	return response.Header, responseBody, nil
+}
+
+// Decodes the JSON body of a response from the ACME server into the given value. An error is returned if the response does not contain a JSON body.
+func decodeACMEResponse(URL string, header nethttp.Header, body []byte, resource any) error {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return newACMEError(URL, 0, fmt.Sprintf("Response has the content type [%s] instead of a JSON body", mediaType))
+	}
+
+	if err := json.Unmarshal(body, resource); err != nil {
+		return newACMEError(URL, 0, fmt.Sprintf("Error while decoding the response :: %s", err.Error()))
+	}
+
+	return nil
+}
+
+// Creates the error returned when a request to the given URL of the ACME server fails.
+func newACMEError(URL string, Status int, Message string) *ACMEError {
+	acmeErr := new(ACMEError)
+	acmeErr.URL = URL
+	acmeErr.Status = Status
+	acmeErr.Message = Message
+	return acmeErr
+}
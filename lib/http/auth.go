@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Structure to represent the identity of the client authenticated by an authentication middleware.
+type Principal struct {
+	// Unique name of the authenticated identity, like the user name or the subject of a token.
+	Name string
+	// Additional information about the authenticated identity, like roles or token claims.
+	Attributes map[string]any
+}
+
+// Type of the key against which the authenticated principal is stored in the request context.
+type principalContextKey struct{}
+
+// Returns the principal authenticated for the request by the BasicAuth() or the BearerAuth() middleware. The function also returns a boolean value to indicate if the request has been authenticated.
+func (req *HttpRequest) Principal() (Principal, bool) {
+	principal, ok := req.GetValue(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+// Returns a middleware which authenticates the requests using the HTTP Basic authentication scheme, with the user name and the password validated by the given function.
+// Requests which are not authenticated are rejected with a 401 (Unauthorized) response containing the Basic challenge for the realm set in the "auth_realm" server default.
+func BasicAuth(validator func(user string, password string) bool) Middleware {
+	challenge := fmt.Sprintf(`Basic realm="%s", charset="UTF-8"`, getServerDefaults("auth_realm"))
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			user, password, ok := request.basicCredentials()
+			if !ok || !validator(user, password) {
+				response.Headers["Www-Authenticate"] = []string{ challenge }
+				response.Status(StatusUnauthorized)
+				return handleError(request, response)
+			}
+
+			request.SetValue(principalContextKey{}, Principal{ Name: user })
+			return next(request, response)
+		}
+	}
+}
+
+// Returns a middleware which authenticates the requests using bearer tokens (RFC 6750), with the token validated by the given function, which returns the principal identified by the token.
+// Requests without a token or with a token rejected by the function get a 401 (Unauthorized) response, while requests with a malformed Authorization header get a 400 (Bad Request) response.
+// Both contain the Bearer challenge for the realm set in the "auth_realm" server default.
+func BearerAuth(validator func(token string) (Principal, error)) Middleware {
+	realm := getServerDefaults("auth_realm")
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			authorization, found := request.Headers.Get("Authorization")
+			if !found {
+				return rejectBearerRequest(request, response, fmt.Sprintf(`Bearer realm="%s"`, realm), StatusUnauthorized)
+			}
+
+			token, ok := getAuthorizationCredentials(authorization, "Bearer")
+			if !ok || token == "" {
+				return rejectBearerRequest(request, response, fmt.Sprintf(`Bearer realm="%s", error="invalid_request", error_description="The Authorization header is malformed"`, realm), StatusBadRequest)
+			}
+
+			principal, err := validator(token)
+			if err != nil {
+				return rejectBearerRequest(request, response, fmt.Sprintf(`Bearer realm="%s", error="invalid_token", error_description=%s`, realm, quoteHeaderString(err.Error())), StatusUnauthorized)
+			}
+
+			request.SetValue(principalContextKey{}, principal)
+			return next(request, response)
+		}
+	}
+}
+
+// Sends the error response with the given status and the given Bearer challenge for a request which could not be authenticated.
+func rejectBearerRequest(request *HttpRequest, response *HttpResponse, challenge string, status StatusCode) error {
+	response.Headers["Www-Authenticate"] = []string{ challenge }
+	response.Status(status)
+	return handleError(request, response)
+}
+
+// Returns the given text as a quoted string (RFC 9110) to be sent as a header parameter value. The double quotes and the backslashes are escaped, while the control characters other than
+// the horizontal tab are dropped, so that the text cannot end the parameter or the header early.
+func quoteHeaderString(Text string) string {
+	var quotedText strings.Builder
+	quotedText.WriteByte('"')
+	for _, char := range Text {
+		if char == '"' || char == '\\' {
+			quotedText.WriteByte('\\')
+		} else if char != '\t' && isControlChar(char) {
+			continue
+		}
+		quotedText.WriteRune(char)
+	}
+
+	quotedText.WriteByte('"')
+	return quotedText.String()
+}
+
+// Returns the user name and the password sent in the Authorization header of the request using the Basic authentication scheme. The boolean value returned is false if the header is missing or malformed.
+func (req *HttpRequest) basicCredentials() (string, string, bool) {
+	authorization, found := req.Headers.Get("Authorization")
+	if !found {
+		return "", "", false
+	}
+
+	encodedCredentials, ok := getAuthorizationCredentials(authorization, "Basic")
+	if !ok {
+		return "", "", false
+	}
+
+	credentials, err := base64.StdEncoding.DecodeString(encodedCredentials)
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(credentials), ":")
+}
+
+// Returns the credentials present in the given Authorization header value if it uses the given authentication scheme, which is matched ignoring case.
+func getAuthorizationCredentials(authorization string, scheme string) (string, bool) {
+	authScheme, credentials, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(authScheme, scheme) {
+		return "", false
+	}
+
+	return strings.TrimSpace(credentials), true
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// Test case to validate the authentication of requests using the Basic and the Bearer authentication middlewares.
+func Test_Server_Authentication(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	whoAmI := func(req *HttpRequest, res *HttpResponse) error {
+		principal, _ := req.Principal()
+		res.Status(StatusOK)
+		res.Body = []byte(principal.Name)
+		return nil
+	}
+
+	testServer.Get("/basic", whoAmI, BasicAuth(func(user string, password string) bool {
+		return user == "admin" && password == "s3cr3t:pass"
+	}))
+	testServer.Get("/bearer", whoAmI, BearerAuth(func(token string) (Principal, error) {
+		if token == "forged-token" {
+			return Principal{}, errors.New("Token \"forged\" was signed with C:\\keys\r\nX-Injected: true")
+		} else if token != "valid-token" {
+			return Principal{}, errors.New("The access token has expired")
+		}
+		return Principal{ Name: "service-account" }, nil
+	}))
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Authorization string
+		ExpStatus int
+		ExpBody string
+		ExpChallenge string
+	} {
+		{ "Valid basic credentials", "/basic", "Basic YWRtaW46czNjcjN0OnBhc3M=", int(StatusOK), "admin", "" },
+		{ "Invalid basic credentials", "/basic", "Basic YWRtaW46d3Jvbmc=", int(StatusUnauthorized), "", `Basic realm="proteus", charset="UTF-8"` },
+		{ "Basic credentials missing", "/basic", "", int(StatusUnauthorized), "", `Basic realm="proteus", charset="UTF-8"` },
+		{ "Valid bearer token", "/bearer", "bearer valid-token", int(StatusOK), "service-account", "" },
+		{ "Bearer token missing", "/bearer", "", int(StatusUnauthorized), "", `Bearer realm="proteus"` },
+		{ "Bearer token rejected", "/bearer", "Bearer old-token", int(StatusUnauthorized), "", `Bearer realm="proteus", error="invalid_token", error_description="The access token has expired"` },
+		{ "Bearer token rejected with special characters in the error", "/bearer", "Bearer forged-token", int(StatusUnauthorized), "", `Bearer realm="proteus", error="invalid_token", error_description="Token \"forged\" was signed with C:\\keysX-Injected: true"` },
+		{ "Different authentication scheme", "/bearer", "Basic YWRtaW46d3Jvbmc=", int(StatusBadRequest), "", `Bearer realm="proteus", error="invalid_request", error_description="The Authorization header is malformed"` },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			if testCase.Authorization != "" {
+				testRequest.Headers.Add("Authorization", testCase.Authorization)
+			}
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			challenge, _ := testResponse.Headers.Get("WWW-Authenticate")
+			if testResponse.StatusCode != testCase.ExpStatus || challenge != testCase.ExpChallenge {
+				tt.Errorf("Received status %d with challenge [%s], but expected status %d with challenge [%s]", testResponse.StatusCode, challenge, testCase.ExpStatus, testCase.ExpChallenge)
+			} else if testCase.ExpBody != "" && string(testResponse.Body) != testCase.ExpBody {
+				tt.Errorf("Expected the authenticated principal to be [%s], but got [%s]", testCase.ExpBody, string(testResponse.Body))
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
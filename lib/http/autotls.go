@@ -0,0 +1,462 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Port number at which ListenAutoTLS() listens for HTTPS requests.
+const ACME_HTTPS_PORT = 443
+
+// Port number at which ListenAutoTLS() listens for the HTTP-01 challenges of the ACME server.
+const ACME_HTTP_PORT = 80
+
+// ALPN protocol negotiated by ACME servers to validate a TLS-ALPN-01 challenge, as defined in RFC 8737.
+const ACME_TLS_ALPN_PROTOCOL = "acme-tls/1"
+
+// Path prefix of the requests sent by ACME servers to validate a HTTP-01 challenge, followed by the token of the challenge.
+const ACME_CHALLENGE_PATH = "/.well-known/acme-challenge/"
+
+// Object identifier of the certificate extension holding the digest of the key authorization of a TLS-ALPN-01 challenge.
+var acmeIdentifierExtension = asn1.ObjectIdentifier{ 1, 3, 6, 1, 5, 5, 7, 1, 31 }
+
+// Manages the certificates of the domains served by ListenAutoTLS(), which are obtained from an ACME server and renewed before they expire. The certificates and the key of the ACME account
+// are cached in a directory, so that they are reused when the server restarts.
+type autoTLSManager struct {
+	// Lower case names of the domains for which certificates are obtained.
+	domains []string
+	// Directory in which the certificates and the key of the ACME account are cached.
+	cacheDir string
+	// Email address registered as the contact of the ACME account. No contact is registered if it is empty.
+	email string
+	// Duration before the expiry of a certificate at which it is renewed.
+	renewBefore time.Duration
+	// Client used to obtain the certificates from the ACME server.
+	client *acmeClient
+	// Logger to which the certificates obtained and the errors raised are logged.
+	logger Logger
+	// Boolean value to indicate if HTTP-01 challenges can be answered, which requires the requests at port 80 to be received by the server.
+	httpChallenge atomic.Bool
+	// Collection of the certificates obtained, with the domain as key.
+	certificates map[string]*tls.Certificate
+	// Collection of the key authorizations of the pending HTTP-01 challenges, with the token as key.
+	httpTokens map[string]string
+	// Collection of the certificates answering the pending TLS-ALPN-01 challenges, with the domain as key.
+	alpnCertificates map[string]*tls.Certificate
+	// Mutex to synchronize access to the certificates and the pending challenges.
+	mutex sync.Mutex
+	// Mutex to ensure that one certificate is obtained at a time, since the ACME client holds the nonce of its last response.
+	obtainMutex sync.Mutex
+}
+
+// Setup the web server instance to listen for incoming HTTPS requests at port 443, using certificates obtained automatically from an ACME server (Let's Encrypt by default) for the given domains.
+// The method blocks until the server is shut down, in which case it returns nil. The certificates are obtained once the server starts listening and are renewed in the background before they expire.
+// The challenges of the ACME server are answered internally: TLS-ALPN-01 challenges on the TLS listener, and HTTP-01 challenges on a listener at port 80, which redirects all the other requests to HTTPS.
+// The certificates and the key of the ACME account are cached in the given directory, which must be kept across restarts to avoid the rate limits of the ACME server.
+// An error is returned if no domain is given, the cache directory could not be created or the server socket could not be created.
+func (srv *HttpServer) ListenAutoTLS(domains []string, cacheDir string) error {
+	manager, err := newAutoTLSManager(domains, cacheDir, srv.Config, srv.eventLogger)
+	if err != nil {
+		return err
+	}
+
+	var tlsConfig *tls.Config
+	if srv.TLSConfig != nil {
+		tlsConfig = srv.TLSConfig.Clone()
+	} else {
+		tlsConfig = new(tls.Config)
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	tlsConfig.GetCertificate = manager.getCertificate
+	srv.configureHTTP2(tlsConfig)
+	// HTTP/1.1 must be listed, since clients offering only protocols missing from the list are rejected once the ACME protocol is listed.
+	if !slices.Contains(tlsConfig.NextProtos, "http/1.1") {
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, "http/1.1")
+	}
+
+	tlsConfig.NextProtos = append(tlsConfig.NextProtos, ACME_TLS_ALPN_PROTOCOL)
+	srv.autoTLS = manager
+	err = srv.listen(ACME_HTTPS_PORT, "", tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	srv.startChallengeListener(manager)
+	go manager.run(srv.baseContext)
+	srv.serve(srv.Socket)
+	return nil
+}
+
+// Starts a web server instance at port 80 to answer the HTTP-01 challenges of the ACME server, which redirects all the other requests for the managed domains to HTTPS. It is shut down along with the web server instance.
+// Only TLS-ALPN-01 challenges are answered if the port could not be bound, like when the process is not allowed to bind privileged ports.
+func (srv *HttpServer) startChallengeListener(manager *autoTLSManager) {
+	challengeServer := NewServer()
+	challengeServer.SetLogger(srv.eventLogger)
+	challengeServer.autoTLS = manager
+	challengeServer.Get("/*path", func(req *HttpRequest, res *HttpResponse) error {
+		host := getHostname(req.Header("Host"))
+		if !slices.Contains(manager.domains, host) {
+			res.Status(StatusNotFound)
+			return handleError(req, res)
+		}
+
+		location := "https://" + host + req.ResourcePath
+		if req.RawQuery != "" {
+			location += "?" + req.RawQuery
+		}
+
+		return res.Redirect(StatusMovedPermanently, location)
+	})
+
+	err := challengeServer.ListenAndServeAsync(ACME_HTTP_PORT, "")
+	if err != nil {
+		srv.LogWarn("HTTP-01 challenges cannot be answered as the challenge listener could not be created", "error", err.Error())
+		return
+	}
+
+	manager.httpChallenge.Store(true)
+	go func() {
+		<-srv.baseContext.Done()
+		challengeServer.Shutdown()
+	}()
+}
+
+// Obtains the certificates missing for the managed domains, and then checks the certificates twice a day to renew those about to expire, until the given context is done.
+func (manager *autoTLSManager) run(ctx context.Context) {
+	ticker := time.NewTicker(12 * time.Hour)
+	defer ticker.Stop()
+	for {
+		for _, domain := range manager.domains {
+			if certificate := manager.getCachedCertificate(domain); certificate == nil || time.Until(certificate.Leaf.NotAfter) < manager.renewBefore {
+				if _, err := manager.obtainCertificate(ctx, domain); err != nil {
+					manager.logger.Error("Error occurred while obtaining the certificate", "domain", domain, "error", err.Error())
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the certificate for the server name sent by the client, which is used as the GetCertificate function of the TLS configuration. The certificate answering the pending TLS-ALPN-01 challenge
+// is returned for the validation requests of the ACME server. A certificate is obtained during the handshake if there is no valid certificate for the domain yet.
+func (manager *autoTLSManager) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	domain := strings.TrimSuffix(strings.ToLower(hello.ServerName), ".")
+	if domain == "" {
+		domain = manager.domains[0]
+	}
+
+	if slices.Contains(hello.SupportedProtos, ACME_TLS_ALPN_PROTOCOL) {
+		manager.mutex.Lock()
+		defer manager.mutex.Unlock()
+		if certificate, ok := manager.alpnCertificates[domain]; ok {
+			return certificate, nil
+		}
+
+		return nil, fmt.Errorf("no TLS-ALPN-01 challenge is pending for the domain [%s]", domain)
+	}
+
+	if !slices.Contains(manager.domains, domain) {
+		return nil, fmt.Errorf("certificates are not managed for the domain [%s]", domain)
+	}
+
+	if certificate := manager.getCachedCertificate(domain); certificate != nil && time.Now().Before(certificate.Leaf.NotAfter) {
+		return certificate, nil
+	}
+
+	ctx := hello.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return manager.obtainCertificate(ctx, domain)
+}
+
+// Returns the certificate obtained for the given domain, which is loaded from the cache directory if it has not been loaded yet. It returns nil if no certificate has been obtained for the domain.
+func (manager *autoTLSManager) getCachedCertificate(Domain string) *tls.Certificate {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if certificate, ok := manager.certificates[Domain]; ok {
+		return certificate
+	}
+
+	certificateBytes, err := os.ReadFile(filepath.Join(manager.cacheDir, Domain + ".pem"))
+	if err != nil {
+		return nil
+	}
+
+	certificate, err := parseACMECertificate(certificateBytes)
+	if err != nil {
+		manager.logger.Warn("Certificate in the cache directory is ignored as it could not be parsed", "domain", Domain, "error", err.Error())
+		return nil
+	}
+
+	manager.certificates[Domain] = certificate
+	return certificate
+}
+
+// Obtains a new certificate for the given domain from the ACME server, and stores it along with its private key in the cache directory. The certificate is not obtained again if another goroutine
+// has obtained a valid certificate for the domain in the meantime.
+func (manager *autoTLSManager) obtainCertificate(ctx context.Context, Domain string) (*tls.Certificate, error) {
+	manager.obtainMutex.Lock()
+	defer manager.obtainMutex.Unlock()
+	if certificate := manager.getCachedCertificate(Domain); certificate != nil && time.Until(certificate.Leaf.NotAfter) >= manager.renewBefore {
+		return certificate, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5 * time.Minute)
+	defer cancel()
+	if err := manager.setupAccount(ctx); err != nil {
+		return nil, err
+	}
+
+	order, orderURL, err := manager.client.newOrder(ctx, Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, authorizationURL := range order.Authorizations {
+		if err := manager.authorize(ctx, Domain, authorizationURL); err != nil {
+			return nil, err
+		}
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, newACMEError(order.Finalize, 0, fmt.Sprintf("Error while generating the private key of the certificate :: %s", err.Error()))
+	}
+
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{ DNSNames: []string{ Domain } }, privateKey)
+	if err != nil {
+		return nil, newACMEError(order.Finalize, 0, fmt.Sprintf("Error while creating the certificate signing request :: %s", err.Error()))
+	}
+
+	if err := manager.client.postAndDecode(ctx, order.Finalize, map[string]string{ "csr": base64.RawURLEncoding.EncodeToString(request) }, order); err != nil {
+		return nil, err
+	} else if err := manager.client.poll(ctx, orderURL, order, &order.Status); err != nil {
+		return nil, err
+	} else if order.Status != "valid" || order.Certificate == "" {
+		return nil, newACMEError(orderURL, 0, fmt.Sprintf("Order has the status [%s] instead of being valid :: %s", order.Status, getProblemDetail(order.Error)))
+	}
+
+	_, chain, err := manager.client.post(ctx, order.Certificate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, _ := x509.MarshalECPrivateKey(privateKey)
+	certificateBytes := append(pem.EncodeToMemory(&pem.Block{ Type: "EC PRIVATE KEY", Bytes: keyBytes }), chain...)
+	certificate, err := parseACMECertificate(certificateBytes)
+	if err != nil {
+		return nil, newACMEError(order.Certificate, 0, fmt.Sprintf("Error while parsing the certificate chain :: %s", err.Error()))
+	}
+
+	if err := os.WriteFile(filepath.Join(manager.cacheDir, Domain + ".pem"), certificateBytes, 0600); err != nil {
+		manager.logger.Warn("Certificate could not be stored in the cache directory", "domain", Domain, "error", err.Error())
+	}
+
+	manager.mutex.Lock()
+	manager.certificates[Domain] = certificate
+	manager.mutex.Unlock()
+	manager.logger.Info("Certificate obtained from the ACME server", "domain", Domain, "expires", certificate.Leaf.NotAfter.Format(time.RFC3339))
+	return certificate, nil
+}
+
+// Loads the key of the ACME account from the cache directory (or creates and stores a new key) and registers the account with the ACME server, if it has not been registered yet.
+func (manager *autoTLSManager) setupAccount(ctx context.Context) error {
+	if manager.client.accountKey == nil {
+		keyPath := filepath.Join(manager.cacheDir, "acme_account.key")
+		if keyBytes, err := os.ReadFile(keyPath); err == nil {
+			block, _ := pem.Decode(keyBytes)
+			if block == nil {
+				return newACMEError(manager.client.directoryURL, 0, "Account key in the cache directory is not PEM encoded")
+			} else if manager.client.accountKey, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+				return newACMEError(manager.client.directoryURL, 0, fmt.Sprintf("Error while parsing the account key in the cache directory :: %s", err.Error()))
+			}
+		} else {
+			accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				return newACMEError(manager.client.directoryURL, 0, fmt.Sprintf("Error while generating the account key :: %s", err.Error()))
+			}
+
+			keyBytes, _ := x509.MarshalECPrivateKey(accountKey)
+			if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{ Type: "EC PRIVATE KEY", Bytes: keyBytes }), 0600); err != nil {
+				return newACMEError(manager.client.directoryURL, 0, fmt.Sprintf("Error while storing the account key in the cache directory :: %s", err.Error()))
+			}
+
+			manager.client.accountKey = accountKey
+		}
+	}
+
+	if err := manager.client.discover(ctx); err != nil {
+		return err
+	}
+
+	return manager.client.register(ctx, manager.email)
+}
+
+// Completes the authorization at the given URL for the given domain, by answering one of the challenges offered by the ACME server. TLS-ALPN-01 challenges are preferred, since they are answered
+// on the TLS listener itself, while HTTP-01 challenges are answered only if the requests at port 80 are received by the server.
+func (manager *autoTLSManager) authorize(ctx context.Context, Domain string, URL string) error {
+	authorization := new(acmeAuthorization)
+	if err := manager.client.fetch(ctx, URL, authorization); err != nil {
+		return err
+	} else if authorization.Status == "valid" {
+		return nil
+	}
+
+	var challenge *acmeChallenge
+	for _, challengeType := range []string{ "tls-alpn-01", "http-01" } {
+		for index := range authorization.Challenges {
+			if authorization.Challenges[index].Type == challengeType && (challengeType != "http-01" || manager.httpChallenge.Load()) {
+				challenge = &authorization.Challenges[index]
+				break
+			}
+		}
+
+		if challenge != nil {
+			break
+		}
+	}
+
+	if challenge == nil {
+		return newACMEError(URL, 0, "None of the challenges offered by the ACME server can be answered")
+	}
+
+	keyAuthorization := manager.client.keyAuthorization(challenge.Token)
+	if err := manager.addChallenge(Domain, challenge, keyAuthorization); err != nil {
+		return newACMEError(challenge.URL, 0, fmt.Sprintf("Error while creating the response to the challenge :: %s", err.Error()))
+	}
+
+	defer manager.removeChallenge(Domain, challenge)
+	if _, _, err := manager.client.post(ctx, challenge.URL, struct{}{}); err != nil {
+		return err
+	} else if err := manager.client.poll(ctx, URL, authorization, &authorization.Status); err != nil {
+		return err
+	} else if authorization.Status != "valid" {
+		var problem *acmeProblem
+		for _, offered := range authorization.Challenges {
+			if offered.Error != nil {
+				problem = offered.Error
+			}
+		}
+
+		return newACMEError(URL, 0, fmt.Sprintf("Authorization has the status [%s] instead of being valid :: %s", authorization.Status, getProblemDetail(problem)))
+	}
+
+	return nil
+}
+
+// Sets up the response to the given challenge, using the given key authorization. A self-signed certificate holding the digest of the key authorization is created for a TLS-ALPN-01 challenge.
+func (manager *autoTLSManager) addChallenge(Domain string, challenge *acmeChallenge, KeyAuthorization string) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if challenge.Type == "http-01" {
+		manager.httpTokens[challenge.Token] = KeyAuthorization
+		return nil
+	}
+
+	digest := sha256.Sum256([]byte(KeyAuthorization))
+	extensionValue, _ := asn1.Marshal(digest[:])
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{ CommonName: Domain },
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter: time.Now().Add(24 * time.Hour),
+		DNSNames: []string{ Domain },
+		ExtraExtensions: []pkix.Extension{ { Id: acmeIdentifierExtension, Critical: true, Value: extensionValue } },
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return err
+	}
+
+	manager.alpnCertificates[Domain] = &tls.Certificate{ Certificate: [][]byte{ certificateBytes }, PrivateKey: privateKey }
+	return nil
+}
+
+// Removes the response to the given challenge, once the authorization has been completed.
+func (manager *autoTLSManager) removeChallenge(Domain string, challenge *acmeChallenge) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	delete(manager.httpTokens, challenge.Token)
+	if challenge.Type == "tls-alpn-01" {
+		delete(manager.alpnCertificates, Domain)
+	}
+}
+
+// Returns the key authorization of the pending HTTP-01 challenge with the given token. A boolean value of false is returned if no such challenge is pending.
+func (manager *autoTLSManager) getKeyAuthorization(Token string) (string, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	keyAuthorization, ok := manager.httpTokens[Token]
+	return keyAuthorization, ok
+}
+
+// Answers the validation request of the ACME server for a HTTP-01 challenge, by responding with the key authorization of the pending challenge whose token is given in the request path.
+// A 404 (Not Found) response is sent if no challenge is pending for the token.
+func (manager *autoTLSManager) handleChallenge(req *HttpRequest, res *HttpResponse) error {
+	keyAuthorization, ok := manager.getKeyAuthorization(strings.TrimPrefix(req.ResourcePath, ACME_CHALLENGE_PATH))
+	if !ok || (req.Method != "GET" && req.Method != "HEAD") {
+		res.Status(StatusNotFound)
+		return handleError(req, res)
+	}
+
+	res.Status(StatusOK)
+	res.Headers.Add("Content-Type", "text/plain")
+	res.Body = []byte(keyAuthorization)
+	return nil
+}
+
+// Parses the given PEM encoded private key and certificate chain into a certificate, whose leaf certificate is parsed as well.
+func parseACMECertificate(certificateBytes []byte) (*tls.Certificate, error) {
+	certificate, err := tls.X509KeyPair(certificateBytes, certificateBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// Returns the detail of the given problem returned by the ACME server, or a placeholder if there is no problem.
+func getProblemDetail(problem *acmeProblem) string {
+	if problem == nil {
+		return "No problem has been reported by the ACME server"
+	}
+
+	return fmt.Sprintf("%s (%s)", problem.Detail, problem.Type)
+}
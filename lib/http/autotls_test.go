@@ -0,0 +1,376 @@
+package http
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Fake ACME server issuing certificates signed by a test certificate authority, once one of the challenges it offers has been validated using the given validation function.
+type testACMEServer struct {
+	*httptest.Server
+	// Certificate authority signing the certificates issued.
+	authority tls.Certificate
+	// Types of the challenges offered for every authorization.
+	challengeTypes []string
+	// Function invoked to validate a challenge, with the type, the token and the expected key authorization of the challenge.
+	validate func(ChallengeType string, Token string, KeyAuthorization string) bool
+	// Mutex to synchronize access to the state of the server.
+	mutex sync.Mutex
+	// Collection of the nonces issued and not used yet.
+	nonces map[string]bool
+	// JSON web key of the registered account.
+	accountJWK map[string]string
+	// Domain of the last order placed, and the status of its authorization and of the order.
+	domain, authorizationStatus, orderStatus string
+	// Number of orders placed.
+	orderCount int
+	// PEM encoded certificate chain issued for the last order.
+	certificateChain []byte
+}
+
+// Helper function to create and start a fake ACME server offering the given types of challenges.
+func newTestACMEServer(t *testing.T, challengeTypes []string) *testACMEServer {
+	acmeServer := &testACMEServer{ authority: newTestCertificate(t, "Test ACME CA", nil, true), challengeTypes: challengeTypes, nonces: make(map[string]bool) }
+	acmeServer.Server = httptest.NewServer(nethttp.HandlerFunc(acmeServer.handle))
+	t.Cleanup(acmeServer.Close)
+	return acmeServer
+}
+
+// Handles the requests sent to the fake ACME server. Every request other than fetching the directory and a nonce must be signed with a valid nonce.
+func (as *testACMEServer) handle(writer nethttp.ResponseWriter, request *nethttp.Request) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	nonce := base64.RawURLEncoding.EncodeToString(big.NewInt(time.Now().UnixNano()).Bytes())
+	as.nonces[nonce] = true
+	writer.Header().Set("Replay-Nonce", nonce)
+	if request.URL.Path == "/directory" {
+		as.writeJSON(writer, nethttp.StatusOK, map[string]string{ "newNonce": as.URL + "/nonce", "newAccount": as.URL + "/account", "newOrder": as.URL + "/new-order" })
+		return
+	} else if request.URL.Path == "/nonce" {
+		return
+	}
+
+	payload, problem := as.verify(request)
+	if problem != "" {
+		as.writeJSON(writer, nethttp.StatusBadRequest, map[string]string{ "type": "urn:ietf:params:acme:error:" + problem, "detail": "Request could not be verified" })
+		return
+	}
+
+	switch {
+	case request.URL.Path == "/account":
+		writer.Header().Set("Location", as.URL + "/account")
+		as.writeJSON(writer, nethttp.StatusCreated, map[string]string{ "status": "valid" })
+	case request.URL.Path == "/new-order":
+		var order struct{ Identifiers []map[string]string `json:"identifiers"` }
+		json.Unmarshal(payload, &order)
+		as.domain, as.authorizationStatus, as.orderStatus = order.Identifiers[0]["value"], "pending", "pending"
+		as.orderCount++
+		writer.Header().Set("Location", as.URL + "/order")
+		as.writeOrder(writer, nethttp.StatusCreated)
+	case request.URL.Path == "/order":
+		as.writeOrder(writer, nethttp.StatusOK)
+	case request.URL.Path == "/authorization":
+		challenges := make([]map[string]string, 0)
+		for _, challengeType := range as.challengeTypes {
+			challenges = append(challenges, map[string]string{ "type": challengeType, "url": as.URL + "/challenge/" + challengeType, "token": "token-" + challengeType })
+		}
+		as.writeJSON(writer, nethttp.StatusOK, map[string]any{ "status": as.authorizationStatus, "challenges": challenges })
+	case strings.HasPrefix(request.URL.Path, "/challenge/"):
+		challengeType := strings.TrimPrefix(request.URL.Path, "/challenge/")
+		thumbprint := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":"%s","kty":"%s","x":"%s","y":"%s"}`, as.accountJWK["crv"], as.accountJWK["kty"], as.accountJWK["x"], as.accountJWK["y"])))
+		as.mutex.Unlock()
+		isValid := as.validate(challengeType, "token-" + challengeType, "token-" + challengeType + "." + base64.RawURLEncoding.EncodeToString(thumbprint[:]))
+		as.mutex.Lock()
+		as.authorizationStatus = "invalid"
+		if isValid {
+			as.authorizationStatus, as.orderStatus = "valid", "ready"
+		}
+		as.writeJSON(writer, nethttp.StatusOK, map[string]string{ "type": challengeType, "status": "processing" })
+	case request.URL.Path == "/finalize":
+		var finalize struct{ CSR string `json:"csr"` }
+		json.Unmarshal(payload, &finalize)
+		csrBytes, _ := base64.RawURLEncoding.DecodeString(finalize.CSR)
+		csr, err := x509.ParseCertificateRequest(csrBytes)
+		if as.orderStatus != "ready" || err != nil || !slices.Equal(csr.DNSNames, []string{ as.domain }) {
+			as.writeJSON(writer, nethttp.StatusForbidden, map[string]string{ "type": "urn:ietf:params:acme:error:badCSR", "detail": "Order is not ready or the CSR is not valid" })
+			return
+		}
+		template := &x509.Certificate{ SerialNumber: big.NewInt(time.Now().UnixNano()), DNSNames: csr.DNSNames, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(90 * 24 * time.Hour) }
+		certificateBytes, _ := x509.CreateCertificate(rand.Reader, template, as.authority.Leaf, csr.PublicKey, as.authority.PrivateKey)
+		as.certificateChain = append(pem.EncodeToMemory(&pem.Block{ Type: "CERTIFICATE", Bytes: certificateBytes }), pem.EncodeToMemory(&pem.Block{ Type: "CERTIFICATE", Bytes: as.authority.Certificate[0] })...)
+		as.orderStatus = "processing"
+		as.writeOrder(writer, nethttp.StatusOK)
+		as.orderStatus = "valid"
+	case request.URL.Path == "/certificate":
+		writer.Header().Set("Content-Type", "application/pem-certificate-chain")
+		writer.Write(as.certificateChain)
+	default:
+		writer.WriteHeader(nethttp.StatusNotFound)
+	}
+}
+
+// Verifies the signature, the nonce and the URL of the given signed request, and returns its payload. The name of the problem is returned if the request could not be verified.
+func (as *testACMEServer) verify(request *nethttp.Request) ([]byte, string) {
+	var signedRequest map[string]string
+	body, _ := io.ReadAll(request.Body)
+	if json.Unmarshal(body, &signedRequest) != nil {
+		return nil, "malformed"
+	}
+
+	protectedBytes, _ := base64.RawURLEncoding.DecodeString(signedRequest["protected"])
+	var protected struct {
+		Alg string `json:"alg"`
+		Nonce string `json:"nonce"`
+		URL string `json:"url"`
+		KID string `json:"kid"`
+		JWK map[string]string `json:"jwk"`
+	}
+
+	json.Unmarshal(protectedBytes, &protected)
+	if !as.nonces[protected.Nonce] {
+		return nil, "badNonce"
+	} else if protected.Alg != "ES256" || protected.URL != as.URL + request.URL.Path {
+		return nil, "malformed"
+	}
+
+	delete(as.nonces, protected.Nonce)
+	jwk := protected.JWK
+	if request.URL.Path == "/account" && jwk != nil {
+		as.accountJWK = jwk
+	} else if protected.KID != as.URL + "/account" || jwk != nil {
+		return nil, "accountDoesNotExist"
+	}
+
+	x, _ := base64.RawURLEncoding.DecodeString(as.accountJWK["x"])
+	y, _ := base64.RawURLEncoding.DecodeString(as.accountJWK["y"])
+	if _, err := ecdh.P256().NewPublicKey(append(append([]byte{ 4 }, x...), y...)); err != nil {
+		return nil, "badPublicKey"
+	}
+
+	publicKey := &ecdsa.PublicKey{ Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y) }
+	signature, _ := base64.RawURLEncoding.DecodeString(signedRequest["signature"])
+	digest := sha256.Sum256([]byte(signedRequest["protected"] + "." + signedRequest["payload"]))
+	if len(signature) != 64 || !ecdsa.Verify(publicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		return nil, "badSignatureAlgorithm"
+	}
+
+	payload, _ := base64.RawURLEncoding.DecodeString(signedRequest["payload"])
+	return payload, ""
+}
+
+// Writes the given value as the JSON body of the response, with the given status code.
+func (as *testACMEServer) writeJSON(writer nethttp.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	if status >= 400 {
+		writer.Header().Set("Content-Type", "application/problem+json")
+	}
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(value)
+}
+
+// Writes the last order placed as the JSON body of the response, with the given status code.
+func (as *testACMEServer) writeOrder(writer nethttp.ResponseWriter, status int) {
+	order := map[string]any{ "status": as.orderStatus, "authorizations": []string{ as.URL + "/authorization" }, "finalize": as.URL + "/finalize" }
+	if as.orderStatus == "valid" {
+		order["certificate"] = as.URL + "/certificate"
+	}
+	as.writeJSON(writer, status, order)
+}
+
+// Test case to validate that certificates are obtained from the ACME server by answering the TLS-ALPN-01 or the HTTP-01 challenge offered, and that the certificates are cached for later use.
+func Test_AutoTLS_ObtainCertificate(t *testing.T) {
+	testCases := []struct {
+		Name string
+		ChallengeTypes []string
+		HTTPChallenge bool
+		ExpChallenge string
+	} {
+		{ "Certificate obtained using the TLS-ALPN-01 challenge", []string{ "http-01", "tls-alpn-01" }, true, "tls-alpn-01" },
+		{ "Certificate obtained using the HTTP-01 challenge", []string{ "http-01", "dns-01" }, true, "http-01" },
+		{ "HTTP-01 challenge offered when port 80 is not available", []string{ "http-01", "dns-01" }, false, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			acmeServer := newTestACMEServer(t, testCase.ChallengeTypes)
+			serverConfig := newServerConfig()
+			serverConfig.ACMEDirectoryURL = acmeServer.URL + "/directory"
+			cacheDir := filepath.Join(tt.TempDir(), "certificates")
+			manager, err := newAutoTLSManager([]string{ "Example.com" }, cacheDir, serverConfig, NewLogger(new(lockedBuffer), LevelDebug, TextLogFormat))
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while creating the manager, but got this instead - %v", err)
+			}
+			manager.client.pollInterval = 10 * time.Millisecond
+			manager.httpChallenge.Store(testCase.HTTPChallenge)
+
+			challengeServer := NewServer()
+			challengeServer.SetLogger(NewLogger(new(lockedBuffer), LevelDebug, TextLogFormat))
+			challengeServer.autoTLS = manager
+			if err := challengeServer.ListenAndServeAsync(0, "127.0.0.1"); err != nil {
+				tt.Fatalf("Was not expecting an error while starting the challenge server, but got this instead - %v", err)
+			}
+			defer challengeServer.Shutdown()
+
+			validatedChallenge := ""
+			acmeServer.validate = func(ChallengeType string, Token string, KeyAuthorization string) bool {
+				validatedChallenge = ChallengeType
+				if ChallengeType == "http-01" {
+					response, err := nethttp.Get("http://" + challengeServer.Addr().String() + ACME_CHALLENGE_PATH + Token)
+					if err != nil {
+						return false
+					}
+					defer response.Body.Close()
+					body, _ := io.ReadAll(response.Body)
+					return response.StatusCode == nethttp.StatusOK && string(body) == KeyAuthorization
+				}
+
+				certificate, err := manager.getCertificate(&tls.ClientHelloInfo{ ServerName: "example.com", SupportedProtos: []string{ ACME_TLS_ALPN_PROTOCOL } })
+				if err != nil {
+					return false
+				}
+				leaf, _ := x509.ParseCertificate(certificate.Certificate[0])
+				digest := sha256.Sum256([]byte(KeyAuthorization))
+				for _, extension := range leaf.Extensions {
+					var value []byte
+					if extension.Id.Equal(acmeIdentifierExtension) && extension.Critical {
+						asn1.Unmarshal(extension.Value, &value)
+						return string(value) == string(digest[:]) && slices.Equal(leaf.DNSNames, []string{ "example.com" })
+					}
+				}
+				return false
+			}
+
+			certificate, err := manager.getCertificate(&tls.ClientHelloInfo{ ServerName: "example.com" })
+			if testCase.ExpChallenge == "" {
+				if err == nil {
+					tt.Errorf("Was expecting an error as no challenge could be answered, but did not get one")
+				} else {
+					tt.Logf("Received the error as expected - %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while obtaining the certificate, but got this instead - %v", err)
+			} else if validatedChallenge != testCase.ExpChallenge {
+				tt.Errorf("Expected the challenge [%s] to be answered, but [%s] was answered instead", testCase.ExpChallenge, validatedChallenge)
+			} else if !slices.Equal(certificate.Leaf.DNSNames, []string{ "example.com" }) || len(certificate.Certificate) != 2 {
+				tt.Errorf("Expected a certificate chain for [example.com], but got a certificate for %v instead", certificate.Leaf.DNSNames)
+			} else {
+				tt.Logf("Received the certificate for %v using the challenge [%s] as expected", certificate.Leaf.DNSNames, validatedChallenge)
+			}
+
+			if len(manager.httpTokens) != 0 || len(manager.alpnCertificates) != 0 {
+				tt.Errorf("Expected the responses to the challenges to be removed once the certificate has been obtained")
+			}
+
+			if _, err := os.Stat(filepath.Join(cacheDir, "example.com.pem")); err != nil {
+				tt.Errorf("Expected the certificate to be stored in the cache directory, but got this instead - %v", err)
+			}
+
+			restartedManager, _ := newAutoTLSManager([]string{ "example.com" }, cacheDir, serverConfig, manager.logger)
+			cachedCertificate, err := restartedManager.getCertificate(&tls.ClientHelloInfo{ ServerName: "example.com" })
+			if err != nil || acmeServer.orderCount != 1 || !cachedCertificate.Leaf.Equal(certificate.Leaf) {
+				tt.Errorf("Expected the certificate to be loaded from the cache directory without placing a new order, but got %d orders and the error - %v", acmeServer.orderCount, err)
+			}
+
+			if _, err := manager.getCertificate(&tls.ClientHelloInfo{ ServerName: "other.example.com" }); err == nil {
+				tt.Errorf("Was expecting an error for a domain whose certificates are not managed, but did not get one")
+			}
+		})
+	}
+
+	if _, err := newAutoTLSManager([]string{ " " }, t.TempDir(), newServerConfig(), newLogger()); err == nil {
+		t.Errorf("Was expecting an error when no valid domain is given, but did not get one")
+	}
+}
+
+// Test case to validate that a TLS listener using the certificates of the manager negotiates the ACME protocol only for the validation requests of the ACME server.
+func Test_AutoTLS_ChallengeHandshake(t *testing.T) {
+	serverConfig := newServerConfig()
+	serverConfig.ACMEDirectoryURL = "http://127.0.0.1:0/directory"
+	manager, err := newAutoTLSManager([]string{ "example.com" }, t.TempDir(), serverConfig, NewLogger(new(lockedBuffer), LevelDebug, TextLogFormat))
+	if err != nil {
+		t.Fatalf("Was not expecting an error while creating the manager, but got this instead - %v", err)
+	}
+
+	manager.client.accountKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	challenge := &acmeChallenge{ Type: "tls-alpn-01", Token: "token" }
+	if err := manager.addChallenge("example.com", challenge, manager.client.keyAuthorization(challenge.Token)); err != nil {
+		t.Fatalf("Was not expecting an error while creating the response to the challenge, but got this instead - %v", err)
+	}
+
+	socket, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Was not expecting an error while creating the server socket, but got this instead - %v", err)
+	}
+	defer socket.Close()
+	go func() {
+		for {
+			connection, err := socket.Accept()
+			if err != nil {
+				return
+			}
+			serverConnection := tls.Server(connection, &tls.Config{ GetCertificate: manager.getCertificate, NextProtos: []string{ HTTP2_ALPN_PROTOCOL, "http/1.1", ACME_TLS_ALPN_PROTOCOL } })
+			serverConnection.Handshake()
+			serverConnection.Close()
+		}
+	}()
+
+	testCases := []struct {
+		Name string
+		NextProtos []string
+		ExpProtocol string
+	} {
+		{ "Validation request of the ACME server", []string{ ACME_TLS_ALPN_PROTOCOL }, ACME_TLS_ALPN_PROTOCOL },
+		{ "Client request when the certificate cannot be obtained", []string{ "http/1.1" }, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection, err := tls.Dial("tcp", socket.Addr().String(), &tls.Config{ ServerName: "example.com", NextProtos: testCase.NextProtos, InsecureSkipVerify: true })
+			if testCase.ExpProtocol == "" {
+				if err == nil {
+					connection.Close()
+					tt.Errorf("Was expecting the handshake to fail as no certificate has been obtained, but it succeeded")
+				} else {
+					tt.Logf("Handshake failed as expected - %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Fatalf("Was not expecting an error during the handshake, but got this instead - %v", err)
+			}
+			defer connection.Close()
+			state := connection.ConnectionState()
+			if state.NegotiatedProtocol != testCase.ExpProtocol || len(state.PeerCertificates) != 1 || state.PeerCertificates[0].DNSNames[0] != "example.com" {
+				tt.Errorf("Expected the protocol [%s] with the challenge certificate, but got the protocol [%s] instead", testCase.ExpProtocol, state.NegotiatedProtocol)
+			} else {
+				tt.Logf("Negotiated the protocol [%s] with the challenge certificate as expected", state.NegotiatedProtocol)
+			}
+		})
+	}
+}
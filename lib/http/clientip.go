@@ -0,0 +1,117 @@
+package http
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"github.com/mkbworks/proteus/lib/config"
+)
+
+// Sets the proxies (like load balancers) whose forwarding headers are trusted to contain the address of the client, given as IP addresses (like "10.0.0.1") or CIDR ranges (like "10.0.0.0/8").
+// The proxies replace the ones set earlier and they are used by RemoteIP() of the requests received by the web server instance. An error is returned if any of the given values is invalid, in which case the proxies are not changed.
+func (srv *HttpServer) TrustedProxies(proxies ...string) error {
+	trustedProxies, err := parseTrustedProxies(proxies)
+	if err != nil {
+		return err
+	}
+
+	srv.Config.TrustedProxies = trustedProxies
+	return nil
+}
+
+// Parses the given IP addresses and CIDR ranges into the collection of network prefixes of the trusted proxies. An IP address is parsed as a prefix containing only that address.
+func parseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	trustedProxies := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			address, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				ce := new(config.ConfigError)
+				ce.Message = fmt.Sprintf("TrustedProxies: %s is not a valid IP address or CIDR range", proxy)
+				return nil, ce
+			}
+
+			prefix = netip.PrefixFrom(address.Unmap(), address.Unmap().BitLen())
+		}
+
+		trustedProxies = append(trustedProxies, prefix.Masked())
+	}
+
+	return trustedProxies, nil
+}
+
+// Returns the IP address of the client who made the request. If the request has been received from a trusted proxy (as set using TrustedProxies()), the address is taken from the Forwarded header
+// or, if it is not present, from the X-Forwarded-For or the X-Real-IP header. The addresses in these headers are read from the closest hop to the farthest one, and the first address that is not a trusted proxy
+// is returned. The address of the connection itself is returned if the request has not been received from a trusted proxy, so that clients cannot spoof their address by sending the headers.
+func (req *HttpRequest) RemoteIP() string {
+	peerAddress := getHostname(req.ClientAddress)
+	trustedProxies := req.getConfig().TrustedProxies
+	if len(trustedProxies) == 0 || !isTrustedProxy(peerAddress, trustedProxies) {
+		return peerAddress
+	}
+
+	var forwardedAddresses []string
+	if forwarded := req.HeaderValues("Forwarded"); len(forwarded) > 0 {
+		forwardedAddresses = getForwardedFor(forwarded)
+	} else if forwardedFor := req.HeaderValues("X-Forwarded-For"); len(forwardedFor) > 0 {
+		forwardedAddresses = forwardedFor
+	} else if realIP := req.Header("X-Real-Ip"); realIP != "" {
+		forwardedAddresses = []string{ realIP }
+	}
+
+	clientAddress := peerAddress
+	for index := len(forwardedAddresses) - 1; index >= 0; index-- {
+		address, err := netip.ParseAddr(getHostname(forwardedAddresses[index]))
+		if err != nil {
+			// An address which cannot be parsed (like "unknown" or an obfuscated identifier) ends the chain of hops that can be relied upon.
+			break
+		}
+
+		clientAddress = address.Unmap().String()
+		if !isTrustedProxy(clientAddress, trustedProxies) {
+			break
+		}
+	}
+
+	return clientAddress
+}
+
+// Returns the values of the "for" parameters in the given elements of the Forwarded header (as defined in RFC 7239), in the order in which they were sent.
+// An empty value is returned for an element without a "for" parameter, so that it is not skipped while reading the hops.
+func getForwardedFor(elements []string) []string {
+	forwardedFor := make([]string, 0, len(elements))
+	for _, element := range elements {
+		value := ""
+		for _, pair := range strings.Split(element, ";") {
+			name, pairValue, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if found && strings.EqualFold(strings.TrimSpace(name), "for") {
+				value = strings.Trim(strings.TrimSpace(pairValue), "\"")
+				break
+			}
+		}
+
+		forwardedFor = append(forwardedFor, value)
+	}
+
+	return forwardedFor
+}
+
+// Checks if the given IP address lies within any of the given network prefixes of the trusted proxies.
+func isTrustedProxy(Address string, trustedProxies []netip.Prefix) bool {
+	address, err := netip.ParseAddr(Address)
+	if err != nil {
+		return false
+	}
+
+	address = address.Unmap()
+	return slices.ContainsFunc(trustedProxies, func(prefix netip.Prefix) bool {
+		return prefix.Contains(address)
+	})
+}
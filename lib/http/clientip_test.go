@@ -0,0 +1,52 @@
+package http
+
+import (
+	"testing"
+)
+
+// Test case to validate the client IP address resolved from the forwarding headers, which must be honored only for the requests received from a trusted proxy.
+func Test_Request_RemoteIP(t *testing.T) {
+	testServer := NewServer()
+	if err := testServer.TrustedProxies("10.0.0.0/8", "2001:db8::1"); err != nil {
+		t.Fatalf("Was not expecting an error while setting the trusted proxies, but got this instead - %v", err)
+	}
+
+	testCases := []struct {
+		Name string
+		ClientAddress string
+		Headers map[string]string
+		ExpRemoteIP string
+	} {
+		{ "Request without forwarding headers", "203.0.113.7:51234", map[string]string{}, "203.0.113.7" },
+		{ "Forwarding header sent by a client that is not trusted", "203.0.113.7:51234", map[string]string{ "X-Forwarded-For": "198.51.100.1" }, "203.0.113.7" },
+		{ "X-Forwarded-For header sent by a trusted proxy", "10.1.2.3:443", map[string]string{ "X-Forwarded-For": "198.51.100.1" }, "198.51.100.1" },
+		{ "Spoofed address before the address added by the proxy", "10.1.2.3:443", map[string]string{ "X-Forwarded-For": "192.0.2.99, 198.51.100.1, 10.9.9.9" }, "198.51.100.1" },
+		{ "X-Real-IP header sent by a trusted proxy", "10.1.2.3:443", map[string]string{ "X-Real-IP": "198.51.100.2" }, "198.51.100.2" },
+		{ "Forwarded header preferred over the other headers", "[2001:db8::1]:443", map[string]string{ "Forwarded": `for=192.0.2.60;proto=https, for="[2001:db8:cafe::17]:4711"`, "X-Forwarded-For": "198.51.100.1" }, "2001:db8:cafe::17" },
+		{ "Address that cannot be parsed in the chain", "10.1.2.3:443", map[string]string{ "Forwarded": "for=198.51.100.3, for=unknown" }, "10.1.2.3" },
+		{ "Only trusted proxies in the chain", "10.1.2.3:443", map[string]string{ "X-Forwarded-For": "10.4.4.4, 10.5.5.5" }, "10.4.4.4" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.config = testServer.Config
+			testRequest.ClientAddress = testCase.ClientAddress
+			for name, value := range testCase.Headers {
+				testRequest.Headers.Add(name, value)
+			}
+
+			if remoteIP := testRequest.RemoteIP(); remoteIP != testCase.ExpRemoteIP {
+				tt.Errorf("The client IP address [%s] does not match the expected address [%s]", remoteIP, testCase.ExpRemoteIP)
+			} else {
+				tt.Logf("The client IP address [%s] matches the expected address", remoteIP)
+			}
+		})
+	}
+
+	if err := testServer.TrustedProxies("10.0.0.0/33"); err == nil {
+		t.Errorf("Was expecting an error for an invalid CIDR range, but did not get one")
+	} else if len(testServer.Config.TrustedProxies) != 2 {
+		t.Errorf("Expected the trusted proxies to be left unchanged for an invalid CIDR range, but got %v", testServer.Config.TrustedProxies)
+	}
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	GZIP_CONTENT_ENCODING = "gzip"
+	DEFLATE_CONTENT_ENCODING = "deflate"
+	BROTLI_CONTENT_ENCODING = "br"
+)
+
+// Returns the content encoding (gzip or deflate) preferred by the client as per the given Accept-Encoding header value.
+// An empty string is returned if the client does not accept any of the content encodings supported by the server.
+func negotiateEncoding(AcceptEncoding string) string {
+	// gzip is preferred over deflate when both are acceptable with the same quality.
+	return selectEncoding(AcceptEncoding, []string{ GZIP_CONTENT_ENCODING, DEFLATE_CONTENT_ENCODING })
+}
+
+// Returns the content encoding preferred by the client as per the given Accept-Encoding header value, from the given content encodings listed in the order of preference of the server.
+// Encodings not listed in the header are accepted with the quality of the wildcard encoding (*), if present. An empty string is returned if the client does not accept any of the given content encodings.
+func selectEncoding(AcceptEncoding string, Encodings []string) string {
+	qualities := make(map[string]float64)
+	for _, offer := range strings.Split(AcceptEncoding, ",") {
+		encoding, params, _ := strings.Cut(offer, ";")
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding == "" {
+			continue
+		}
+
+		quality := 1.0
+		params = strings.TrimSpace(params)
+		if qValue, found := strings.CutPrefix(params, "q="); found {
+			parsedQuality, err := strconv.ParseFloat(strings.TrimSpace(qValue), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsedQuality
+		}
+
+		qualities[encoding] = quality
+	}
+
+	selectedEncoding := ""
+	selectedQuality := 0.0
+	for _, encoding := range Encodings {
+		quality, found := qualities[encoding]
+		if !found {
+			quality = qualities["*"]
+		}
+
+		if quality > selectedQuality {
+			selectedEncoding = encoding
+			selectedQuality = quality
+		}
+	}
+
+	return selectedEncoding
+}
+
+// Checks if a response body with the given content type can be compressed, based on the list of content types configured in the "compression_types" server default.
+// Each configured content type can either be a complete media type (application/json) or a media type with a wildcard sub-type (text/*).
+func isCompressible(ContentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(ContentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowedType := range strings.Split(getServerDefaults("compression_types"), ",") {
+		allowedType = strings.ToLower(strings.TrimSpace(allowedType))
+		if allowedType == "" {
+			continue
+		}
+
+		if prefix, found := strings.CutSuffix(allowedType, "/*"); found {
+			if strings.HasPrefix(mediaType, prefix + "/") {
+				return true
+			}
+		} else if mediaType == allowedType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Compresses the given content using the given content encoding and returns the compressed content.
+func compressContent(Encoding string, Content []byte) ([]byte, error) {
+	var compressedContent bytes.Buffer
+	var compressor io.WriteCloser
+	var err error
+	switch Encoding {
+	case GZIP_CONTENT_ENCODING:
+		compressor = gzip.NewWriter(&compressedContent)
+	case DEFLATE_CONTENT_ENCODING:
+		// The deflate content encoding (RFC 9110) is the zlib format, which wraps the raw deflate data with a header and a checksum.
+		compressor, err = zlib.NewWriterLevel(&compressedContent, zlib.DefaultCompression)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("content encoding %s is not supported", Encoding)
+	}
+
+	_, err = compressor.Write(Content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = compressor.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return compressedContent.Bytes(), nil
+}
+
+// Compresses the response body if compression is enabled in the server defaults, the client accepts a supported content encoding, the body is at least as large as the configured minimum size
+// and the content type of the body is compressible. The Content-Encoding, Content-Length and Vary headers are updated accordingly.
+func (res *HttpResponse) compress() error {
+	if !strings.EqualFold(getServerDefaults("compression"), "on") || strings.EqualFold(res.Version, "0.9") {
+		return nil
+	}
+
+	if len(res.Body) == 0 || res.StatusCode == int(StatusPartialContent) {
+		return nil
+	}
+
+	if _, exists := res.Headers.Get("Content-Encoding"); exists {
+		return nil
+	}
+
+	minimumSize, err := strconv.Atoi(getServerDefaults("compression_min_size"))
+	if err != nil {
+		minimumSize = 0
+	}
+
+	if len(res.Body) < minimumSize {
+		return nil
+	}
+
+	ContentType, exists := res.Headers.Get("Content-Type")
+	if !exists || !isCompressible(ContentType) {
+		return nil
+	}
+
+	res.addVary("Accept-Encoding")
+	encoding := negotiateEncoding(res.acceptEncoding)
+	if encoding == "" {
+		return nil
+	}
+
+	compressedBody, err := compressContent(encoding, res.Body)
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = encoding
+		resErr.Message = fmt.Sprintf("Error while compressing response body :: %s", err.Error())
+		return resErr
+	}
+
+	res.Body = compressedBody
+	res.Headers.Add("Content-Encoding", encoding)
+	delete(res.Headers, "Content-Length")
+	res.Headers.Add("Content-Length", strconv.Itoa(len(res.Body)))
+	return nil
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Test case to validate the negotiation of the content encoding from the Accept-Encoding header.
+func Test_Compression_NegotiateEncoding(t *testing.T) {
+	testCases := []struct {
+		Name string
+		AcceptEncoding string
+		ExpEncoding string
+	} {
+		{ "No encodings accepted", "", "" },
+		{ "Only gzip accepted", "gzip", "gzip" },
+		{ "Both gzip and deflate accepted", "deflate, gzip", "gzip" },
+		{ "Deflate preferred with quality values", "gzip;q=0.5, deflate;q=0.8", "deflate" },
+		{ "Gzip explicitly rejected", "gzip;q=0, deflate", "deflate" },
+		{ "Wildcard encoding accepted", "*", "gzip" },
+		{ "Unsupported encodings accepted", "br, identity", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			encoding := negotiateEncoding(testCase.AcceptEncoding)
+			if encoding != testCase.ExpEncoding {
+				tt.Errorf("The negotiated encoding [%s] does not match the expected encoding [%s]", encoding, testCase.ExpEncoding)
+			} else {
+				tt.Logf("The negotiated encoding [%s] matches the expected encoding [%s]", encoding, testCase.ExpEncoding)
+			}
+		})
+	}
+}
+
+// Test case to validate if the response body is compressed only when the content type and size allow it.
+func Test_Compression_Response(t *testing.T) {
+	largeContent := strings.Repeat("proteus web server ", 100)
+	testCases := []struct {
+		Name string
+		AcceptEncoding string
+		ContentType string
+		Content string
+		ExpCompressed bool
+	} {
+		{ "Large text response", "gzip", "text/plain; charset=utf-8", largeContent, true },
+		{ "Large JSON response", "gzip", "application/json", largeContent, true },
+		{ "Large text response with deflate", "deflate", "text/plain", largeContent, true },
+		{ "Small text response", "gzip", "text/plain", "proteus", false },
+		{ "Large image response", "gzip", "image/png", largeContent, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			res.acceptEncoding = testCase.AcceptEncoding
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			res.Status(StatusOK)
+			res.Headers.Add("Content-Type", testCase.ContentType)
+			res.Body = []byte(testCase.Content)
+			err := res.write()
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet got this error - %v", err)
+				return
+			}
+
+			encoding, compressed := res.Headers.Get("Content-Encoding")
+			if compressed != testCase.ExpCompressed {
+				tt.Errorf("Expected the response to be compressed to be %t, but got %t", testCase.ExpCompressed, compressed)
+				return
+			}
+
+			if compressed {
+				_, body, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+				var reader io.Reader
+				if encoding == DEFLATE_CONTENT_ENCODING {
+					reader, err = zlib.NewReader(strings.NewReader(body))
+				} else {
+					reader, err = gzip.NewReader(strings.NewReader(body))
+				}
+				if err != nil {
+					tt.Errorf("The compressed response body could not be read - %v", err)
+					return
+				}
+
+				decompressed, _ := io.ReadAll(reader)
+				if string(decompressed) != testCase.Content {
+					tt.Errorf("The decompressed response body does not match the original content")
+				} else {
+					tt.Logf("The response body was compressed using %s as expected", encoding)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Represents the value of the SameSite attribute of a cookie.
+type SameSite string
+
+const (
+	SameSiteDefault SameSite = ""
+	SameSiteLax SameSite = "Lax"
+	SameSiteStrict SameSite = "Strict"
+	SameSiteNone SameSite = "None"
+)
+
+// Structure to represent a HTTP cookie, either received in the Cookie request header or sent in the Set-Cookie response header.
+type Cookie struct {
+	// Name of the cookie.
+	Name string
+	// Value of the cookie.
+	Value string
+	// Path for which the cookie is applicable. If empty, the attribute is not sent.
+	Path string
+	// Domain for which the cookie is applicable. If empty, the attribute is not sent.
+	Domain string
+	// Time at which the cookie expires. If zero, the attribute is not sent.
+	Expires time.Time
+	// Number of seconds until the cookie expires. A value of zero means the attribute is not sent, while a negative value means that the cookie must be deleted immediately (sent as Max-Age=0).
+	MaxAge int
+	// Indicates if the cookie must be sent by the client only over secure connections.
+	Secure bool
+	// Indicates if the cookie must not be accessible to client-side scripts.
+	HttpOnly bool
+	// Controls whether the cookie is sent with cross-site requests. If empty, the attribute is not sent.
+	SameSite SameSite
+}
+
+// Returns the serialized form of the cookie to be sent in the Set-Cookie response header.
+func (ck *Cookie) String() string {
+	var cookieBuilder strings.Builder
+	cookieBuilder.WriteString(ck.Name)
+	cookieBuilder.WriteString("=")
+	cookieBuilder.WriteString(ck.Value)
+	if ck.Path != "" {
+		cookieBuilder.WriteString("; Path=" + ck.Path)
+	}
+
+	if ck.Domain != "" {
+		cookieBuilder.WriteString("; Domain=" + strings.TrimPrefix(ck.Domain, "."))
+	}
+
+	if !ck.Expires.IsZero() {
+		cookieBuilder.WriteString("; Expires=" + ck.Expires.UTC().Format(HTTP_DATE_FORMAT))
+	}
+
+	if ck.MaxAge > 0 {
+		cookieBuilder.WriteString("; Max-Age=" + strconv.Itoa(ck.MaxAge))
+	} else if ck.MaxAge < 0 {
+		cookieBuilder.WriteString("; Max-Age=0")
+	}
+
+	if ck.Secure {
+		cookieBuilder.WriteString("; Secure")
+	}
+
+	if ck.HttpOnly {
+		cookieBuilder.WriteString("; HttpOnly")
+	}
+
+	if ck.SameSite != SameSiteDefault {
+		cookieBuilder.WriteString("; SameSite=" + string(ck.SameSite))
+	}
+
+	return cookieBuilder.String()
+}
+
+// Validates the name, value and attributes of the cookie and returns an error if any of them contain characters not allowed by RFC 6265.
+func (ck *Cookie) validate() error {
+	if ck.Name == "" || !isToken(ck.Name) {
+		return fmt.Errorf("cookie name [%s] is not a valid token", ck.Name)
+	}
+
+	for _, char := range ck.Value {
+		if !isCookieValueChar(char) {
+			return fmt.Errorf("cookie value for [%s] contains an invalid character - %q", ck.Name, char)
+		}
+	}
+
+	for _, attribute := range []string{ ck.Path, ck.Domain } {
+		if strings.ContainsAny(attribute, ";\r\n") {
+			return fmt.Errorf("cookie attribute [%s] for [%s] contains an invalid character", attribute, ck.Name)
+		}
+	}
+
+	switch ck.SameSite {
+	case SameSiteDefault, SameSiteLax, SameSiteStrict, SameSiteNone:
+		return nil
+	default:
+		return fmt.Errorf("cookie SameSite attribute [%s] for [%s] is not valid", ck.SameSite, ck.Name)
+	}
+}
+
+// Parses all the cookies present in the given Cookie request header value.
+func parseCookies(CookieHeader string) []*Cookie {
+	cookies := make([]*Cookie, 0)
+	for _, cookiePair := range strings.Split(CookieHeader, ";") {
+		cookiePair = strings.TrimSpace(cookiePair)
+		name, value, found := strings.Cut(cookiePair, "=")
+		if !found {
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" || !isToken(name) {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) > 1 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+			value = value[1 : len(value) - 1]
+		}
+
+		cookie := new(Cookie)
+		cookie.Name = name
+		cookie.Value = value
+		cookies = append(cookies, cookie)
+	}
+
+	return cookies
+}
+
+// Checks if the given character is allowed in a cookie value as per RFC 6265.
+func isCookieValueChar(char rune) bool {
+	return char == 0x21 || (char >= 0x23 && char <= 0x2B) || (char >= 0x2D && char <= 0x3A) || (char >= 0x3C && char <= 0x5B) || (char >= 0x5D && char <= 0x7E)
+}
+
+// Checks if the given value is a valid token (as defined in RFC 9110), which can be used as a header name or a cookie name.
+func isToken(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, char := range value {
+		if char <= 0x20 || char >= 0x7F || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns all the cookies sent by the client in the Cookie request header.
+func (req *HttpRequest) Cookies() []*Cookie {
+	cookieHeader, ok := req.Headers.Get("Cookie")
+	if !ok {
+		return []*Cookie{}
+	}
+
+	return parseCookies(cookieHeader)
+}
+
+// Returns the cookie with the given name sent by the client in the Cookie request header. The function also returns a boolean value to indicate if the cookie was found.
+func (req *HttpRequest) Cookie(Name string) (*Cookie, bool) {
+	for _, cookie := range req.Cookies() {
+		if cookie.Name == Name {
+			return cookie, true
+		}
+	}
+
+	return nil, false
+}
+
+// Adds a Set-Cookie header for the given cookie to the response. An error is returned if the cookie is not valid.
+func (res *HttpResponse) SetCookie(cookie Cookie) error {
+	err := cookie.validate()
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "Header"
+		resErr.Value = cookie.Name
+		resErr.Message = err.Error()
+		return resErr
+	}
+
+	// Set-Cookie values are stored without splitting them on commas, since the Expires attribute contains a comma and each cookie must be sent in a separate header line.
+	res.Headers[SET_COOKIE_HEADER] = append(res.Headers[SET_COOKIE_HEADER], cookie.String())
+	return nil
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the parsing of cookies from the Cookie request header.
+func Test_Request_Cookies(t *testing.T) {
+	testRequest := newTestRequest(t)
+	testRequest.Headers.Add("Cookie", `session=abc123; theme="dark"; invalid; lang=en`)
+	testCases := []struct {
+		Name string
+		CookieName string
+		ExpFound bool
+		ExpValue string
+	} {
+		{ "Fetching a plain cookie", "session", true, "abc123" },
+		{ "Fetching a quoted cookie", "theme", true, "dark" },
+		{ "Fetching the last cookie", "lang", true, "en" },
+		{ "Fetching a cookie not sent", "user", false, "" },
+	}
+
+	if len(testRequest.Cookies()) != 3 {
+		t.Errorf("Expected 3 cookies to be parsed from the request, but got %d", len(testRequest.Cookies()))
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			cookie, found := testRequest.Cookie(testCase.CookieName)
+			if found != testCase.ExpFound {
+				tt.Errorf("Expected the cookie [%s] to be found to be %t, but got %t", testCase.CookieName, testCase.ExpFound, found)
+				return
+			}
+
+			if found && cookie.Value != testCase.ExpValue {
+				tt.Errorf("The cookie value [%s] does not match the expected value [%s]", cookie.Value, testCase.ExpValue)
+			} else {
+				tt.Logf("The cookie [%s] was parsed as expected", testCase.CookieName)
+			}
+		})
+	}
+}
+
+// Test case to validate the serialization of cookies into Set-Cookie response headers.
+func Test_Response_SetCookie(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IpCookie Cookie
+		ExpHeader string
+		ExpErr string
+	} {
+		{ "A simple cookie", Cookie{ Name: "session", Value: "abc123" }, "session=abc123", "" },
+		{ "A cookie with all attributes", Cookie{ Name: "session", Value: "abc123", Path: "/", Domain: "example.com", Expires: time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC), MaxAge: 3600, Secure: true, HttpOnly: true, SameSite: SameSiteLax }, "session=abc123; Path=/; Domain=example.com; Expires=Thu, 02 Jan 2025 03:04:05 GMT; Max-Age=3600; Secure; HttpOnly; SameSite=Lax", "" },
+		{ "A cookie to be deleted", Cookie{ Name: "session", Value: "", MaxAge: -1 }, "session=; Max-Age=0", "" },
+		{ "A cookie with an invalid name", Cookie{ Name: "sess ion", Value: "abc" }, "", "ResponseError" },
+		{ "A cookie with an invalid value", Cookie{ Name: "session", Value: "a;b" }, "", "ResponseError" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testResponse := newTestResponse(tt, "1.1")
+			err := testResponse.SetCookie(testCase.IpCookie)
+			if testCase.ExpErr == "ResponseError" {
+				if _, ok := err.(*ResponseError); !ok {
+					tt.Errorf("Was expecting a response error, but got this instead - %v", err)
+				} else {
+					tt.Logf("Received a response error as expected - %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+				return
+			}
+
+			headerValues := testResponse.Headers[SET_COOKIE_HEADER]
+			if len(headerValues) != 1 || headerValues[0] != testCase.ExpHeader {
+				tt.Errorf("The Set-Cookie header %v does not match the expected header [%s]", headerValues, testCase.ExpHeader)
+			} else {
+				tt.Logf("The Set-Cookie header [%s] matches the expected header", headerValues[0])
+			}
+		})
+	}
+}
+
+// Test case to validate if multiple cookies are written in separate Set-Cookie header lines.
+func Test_Response_WriteMultipleCookies(t *testing.T) {
+	testResponse := newTestResponse(t, "1.1")
+	var opBuffer bytes.Buffer
+	testResponse.setWriter(bufio.NewWriter(&opBuffer))
+	testResponse.Status(StatusOK)
+	testResponse.SetCookie(Cookie{ Name: "one", Value: "1", Expires: time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC) })
+	testResponse.SetCookie(Cookie{ Name: "two", Value: "2" })
+	err := testResponse.write()
+	if err != nil {
+		t.Errorf("Was not expecting an error and yet got this error - %v", err)
+		return
+	}
+
+	output := opBuffer.String()
+	if !strings.Contains(output, "Set-Cookie: one=1; Expires=Thu, 02 Jan 2025 03:04:05 GMT\r\n") || !strings.Contains(output, "Set-Cookie: two=2\r\n") {
+		t.Errorf("The cookies were not written in separate header lines - %q", output)
+	} else {
+		t.Logf("The cookies were written in separate header lines as expected")
+	}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Structure to contain the Cross-Origin Resource Sharing (CORS) settings of the web server instance.
+type CORSConfig struct {
+	// Collection of origins allowed to make cross-origin requests. An origin of "*" allows requests from any origin.
+	AllowedOrigins []string
+	// Collection of HTTP methods allowed in cross-origin requests. If empty, the methods for which the requested route is defined are allowed.
+	AllowedMethods []string
+	// Collection of request headers allowed in cross-origin requests. If empty, the headers requested in the preflight request are allowed.
+	AllowedHeaders []string
+	// Collection of response headers that can be accessed by client-side scripts.
+	ExposedHeaders []string
+	// Indicates if cross-origin requests can include credentials (cookies, authorization headers etc.).
+	AllowCredentials bool
+	// Number of seconds for which the result of a preflight request can be cached by the client. If zero, the Access-Control-Max-Age header is not sent.
+	MaxAge int
+}
+
+// Checks if the given origin is allowed to make cross-origin requests.
+func (cors *CORSConfig) isOriginAllowed(Origin string) bool {
+	for _, allowedOrigin := range cors.AllowedOrigins {
+		allowedOrigin = strings.TrimSpace(allowedOrigin)
+		if allowedOrigin == "*" || strings.EqualFold(allowedOrigin, Origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Adds the Access-Control-Allow-Origin and Access-Control-Allow-Credentials headers to the response for the given origin.
+// The origin is sent back as is when credentials are allowed or when specific origins are configured, since a wildcard origin is not allowed in such cases.
+func (cors *CORSConfig) addOriginHeaders(response *HttpResponse, Origin string) {
+	if slices.Contains(cors.AllowedOrigins, "*") && !cors.AllowCredentials {
+		response.Headers.Add("Access-Control-Allow-Origin", "*")
+	} else {
+		response.Headers.Add("Access-Control-Allow-Origin", Origin)
+		response.Headers.Add("Vary", "Origin")
+	}
+
+	if cors.AllowCredentials {
+		response.Headers.Add("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// Checks if the given request is a CORS preflight request.
+func isPreflightRequest(request *HttpRequest) bool {
+	_, hasOrigin := request.Headers.Get("Origin")
+	_, hasRequestMethod := request.Headers.Get("Access-Control-Request-Method")
+	return strings.EqualFold(request.Method, "OPTIONS") && hasOrigin && hasRequestMethod
+}
+
+// Responds to the given CORS preflight request, where RouteMethods contains the methods for which the requested route is defined.
+// If the origin or the requested method is not allowed, the response is sent without any of the Access-Control-* headers, so that the client blocks the actual request.
+func (cors *CORSConfig) handlePreflight(request *HttpRequest, response *HttpResponse, RouteMethods []string) {
+	response.Status(StatusNoContent)
+	Origin, _ := request.Headers.Get("Origin")
+	if !cors.isOriginAllowed(Origin) {
+		return
+	}
+
+	allowedMethods := cors.AllowedMethods
+	if len(allowedMethods) == 0 {
+		allowedMethods = RouteMethods
+	}
+
+	requestMethod, _ := request.Headers.Get("Access-Control-Request-Method")
+	requestMethod = strings.TrimSpace(requestMethod)
+	isMethodFound := slices.ContainsFunc(allowedMethods, func(method string) bool {
+		return strings.EqualFold(strings.TrimSpace(method), requestMethod)
+	})
+	if !isMethodFound {
+		return
+	}
+
+	cors.addOriginHeaders(response, Origin)
+	response.Headers.Add("Access-Control-Allow-Methods", strings.Join(allowedMethods, ","))
+	if len(cors.AllowedHeaders) > 0 {
+		response.Headers.Add("Access-Control-Allow-Headers", strings.Join(cors.AllowedHeaders, ","))
+	} else if requestHeaders, exists := request.Headers.Get("Access-Control-Request-Headers"); exists {
+		response.Headers.Add("Access-Control-Allow-Headers", requestHeaders)
+		response.Headers.Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	if cors.MaxAge > 0 {
+		response.Headers.Add("Access-Control-Max-Age", strconv.Itoa(cors.MaxAge))
+	}
+}
+
+// Creates and returns a middleware which adds the CORS response headers to the responses of cross-origin requests made from allowed origins.
+func (cors *CORSConfig) middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			if Origin, exists := request.Headers.Get("Origin"); exists && cors.isOriginAllowed(Origin) {
+				cors.addOriginHeaders(response, Origin)
+				if len(cors.ExposedHeaders) > 0 {
+					response.Headers.Add("Access-Control-Expose-Headers", strings.Join(cors.ExposedHeaders, ","))
+				}
+			}
+
+			return next(request, response)
+		}
+	}
+}
+
+// Enables Cross-Origin Resource Sharing (CORS) for all the routes defined in the web server instance using the given settings.
+// Preflight requests made for any of the defined routes are answered automatically, while the Access-Control-* headers are added to the responses of all the other cross-origin requests.
+func (srv *HttpServer) UseCORS(config CORSConfig) {
+	srv.corsConfig = &config
+	srv.Use(srv.corsConfig.middleware())
+}
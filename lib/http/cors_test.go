@@ -0,0 +1,60 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+// Test case to validate the handling of CORS preflight and cross-origin requests.
+func Test_Server_CORS(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.UseCORS(CORSConfig{ AllowedOrigins: []string{ "https://example.com" }, ExposedHeaders: []string{ "X-Request-Id" }, AllowCredentials: true, MaxAge: 600 })
+	testServer.Get("/users/:id", func(req *HttpRequest, res *HttpResponse) error { return nil })
+	testServer.Put("/users/:id", func(req *HttpRequest, res *HttpResponse) error { return nil })
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		RequestHeaders map[string]string
+		ExpStatus int
+		ExpHeaders map[string]string
+	} {
+		{ "Preflight request from an allowed origin", "OPTIONS", "/users/10", map[string]string{ "Origin": "https://example.com", "Access-Control-Request-Method": "PUT", "Access-Control-Request-Headers": "Content-Type" }, int(StatusNoContent), map[string]string{ "Access-Control-Allow-Origin": "https://example.com", "Access-Control-Allow-Methods": "GET,PUT,HEAD", "Access-Control-Allow-Headers": "Content-Type", "Access-Control-Allow-Credentials": "true", "Access-Control-Max-Age": "600" } },
+		{ "Preflight request from a disallowed origin", "OPTIONS", "/users/10", map[string]string{ "Origin": "https://other.com", "Access-Control-Request-Method": "PUT" }, int(StatusNoContent), map[string]string{ "Access-Control-Allow-Origin": "" } },
+		{ "Preflight request for a disallowed method", "OPTIONS", "/users/10", map[string]string{ "Origin": "https://example.com", "Access-Control-Request-Method": "DELETE" }, int(StatusNoContent), map[string]string{ "Access-Control-Allow-Origin": "" } },
+		{ "Preflight request for an undefined route", "OPTIONS", "/orders", map[string]string{ "Origin": "https://example.com", "Access-Control-Request-Method": "GET" }, int(StatusNotFound), map[string]string{ "Access-Control-Allow-Origin": "" } },
+		{ "Cross-origin request from an allowed origin", "GET", "/users/10", map[string]string{ "Origin": "https://example.com" }, int(StatusOK), map[string]string{ "Access-Control-Allow-Origin": "https://example.com", "Access-Control-Expose-Headers": "X-Request-Id", "Vary": "Origin" } },
+		{ "Same-origin request", "GET", "/users/10", map[string]string{}, int(StatusOK), map[string]string{ "Access-Control-Allow-Origin": "" } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			for key, value := range testCase.RequestHeaders {
+				testRequest.Headers.Add(key, value)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.end()
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			}
+
+			for key, expValue := range testCase.ExpHeaders {
+				value, _ := testResponse.Headers.Get(key)
+				if value != expValue {
+					tt.Errorf("Expected header %s to be [%s], but got [%s]", key, expValue, value)
+				} else {
+					tt.Logf("Header %s matches the expected value [%s]", key, expValue)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"crypto/subtle"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// Key against which the CSRF token is stored in the session of the client, when sessions have been enabled.
+const CSRF_SESSION_KEY = "csrf_token"
+
+// Maximum size (in bytes) of the file parts kept in memory, while parsing a multipart form to read the CSRF token sent in it.
+const CSRF_MULTIPART_MAX_MEMORY = 1 << 20
+
+// Structure containing the settings of the CSRF protection middleware. The settings left empty are taken from the server defaults.
+type CSRFConfig struct {
+	// Name of the cookie holding the CSRF token, when sessions have not been enabled. It is taken from the "csrf_cookie_name" server default if empty.
+	CookieName string
+	// Name of the request header in which the CSRF token can be sent, like by client-side scripts. It is taken from the "csrf_header_name" server default if empty.
+	HeaderName string
+	// Name of the form field in which the CSRF token can be sent, like by HTML forms. It is taken from the "csrf_field_name" server default if empty.
+	FieldName string
+}
+
+// State of the CSRF protection for a single request.
+type csrfState struct {
+	// CSRF token of the client. It is empty until a token has been issued to the client.
+	token string
+	// Session in which the CSRF token is stored. It is nil if the token is stored in a cookie.
+	session *Session
+	// Boolean value to indicate if a new token has been issued while processing the request, which must be sent to the client in the CSRF cookie.
+	isIssued bool
+}
+
+// Returns a middleware which protects the routes from cross-site request forgery. Requests with an unsafe method (like POST, PUT, PATCH or DELETE) must send the CSRF token of the client,
+// in the configured request header or form field, and are rejected with a 403 (Forbidden) response otherwise. The token is issued by CSRFToken() and is stored in the session of the client
+// (synchronizer token) if sessions have been enabled using UseSessions() before adding the middleware, or else in a cookie which must be sent back along with the token (double-submit cookie).
+func CSRF(config CSRFConfig) Middleware {
+	if config.CookieName == "" {
+		config.CookieName = getServerDefaults("csrf_cookie_name")
+	}
+
+	if config.HeaderName == "" {
+		config.HeaderName = getServerDefaults("csrf_header_name")
+	}
+
+	if config.FieldName == "" {
+		config.FieldName = getServerDefaults("csrf_field_name")
+	}
+
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			state := new(csrfState)
+			if session := request.Session(); session != nil {
+				state.session = session
+				if token, ok := session.Get(CSRF_SESSION_KEY); ok {
+					state.token, _ = token.(string)
+				}
+			} else if cookie, found := request.Cookie(config.CookieName); found && cookie.Value != "" {
+				state.token = cookie.Value
+			}
+
+			request.csrf = state
+			response.onBeforeWrite(func(res *HttpResponse) {
+				if state.isIssued {
+					// The cookie must be readable by client-side scripts, which send the token back in the request header.
+					res.SetCookie(Cookie{ Name: config.CookieName, Value: state.token, Path: "/", Secure: isSecureRequest(request), SameSite: SameSiteLax })
+				}
+			})
+
+			if !isSafeMethod(request.Method) {
+				submittedToken := getSubmittedCSRFToken(request, &config)
+				if state.token == "" || subtle.ConstantTimeCompare([]byte(submittedToken), []byte(state.token)) != 1 {
+					request.Logger().Warn("Request rejected as its CSRF token is missing or invalid", "method", request.Method, "path", request.ResourcePath)
+					response.Status(StatusForbidden)
+					return handleError(request, response)
+				}
+			}
+
+			return next(request, response)
+		}
+	}
+}
+
+// Returns the CSRF token of the client, which must be sent back in the requests with an unsafe method, like in a hidden field of the HTML forms or in the request header sent by client-side scripts.
+// A new token is issued if the client does not have one yet. It returns an empty string if the CSRF middleware has not been added to the route.
+func (req *HttpRequest) CSRFToken() string {
+	if req.csrf == nil {
+		return ""
+	}
+
+	if req.csrf.token == "" {
+		token, err := generateSessionID()
+		if err != nil {
+			req.Logger().Error("Error occurred while generating the CSRF token", "error", err.Error())
+			return ""
+		}
+
+		req.csrf.token = token
+		if req.csrf.session != nil {
+			req.csrf.session.Set(CSRF_SESSION_KEY, token)
+		} else {
+			req.csrf.isIssued = true
+		}
+	}
+
+	return req.csrf.token
+}
+
+// Returns the CSRF token sent in the given request, either in the request header or in the form field given in the configuration. The form field is read only from a body sent as an URL encoded or a multipart form,
+// since a token sent in the query string can leak through logs and the Referer header.
+func getSubmittedCSRFToken(request *HttpRequest, config *CSRFConfig) string {
+	if token := request.Header(config.HeaderName); token != "" {
+		return token
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header("Content-Type"))
+	if strings.EqualFold(mediaType, FORM_URLENCODED_CONTENT_TYPE) {
+		bodyValues, err := url.ParseQuery(string(request.Body))
+		if err == nil {
+			return bodyValues.Get(config.FieldName)
+		}
+	} else if strings.EqualFold(mediaType, MULTIPART_FORM_CONTENT_TYPE) {
+		form, err := request.ParseMultipart(CSRF_MULTIPART_MAX_MEMORY)
+		if err == nil && len(form.Fields.GetAll(config.FieldName)) > 0 {
+			return form.Fields.GetAll(config.FieldName)[0]
+		}
+	}
+
+	return ""
+}
+
+// Checks if the given request method is safe (as defined in RFC 9110), that is, it is not expected to change the state of the server.
+func isSafeMethod(Method string) bool {
+	switch strings.ToUpper(Method) {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return true
+	default:
+		return false
+	}
+}
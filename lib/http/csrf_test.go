@@ -0,0 +1,90 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate that the CSRF middleware accepts the requests with an unsafe method only if they send back the token issued to the client, using both a cookie and the session to store the token.
+func Test_Server_CSRF(t *testing.T) {
+	for _, useSessions := range []bool{ false, true } {
+		testServer := NewServer()
+		testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+		if useSessions {
+			testServer.UseSessions(NewMemorySessionStore(time.Minute))
+		}
+
+		testServer.Use(CSRF(CSRFConfig{}))
+		testServer.Get("/form", func(req *HttpRequest, res *HttpResponse) error {
+			res.Status(StatusOK)
+			res.Body = []byte(req.CSRFToken())
+			return nil
+		})
+		testServer.Post("/orders", func(req *HttpRequest, res *HttpResponse) error {
+			res.Status(StatusCreated)
+			return nil
+		})
+
+		// The token and the cookie (the CSRF cookie or the session cookie) are issued to the client by the first request.
+		testRequest := newTestRequest(t)
+		testRequest.Method = "GET"
+		testRequest.ResourcePath = "/form"
+		testResponse := newTestResponse(t, "1.1")
+		testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+		testServer.processRequest(testRequest, testResponse)
+		testResponse.end()
+		token := string(testResponse.Body)
+		setCookies := testResponse.Headers[SET_COOKIE_HEADER]
+		if testResponse.StatusCode != int(StatusOK) || token == "" || len(setCookies) != 1 {
+			t.Fatalf("Expected a token and a cookie to be issued, but got the status %d with the cookies %v", testResponse.StatusCode, setCookies)
+		}
+		clientCookie, _, _ := strings.Cut(setCookies[0], ";")
+		modeName := "cookie"
+		if useSessions {
+			modeName = "session"
+		}
+
+		testCases := []struct {
+			Name string
+			Cookie string
+			Headers map[string]string
+			Body string
+			ExpStatus int
+		} {
+			{ "Token sent in the request header", clientCookie, map[string]string{ "X-CSRF-Token": token }, "", int(StatusCreated) },
+			{ "Token sent in a form field", clientCookie, map[string]string{ "Content-Type": "application/x-www-form-urlencoded" }, "item=book&csrf_token=" + token, int(StatusCreated) },
+			{ "Token sent in a multipart form field", clientCookie, map[string]string{ "Content-Type": "multipart/form-data; boundary=XYZ" }, "--XYZ\r\nContent-Disposition: form-data; name=\"csrf_token\"\r\n\r\n" + token + "\r\n--XYZ--\r\n", int(StatusCreated) },
+			{ "Request without a token", clientCookie, map[string]string{}, "", int(StatusForbidden) },
+			{ "Request with a token of another client", clientCookie, map[string]string{ "X-CSRF-Token": "forged" + token[6:] }, "", int(StatusForbidden) },
+			{ "Token sent without the cookie", "", map[string]string{ "X-CSRF-Token": token }, "", int(StatusForbidden) },
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Name + " (" + modeName + ")", func(tt *testing.T) {
+				testRequest := newTestRequest(tt)
+				testRequest.Method = "POST"
+				testRequest.ResourcePath = "/orders"
+				if testCase.Cookie != "" {
+					testRequest.Headers.Add("Cookie", testCase.Cookie)
+				}
+				for name, value := range testCase.Headers {
+					testRequest.Headers.Add(name, value)
+				}
+				testRequest.Body = []byte(testCase.Body)
+
+				testResponse := newTestResponse(tt, "1.1")
+				testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+				testServer.processRequest(testRequest, testResponse)
+				if testResponse.StatusCode != testCase.ExpStatus {
+					tt.Errorf("Expected the status %d with sessions enabled [%t], but got %d instead", testCase.ExpStatus, useSessions, testResponse.StatusCode)
+				} else {
+					tt.Logf("Received the status %d with sessions enabled [%t] as expected", testResponse.StatusCode, useSessions)
+				}
+				testRequest.cleanupMultipartForm()
+			})
+		}
+	}
+}
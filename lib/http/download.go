@@ -0,0 +1,355 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/mkbworks/proteus/lib/fs"
+)
+
+// Structure to represent a byte range of a file requested using the Range header.
+type fileRange struct {
+	// Offset of the first byte of the range.
+	offset int64
+	// Number of bytes in the range.
+	length int64
+}
+
+// Returns the value of the Content-Range header for the byte range of a file with the given size.
+func (byteRange fileRange) getContentRange(fileSize int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", byteRange.offset, byteRange.offset + byteRange.length - 1, fileSize)
+}
+
+// Structure to contain the settings for sending a file using SendFile().
+type DownloadOptions struct {
+	// Boolean value to indicate if the client must save the file instead of displaying it, which is done by sending the file as an attachment in the Content-Disposition header.
+	Attachment bool
+	// Name suggested to the client for saving the file. It defaults to the base name of the file and setting it sends the file as an attachment.
+	FileName string
+	// Media type of the file sent in the Content-Type header. If empty, the media type is determined from the file extension.
+	ContentType string
+}
+
+// Sends the file available at the given path in the local file system as the response, along with the headers describing the file (Content-Type, Content-Length and Last-Modified). The settings for sending the file
+// can be given as an optional DownloadOptions value. Large files are copied directly from the file system to the client connection and the byte ranges requested by the client using the Range header are sent
+// as a 206 (Partial Content) response. The status defaults to 200 OK if it has not been set. An error is returned if the file could not be read.
+func (res *HttpResponse) SendFile(CompleteFilePath string, options ...DownloadOptions) error {
+	var downloadOptions DownloadOptions
+	if len(options) > 0 {
+		downloadOptions = options[0]
+	}
+
+	fileMediaType := strings.TrimSpace(downloadOptions.ContentType)
+	if fileMediaType == "" {
+		var exists bool
+		fileMediaType, exists = getContentType(CompleteFilePath, res.contentTypes, res.defaultContentType)
+		if !exists {
+			resErr := new(ResponseError)
+			resErr.Section = "Body"
+			resErr.Value = CompleteFilePath
+			resErr.Message = "SendFile: Given path does not point to a file"
+			return resErr
+		}
+	}
+
+	file, err := fs.GetFile(CompleteFilePath, fileMediaType, true)
+	if err != nil {
+		return err
+	}
+
+	if downloadOptions.Attachment || strings.TrimSpace(downloadOptions.FileName) != "" {
+		fileName := strings.TrimSpace(downloadOptions.FileName)
+		if fileName == "" {
+			fileName = file.Name
+		}
+
+		// The header value is stored without splitting it on commas, since the file name can contain commas.
+		res.Headers["Content-Disposition"] = []string{ getContentDisposition(fileName) }
+	}
+
+	return res.serveFile(fs.OSFileSystem{}, CompleteFilePath, file, false)
+}
+
+// Sends the given file available at the given path in the given file system as the response. If the contents of the file are present
+// in the given file, they are sent instead of reading them from the file system. If OnlyMetadata is true, only the headers describing the file are sent. For a response with status 200 OK, the byte ranges requested by the client are sent as a 206 (Partial Content) response,
+// with multiple byte ranges sent as a multipart/byteranges body, or a 416 (Range Not Satisfiable) response is sent if all the requested ranges lie outside the file.
+func (res *HttpResponse) serveFile(FileSystem fs.FileSystem, CompleteFilePath string, file *fs.File, OnlyMetadata bool) error {
+	if res.StatusCode == 0 {
+		res.Status(StatusOK)
+	}
+
+	offset, length, isPartial := int64(0), file.Size, false
+	if res.StatusCode == int(StatusOK) {
+		res.Headers.Add("Accept-Ranges", "bytes")
+		fileRanges, isSatisfiable := res.getFileRanges(file)
+		if !isSatisfiable {
+			res.Status(StatusRangeNotSatisfiable)
+			res.Headers.Add("Content-Range", fmt.Sprintf("bytes */%d", file.Size))
+			return res.SendError(StatusRangeNotSatisfiable.GetErrorContent())
+		}
+
+		if len(fileRanges) > 1 {
+			return res.serveFileRanges(FileSystem, CompleteFilePath, file, fileRanges)
+		}
+
+		if len(fileRanges) == 1 {
+			offset, length, isPartial = fileRanges[0].offset, fileRanges[0].length, true
+			res.Status(StatusPartialContent)
+			res.Headers.Add("Content-Range", fileRanges[0].getContentRange(file.Size))
+		}
+	}
+
+	res.Headers.Add("Content-Type", file.ContentType)
+	res.Headers.Add("Content-Length", strconv.FormatInt(length, 10))
+	res.addLastModified(file)
+	if !OnlyMetadata && !res.isHeadRequest {
+		if file.Contents != nil {
+			res.Body = file.Contents[offset: offset + length]
+		} else if isPartial || isStreamable(file) {
+			bodyFile, err := fs.OpenFileFS(FileSystem, CompleteFilePath)
+			if err != nil {
+				return err
+			}
+
+			if bodyFileSeeker, isSeekable := bodyFile.(io.Seeker); isSeekable {
+				res.bodyFile = bodyFile
+				res.bodyFilePath = CompleteFilePath
+				res.bodyFileSize = length
+				_, err = bodyFileSeeker.Seek(offset, io.SeekStart)
+				if err != nil {
+					res.closeBodyFile()
+					return err
+				}
+			} else {
+				// Files of a file system which cannot be read from a given offset are read completely instead.
+				bodyFile.Close()
+				fileContents, err := fs.ReadFileContentsFS(FileSystem, CompleteFilePath)
+				if err != nil {
+					return err
+				} else if int64(len(fileContents)) < offset + length {
+					return io.ErrUnexpectedEOF
+				}
+				res.Body = fileContents[offset: offset + length]
+			}
+		} else {
+			fileContents, err := fs.ReadFileContentsFS(FileSystem, CompleteFilePath)
+			if err != nil {
+				return err
+			}
+			res.Body = fileContents
+		}
+	}
+
+	return res.write()
+}
+
+// Sends the given byte ranges of the given file as a 206 (Partial Content) response with a multipart/byteranges body (as defined in RFC 9110), where each part contains a single byte range
+// along with its Content-Type and Content-Range headers.
+func (res *HttpResponse) serveFileRanges(FileSystem fs.FileSystem, CompleteFilePath string, file *fs.File, fileRanges []fileRange) error {
+	fileContents := file.Contents
+	var bodyFile io.ReaderAt
+	if fileContents == nil {
+		openedFile, err := fs.OpenFileFS(FileSystem, CompleteFilePath)
+		if err != nil {
+			return err
+		}
+		defer openedFile.Close()
+
+		var isReadableAt bool
+		bodyFile, isReadableAt = openedFile.(io.ReaderAt)
+		if !isReadableAt {
+			// Files of a file system which cannot be read from a given offset are read completely instead.
+			fileContents, err = fs.ReadFileContentsFS(FileSystem, CompleteFilePath)
+			if err != nil {
+				return err
+			} else if int64(len(fileContents)) != file.Size {
+				return io.ErrUnexpectedEOF
+			}
+		}
+	}
+
+	var body bytes.Buffer
+	partWriter := multipart.NewWriter(&body)
+	for _, byteRange := range fileRanges {
+		partHeader := make(textproto.MIMEHeader)
+		partHeader.Set("Content-Type", file.ContentType)
+		partHeader.Set("Content-Range", byteRange.getContentRange(file.Size))
+		part, err := partWriter.CreatePart(partHeader)
+		if err != nil {
+			return err
+		}
+
+		if fileContents != nil {
+			_, err = part.Write(fileContents[byteRange.offset: byteRange.offset + byteRange.length])
+		} else {
+			_, err = io.Copy(part, io.NewSectionReader(bodyFile, byteRange.offset, byteRange.length))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	partWriter.Close()
+
+	res.Status(StatusPartialContent)
+	res.Headers.Add("Content-Type", "multipart/byteranges; boundary=" + partWriter.Boundary())
+	res.Headers.Add("Content-Length", strconv.Itoa(body.Len()))
+	res.addLastModified(file)
+	if !res.isHeadRequest {
+		res.Body = body.Bytes()
+	}
+
+	return res.write()
+}
+
+// Adds the Last-Modified header for the given file to the response. The header is left out for files without a last modified time (like the files of an embed.FS).
+func (res *HttpResponse) addLastModified(file *fs.File) {
+	if !file.LastModifiedAt.IsZero() {
+		res.Headers.Add("Last-Modified", formatHttpDate(file.LastModifiedAt))
+	}
+}
+
+// Checks if the contents of the given file can be copied to the response byte stream as they are, instead of being read into the response body. This is done for files which are at least as large as the
+// "sendfile_min_size" server default and which are not compressed (as compression requires the complete contents of the file).
+func isStreamable(file *fs.File) bool {
+	minimumSize, err := strconv.ParseInt(getServerDefaults("sendfile_min_size"), 10, 64)
+	if err != nil || minimumSize <= 0 || file.Size < minimumSize {
+		return false
+	}
+
+	return !strings.EqualFold(getServerDefaults("compression"), "on") || !isCompressible(file.ContentType)
+}
+
+// Returns the byte ranges of the given file requested in the Range header of the request, in the order in which they were requested. No byte ranges are returned if the complete file must be sent,
+// as the request does not contain a valid Range header or the file has changed since the client fetched it (as per the If-Range header). The requested ranges which lie outside the file are left out
+// and the boolean value returned is false if none of the requested ranges lie within the file.
+func (res *HttpResponse) getFileRanges(file *fs.File) ([]fileRange, bool) {
+	rangeSet, found := strings.CutPrefix(strings.TrimSpace(res.rangeHeader), "bytes=")
+	if !found || !res.isIfRangeMatch(file) {
+		// Range units other than bytes are not supported, in which case the complete file is sent.
+		return nil, true
+	}
+
+	fileRanges := make([]fileRange, 0)
+	rangeCount, totalLength := 0, int64(0)
+	for _, rangeSpec := range strings.Split(rangeSet, ",") {
+		rangeSpec = strings.TrimSpace(rangeSpec)
+		if rangeSpec == "" {
+			continue
+		}
+
+		byteRange, isValid, isSatisfiable := parseByteRange(rangeSpec, file.Size)
+		if !isValid {
+			// The Range header is ignored altogether if any of the ranges is not valid.
+			return nil, true
+		}
+
+		rangeCount++
+		if isSatisfiable {
+			fileRanges = append(fileRanges, byteRange)
+			totalLength += byteRange.length
+		}
+	}
+
+	if rangeCount == 0 {
+		return nil, true
+	} else if len(fileRanges) == 0 {
+		return nil, false
+	} else if len(fileRanges) > 1 && totalLength > file.Size {
+		// Overlapping ranges requesting more bytes than the file contains are answered with the complete file, so that a small request cannot make the server send a large response.
+		return nil, true
+	}
+
+	return fileRanges, true
+}
+
+// Parses the given byte range (like "0-499", "500-" or "-500") of a file with the given size. The first boolean value returned is false if the byte range is not valid
+// and the second boolean value returned is false if the byte range lies outside the file.
+func parseByteRange(rangeSpec string, fileSize int64) (fileRange, bool, bool) {
+	firstPosition, lastPosition, found := strings.Cut(rangeSpec, "-")
+	firstPosition, lastPosition = strings.TrimSpace(firstPosition), strings.TrimSpace(lastPosition)
+	if !found {
+		return fileRange{}, false, false
+	}
+
+	if firstPosition == "" {
+		suffixLength, err := strconv.ParseInt(lastPosition, 10, 64)
+		if err != nil || suffixLength < 0 {
+			return fileRange{}, false, false
+		} else if suffixLength == 0 || fileSize == 0 {
+			return fileRange{}, true, false
+		}
+
+		suffixLength = min(suffixLength, fileSize)
+		return fileRange{ offset: fileSize - suffixLength, length: suffixLength }, true, true
+	}
+
+	start, err := strconv.ParseInt(firstPosition, 10, 64)
+	if err != nil || start < 0 {
+		return fileRange{}, false, false
+	}
+
+	end := fileSize - 1
+	if lastPosition != "" {
+		end, err = strconv.ParseInt(lastPosition, 10, 64)
+		if err != nil || end < start {
+			return fileRange{}, false, false
+		}
+		end = min(end, fileSize - 1)
+	}
+
+	if start >= fileSize {
+		return fileRange{}, true, false
+	}
+
+	return fileRange{ offset: start, length: end - start + 1 }, true, true
+}
+
+// Checks if the If-Range header of the request matches the current state of the given file, in which case the requested range can be sent. The header can contain either a strong entity tag,
+// which must match the ETag header of the response, or a HTTP date, which must match the last modified time of the file. It always matches if the request does not contain an If-Range header.
+func (res *HttpResponse) isIfRangeMatch(file *fs.File) bool {
+	ifRange := strings.TrimSpace(res.ifRangeHeader)
+	if ifRange == "" {
+		return true
+	}
+
+	if strings.HasPrefix(ifRange, "\"") {
+		ETag, _ := res.Headers.Get("ETag")
+		return ifRange == strings.TrimSpace(ETag)
+	}
+
+	isValid, modifiedSince := isHttpDate(ifRange)
+	return isValid && file.LastModifiedAt.Truncate(time.Second).Equal(modifiedSince)
+}
+
+// Returns the value of the Content-Disposition header to send a file as an attachment with the given file name. File names containing characters other than printable ASCII characters are
+// sent using the filename* parameter (as defined in RFC 6266), along with an ASCII fallback in the filename parameter.
+func getContentDisposition(FileName string) string {
+	asciiName := strings.Map(func(char rune) rune {
+		if char < 0x20 || char > 0x7E || char == '"' || char == '\\' {
+			return '_'
+		}
+		return char
+	}, FileName)
+
+	disposition := fmt.Sprintf("attachment; filename=\"%s\"", asciiName)
+	if asciiName == FileName {
+		return disposition
+	}
+
+	var encodedName strings.Builder
+	for _, char := range []byte(FileName) {
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || strings.IndexByte("!#$&+-.^_`|~", char) != -1 {
+			encodedName.WriteByte(char)
+		} else {
+			encodedName.WriteString(fmt.Sprintf("%%%02X", char))
+		}
+	}
+
+	return disposition + "; filename*=UTF-8''" + encodedName.String()
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the files sent from a dynamic route using SendFile(), along with the Content-Disposition header and the byte ranges requested by the client.
+func Test_Response_SendFile(t *testing.T) {
+	testFolder := t.TempDir()
+	fileContents := []byte("0123456789abcdefghij")
+	filePath := filepath.Join(testFolder, "report.png")
+	os.WriteFile(filePath, fileContents, 0644)
+	lastModified := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(filePath, lastModified, lastModified)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Get("/download", func(req *HttpRequest, res *HttpResponse) error {
+		return res.SendFile(filePath, DownloadOptions{ Attachment: true })
+	})
+	testServer.Get("/named", func(req *HttpRequest, res *HttpResponse) error {
+		return res.SendFile(filePath, DownloadOptions{ FileName: "résumé \"final\".png" })
+	})
+	testServer.Get("/inline", func(req *HttpRequest, res *HttpResponse) error {
+		return res.SendFile(filePath)
+	})
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Range string
+		IfRange string
+		ExpStatus int
+		ExpBody string
+		ExpContentRange string
+		ExpDisposition string
+	} {
+		{ "File sent as an attachment", "/download", "", "", int(StatusOK), string(fileContents), "", "attachment; filename=\"report.png\"" },
+		{ "File sent with a non-ASCII file name", "/named", "", "", int(StatusOK), string(fileContents), "", "attachment; filename=\"r_sum_ _final_.png\"; filename*=UTF-8''r%C3%A9sum%C3%A9%20%22final%22.png" },
+		{ "File sent inline", "/inline", "", "", int(StatusOK), string(fileContents), "", "" },
+		{ "Byte range", "/inline", "bytes=0-9", "", int(StatusPartialContent), "0123456789", "bytes 0-9/20", "" },
+		{ "Byte range without an end", "/inline", "bytes=15-", "", int(StatusPartialContent), "fghij", "bytes 15-19/20", "" },
+		{ "Suffix byte range", "/inline", "bytes=-3", "", int(StatusPartialContent), "hij", "bytes 17-19/20", "" },
+		{ "Byte range past the end of the file", "/inline", "bytes=10-100", "", int(StatusPartialContent), "abcdefghij", "bytes 10-19/20", "" },
+		{ "Byte range outside the file", "/inline", "bytes=20-", "", int(StatusRangeNotSatisfiable), "", "bytes */20", "" },
+		{ "Multiple byte ranges with a range outside the file", "/inline", "bytes=0-1, 50-60", "", int(StatusPartialContent), "01", "bytes 0-1/20", "" },
+		{ "Overlapping byte ranges larger than the file", "/inline", "bytes=0-15,5-19", "", int(StatusOK), string(fileContents), "", "" },
+		{ "Multiple byte ranges outside the file", "/inline", "bytes=20-,30-40", "", int(StatusRangeNotSatisfiable), "", "bytes */20", "" },
+		{ "Invalid byte range", "/inline", "bytes=9-2", "", int(StatusOK), string(fileContents), "", "" },
+		{ "If-Range matching the last modified time", "/inline", "bytes=0-4", lastModified.UTC().Format(time.RFC1123), int(StatusPartialContent), "01234", "bytes 0-4/20", "" },
+		{ "If-Range not matching the entity tag", "/inline", "bytes=0-4", "\"outdated\"", int(StatusOK), string(fileContents), "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.rangeHeader = testCase.Range
+			testResponse.ifRangeHeader = testCase.IfRange
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			_, responseBody, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			contentRange, _ := testResponse.Headers.Get("Content-Range")
+			disposition, _ := testResponse.Headers.Get("Content-Disposition")
+			lastModifiedHeader, _ := testResponse.Headers.Get("Last-Modified")
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("Expected status code to be %d, but got %d", testCase.ExpStatus, testResponse.StatusCode)
+			} else if testCase.ExpBody != "" && responseBody != testCase.ExpBody {
+				tt.Errorf("Expected the response body to be [%s], but got [%s]", testCase.ExpBody, responseBody)
+			} else if contentRange != testCase.ExpContentRange {
+				tt.Errorf("Expected the Content-Range header to be [%s], but got [%s]", testCase.ExpContentRange, contentRange)
+			} else if disposition != testCase.ExpDisposition {
+				tt.Errorf("Expected the Content-Disposition header to be [%s], but got [%s]", testCase.ExpDisposition, disposition)
+			} else if testCase.ExpStatus != int(StatusRangeNotSatisfiable) && lastModifiedHeader != lastModified.UTC().Format(HTTP_DATE_FORMAT) {
+				tt.Errorf("Expected the Last-Modified header to be [%s], but got [%s]", lastModified.UTC().Format(HTTP_DATE_FORMAT), lastModifiedHeader)
+			} else {
+				tt.Logf("Received status %d with the expected headers and body", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate the multipart/byteranges responses sent when multiple byte ranges of a file are requested, for files read from the file system and files held in a static file cache.
+func Test_Response_SendFile_MultipleRanges(t *testing.T) {
+	testFolder := t.TempDir()
+	filePath := filepath.Join(testFolder, "report.txt")
+	os.WriteFile(filePath, []byte("0123456789abcdefghij"), 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Get("/report", func(req *HttpRequest, res *HttpResponse) error {
+		return res.SendFile(filePath)
+	})
+	testServer.Static("/cached", testFolder, StaticOptions{ Cache: NewStaticFileCache(1024, 1024) })
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Range string
+		ExpRanges []string
+		ExpParts []string
+	} {
+		{ "Multiple byte ranges", "/report", "bytes=0-1,5-6", []string{ "bytes 0-1/20", "bytes 5-6/20" }, []string{ "01", "56" } },
+		{ "Byte ranges in the requested order", "/report", "bytes=-2, 0-0, 10-", []string{ "bytes 18-19/20", "bytes 0-0/20", "bytes 10-19/20" }, []string{ "ij", "0", "abcdefghij" } },
+		{ "Multiple byte ranges of a cached file", "/cached/report.txt", "bytes=0-1,5-6", []string{ "bytes 0-1/20", "bytes 5-6/20" }, []string{ "01", "56" } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			for attempt := 0; attempt < 2; attempt++ {
+				// The request is sent twice, so that the second request for a cached file is served from the cache.
+				testRequest := newTestRequest(tt)
+				testRequest.Method = "GET"
+				testRequest.ResourcePath = testCase.ResourcePath
+				testResponse := newTestResponse(tt, "1.1")
+				testResponse.rangeHeader = testCase.Range
+				var opBuffer bytes.Buffer
+				testResponse.setWriter(bufio.NewWriter(&opBuffer))
+				testServer.processRequest(testRequest, testResponse)
+				_, responseBody, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+				contentType, _ := testResponse.Headers.Get("Content-Type")
+				mediaType, params, err := mime.ParseMediaType(contentType)
+				if testResponse.StatusCode != int(StatusPartialContent) || err != nil || mediaType != "multipart/byteranges" {
+					tt.Fatalf("Expected a 206 response with a multipart/byteranges body, but got status %d with content type [%s]", testResponse.StatusCode, contentType)
+				}
+
+				contentRanges := make([]string, 0)
+				parts := make([]string, 0)
+				partReader := multipart.NewReader(strings.NewReader(responseBody), params["boundary"])
+				for {
+					part, err := partReader.NextPart()
+					if err == io.EOF {
+						break
+					} else if err != nil {
+						tt.Fatalf("Was not expecting an error while reading the parts of the response body and yet received one - %v", err)
+					}
+
+					partContents, _ := io.ReadAll(part)
+					contentRanges = append(contentRanges, part.Header.Get("Content-Range"))
+					parts = append(parts, string(partContents))
+					if !strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain") {
+						tt.Errorf("Expected the content type of the part to be [text/plain], but got [%s]", part.Header.Get("Content-Type"))
+					}
+				}
+
+				if strings.Join(contentRanges, ";") != strings.Join(testCase.ExpRanges, ";") || strings.Join(parts, ";") != strings.Join(testCase.ExpParts, ";") {
+					tt.Errorf("Expected the parts %v with the ranges %v, but got the parts %v with the ranges %v", testCase.ExpParts, testCase.ExpRanges, parts, contentRanges)
+				} else {
+					tt.Logf("Received the parts %v with the expected ranges", parts)
+				}
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ type RequestParseError struct {
 	Value string
 	// Refers to the actual error message raised.
 	Message string
+	// Response status code to be sent back to the client for the request that could not be parsed. If zero, the connection is closed without sending a response.
+	Status StatusCode
 }
 
 // Returns the error message associated with the instance of RequestParseError.
@@ -40,9 +42,97 @@ type ResponseError struct {
 	Value string
 	// Refers to the actual error message raised.
 	Message string
+	// Response status code to be sent back to the client for the request that could not be parsed. If zero, the connection is closed without sending a response.
+	Status StatusCode
 }
 
 // Returns the error message associated with the instance of RequestParseError.
 func (resErr ResponseError) Error() string {
 	return fmt.Sprintf("ResponseError :: Section: (%s) :: Value: (%s) :: %s", resErr.Section, resErr.Value, resErr.Message)
-}
\ No newline at end of file
+}
+
+// Custom error to represent the closure of a WebSocket connection, either by the client or by the server due to a protocol violation.
+type WebSocketCloseError struct {
+	// Status code sent in the close frame, indicating the reason for closing the connection.
+	Code int
+	// Textual reason sent in the close frame, if any.
+	Reason string
+}
+
+// Returns the error message associated with the instance of WebSocketCloseError.
+func (wce *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("WebSocketCloseError :: Code: (%d) :: %s", wce.Code, wce.Reason)
+}
+
+// Custom error to track errors raised while setting up the web server instance to listen for incoming requests.
+type ListenError struct {
+	// Address at which the web server instance was being set up to listen.
+	Address string
+	// Refers to the actual error message raised.
+	Message string
+}
+
+// Returns the error message associated with the instance of ListenError.
+func (le *ListenError) Error() string {
+	return fmt.Sprintf("ListenError :: Address - [%s] :: %s", le.Address, le.Message)
+}
+
+// Custom error to track errors raised while obtaining a certificate from an ACME server (like Let's Encrypt), including the problem documents returned by the server.
+type ACMEError struct {
+	// URL of the ACME server resource for which the error has been raised.
+	URL string
+	// Response status code returned by the ACME server. It is zero if no response has been received.
+	Status int
+	// Type of the problem returned by the ACME server, like "urn:ietf:params:acme:error:rateLimited". It is empty if the response did not contain a problem document.
+	Type string
+	// Refers to the actual error message raised.
+	Message string
+}
+
+// Returns the error message associated with the instance of ACMEError.
+func (ae *ACMEError) Error() string {
+	return fmt.Sprintf("ACMEError :: URL - [%s] :: Status: (%d) :: Type: (%s) :: %s", ae.URL, ae.Status, ae.Type, ae.Message)
+}
+
+// Custom error which can be returned by a handler to send an error response with the given status code back to the client. The response is sent using the error handler registered
+// for the status code using OnError(), or the default ErrorHandler which shows the message in the response body.
+type HttpError struct {
+	// Response status code (4xx or 5xx) to be sent back to the client.
+	Status StatusCode
+	// Message describing the error to the client. The default error description of the status code is shown if it is empty.
+	Message string
+	// Underlying error which caused the error response, if any. It is never sent to the client, but is logged by the server.
+	Err error
+}
+
+// Returns the error message associated with the instance of HttpError.
+func (he *HttpError) Error() string {
+	if he.Err != nil {
+		return fmt.Sprintf("HttpError :: Status: (%d) :: %s :: %s", he.Status, he.Message, he.Err.Error())
+	}
+	return fmt.Sprintf("HttpError :: Status: (%d) :: %s", he.Status, he.Message)
+}
+
+// Returns the underlying error of the HttpError instance, so that it can be checked using errors.Is() and errors.As().
+func (he *HttpError) Unwrap() error {
+	return he.Err
+}
+
+// Custom error to represent a panic raised by a handler, which is passed to the function set using ErrorTransformer() to choose the error response sent for the panic.
+type PanicError struct {
+	// Value recovered from the panic raised by the handler.
+	Value any
+}
+
+// Returns the error message associated with the instance of PanicError.
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("PanicError :: %v", pe.Value)
+}
+
+// Returns the value recovered from the panic if it is an error, so that it can be checked using errors.Is() and errors.As().
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
+	}
+	return nil
+}
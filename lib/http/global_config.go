@@ -1,14 +1,25 @@
 package http
 
 import (
+	"fmt"
+	"maps"
+	"mime"
+	"strings"
+	"sync"
 	"github.com/mkbworks/proteus/lib/config"
 )
 
 const (
 	ERROR_MSG_CONTENT_TYPE = "text/html"
+	JSON_CONTENT_TYPE = "application/json"
+	FORM_URLENCODED_CONTENT_TYPE = "application/x-www-form-urlencoded"
 	HEADER_LINE_SEPERATOR = "\r\n"
 	REQUEST_LINE_SEPERATOR = " "
 	HEADER_KEY_VALUE_SEPERATOR = ":"
+	HTTP_DATE_FORMAT = "Mon, 02 Jan 2006 15:04:05 GMT"
+	SET_COOKIE_HEADER = "Set-Cookie"
+	// Port number which makes the web server instance listen at the port number set in the "port" server default. A port number of zero makes it listen at a port assigned by the operating system.
+	DEFAULT_PORT_NUMBER = -1
 )
 
 // Collection of headers supported by the server that has a date value.
@@ -21,6 +32,10 @@ var ServerDefaults map[string]string
 var Versions map[string][]string
 // List of response status codes and their associated information.
 var ResponseStatusCodes []respStatus
+// Mutex to synchronize access to the server defaults and the list of allowed content types, which can be reloaded while requests are being processed.
+var configMutex sync.RWMutex
+// Path of the configuration file last loaded using LoadConfig(), which is loaded again when the configuration is reloaded. It is empty if no configuration file has been loaded.
+var configFilePath string
 
 // Initializes the global variables used in the 'http' package.
 func init() {
@@ -43,4 +58,76 @@ func init() {
 		}
 		ResponseStatusCodes = append(ResponseStatusCodes, newStat)
 	}
-}
\ No newline at end of file
+}
+
+// Loads the server settings from the JSON or YAML configuration file at the given path, along with the overrides set in the "PROTEUS_" environment variables, and applies them to the server defaults
+// and the list of allowed content types. The configuration must be loaded before the web server instances are created, as the settings in ServerConfig are read from the server defaults when an instance is created.
+// The static roots present in the configuration are defined for a server instance by passing the returned configuration to its ApplyConfig() method.
+func LoadConfig(path string) (*config.FileConfig, error) {
+	fileConfig, err := config.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	applyFileConfig(fileConfig, ServerDefaults, AllowedContentTypes)
+	configFilePath = path
+	return fileConfig, nil
+}
+
+// Adds the server defaults and content types present in the given configuration to the given maps.
+func applyFileConfig(fileConfig *config.FileConfig, serverDefaults map[string]string, contentTypes map[string]string) {
+	for key, value := range fileConfig.GetServerDefaults() {
+		serverDefaults[key] = value
+	}
+
+	for extension, contentType := range fileConfig.ContentTypes {
+		extension = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extension), "."))
+		if extension != "" {
+			contentTypes[extension] = strings.TrimSpace(contentType)
+		}
+	}
+}
+
+// Reloads the server defaults and the list of allowed content types from "config.json" and the configuration file last loaded using LoadConfig() (along with the "PROTEUS_" environment variables),
+// replacing the current values at once. The current values are left unchanged if the configuration could not be loaded.
+func reloadConfig() error {
+	serverConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	configMutex.RLock()
+	path := configFilePath
+	configMutex.RUnlock()
+	serverDefaults := maps.Clone(serverConfig.ServerDefaults)
+	contentTypes := maps.Clone(serverConfig.AllowedContentTypes)
+	if path != "" {
+		fileConfig, err := config.LoadFile(path)
+		if err != nil {
+			return err
+		}
+
+		applyFileConfig(fileConfig, serverDefaults, contentTypes)
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	ServerDefaults = serverDefaults
+	AllowedContentTypes = contentTypes
+	return nil
+}
+
+// Returns the given media type after checking if it is a valid media type. The function name is used as the prefix of the error message returned for an invalid media type.
+func parseContentType(funcName string, MediaType string) (string, error) {
+	MediaType = strings.TrimSpace(MediaType)
+	if _, _, err := mime.ParseMediaType(MediaType); err != nil {
+		ce := new(config.ConfigError)
+		ce.Message = fmt.Sprintf("%s: %s is not a valid media type", funcName, MediaType)
+		return "", ce
+	}
+
+	return MediaType, nil
+}
+
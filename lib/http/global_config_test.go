@@ -0,0 +1,209 @@
+package http
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test case to validate the loading of the server settings from JSON and YAML configuration files, along with the environment variable overrides.
+func Test_LoadConfig(t *testing.T) {
+	jsonConfig := `{ "host": "0.0.0.0", "port": 9090, "read_timeout": "5s", "max_body_size": 2048, "log_level": "debug", "content_types": { ".webp": "image/webp" }, "static_roots": [{ "route": "/assets", "directory": "./public" }] }`
+	yamlConfig := "host: 0.0.0.0\nport: 9090\nread_timeout: 5s\nmax_body_size: 2048\nlog_level: debug\ncontent_types:\n  webp: image/webp\nstatic_roots:\n  - route: /assets\n    directory: ./public\n"
+	testCases := []struct {
+		Name string
+		FileName string
+		Contents string
+		EnvPort string
+		ExpError bool
+		ExpPort string
+	} {
+		{ "JSON configuration file", "proteus.json", jsonConfig, "", false, "9090" },
+		{ "YAML configuration file", "proteus.yaml", yamlConfig, "", false, "9090" },
+		{ "Port overridden by environment variable", "proteus.yml", yamlConfig, "7070", false, "7070" },
+		{ "Invalid timeout value", "proteus.json", `{ "read_timeout": "five seconds" }`, "", true, "" },
+		{ "Unsupported file extension", "proteus.toml", `port = 9090`, "", true, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			originalDefaults := maps.Clone(ServerDefaults)
+			originalContentTypes := maps.Clone(AllowedContentTypes)
+			defer func() {
+				ServerDefaults = originalDefaults
+				AllowedContentTypes = originalContentTypes
+				configFilePath = ""
+			}()
+
+			if testCase.EnvPort != "" {
+				tt.Setenv("PROTEUS_PORT", testCase.EnvPort)
+			}
+
+			configPath := filepath.Join(tt.TempDir(), testCase.FileName)
+			err := os.WriteFile(configPath, []byte(testCase.Contents), 0644)
+			if err != nil {
+				tt.Fatalf("Error occurred while writing the configuration file - %v", err)
+			}
+
+			fileConfig, err := LoadConfig(configPath)
+			if testCase.ExpError {
+				if err == nil {
+					tt.Errorf("Expected an error while loading the configuration, but got none")
+				} else {
+					tt.Logf("Received the expected error - %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Fatalf("Was not expecting an error and yet received one - %v", err)
+			}
+
+			if getServerDefaults("port") != testCase.ExpPort || getServerDefaults("hostname") != "0.0.0.0" || getDefaultDuration("read_timeout").Seconds() != 5 || getMaxBodySize() != 2048 {
+				tt.Errorf("The server defaults %v do not contain the settings from the configuration file", ServerDefaults)
+			} else if AllowedContentTypes["webp"] != "image/webp" {
+				tt.Errorf("Expected the content type for webp to be [image/webp], but got [%s]", AllowedContentTypes["webp"])
+			} else if len(fileConfig.StaticRoots) != 1 || fileConfig.StaticRoots[0].Route != "/assets" {
+				tt.Errorf("Expected a single static root for /assets, but got %v", fileConfig.StaticRoots)
+			} else {
+				tt.Logf("The configuration has been loaded as expected")
+			}
+		})
+	}
+}
+
+// Test case to validate the reloading of the server defaults and the allowed content types from the configuration file, while the server instance is running.
+func Test_Server_ReloadConfig(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Contents string
+		ExpError bool
+		ExpContentType string
+		ExpMaxBodySize int64
+	} {
+		{ "New content type and body size limit", `{ "max_body_size": 4096, "content_types": { "proteus": "application/x-proteus" } }`, false, "application/x-proteus", 4096 },
+		{ "Content type removed from the configuration file", `{ "max_body_size": 4096 }`, false, "", 4096 },
+		{ "Invalid configuration file", `{ "max_body_size": "large" }`, true, "text/x-proteus", 1024 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			originalDefaults := maps.Clone(ServerDefaults)
+			originalContentTypes := maps.Clone(AllowedContentTypes)
+			defer func() {
+				ServerDefaults = originalDefaults
+				AllowedContentTypes = originalContentTypes
+				configFilePath = ""
+			}()
+
+			configPath := filepath.Join(tt.TempDir(), "proteus.json")
+			err := os.WriteFile(configPath, []byte(`{ "max_body_size": 1024, "content_types": { "proteus": "text/x-proteus" } }`), 0644)
+			if err != nil {
+				tt.Fatalf("Error occurred while writing the configuration file - %v", err)
+			}
+
+			_, err = LoadConfig(configPath)
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while loading the configuration and yet received one - %v", err)
+			}
+
+			testServer := NewServer()
+			testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+			err = os.WriteFile(configPath, []byte(testCase.Contents), 0644)
+			if err != nil {
+				tt.Fatalf("Error occurred while writing the configuration file - %v", err)
+			}
+
+			err = testServer.ReloadConfig()
+			if testCase.ExpError && err == nil {
+				tt.Errorf("Expected an error while reloading the configuration, but got none")
+			} else if !testCase.ExpError && err != nil {
+				tt.Errorf("Was not expecting an error while reloading the configuration and yet received one - %v", err)
+			} else if AllowedContentTypes["proteus"] != testCase.ExpContentType || getMaxBodySize() != testCase.ExpMaxBodySize {
+				tt.Errorf("Expected content type [%s] with maximum body size %d, but got content type [%s] with maximum body size %d", testCase.ExpContentType, testCase.ExpMaxBodySize, AllowedContentTypes["proteus"], getMaxBodySize())
+			} else if getServerDefaults("content_type") != originalDefaults["content_type"] {
+				tt.Errorf("Expected the server defaults from config.json to be retained, but got %v", ServerDefaults)
+			} else {
+				tt.Logf("The configuration has been reloaded as expected")
+			}
+		})
+	}
+}
+
+// Test case to validate the content types resolved for files, after registering content types and setting the default content type for a web server instance.
+func Test_Server_AddContentType(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Extension string
+		MediaType string
+		DefaultType string
+		FileName string
+		ExpError bool
+		ExpContentType string
+		ExpOtherContentType string
+	} {
+		{ "Registered content type", ".proteus", "application/x-proteus", "", "module.proteus", false, "application/x-proteus", "application/octet-stream" },
+		{ "Registered content type with an extension in upper case", "PROTEUS", "application/x-proteus", "", "module.proteus", false, "application/x-proteus", "application/octet-stream" },
+		{ "Content type known to the mime package", "", "", "", "image.webp", false, "image/webp", "image/webp" },
+		{ "Default content type for an unknown extension", "", "", "text/plain", "module.unknown", false, "text/plain", "application/octet-stream" },
+		{ "Empty file extension", " . ", "application/x-proteus", "", "module.proteus", true, "", "" },
+		{ "Invalid media type", ".proteus", "application/", "", "module.proteus", true, "", "" },
+		{ "Invalid default content type", "", "", "text/", "module.unknown", true, "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			originalDefaults := maps.Clone(ServerDefaults)
+			originalContentTypes := maps.Clone(AllowedContentTypes)
+			defer func() {
+				ServerDefaults = originalDefaults
+				AllowedContentTypes = originalContentTypes
+			}()
+
+			testServer := NewServer()
+			var err error
+			if testCase.MediaType != "" {
+				err = testServer.AddContentType(testCase.Extension, testCase.MediaType)
+			}
+			if testCase.DefaultType != "" {
+				err = testServer.SetDefaultContentType(testCase.DefaultType)
+			}
+
+			if testCase.ExpError {
+				if err == nil {
+					tt.Errorf("Expected an error while registering the content type, but got none")
+				} else {
+					tt.Logf("Received the expected error - %v", err)
+				}
+				return
+			}
+
+			filePath := filepath.Join(tt.TempDir(), testCase.FileName)
+			// Binary contents are written, so that the content sniffed for unknown extensions is not a more specific media type.
+			err = os.WriteFile(filePath, []byte("proteus\x00"), 0644)
+			if err != nil {
+				tt.Fatalf("Error occurred while writing the file - %v", err)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			testServer.attachResponse(testResponse)
+			otherResponse := newTestResponse(tt, "1.1")
+			NewServer().attachResponse(otherResponse)
+			contentType, _ := getContentType(filePath, testResponse.contentTypes, testResponse.defaultContentType)
+			if err = reloadConfig(); err != nil {
+				tt.Fatalf("Was not expecting an error while reloading the configuration and yet received one - %v", err)
+			}
+			reloadedType, _ := getContentType(filePath, testResponse.contentTypes, testResponse.defaultContentType)
+			otherType, _ := getContentType(filePath, otherResponse.contentTypes, otherResponse.defaultContentType)
+			if contentType != testCase.ExpContentType || reloadedType != testCase.ExpContentType {
+				tt.Errorf("Expected the content type to be [%s] before and after reloading, but got [%s] and [%s]", testCase.ExpContentType, contentType, reloadedType)
+			} else if otherType != testCase.ExpOtherContentType {
+				tt.Errorf("Expected the content type for another server to be [%s], but got [%s]", testCase.ExpOtherContentType, otherType)
+			} else {
+				tt.Logf("Received the content type [%s] as expected", contentType)
+			}
+		})
+	}
+}
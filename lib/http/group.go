@@ -0,0 +1,96 @@
+package http
+
+// Structure to represent a group of routes sharing a common route prefix and a common set of middlewares.
+type RouteGroup struct {
+	// Route prefix shared by all the routes defined in the group.
+	Prefix string
+	// Router instance where the routes of the group are defined.
+	router *Router
+	// Parent group of the current group. It is nil for groups created directly from the web server instance.
+	parent *RouteGroup
+	// Collection of middlewares to be executed for all the routes defined in the group and its sub-groups.
+	middlewares []Middleware
+}
+
+// Creates and returns pointer to a new route group with the given prefix. The middlewares given are executed for all routes defined in the group.
+func (srv *HttpServer) Group(Prefix string, middlewares ...Middleware) *RouteGroup {
+	return newRouteGroup(srv.innerRouter, nil, Prefix, middlewares)
+}
+
+// Creates and returns pointer to a new route group nested within the current group. The prefix of the new group is appended to the prefix of the current group.
+func (grp *RouteGroup) Group(Prefix string, middlewares ...Middleware) *RouteGroup {
+	return newRouteGroup(grp.router, grp, joinRoute(grp.Prefix, Prefix), middlewares)
+}
+
+// Adds the given middlewares to the route group. These middlewares are executed for all the routes in the group and its sub-groups, irrespective of whether the routes were defined before or after the middlewares were added.
+func (grp *RouteGroup) Use(middlewares ...Middleware) {
+	grp.middlewares = append(grp.middlewares, middlewares...)
+}
+
+// Creates a new GET endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Get(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("GET", routePath, handlerFunc, middlewares)
+}
+
+// Creates a new HEAD endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Head(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("HEAD", routePath, handlerFunc, middlewares)
+}
+
+// Creates a new POST endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Post(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("POST", routePath, handlerFunc, middlewares)
+}
+
+// Creates a new PUT endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Put(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("PUT", routePath, handlerFunc, middlewares)
+}
+
+// Creates a new PATCH endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Patch(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("PATCH", routePath, handlerFunc, middlewares)
+}
+
+// Creates a new DELETE endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Delete(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("DELETE", routePath, handlerFunc, middlewares)
+}
+
+// Creates a new OPTIONS endpoint at the given route path (relative to the group prefix) and sets the handler function to be invoked when the route is requested by the user.
+func (grp *RouteGroup) Options(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return grp.addRoute("OPTIONS", routePath, handlerFunc, middlewares)
+}
+
+// Adds a new dynamic route to the router for the given method and route path (relative to the group prefix).
+// The handler is wrapped so that the middlewares of the group (and its parent groups) are executed before the middlewares given for the route.
+func (grp *RouteGroup) addRoute(Method string, routePath string, handlerFunc Handler, middlewares []Middleware) error {
+	completeRoutePath := joinRoute(grp.Prefix, routePath)
+	if hasTrailingSlash(routePath) && completeRoutePath != "/" {
+		completeRoutePath += "/"
+	}
+	groupHandler := func(request *HttpRequest, response *HttpResponse) error {
+		routeMiddlewares := append(grp.getMiddlewares(), middlewares...)
+		return chainMiddlewares(handlerFunc, routeMiddlewares)(request, response)
+	}
+
+	groupRoute, err := grp.router.newDynamicRoute(Method, completeRoutePath, groupHandler)
+	if err != nil {
+		return grp.router.failRegistration(err)
+	}
+
+	// The route is reported with the name of the handler given, instead of the wrapper executing the middlewares of the group.
+	groupRoute.HandlerName = getHandlerName(handlerFunc)
+	return grp.router.insertRoute(groupRoute)
+}
+
+// Returns the collection of all middlewares applicable to the group, starting with the middlewares of the outermost parent group.
+func (grp *RouteGroup) getMiddlewares() []Middleware {
+	middlewares := make([]Middleware, 0)
+	if grp.parent != nil {
+		middlewares = append(middlewares, grp.parent.getMiddlewares()...)
+	}
+
+	middlewares = append(middlewares, grp.middlewares...)
+	return middlewares
+}
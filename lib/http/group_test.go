@@ -0,0 +1,49 @@
+package http
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test case to validate if the routes defined in a route group are registered with the group prefix and execute the group middlewares.
+func Test_RouteGroup_AddRoute(t *testing.T) {
+	testRouter := newRouter()
+	executionOrder := make([]string, 0)
+	newTestMiddleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *HttpRequest, res *HttpResponse) error {
+				executionOrder = append(executionOrder, name)
+				return next(req, res)
+			}
+		}
+	}
+
+	apiGroup := newRouteGroup(testRouter, nil, "/api", []Middleware{ newTestMiddleware("api") })
+	versionGroup := apiGroup.Group("/v1/")
+	err := versionGroup.Get("/users", func(req *HttpRequest, res *HttpResponse) error {
+		executionOrder = append(executionOrder, "handler")
+		return nil
+	}, newTestMiddleware("route"))
+	if err != nil {
+		t.Errorf("Was not expecting an error while adding the route, but got this instead - %v", err)
+		return
+	}
+
+	versionGroup.Use(newTestMiddleware("v1"))
+	testRequest := newTestRequest(t)
+	testRequest.Method = "GET"
+	testRequest.ResourcePath = "/api/v1/users"
+	handler, err := testRouter.matchRoute(testRequest)
+	if err != nil {
+		t.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+		return
+	}
+
+	handler(testRequest, newTestResponse(t, "1.1"))
+	expectedOrder := "api,v1,route,handler"
+	if strings.Join(executionOrder, ",") != expectedOrder {
+		t.Errorf("The execution order [%s] does not match the expected order [%s]", strings.Join(executionOrder, ","), expectedOrder)
+	} else {
+		t.Logf("The execution order [%s] matches the expected order [%s]", strings.Join(executionOrder, ","), expectedOrder)
+	}
+}
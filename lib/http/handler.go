@@ -2,35 +2,128 @@ package http
 
 import (
 	"strings"
+	"github.com/mkbworks/proteus/lib/fs"
 )
 
 // Represents a handler function that is executed once any received request is parsed. You can define different handlers for different routes and HTTP methods.
 type Handler func (*HttpRequest, *HttpResponse) error
 
+// Represents a middleware function that wraps a handler and returns a new handler. Middlewares can run logic before and/or after invoking the wrapped handler, or skip invoking it altogether.
+type Middleware func (Handler) Handler
+
+// Wraps the given handler with the given middlewares and returns the resulting handler. The first middleware in the slice is the outermost one and hence is the first to be executed.
+func chainMiddlewares(handler Handler, middlewares []Middleware) Handler {
+	for index := len(middlewares) - 1; index >= 0; index-- {
+		if middlewares[index] != nil {
+			handler = middlewares[index](handler)
+		}
+	}
+
+	return handler
+}
+
 // Handler to fetch static file and send the file contents as response back to the client.
+// If a folder is requested, the first index file configured for the static route that is present in the folder is sent. Otherwise, a HTML listing of the folder contents is sent when directory listing is enabled for the static route.
+// Request paths which do not match any file are answered with the single-page application entry point, if one has been configured for the static route.
+// If precompressed files are enabled for the static route, the precompressed version of the file accepted by the client (like app.js.br or app.js.gz) is sent with the Content-Encoding header instead.
+// An ETag is generated for the file and a 304 (Not Modified) response is sent back if the conditional headers in the request match the current state of the file. Small files are served from the cache of the static route, if one has been set, and the Cache-Control and Expires headers are sent as configured for the static route.
 var StaticFileHandler = func (request *HttpRequest, response *HttpResponse) error {
 	targetFilePath := request.staticFilePath
 	targetFilePath = strings.TrimSpace(targetFilePath)
-	isCondGet, err := request.isConditionalGet(targetFilePath)
+	var staticOptions *StaticOptions
+	if request.staticRoute != nil {
+		staticOptions = request.staticRoute.StaticOptions
+	}
+
+	if targetFilePath == "" || (request.staticRoute != nil && !request.staticRoute.isSymlinkAllowed(targetFilePath)) {
+		response.Status(StatusNotFound)
+		return handleError(request, response)
+	}
+
+	fileSystem := request.staticRoute.getFileSystem()
+	if PathType, err := fs.GetPathTypeFS(fileSystem, targetFilePath); err == nil && PathType == fs.FOLDER_TYPE_PATH {
+		indexFilePath, found := resolveIndexFile(fileSystem, targetFilePath, staticOptions)
+		if !found {
+			fallbackFilePath, hasFallback := request.staticRoute.getFallbackFilePath(fileSystem)
+			if !hasFallback || staticOptions.DirectoryListing {
+				return sendDirectoryListing(request, response)
+			}
+			indexFilePath = fallbackFilePath
+		}
+
+		targetFilePath = indexFilePath
+		if request.staticRoute != nil && !request.staticRoute.isSymlinkAllowed(targetFilePath) {
+			response.Status(StatusNotFound)
+			return handleError(request, response)
+		}
+	} else if err != nil {
+		if fallbackFilePath, hasFallback := request.staticRoute.getFallbackFilePath(fileSystem); hasFallback {
+			targetFilePath = fallbackFilePath
+		}
+	}
+
+	file, err := fs.GetFileFS(fileSystem, targetFilePath, "", true)
+	if err != nil {
+		response.Status(StatusNotFound)
+		return handleError(request, response)
+	}
+
+	fileMediaType := getFileContentType(fileSystem, targetFilePath, response.contentTypes, response.defaultContentType)
+	file.ContentType = fileMediaType
+	if precompressedFilePath, encoding, found := request.staticRoute.getPrecompressedFile(fileSystem, targetFilePath, response); found {
+		if precompressedFile, err := fs.GetFileFS(fileSystem, precompressedFilePath, fileMediaType, true); err == nil {
+			// The precompressed file is sent with its own ETag and size, but with the media type of the original file.
+			targetFilePath, file = precompressedFilePath, precompressedFile
+			response.Headers.Add("Content-Encoding", encoding)
+		}
+	}
+
+	ETag, err := generateETag(fileSystem, targetFilePath, file)
 	if err != nil {
 		return err
 	}
 
-	if isCondGet {
-		response.Status(StatusOK)
-		return response.SendFile(targetFilePath, false)
-	} else {
+	response.Headers.Add("ETag", ETag)
+	staticOptions.addCacheHeaders(response)
+	if strings.EqualFold(getServerDefaults("compression"), "on") && isCompressible(fileMediaType) {
+		// The response body may be compressed depending on the Accept-Encoding header, even if the file sent in this response is too small to be compressed.
+		response.addVary("Accept-Encoding")
+	}
+
+	if request.isNotModified(file, ETag) {
 		response.Status(StatusNotModified)
-		return response.SendFile(targetFilePath, true)
+		return response.serveFile(fileSystem, targetFilePath, file, true)
 	}
+
+	response.Status(StatusOK)
+	if staticOptions != nil && staticOptions.Cache != nil && !strings.EqualFold(request.Method, "HEAD") {
+		if cachedFile, found := staticOptions.Cache.load(request.staticRoute.getCacheKey(targetFilePath), fileSystem, targetFilePath, file); found {
+			return response.serveFile(fileSystem, targetFilePath, cachedFile, false)
+		}
+	}
+
+	return response.serveFile(fileSystem, targetFilePath, file, false)
 }
 
 // Default error handler logic to be implemented for sending an error response back to client.
 var ErrorHandler = func (request *HttpRequest, response *HttpResponse) error {
-	if response.StatusCode == int(StatusMethodNotAllowed) {
+	if _, exists := response.Headers.Get("Allow"); !exists && response.StatusCode == int(StatusMethodNotAllowed) {
 		response.Headers.Add("Allow", getAllowedMethods(response.Version))
-	} 
+	}
 
 	statusCode := StatusCode(response.StatusCode)
+	if response.httpError != nil {
+		return response.SendError(statusCode.getErrorContent(response.httpError.Message))
+	}
 	return response.SendError(statusCode.GetErrorContent())
-}
\ No newline at end of file
+}
+
+// Sends the error response for the status code set in the response, using the error handler registered for the status code in the web server instance.
+// If no error handler has been registered for the status code, the default ErrorHandler is used.
+func handleError(request *HttpRequest, response *HttpResponse) error {
+	if errorHandler, ok := response.errorHandlers[StatusCode(response.StatusCode)]; ok {
+		return errorHandler(request, response)
+	}
+
+	return ErrorHandler(request, response)
+}
@@ -2,12 +2,16 @@ package http
 
 import (
 	"net/textproto"
+	"slices"
 	"strings"
 )
 
 // Represents a collection of headers (request or response).
 type Headers map[string][]string
 
+// Collection of the response headers whose values cannot be combined into a single header line, since the values can contain commas themselves. Each value of these headers is sent in a separate header line.
+var repeatedHeaders = []string{ SET_COOKIE_HEADER, "Www-Authenticate", "Proxy-Authenticate" }
+
 // Add a new key-value pair to the collection of headers.
 func (headers Headers) Add(key string, value string) {
 	key = textproto.CanonicalMIMEHeaderKey(key)
@@ -31,6 +35,44 @@ func (headers Headers) Get(key string) (string, bool) {
 	}
 }
 
+// Returns the lines in which the values of the given header must be sent. The values are joined by commas in a single line, unless the header cannot be combined.
+func (headers Headers) getHeaderLines(key string) []string {
+	values, ok := headers[key]
+	if !ok || slices.Contains(repeatedHeaders, key) {
+		return values
+	}
+
+	return []string{ strings.Join(headers[key], ",") }
+}
+
+// Splits the given header value into the elements of the comma-separated list it contains, ignoring the commas present within quoted strings. Empty elements are skipped.
+func splitHeaderList(value string) []string {
+	elements := make([]string, 0)
+	isQuoted := false
+	isEscaped := false
+	elementStart := 0
+	for index, char := range value {
+		if isEscaped {
+			isEscaped = false
+		} else if char == '\\' && isQuoted {
+			isEscaped = true
+		} else if char == '"' {
+			isQuoted = !isQuoted
+		} else if char == ',' && !isQuoted {
+			if element := strings.TrimSpace(value[elementStart:index]); element != "" {
+				elements = append(elements, element)
+			}
+			elementStart = index + 1
+		}
+	}
+
+	if element := strings.TrimSpace(value[elementStart:]); element != "" {
+		elements = append(elements, element)
+	}
+
+	return elements
+}
+
 // Returns the number of header key-value pairs in the collection.
 func (headers Headers) Length() int {
 	return len(headers)
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Structure to represent a single check performed by a health endpoint, like checking the connection to a database.
+type HealthCheck struct {
+	// Name of the check, which is used to identify the check in the health report.
+	Name string
+	// Function performing the check, which returns an error if the check fails. The given context is cancelled when the timeout of the check elapses or the client disconnects.
+	Check func(ctx context.Context) error
+	// Maximum duration allowed for the check, after which the check is considered to have failed. If zero, the "health_check_timeout" server default is used.
+	Timeout time.Duration
+	// Boolean value to indicate if the failure of the check makes the server unhealthy, in which case a 503 (Service Unavailable) response is sent. The failure of a check that is not critical
+	// is only reported, with the overall status of the server reported as degraded.
+	Critical bool
+}
+
+// Structure to represent the result of a single health check, as sent in the health report.
+type healthCheckResult struct {
+	// Name of the check.
+	Name string `json:"name"`
+	// Status of the check, which is either "pass" or "fail".
+	Status string `json:"status"`
+	// Boolean value to indicate if the check is critical.
+	Critical bool `json:"critical"`
+	// Time taken by the check.
+	Duration string `json:"duration"`
+	// Error returned by the check, if it has failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Structure to represent the health report sent by a health endpoint.
+type healthReport struct {
+	// Overall status of the server, which is "pass" if all the checks passed, "degraded" if only checks that are not critical failed and "fail" if any critical check failed.
+	Status string `json:"status"`
+	// Results of the individual checks, in the order in which the checks were given.
+	Checks []healthCheckResult `json:"checks"`
+}
+
+// Runs the given health check with its timeout and returns the result of the check. A check which panics is considered to have failed.
+func (check *HealthCheck) run(ctx context.Context) healthCheckResult {
+	result := healthCheckResult{ Name: check.Name, Status: "pass", Critical: check.Critical }
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = getDefaultDuration("health_check_timeout")
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	startTime := time.Now()
+	checkErr := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				checkErr <- fmt.Errorf("health check panicked :: %v", recovered)
+			}
+		}()
+		checkErr <- check.Check(ctx)
+	}()
+
+	// The check is abandoned once its context is done, even if the check function ignores the context.
+	var err error
+	select {
+	case err = <-checkErr:
+	case <-ctx.Done():
+		err = fmt.Errorf("health check did not complete in time :: %v", ctx.Err())
+	}
+
+	result.Duration = time.Since(startTime).String()
+	if err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Adds a GET route at the given route path (like "/healthz" or "/readyz"), which runs the given health checks concurrently and sends a JSON report of their results. The response status is 200 OK,
+// unless any critical check fails, in which case a 503 (Service Unavailable) response is sent. A route without checks reports that the server is alive, which can be used as a liveness probe.
+func (srv *HttpServer) Health(routePath string, checks ...HealthCheck) error {
+	return srv.Get(routePath, func(request *HttpRequest, response *HttpResponse) error {
+		report := healthReport{ Status: "pass", Checks: make([]healthCheckResult, len(checks)) }
+		var waitGroup sync.WaitGroup
+		for index := range checks {
+			waitGroup.Add(1)
+			go func(index int) {
+				defer waitGroup.Done()
+				report.Checks[index] = checks[index].run(request.Context())
+			}(index)
+		}
+		waitGroup.Wait()
+
+		status := StatusOK
+		for _, result := range report.Checks {
+			if result.Status == "fail" && result.Critical {
+				report.Status = "fail"
+				status = StatusServiceUnavailable
+			} else if result.Status == "fail" && report.Status == "pass" {
+				report.Status = "degraded"
+			}
+		}
+
+		// Probes must always receive the current health of the server.
+		response.Headers["Cache-Control"] = []string{ "no-store" }
+		return response.JSON(status, report)
+	})
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the health report sent by health endpoints, based on the results of their critical and non-critical checks.
+func Test_Server_Health(t *testing.T) {
+	passingCheck := func(ctx context.Context) error {
+		return nil
+	}
+	failingCheck := func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}
+	slowCheck := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Health("/livez")
+	testServer.Health("/readyz", HealthCheck{ Name: "database", Check: passingCheck, Critical: true }, HealthCheck{ Name: "cache", Check: failingCheck })
+	testServer.Health("/healthz", HealthCheck{ Name: "database", Check: failingCheck, Critical: true }, HealthCheck{ Name: "cache", Check: passingCheck })
+	testServer.Health("/slowz", HealthCheck{ Name: "queue", Check: slowCheck, Timeout: 10 * time.Millisecond, Critical: true })
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		ExpStatus int
+		ExpReport string
+		ExpFailures []string
+	} {
+		{ "Health endpoint without checks", "/livez", int(StatusOK), "pass", []string{} },
+		{ "Failure of a check that is not critical", "/readyz", int(StatusOK), "degraded", []string{ "cache" } },
+		{ "Failure of a critical check", "/healthz", int(StatusServiceUnavailable), "fail", []string{ "database" } },
+		{ "Critical check exceeding its timeout", "/slowz", int(StatusServiceUnavailable), "fail", []string{ "queue" } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			_, responseBody, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			var report healthReport
+			err := json.Unmarshal([]byte(responseBody), &report)
+			failures := make([]string, 0)
+			for _, result := range report.Checks {
+				if result.Status == "fail" {
+					failures = append(failures, result.Name)
+				}
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error while parsing the health report [%s], but got this instead - %v", responseBody, err)
+			} else if testResponse.StatusCode != testCase.ExpStatus || report.Status != testCase.ExpReport {
+				tt.Errorf("Expected status %d with report status [%s], but got status %d with report status [%s]", testCase.ExpStatus, testCase.ExpReport, testResponse.StatusCode, report.Status)
+			} else if strings.Join(failures, ",") != strings.Join(testCase.ExpFailures, ",") {
+				tt.Errorf("Expected the failed checks to be %v, but got %v", testCase.ExpFailures, failures)
+			} else {
+				tt.Logf("Received status %d with report status [%s] as expected", testResponse.StatusCode, report.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"runtime/debug"
+	"time"
+)
+
+// Details of a request passed to the hooks registered using OnRequest(), once the request has been read completely.
+type RequestEvent struct {
+	// Time at which the server started reading the request.
+	StartTime time.Time
+	// Time taken to read the request line, the headers and the body of the request.
+	ReadDuration time.Duration
+	// Number of bytes in the request body.
+	BodySize int
+}
+
+// Details of a response passed to the hooks registered using OnResponse(), once the response has been sent.
+type ResponseEvent struct {
+	// Time at which the server started reading the request.
+	StartTime time.Time
+	// Time taken to read and process the request and send the response.
+	Duration time.Duration
+	// Status code of the response sent.
+	Status int
+	// Number of bytes of the response body written to the client.
+	BodySize int
+	// Error occurred while sending the response, if any. The response may not have been received completely by the client if it is not nil.
+	Error error
+}
+
+// Details of a client connection passed to the hooks registered using OnConnectionClose(), once the connection has been closed.
+type ConnectionEvent struct {
+	// Network address of the client.
+	RemoteAddress net.Addr
+	// Time at which the connection was accepted.
+	OpenedAt time.Time
+	// Duration for which the connection was open.
+	Duration time.Duration
+	// Number of requests processed on the connection, including the requests received as HTTP/2 streams.
+	RequestCount int
+}
+
+// Registers a hook to be invoked for every request read by the web server instance, before the request is routed to its handler. The hook can inspect the request and add headers to the response
+// (like a correlation header), without having to be written as a middleware. Hooks are invoked in the order in which they were registered and must be registered before the server starts listening.
+func (srv *HttpServer) OnRequest(hook func(req *HttpRequest, res *HttpResponse, event RequestEvent)) {
+	srv.requestHooks = append(srv.requestHooks, hook)
+}
+
+// Registers a hook to be invoked for every request processed by the web server instance, once its response has been sent. This can be used for audit logging or to record metrics like the latency and the size
+// of the responses. Hooks are invoked in the order in which they were registered and must be registered before the server starts listening.
+func (srv *HttpServer) OnResponse(hook func(req *HttpRequest, res *HttpResponse, event ResponseEvent)) {
+	srv.responseHooks = append(srv.responseHooks, hook)
+}
+
+// Registers a hook to be invoked once a client connection accepted by the web server instance has been closed. Hooks are invoked in the order in which they were registered and must be registered
+// before the server starts listening.
+func (srv *HttpServer) OnConnectionClose(hook func(event ConnectionEvent)) {
+	srv.connectionCloseHooks = append(srv.connectionCloseHooks, hook)
+}
+
+// Invokes the hooks registered using OnRequest() for the given request, which was read starting from the given time.
+func (srv *HttpServer) runRequestHooks(httpRequest *HttpRequest, httpResponse *HttpResponse, RequestStartTime time.Time) {
+	if len(srv.requestHooks) == 0 {
+		return
+	}
+
+	event := RequestEvent{ StartTime: RequestStartTime, ReadDuration: time.Since(RequestStartTime), BodySize: len(httpRequest.Body) }
+	for _, hook := range srv.requestHooks {
+		srv.invokeHook("OnRequest", func() { hook(httpRequest, httpResponse, event) })
+	}
+}
+
+// Invokes the hooks registered using OnResponse() for the given request and its response, where err is the error occurred while sending the response (if any).
+func (srv *HttpServer) runResponseHooks(httpRequest *HttpRequest, httpResponse *HttpResponse, RequestStartTime time.Time, err error) {
+	if len(srv.responseHooks) == 0 {
+		return
+	}
+
+	event := ResponseEvent{ StartTime: RequestStartTime, Duration: time.Since(RequestStartTime), Status: httpResponse.StatusCode, BodySize: httpResponse.bodySize, Error: err }
+	for _, hook := range srv.responseHooks {
+		srv.invokeHook("OnResponse", func() { hook(httpRequest, httpResponse, event) })
+	}
+}
+
+// Invokes the hooks registered using OnConnectionClose() for the given client connection, which was accepted at the given time.
+func (srv *HttpServer) runConnectionCloseHooks(ClientConnection net.Conn, OpenedAt time.Time, RequestCount int) {
+	if len(srv.connectionCloseHooks) == 0 {
+		return
+	}
+
+	event := ConnectionEvent{ RemoteAddress: ClientConnection.RemoteAddr(), OpenedAt: OpenedAt, Duration: time.Since(OpenedAt), RequestCount: RequestCount }
+	for _, hook := range srv.connectionCloseHooks {
+		srv.invokeHook("OnConnectionClose", func() { hook(event) })
+	}
+}
+
+// Invokes the given hook and recovers from any panic raised by it, so that a faulty hook does not bring down the web server instance. The stack trace of the panic is logged.
+func (srv *HttpServer) invokeHook(HookType string, hook func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			srv.LogError("Panic occurred while invoking the lifecycle hook", "hook", HookType, "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		}
+	}()
+
+	hook()
+}
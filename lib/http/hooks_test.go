@@ -0,0 +1,110 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test case to validate the details passed to the lifecycle hooks for the requests processed on a client connection, and that a panic raised by a hook does not affect the response.
+func Test_Server_LifecycleHooks(t *testing.T) {
+	var hookMutex sync.Mutex
+	requestEvents := make([]RequestEvent, 0)
+	responseEvents := make([]ResponseEvent, 0)
+	connectionClosed := make(chan ConnectionEvent, 1)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(lockedBuffer), LevelDebug, TextLogFormat))
+	testServer.Post("/echo", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Body = req.Body
+		return nil
+	})
+	testServer.OnRequest(func(req *HttpRequest, res *HttpResponse, event RequestEvent) {
+		hookMutex.Lock()
+		defer hookMutex.Unlock()
+		requestEvents = append(requestEvents, event)
+		res.Headers.Add("X-Audited", "true")
+	})
+	testServer.OnRequest(func(req *HttpRequest, res *HttpResponse, event RequestEvent) {
+		panic("faulty hook")
+	})
+	testServer.OnResponse(func(req *HttpRequest, res *HttpResponse, event ResponseEvent) {
+		hookMutex.Lock()
+		defer hookMutex.Unlock()
+		responseEvents = append(responseEvents, event)
+	})
+	testServer.OnConnectionClose(func(event ConnectionEvent) {
+		connectionClosed <- event
+	})
+
+	if err := testServer.ListenAndServeAsync(0, "127.0.0.1"); err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	connection, err := net.Dial("tcp", testServer.Addr().String())
+	if err != nil {
+		t.Fatalf("Was not expecting an error while connecting to the server, but got this instead - %v", err)
+	}
+	connection.SetDeadline(time.Now().Add(2 * time.Second))
+	reader := bufio.NewReader(connection)
+	testCases := []struct {
+		Name string
+		Body string
+		ExpStatus int
+	} {
+		{ "Request with a body", "hello", int(StatusOK) },
+		{ "Request with a larger body", "hello world", int(StatusOK) },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection.Write([]byte("POST /echo HTTP/1.1\r\nHost: localhost\r\nContent-Length: " + strconv.Itoa(len(testCase.Body)) + "\r\n\r\n" + testCase.Body))
+			var response bytes.Buffer
+			for {
+				line, err := reader.ReadString('\n')
+				response.WriteString(line)
+				if err != nil || line == "\r\n" {
+					break
+				}
+			}
+			body := make([]byte, len(testCase.Body))
+			io.ReadFull(reader, body)
+			if !strings.HasPrefix(response.String(), "HTTP/1.1 200") || !strings.Contains(response.String(), "X-Audited: true") || string(body) != testCase.Body {
+				tt.Errorf("Expected a 200 response with the header added by the request hook, but got this instead - %s%s", response.String(), string(body))
+			} else {
+				tt.Logf("Received the response with the header added by the request hook as expected")
+			}
+		})
+	}
+
+	connection.Close()
+	var connectionEvent ConnectionEvent
+	select {
+	case connectionEvent = <-connectionClosed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the connection close hook to be invoked, but it was not")
+	}
+
+	hookMutex.Lock()
+	defer hookMutex.Unlock()
+	if connectionEvent.RequestCount != len(testCases) || connectionEvent.RemoteAddress == nil || connectionEvent.Duration <= 0 {
+		t.Errorf("Expected the connection close event to report %d requests, but got %+v", len(testCases), connectionEvent)
+	} else if len(requestEvents) != len(testCases) || len(responseEvents) != len(testCases) {
+		t.Errorf("Expected %d request and response events, but got %d and %d", len(testCases), len(requestEvents), len(responseEvents))
+	} else {
+		for index, testCase := range testCases {
+			if requestEvents[index].BodySize != len(testCase.Body) || responseEvents[index].BodySize != len(testCase.Body) || responseEvents[index].Status != testCase.ExpStatus || responseEvents[index].Error != nil {
+				t.Errorf("The events %+v and %+v do not match the request [%s]", requestEvents[index], responseEvents[index], testCase.Name)
+			} else if responseEvents[index].Duration < requestEvents[index].ReadDuration || !responseEvents[index].StartTime.Equal(requestEvents[index].StartTime) {
+				t.Errorf("The timings of the events %+v and %+v are not consistent", requestEvents[index], responseEvents[index])
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+	"golang.org/x/net/http2"
+)
+
+// Version number of HTTP/2, as listed in the versions supported by the web server.
+const HTTP2_VERSION = "2.0"
+
+// Protocol identifier of HTTP/2 over TLS, negotiated using ALPN.
+const HTTP2_ALPN_PROTOCOL = "h2"
+
+// Protocol identifier of HTTP/2 over cleartext TCP, used in the Upgrade header of HTTP/1.1 requests.
+const HTTP2_CLEARTEXT_PROTOCOL = "h2c"
+
+// Collection of connection-specific headers which must not be sent in HTTP/2 responses.
+var http2ConnectionHeaders = []string{ "Connection", "Keep-Alive", "Proxy-Connection", "Transfer-Encoding", "Upgrade" }
+
+// Adapter which maps every HTTP/2 stream received on a connection onto the handlers of the web server instance.
+type http2Handler struct {
+	// Web server instance processing the requests received as HTTP/2 streams.
+	server *HttpServer
+	// Number of requests received as HTTP/2 streams on the connection.
+	requestCount atomic.Int64
+}
+
+// Processes the request received as a HTTP/2 stream, in the same way as the requests received over HTTP/1.x.
+func (handler *http2Handler) ServeHTTP(writer nethttp.ResponseWriter, request *nethttp.Request) {
+	handler.requestCount.Add(1)
+	handler.server.handleHTTP2Request(writer, request)
+}
+
+// Network connection whose bytes are read from the given buffered reader, so that the bytes already buffered while detecting the protocol are not lost.
+type bufferedConnection struct {
+	net.Conn
+	// Buffered reader wrapping the network connection.
+	reader *bufio.Reader
+}
+
+// Reads the bytes received on the connection, starting with the bytes already buffered.
+func (conn *bufferedConnection) Read(data []byte) (int, error) {
+	return conn.reader.Read(data)
+}
+
+// Adds the HTTP/2 and HTTP/1.1 ALPN protocol identifiers to the given TLS configuration if HTTP/2 is enabled for the web server instance.
+func (srv *HttpServer) configureHTTP2(tlsConfig *tls.Config) {
+	if !srv.Config.HTTP2 {
+		return
+	}
+
+	if !slices.Contains(tlsConfig.NextProtos, HTTP2_ALPN_PROTOCOL) {
+		tlsConfig.NextProtos = append([]string{ HTTP2_ALPN_PROTOCOL }, tlsConfig.NextProtos...)
+	}
+
+	if !slices.Contains(tlsConfig.NextProtos, "http/1.1") {
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, "http/1.1")
+	}
+}
+
+// Serves the given client connection using HTTP/2 until the client closes it or the server shuts down. The request read from an HTTP/1.1 connection being upgraded to h2c, if any, is processed as the first stream.
+// The number of requests received as HTTP/2 streams on the connection is returned.
+func (srv *HttpServer) serveHTTP2(ClientConnection net.Conn, upgradeRequest *nethttp.Request, settings []byte) int {
+	ClientConnection.SetDeadline(time.Time{})
+	server := new(http2.Server)
+	server.IdleTimeout = srv.Config.IdleTimeout
+	if srv.Config.MinWriteRate > 0 {
+		server.WriteByteTimeout = srv.Config.MinWriteRateGracePeriod
+	}
+	baseConfig := new(nethttp.Server)
+	baseConfig.ReadTimeout = srv.Config.ReadTimeout
+	baseConfig.WriteTimeout = srv.Config.WriteTimeout
+	handler := &http2Handler{ server: srv }
+	server.ServeConn(ClientConnection, &http2.ServeConnOpts{
+		Context: srv.baseContext,
+		BaseConfig: baseConfig,
+		Handler: handler,
+		UpgradeRequest: upgradeRequest,
+		Settings: settings,
+	})
+	return int(handler.requestCount.Load())
+}
+
+// Processes a single request received as a HTTP/2 stream and sends its response back on the same stream.
+func (srv *HttpServer) handleHTTP2Request(writer nethttp.ResponseWriter, request *nethttp.Request) {
+	requestStartTime := time.Now()
+	httpRequest, err := newHTTP2Request(request, srv.innerRouter)
+	httpResponse := newHTTP2Response(writer, httpRequest)
+	if err != nil {
+		srv.LogError(err.Error())
+		if reqError, ok := err.(*RequestParseError); ok && reqError.Status != 0 {
+			srv.attachResponse(httpResponse)
+			httpResponse.Status(reqError.Status)
+			handleError(httpRequest, httpResponse)
+			srv.Log(httpRequest, httpResponse)
+		}
+		return
+	}
+
+	httpRequest.ctx = request.Context()
+	httpResponse.ctx = request.Context()
+	srv.assignRequestID(httpRequest, httpResponse)
+	srv.runRequestHooks(httpRequest, httpResponse, requestStartTime)
+	srv.processRequest(httpRequest, httpResponse)
+	err = httpResponse.end()
+	if cleanupErr := httpRequest.cleanupMultipartForm(); cleanupErr != nil {
+		srv.getRequestLogger(httpRequest).Error(cleanupErr.Error())
+	}
+
+	srv.logAccess(httpRequest, httpResponse, requestStartTime)
+	srv.runResponseHooks(httpRequest, httpResponse, requestStartTime, err)
+	if err != nil {
+		srv.getRequestLogger(httpRequest).Error(err.Error())
+		return
+	}
+
+	srv.Log(httpRequest, httpResponse)
+}
+
+// Checks if the bytes received on the connection start with the HTTP/2 client connection preface, which is sent by clients using HTTP/2 over cleartext TCP with prior knowledge.
+// The bytes are compared one at a time, so that an HTTP/1.x request shorter than the connection preface does not block the check.
+func hasHTTP2Preface(reader *bufio.Reader) bool {
+	for index := 1; index <= len(http2.ClientPreface); index++ {
+		peekedBytes, err := reader.Peek(index)
+		if err != nil || peekedBytes[index - 1] != http2.ClientPreface[index - 1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Checks if the request asks for the connection to be upgraded to HTTP/2 over cleartext TCP (h2c) and returns the decoded contents of its HTTP2-Settings header.
+func (req *HttpRequest) getHTTP2Upgrade() ([]byte, bool) {
+	if !strings.EqualFold(strings.TrimSpace(req.Version), "1.1") || !hasHeaderToken(req.Headers, "Upgrade", HTTP2_CLEARTEXT_PROTOCOL) || !hasHeaderToken(req.Headers, "Connection", "HTTP2-Settings") {
+		return nil, false
+	}
+
+	settingsValue, ok := req.Headers.Get("HTTP2-Settings")
+	if !ok {
+		return nil, false
+	}
+
+	settings, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(settingsValue), "="))
+	if err != nil {
+		return nil, false
+	}
+
+	return settings, true
+}
+
+// Upgrades the connection of the given HTTP/1.1 request to HTTP/2 over cleartext TCP (h2c) by sending the 101 (Switching Protocols) response, and serves the connection using HTTP/2 thereafter.
+// The request is processed as the first HTTP/2 stream of the connection. The number of requests received as HTTP/2 streams on the connection is returned.
+func (srv *HttpServer) upgradeToHTTP2(ClientConnection net.Conn, reader *bufio.Reader, httpRequest *HttpRequest, settings []byte) (int, error) {
+	_, err := ClientConnection.Write([]byte("HTTP/1.1 101 Switching Protocols" + HEADER_LINE_SEPERATOR + "Connection: Upgrade" + HEADER_LINE_SEPERATOR + "Upgrade: " + HTTP2_CLEARTEXT_PROTOCOL + HEADER_LINE_SEPERATOR + HEADER_LINE_SEPERATOR))
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "RespWrite"
+		resErr.Value = ""
+		resErr.Message = "Error while writing the response to upgrade the connection to HTTP/2 :: " + err.Error()
+		return 0, resErr
+	}
+
+	return srv.serveHTTP2(&bufferedConnection{ Conn: ClientConnection, reader: reader }, httpRequest.toHTTP2UpgradeRequest(), settings), nil
+}
+
+// Returns the request upgraded to h2c in the form expected by the HTTP/2 connection, so that it can be processed as the first stream.
+func (req *HttpRequest) toHTTP2UpgradeRequest() *nethttp.Request {
+	requestURI := req.getRawPath()
+	if req.RawQuery != "" {
+		requestURI += "?" + req.RawQuery
+	}
+
+	upgradeRequest := new(nethttp.Request)
+	upgradeRequest.Method = req.Method
+	upgradeRequest.RequestURI = requestURI
+	upgradeRequest.URL, _ = url.ParseRequestURI(requestURI)
+	if upgradeRequest.URL == nil {
+		upgradeRequest.URL = &url.URL{ Path: req.ResourcePath }
+	}
+	upgradeRequest.Proto = "HTTP/1.1"
+	upgradeRequest.ProtoMajor = 1
+	upgradeRequest.ProtoMinor = 1
+	upgradeRequest.Header = make(nethttp.Header)
+	for key, values := range req.Headers {
+		if key == "Host" || slices.Contains(http2ConnectionHeaders, key) || key == "Http2-Settings" {
+			continue
+		}
+		upgradeRequest.Header[key] = []string{ strings.Join(values, ",") }
+	}
+	upgradeRequest.Host, _ = req.Headers.Get("Host")
+	upgradeRequest.RemoteAddr = req.ClientAddress
+	upgradeRequest.ContentLength = int64(len(req.Body))
+	upgradeRequest.Body = nethttp.NoBody
+	if len(req.Body) > 0 {
+		upgradeRequest.Body = io.NopCloser(bytes.NewReader(req.Body))
+	}
+
+	return upgradeRequest
+}
+
+// Sends the status code and the headers of the response on the HTTP/2 stream. Connection-specific headers are not sent, since they are not allowed in HTTP/2.
+func (res *HttpResponse) writeHTTP2Headers() {
+	streamHeaders := res.http2Writer.Header()
+	for key := range res.Headers {
+		if slices.Contains(http2ConnectionHeaders, key) {
+			continue
+		}
+
+		streamHeaders[key] = append([]string{}, res.Headers.getHeaderLines(key)...)
+	}
+
+	res.http2Writer.WriteHeader(res.StatusCode)
+}
+
+// Sends the response data buffered in the HTTP/2 stream to the client.
+func (res *HttpResponse) flushHTTP2() {
+	if flusher, ok := res.http2Writer.(nethttp.Flusher); ok {
+		flusher.Flush()
+	}
+}
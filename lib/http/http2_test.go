@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"golang.org/x/net/http2"
+)
+
+// Test case to validate the detection of the HTTP/2 client connection preface.
+func Test_HasHTTP2Preface(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Input string
+		ExpResult bool
+	} {
+		{ "HTTP/2 connection preface", http2.ClientPreface + "frames", true },
+		{ "HTTP/1.1 request", "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n", false },
+		{ "HTTP/1.0 request shorter than the preface", "PUT / HTTP/1.0\r\n\r\n", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(testCase.Input))
+			if hasHTTP2Preface(reader) != testCase.ExpResult {
+				tt.Errorf("Expected the preface check for %q to return %t", testCase.Input, testCase.ExpResult)
+			} else {
+				tt.Logf("The preface check for %q returned %t as expected", testCase.Input, testCase.ExpResult)
+			}
+		})
+	}
+}
+
+// Test case to validate the detection of requests asking for the connection to be upgraded to h2c.
+func Test_Request_GetHTTP2Upgrade(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Version string
+		Upgrade string
+		Connection string
+		Settings string
+		ExpUpgrade bool
+	} {
+		{ "Valid h2c upgrade request", "1.1", "h2c", "Upgrade, HTTP2-Settings", "AAMAAABkAAQAAP__", true },
+		{ "Upgrade requested using HTTP/1.0", "1.0", "h2c", "Upgrade, HTTP2-Settings", "AAMAAABkAAQAAP__", false },
+		{ "HTTP2-Settings missing from the Connection header", "1.1", "h2c", "Upgrade", "AAMAAABkAAQAAP__", false },
+		{ "Upgrade to a different protocol", "1.1", "websocket", "Upgrade, HTTP2-Settings", "AAMAAABkAAQAAP__", false },
+		{ "Malformed HTTP2-Settings header", "1.1", "h2c", "Upgrade, HTTP2-Settings", "###", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Version = testCase.Version
+			testRequest.Headers.Add("Upgrade", testCase.Upgrade)
+			testRequest.Headers.Add("Connection", testCase.Connection)
+			testRequest.Headers.Add("HTTP2-Settings", testCase.Settings)
+			_, isUpgrade := testRequest.getHTTP2Upgrade()
+			if isUpgrade != testCase.ExpUpgrade {
+				tt.Errorf("Expected the h2c upgrade check to return %t, but got %t", testCase.ExpUpgrade, isUpgrade)
+			} else {
+				tt.Logf("The h2c upgrade check returned %t as expected", isUpgrade)
+			}
+		})
+	}
+}
+
+// Test case to validate that requests sent over HTTP/2 with prior knowledge are routed to the handlers of the web server.
+func Test_Server_HTTP2PriorKnowledge(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Post("/echo/:name", func(req *HttpRequest, res *HttpResponse) error {
+		names, _ := req.Segments.Get("name")
+		values, _ := req.Query.Get("suffix")
+		res.Headers.Add("Content-Type", "text/plain")
+		res.Headers.Add("Connection", "keep-alive")
+		res.Body = []byte(names[0] + ":" + string(req.Body) + values[0] + ":" + req.Version)
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error occurred while setting up the listener socket - %v", err)
+	}
+	go testServer.Serve(listener)
+	defer testServer.Shutdown()
+
+	transport := new(http2.Transport)
+	transport.AllowHTTP = true
+	transport.DialTLSContext = func(ctx context.Context, network string, address string, _ *tls.Config) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	client := &nethttp.Client{ Transport: transport }
+	response, err := client.Post("http://" + listener.Addr().String() + "/echo/proteus?suffix=!", "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("Was not expecting an error while sending the HTTP/2 request and yet received one - %v", err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+	if response.ProtoMajor != 2 || response.StatusCode != int(StatusOK) || string(body) != "proteus:hello!:2.0" {
+		t.Errorf("Received %s response with status %d and body [%s], which does not match the expected response", response.Proto, response.StatusCode, string(body))
+	} else {
+		t.Logf("Received the expected %s response with body [%s]", response.Proto, string(body))
+	}
+
+	if response.Header.Get("Connection") != "" {
+		t.Errorf("Was not expecting the connection-specific header Connection to be sent over HTTP/2")
+	}
+}
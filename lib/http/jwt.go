@@ -0,0 +1,185 @@
+package http
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// Smallest numeric date claim value accepted, which is the start of the year 1 (0001-01-01T00:00:00Z).
+	jwtMinNumericDate = -62135596800
+	// Largest numeric date claim value accepted, which is the end of the year 9999 (9999-12-31T23:59:59Z).
+	jwtMaxNumericDate = 253402300799
+)
+
+// Structure containing the settings used to verify JSON Web Tokens (RFC 7519). A token is accepted only if it is signed using an algorithm for which a key has been configured.
+type JWTConfig struct {
+	// Secret key used to verify the tokens signed using HS256. If empty, HS256 signed tokens are rejected.
+	Secret []byte
+	// Public key used to verify the tokens signed using RS256. If nil, RS256 signed tokens are rejected.
+	PublicKey *rsa.PublicKey
+	// Expected value of the "iss" claim. If empty, the issuer of the token is not checked.
+	Issuer string
+	// Value which must be present in the "aud" claim. If empty, the audience of the token is not checked.
+	Audience string
+	// Allowed clock difference between the issuer and the web server, while checking the "exp" and "nbf" claims.
+	Leeway time.Duration
+}
+
+// Returns a middleware which authenticates the requests using JSON Web Tokens sent as bearer tokens in the Authorization header. The signature and the exp, nbf, iss and aud claims of the token are verified
+// as per the given configuration, and requests with a missing or invalid token are rejected with a 401 (Unauthorized) response. The claims of the token are available to the handlers through the
+// Attributes of the request Principal, whose Name is the "sub" claim.
+func JWTAuth(config JWTConfig) Middleware {
+	return BearerAuth(func(token string) (Principal, error) {
+		claims, err := config.verify(token, time.Now())
+		if err != nil {
+			return Principal{}, err
+		}
+
+		subject, _ := claims["sub"].(string)
+		return Principal{ Name: subject, Attributes: claims }, nil
+	})
+}
+
+// Verifies the signature and the claims of the given token at the given time and returns the claims of the token.
+func (config *JWTConfig) verify(token string, now time.Time) (map[string]any, error) {
+	tokenParts := strings.Split(token, ".")
+	if len(tokenParts) != 3 {
+		return nil, errors.New("Token must contain a header, a payload and a signature")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	err := decodeJWTSegment(tokenParts[0], &header)
+	if err != nil {
+		return nil, errors.New("Token header is malformed")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(tokenParts[2])
+	if err != nil {
+		return nil, errors.New("Token signature is malformed")
+	}
+
+	signedContent := []byte(tokenParts[0] + "." + tokenParts[1])
+	switch header.Algorithm {
+	case "HS256":
+		if len(config.Secret) == 0 {
+			return nil, errors.New("Token signing algorithm HS256 is not accepted")
+		}
+
+		mac := hmac.New(sha256.New, config.Secret)
+		mac.Write(signedContent)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, errors.New("Token signature is invalid")
+		}
+	case "RS256":
+		if config.PublicKey == nil {
+			return nil, errors.New("Token signing algorithm RS256 is not accepted")
+		}
+
+		hash := sha256.Sum256(signedContent)
+		if rsa.VerifyPKCS1v15(config.PublicKey, crypto.SHA256, hash[:], signature) != nil {
+			return nil, errors.New("Token signature is invalid")
+		}
+	default:
+		return nil, fmt.Errorf("Token signing algorithm %s is not supported", header.Algorithm)
+	}
+
+	claims := make(map[string]any)
+	err = decodeJWTSegment(tokenParts[1], &claims)
+	if err != nil {
+		return nil, errors.New("Token payload is malformed")
+	}
+
+	err = config.verifyClaims(claims, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Verifies the registered claims (exp, nbf, iss and aud) of a token at the given time.
+func (config *JWTConfig) verifyClaims(claims map[string]any, now time.Time) error {
+	if value, exists := claims["exp"]; exists {
+		expiresAt, ok := getNumericDate(value)
+		if !ok {
+			return errors.New("Token exp claim must be a numeric date")
+		}
+
+		if !now.Before(expiresAt.Add(config.Leeway)) {
+			return errors.New("Token has expired")
+		}
+	}
+
+	if value, exists := claims["nbf"]; exists {
+		notBefore, ok := getNumericDate(value)
+		if !ok {
+			return errors.New("Token nbf claim must be a numeric date")
+		}
+
+		if now.Add(config.Leeway).Before(notBefore) {
+			return errors.New("Token is not valid yet")
+		}
+	}
+
+	if config.Issuer != "" {
+		if issuer, _ := claims["iss"].(string); issuer != config.Issuer {
+			return errors.New("Token has not been issued by the expected issuer")
+		}
+	}
+
+	if config.Audience != "" {
+		var audiences []string
+		switch audience := claims["aud"].(type) {
+		case string:
+			audiences = []string{ audience }
+		case []any:
+			for _, value := range audience {
+				if audienceValue, ok := value.(string); ok {
+					audiences = append(audiences, audienceValue)
+				}
+			}
+		}
+
+		if !slices.Contains(audiences, config.Audience) {
+			return errors.New("Token is not intended for the expected audience")
+		}
+	}
+
+	return nil
+}
+
+// Decodes the given base64url encoded JSON segment of a token into the given target value.
+func decodeJWTSegment(segment string, target any) error {
+	decodedSegment, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(decodedSegment, target)
+}
+
+// Returns the time represented by the given numeric date claim value, which is the number of seconds elapsed since the Unix epoch. The boolean value returned is false if the value is not a number
+// or is not a time between the years 1 and 9999, as such a value cannot be converted to a time.
+func getNumericDate(value any) (time.Time, bool) {
+	seconds, ok := value.(float64)
+	if !ok || math.IsNaN(seconds) || seconds < jwtMinNumericDate || seconds > jwtMaxNumericDate {
+		return time.Time{}, false
+	}
+
+	// The whole seconds and the fraction are converted separately, as the number of nanoseconds since the Unix epoch overflows for the times after the year 2262.
+	wholeSeconds, fraction := math.Modf(seconds)
+	return time.Unix(int64(wholeSeconds), int64(fraction * float64(time.Second))), true
+}
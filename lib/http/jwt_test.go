@@ -0,0 +1,94 @@
+package http
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// Helper function to create a token with the given claims, signed using the given algorithm and key.
+func newTestJWT(t testing.TB, algorithm string, key any, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{ "alg": algorithm, "typ": "JWT" })
+	payload, _ := json.Marshal(claims)
+	signedContent := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	var signature []byte
+	switch algorithm {
+	case "HS256":
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write([]byte(signedContent))
+		signature = mac.Sum(nil)
+	case "RS256":
+		hash := sha256.Sum256([]byte(signedContent))
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, hash[:])
+		if err != nil {
+			t.Fatalf("Error occurred while signing the test token - %v", err)
+		}
+	}
+
+	return signedContent + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Test case to validate the verification of the signature and the claims of JSON Web Tokens.
+func Test_JWTConfig_Verify(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error occurred while generating the RSA key - %v", err)
+	}
+
+	secret := []byte("top-secret")
+	now := time.Unix(1700000000, 0)
+	config := JWTConfig{ Secret: secret, PublicKey: &privateKey.PublicKey, Issuer: "https://auth.example.com", Audience: "proteus-api", Leeway: 30 * time.Second }
+	validClaims := func() map[string]any {
+		return map[string]any{ "sub": "user-42", "iss": "https://auth.example.com", "aud": []string{ "proteus-api", "other-api" }, "exp": now.Add(time.Hour).Unix(), "nbf": now.Unix() }
+	}
+	withClaim := func(key string, value any) map[string]any {
+		claims := validClaims()
+		claims[key] = value
+		return claims
+	}
+
+	otherKey := []byte("other-secret")
+	testCases := []struct {
+		Name string
+		Token string
+		ExpValid bool
+	} {
+		{ "Valid HS256 token", newTestJWT(t, "HS256", secret, validClaims()), true },
+		{ "Valid RS256 token", newTestJWT(t, "RS256", privateKey, validClaims()), true },
+		{ "Token signed with a different secret", newTestJWT(t, "HS256", otherKey, validClaims()), false },
+		{ "Token using the none algorithm", newTestJWT(t, "none", nil, validClaims()), false },
+		{ "Expired token", newTestJWT(t, "HS256", secret, withClaim("exp", now.Add(-time.Minute).Unix())), false },
+		{ "Expired token within the leeway", newTestJWT(t, "HS256", secret, withClaim("exp", now.Add(-10 * time.Second).Unix())), true },
+		{ "Token expiring after the year 2262", newTestJWT(t, "HS256", secret, withClaim("exp", 1e10)), true },
+		{ "Token expiring after a fraction of a second", newTestJWT(t, "HS256", secret, withClaim("exp", float64(now.Unix()) + 0.5)), true },
+		{ "Token with an exp claim out of range", newTestJWT(t, "HS256", secret, withClaim("exp", 1e300)), false },
+		{ "Token with an nbf claim out of range", newTestJWT(t, "HS256", secret, withClaim("nbf", -1e300)), false },
+		{ "Token not valid yet", newTestJWT(t, "HS256", secret, withClaim("nbf", now.Add(time.Minute).Unix())), false },
+		{ "Token from a different issuer", newTestJWT(t, "HS256", secret, withClaim("iss", "https://evil.example.com")), false },
+		{ "Token for a different audience", newTestJWT(t, "HS256", secret, withClaim("aud", "other-api")), false },
+		{ "Malformed token", "not-a-token", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			claims, err := config.verify(testCase.Token, now)
+			if testCase.ExpValid && err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+			} else if !testCase.ExpValid && err == nil {
+				tt.Errorf("Expected the token to be rejected, but it was accepted")
+			} else if testCase.ExpValid && claims["sub"] != "user-42" {
+				tt.Errorf("Expected the sub claim to be [user-42], but got [%v]", claims["sub"])
+			} else {
+				tt.Logf("The token was verified as expected - %v", err)
+			}
+		})
+	}
+}
@@ -1,32 +1,237 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"strings"
+	"sync"
+	"time"
 )
 
-// Logger to log messages to the console or to a file.
+// Represents the severity level of a log entry.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Returns the name of the log level as it appears in the log entries.
+func (level LogLevel) String() string {
+	switch level {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(level))
+	}
+}
+
+// Represents the format in which the log entries are written.
+type LogFormat string
+
+const (
+	TextLogFormat LogFormat = "text"
+	JSONLogFormat LogFormat = "json"
+)
+
+// Represents a leveled logger used by the web server instance. Each log method accepts a message followed by an optional list of structured fields given as alternating key-value pairs.
+// Implement this interface to route the server logs to a logging library of your choice.
+type Logger interface {
+	// Logs the given message and fields with the debug level.
+	Debug(Msg string, fields ...any)
+	// Logs the given message and fields with the info level.
+	Info(Msg string, fields ...any)
+	// Logs the given message and fields with the warn level.
+	Warn(Msg string, fields ...any)
+	// Logs the given message and fields with the error level.
+	Error(Msg string, fields ...any)
+}
+
+// Default implementation of Logger, which writes the log entries in text or JSON format to the given writer.
 type logger struct {
-	// Pointer to the log.Logger instance created.
-	srvLogger *log.Logger
-	// Name of the server instance for which logs sre being recorded.
+	// Writer to which the log entries are written.
+	output io.Writer
+	// Minimum level of the log entries to be written. Log entries with a lower level are discarded.
+	level LogLevel
+	// Format in which the log entries are written.
+	format LogFormat
+	// Name of the server instance for which logs are being recorded.
 	serverName string
+	// Mutex to synchronize the writing of log entries from multiple goroutines.
+	mutex sync.Mutex
+}
+
+// Logs the given message and fields with the debug level.
+func (lg *logger) Debug(Msg string, fields ...any) {
+	lg.log(LevelDebug, Msg, fields)
+}
+
+// Logs the given message and fields with the info level.
+func (lg *logger) Info(Msg string, fields ...any) {
+	lg.log(LevelInfo, Msg, fields)
+}
+
+// Logs the given message and fields with the warn level.
+func (lg *logger) Warn(Msg string, fields ...any) {
+	lg.log(LevelWarn, Msg, fields)
+}
+
+// Logs the given message and fields with the error level.
+func (lg *logger) Error(Msg string, fields ...any) {
+	lg.log(LevelError, Msg, fields)
 }
 
-// Logs an error message to the log file. If logger is not initialized, the error message is printed to stdout.
-func (lg *logger) logError(Msg string) {
-	if lg.srvLogger != nil {
-		lg.srvLogger.Printf("%s  ERROR  %s", lg.serverName, Msg)
+// Writes a log entry with the given level, message and fields, if the level is at least the minimum level configured for the logger.
+func (lg *logger) log(level LogLevel, Msg string, fields []any) {
+	if level < lg.level {
+		return
+	}
+
+	var entry string
+	if lg.format == JSONLogFormat {
+		entry = lg.formatJSON(level, Msg, fields)
 	} else {
-		fmt.Printf("%s  %s  ERROR  %s", getRfc1123Time(), lg.serverName, Msg)
+		entry = lg.formatText(level, Msg, fields)
 	}
+
+	lg.mutex.Lock()
+	defer lg.mutex.Unlock()
+	io.WriteString(lg.output, entry + "\n")
 }
 
-// Logs a message to the log file. If logger is not initialized, the message is printed to stdout.
-func (lg *logger)logInfo(Msg string) {
-	if lg.srvLogger != nil {
-		lg.srvLogger.Printf("%s  INFO  %s", lg.serverName, Msg)
-	} else {
-		fmt.Printf("%s  %s  INFO  %s", getRfc1123Time(), lg.serverName, Msg)
+// Returns the log entry for the given level, message and fields in text format.
+func (lg *logger) formatText(level LogLevel, Msg string, fields []any) string {
+	var entryBuilder strings.Builder
+	entryBuilder.WriteString(time.Now().Format("2006/01/02 15:04:05"))
+	entryBuilder.WriteString(fmt.Sprintf(" %s  %s  %s", lg.serverName, level.String(), Msg))
+	for index := 0; index < len(fields); index += 2 {
+		key, value := getLogField(fields, index)
+		entryBuilder.WriteString(fmt.Sprintf("  %s=%s", key, formatTextValue(value)))
+	}
+
+	return entryBuilder.String()
+}
+
+// Returns the log entry for the given level, message and fields in JSON format.
+func (lg *logger) formatJSON(level LogLevel, Msg string, fields []any) string {
+	var entryBuilder strings.Builder
+	entryBuilder.WriteString("{")
+	writeJSONField(&entryBuilder, "time", time.Now().Format(time.RFC3339), false)
+	writeJSONField(&entryBuilder, "server", lg.serverName, true)
+	writeJSONField(&entryBuilder, "level", level.String(), true)
+	writeJSONField(&entryBuilder, "msg", Msg, true)
+	for index := 0; index < len(fields); index += 2 {
+		key, value := getLogField(fields, index)
+		writeJSONField(&entryBuilder, key, value, true)
+	}
+
+	entryBuilder.WriteString("}")
+	return entryBuilder.String()
+}
+
+// Returns the key and value of the structured field starting at the given index. If the key is not a string, it is formatted as one and if the value is missing, "!MISSING" is returned as the value.
+func getLogField(fields []any, index int) (string, any) {
+	key, ok := fields[index].(string)
+	if !ok {
+		key = fmt.Sprint(fields[index])
+	}
+
+	if index + 1 >= len(fields) {
+		return key, "!MISSING"
+	}
+
+	return key, fields[index + 1]
+}
+
+// Returns the text representation of the given field value. Values containing spaces or quotes are quoted.
+func formatTextValue(value any) string {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	textValue := fmt.Sprint(value)
+	if textValue == "" || strings.ContainsAny(textValue, " \"=\t\r\n") {
+		return fmt.Sprintf("%q", textValue)
+	}
+
+	return textValue
+}
+
+// Writes the given key and value as a JSON object member to the given builder. Values which cannot be marshalled to JSON are written as strings.
+func writeJSONField(builder *strings.Builder, key string, value any, prefixComma bool) {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	if prefixComma {
+		builder.WriteString(",")
+	}
+
+	encodedKey, _ := json.Marshal(key)
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		encodedValue, _ = json.Marshal(fmt.Sprint(value))
+	}
+
+	builder.Write(encodedKey)
+	builder.WriteString(":")
+	builder.Write(encodedValue)
+}
+
+// Returns the log level corresponding to the given name (debug, info, warn or error). The info level is returned if the name is not valid.
+func parseLogLevel(Name string) LogLevel {
+	switch strings.ToLower(strings.TrimSpace(Name)) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
 	}
-}
\ No newline at end of file
+}
+
+// Implementation of Logger which adds a fixed set of structured fields to every log entry, before writing it using the underlying logger.
+type fieldLogger struct {
+	// Logger to which the log entries are written.
+	logger Logger
+	// Structured fields added to every log entry, given as alternating key-value pairs.
+	fields []any
+}
+
+// Logs the given message and fields with the debug level.
+func (flg *fieldLogger) Debug(Msg string, fields ...any) {
+	flg.logger.Debug(Msg, flg.withFields(fields)...)
+}
+
+// Logs the given message and fields with the info level.
+func (flg *fieldLogger) Info(Msg string, fields ...any) {
+	flg.logger.Info(Msg, flg.withFields(fields)...)
+}
+
+// Logs the given message and fields with the warn level.
+func (flg *fieldLogger) Warn(Msg string, fields ...any) {
+	flg.logger.Warn(Msg, flg.withFields(fields)...)
+}
+
+// Logs the given message and fields with the error level.
+func (flg *fieldLogger) Error(Msg string, fields ...any) {
+	flg.logger.Error(Msg, flg.withFields(fields)...)
+}
+
+// Returns the given fields preceded by the fixed fields of the logger.
+func (flg *fieldLogger) withFields(fields []any) []any {
+	allFields := make([]any, 0, len(flg.fields) + len(fields))
+	allFields = append(allFields, flg.fields...)
+	return append(allFields, fields...)
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Test case to validate the level filtering and the text and JSON output formats of the default logger.
+func Test_Logger_Output(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Level LogLevel
+		Format LogFormat
+		LogFunc func(Logger)
+		ExpOutput []string
+	} {
+		{ "Info message in text format", LevelInfo, TextLogFormat, func(lg Logger) { lg.Info("Request processed", "status", 200, "path", "/users") }, []string{ "INFO  Request processed", "status=200", "path=/users" } },
+		{ "Field value with spaces in text format", LevelInfo, TextLogFormat, func(lg Logger) { lg.Warn("Slow request", "agent", "curl 8.0") }, []string{ "WARN  Slow request", `agent="curl 8.0"` } },
+		{ "Field without a value in text format", LevelInfo, TextLogFormat, func(lg Logger) { lg.Error("Failure", "reason") }, []string{ "ERROR  Failure", "reason=!MISSING" } },
+		{ "Debug message below the minimum level", LevelInfo, TextLogFormat, func(lg Logger) { lg.Debug("Connection accepted") }, []string{} },
+		{ "Error message in JSON format", LevelWarn, JSONLogFormat, func(lg Logger) { lg.Error("Request failed", "status", 500) }, []string{ `"level":"ERROR"`, `"msg":"Request failed"`, `"status":500` } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			var opBuffer bytes.Buffer
+			testCase.LogFunc(NewLogger(&opBuffer, testCase.Level, testCase.Format))
+			output := opBuffer.String()
+			if len(testCase.ExpOutput) == 0 && output != "" {
+				tt.Errorf("Was not expecting any log output, but got [%s]", output)
+				return
+			}
+
+			for _, expValue := range testCase.ExpOutput {
+				if !strings.Contains(output, expValue) {
+					tt.Errorf("Expected the log output [%s] to contain [%s]", output, expValue)
+				} else {
+					tt.Logf("The log output contains [%s]", expValue)
+				}
+			}
+
+			if testCase.Format == JSONLogFormat && !json.Valid([]byte(strings.TrimSpace(output))) {
+				tt.Errorf("The log output [%s] is not a valid JSON object", output)
+			}
+		})
+	}
+}
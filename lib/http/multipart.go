@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"strings"
+)
+
+// Media type of the request bodies containing form fields and files encoded as multiple parts.
+const MULTIPART_FORM_CONTENT_TYPE = "multipart/form-data"
+
+// Structure to represent a parsed multipart/form-data request body.
+type MultipartForm struct {
+	// Collection of all the form field values stored as key-value pair.
+	Fields Params
+	// Collection of all the uploaded files, with the form field name as key.
+	Files map[string][]*UploadedFile
+	// Parsed form from which the fields and files were read.
+	form *multipart.Form
+}
+
+// Removes all the temporary files created for the uploaded files which did not fit in memory.
+func (mf *MultipartForm) RemoveAll() error {
+	return mf.form.RemoveAll()
+}
+
+// Returns the first file uploaded for the given form field name. The function also returns a boolean value to indicate if a file was uploaded for the form field.
+func (mf *MultipartForm) File(Name string) (*UploadedFile, bool) {
+	files, ok := mf.Files[Name]
+	if !ok || len(files) == 0 {
+		return nil, false
+	}
+
+	return files[0], true
+}
+
+// Structure to represent a file uploaded as a part of a multipart/form-data request body.
+type UploadedFile struct {
+	// Name of the form field for which the file was uploaded.
+	FieldName string
+	// Name of the file as given by the client. It must not be trusted to be a safe file system path.
+	Filename string
+	// Collection of all the headers sent for the file part.
+	Headers Headers
+	// Size of the file in bytes.
+	Size int64
+	// Metadata of the file part, used to open the file contents.
+	fileHeader *multipart.FileHeader
+}
+
+// Opens the uploaded file for reading its contents. The file contents are read from memory, or from the temporary file on disk if the file did not fit in memory. The returned file must be closed by the caller.
+func (uf *UploadedFile) Open() (multipart.File, error) {
+	return uf.fileHeader.Open()
+}
+
+// Copies the contents of the uploaded file to a new file created at the given path.
+func (uf *UploadedFile) SaveTo(CompleteFilePath string) error {
+	source, err := uf.Open()
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.Create(CompleteFilePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(target, source)
+	closeErr := target.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// Parses the request body as multipart/form-data and returns the form fields and files present in it. Up to maxMemory bytes of the file parts are stored in memory,
+// while the remaining file parts are stored in temporary files on disk. The temporary files are removed once the request has been processed.
+// The request body is parsed only once and the same form is returned on subsequent calls.
+func (req *HttpRequest) ParseMultipart(maxMemory int64) (*MultipartForm, error) {
+	if req.multipartForm != nil {
+		return req.multipartForm, nil
+	}
+
+	contentType, _ := req.Headers.Get("Content-Type")
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.EqualFold(mediaType, MULTIPART_FORM_CONTENT_TYPE) {
+		reqError := new(RequestParseError)
+		reqError.Section = "Header"
+		reqError.Value = contentType
+		reqError.Message = "Request body can be parsed as a multipart form only if its content type is multipart/form-data"
+		reqError.Status = StatusUnsupportedMediaType
+		return nil, reqError
+	}
+
+	boundary, ok := params["boundary"]
+	if !ok || boundary == "" {
+		reqError := new(RequestParseError)
+		reqError.Section = "Header"
+		reqError.Value = contentType
+		reqError.Message = "Multipart form boundary is missing in the Content-Type header"
+		reqError.Status = StatusBadRequest
+		return nil, reqError
+	}
+
+	form, err := multipart.NewReader(req.BodyReader(), boundary).ReadForm(maxMemory)
+	if err != nil {
+		reqError := new(RequestParseError)
+		reqError.Section = "Body"
+		reqError.Value = "Request Body"
+		reqError.Message = fmt.Sprintf("Error while parsing request body as a multipart form :: %s", err.Error())
+		reqError.Status = StatusBadRequest
+		if errors.Is(err, multipart.ErrMessageTooLarge) {
+			reqError.Status = StatusContentTooLarge
+		}
+		return nil, reqError
+	}
+
+	multipartForm := new(MultipartForm)
+	multipartForm.form = form
+	multipartForm.Fields = make(Params)
+	for Name, values := range form.Value {
+		multipartForm.Fields[Name] = append(multipartForm.Fields[Name], values...)
+	}
+
+	multipartForm.Files = make(map[string][]*UploadedFile)
+	for Name, fileHeaders := range form.File {
+		for _, fileHeader := range fileHeaders {
+			uploadedFile := new(UploadedFile)
+			uploadedFile.FieldName = Name
+			uploadedFile.Filename = fileHeader.Filename
+			uploadedFile.Size = fileHeader.Size
+			uploadedFile.Headers = make(Headers)
+			for key, values := range fileHeader.Header {
+				uploadedFile.Headers[key] = append(uploadedFile.Headers[key], values...)
+			}
+			uploadedFile.fileHeader = fileHeader
+			multipartForm.Files[Name] = append(multipartForm.Files[Name], uploadedFile)
+		}
+	}
+
+	req.multipartForm = multipartForm
+	return multipartForm, nil
+}
+
+// Removes the temporary files created while parsing the multipart form of the request, if any.
+func (req *HttpRequest) cleanupMultipartForm() error {
+	if req.multipartForm == nil {
+		return nil
+	}
+
+	return req.multipartForm.RemoveAll()
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Helper function to create a multipart/form-data request body with a text field and a file, returning the body and its content type.
+func newTestMultipartBody(t testing.TB, fileContents string) ([]byte, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("title", "Quarterly report")
+	fileWriter, err := writer.CreateFormFile("attachment", "report.txt")
+	if err != nil {
+		t.Fatalf("Error occurred while creating the multipart test body - %v", err)
+	}
+
+	fileWriter.Write([]byte(fileContents))
+	writer.Close()
+	return body.Bytes(), writer.FormDataContentType()
+}
+
+// Test case to validate the parsing of multipart/form-data request bodies.
+func Test_Request_ParseMultipart(t *testing.T) {
+	multipartBody, multipartType := newTestMultipartBody(t, "report contents")
+	testCases := []struct {
+		Name string
+		ContentType string
+		Body []byte
+		MaxMemory int64
+		ExpErrStatus StatusCode
+	} {
+		{ "Multipart body parsed in memory", multipartType, multipartBody, 1 << 20, 0 },
+		{ "Multipart body with the file spilled to disk", multipartType, multipartBody, 1, 0 },
+		{ "Request body with a different content type", JSON_CONTENT_TYPE, []byte(`{}`), 1 << 20, StatusUnsupportedMediaType },
+		{ "Multipart content type without a boundary", "multipart/form-data", multipartBody, 1 << 20, StatusBadRequest },
+		{ "Malformed multipart body", multipartType, []byte("not a multipart body"), 1 << 20, StatusBadRequest },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Headers.Add("Content-Type", testCase.ContentType)
+			testRequest.Body = testCase.Body
+			form, err := testRequest.ParseMultipart(testCase.MaxMemory)
+			if testCase.ExpErrStatus != 0 {
+				reqErr, ok := err.(*RequestParseError)
+				if !ok || reqErr.Status != testCase.ExpErrStatus {
+					tt.Errorf("Expected a request parse error with status %d, but got this instead - %v", testCase.ExpErrStatus, err)
+				} else {
+					tt.Logf("Received a request parse error as expected - %v", reqErr)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+				return
+			}
+			defer testRequest.cleanupMultipartForm()
+
+			titles, _ := form.Fields.Get("title")
+			if len(titles) != 1 || titles[0] != "Quarterly report" {
+				tt.Errorf("Expected the title field to be [Quarterly report], but got %v", titles)
+			}
+
+			uploadedFile, found := form.File("attachment")
+			if !found {
+				tt.Errorf("Expected a file to be uploaded for the attachment field")
+				return
+			}
+
+			file, err := uploadedFile.Open()
+			if err != nil {
+				tt.Errorf("Was not expecting an error while opening the uploaded file and yet received one - %v", err)
+				return
+			}
+			defer file.Close()
+
+			contents, _ := io.ReadAll(file)
+			if uploadedFile.Filename != "report.txt" || string(contents) != "report contents" || uploadedFile.Size != int64(len(contents)) {
+				tt.Errorf("The uploaded file [%s] with contents [%s] does not match the expected file", uploadedFile.Filename, string(contents))
+			} else {
+				tt.Logf("The uploaded file [%s] matches the expected file", uploadedFile.Filename)
+			}
+
+			sameForm, _ := testRequest.ParseMultipart(testCase.MaxMemory)
+			if sameForm != form {
+				tt.Errorf("Expected the parsed form to be returned on subsequent calls")
+			}
+		})
+	}
+}
+
+// Test case to validate saving an uploaded file to the file system.
+func Test_UploadedFile_SaveTo(t *testing.T) {
+	multipartBody, multipartType := newTestMultipartBody(t, "saved contents")
+	testRequest := newTestRequest(t)
+	testRequest.Headers.Add("Content-Type", multipartType)
+	testRequest.Body = multipartBody
+	form, err := testRequest.ParseMultipart(1 << 20)
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+
+	uploadedFile, _ := form.File("attachment")
+	targetPath := filepath.Join(t.TempDir(), "report.txt")
+	err = uploadedFile.SaveTo(targetPath)
+	if err != nil {
+		t.Fatalf("Was not expecting an error while saving the uploaded file and yet received one - %v", err)
+	}
+
+	contents, _ := os.ReadFile(targetPath)
+	if string(contents) != "saved contents" {
+		t.Errorf("The saved file contents [%s] do not match the uploaded file contents", string(contents))
+	}
+}
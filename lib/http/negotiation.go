@@ -0,0 +1,184 @@
+package http
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Structure to represent a single value present in a header with quality values, like Accept or Accept-Language.
+type acceptedValue struct {
+	// Value accepted by the client in lower case, without its parameters.
+	value string
+	// Quality value (between 0 and 1) of the value, which indicates the preference of the client for the value.
+	quality float64
+}
+
+// Parses the given value of a header with quality values (as defined in RFC 9110) and returns the accepted values, ordered by their quality values from the most preferred to the least preferred.
+// Values having the same quality value retain the order in which they were given. Values with an invalid quality value are ignored.
+func parseAcceptedValues(HeaderValue string) []acceptedValue {
+	acceptedValues := make([]acceptedValue, 0)
+	for _, element := range strings.Split(HeaderValue, ",") {
+		params := strings.Split(element, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+
+		quality := 1.0
+		isValid := true
+		for _, param := range params[1:] {
+			name, paramValue, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				parsedQuality, err := strconv.ParseFloat(strings.TrimSpace(paramValue), 64)
+				isValid = err == nil && parsedQuality >= 0 && parsedQuality <= 1
+				quality = parsedQuality
+				break
+			}
+		}
+
+		if isValid {
+			acceptedValues = append(acceptedValues, acceptedValue{ value: value, quality: quality })
+		}
+	}
+
+	slices.SortStableFunc(acceptedValues, func(first acceptedValue, second acceptedValue) int {
+		if first.quality > second.quality {
+			return -1
+		} else if first.quality < second.quality {
+			return 1
+		}
+		return 0
+	})
+
+	return acceptedValues
+}
+
+// Returns the media type among the given offers that is preferred by the client, as per the Accept header of the request. Each offer is matched against the most specific media range of the
+// Accept header that covers it (text/html is matched by text/html before text/* and */*). When multiple offers are equally preferred, the offer given first is returned. If the request does not
+// contain an Accept header, the first offer is returned. An empty string is returned if none of the offers are acceptable to the client.
+func (req *HttpRequest) Negotiate(offers ...string) string {
+	acceptHeader, found := req.Headers.Get("Accept")
+	if !found || strings.TrimSpace(acceptHeader) == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+
+	acceptedTypes := parseAcceptedValues(acceptHeader)
+	selectedOffer := ""
+	selectedQuality := 0.0
+	for _, offer := range offers {
+		quality := getMediaTypeQuality(strings.ToLower(strings.TrimSpace(offer)), acceptedTypes)
+		if quality > selectedQuality {
+			selectedOffer = offer
+			selectedQuality = quality
+		}
+	}
+
+	return selectedOffer
+}
+
+// Returns the quality value of the most specific media range among the given accepted media types that matches the given media type. Zero is returned if none of the media ranges match the media type.
+func getMediaTypeQuality(MediaType string, acceptedTypes []acceptedValue) float64 {
+	mainType, _, _ := strings.Cut(MediaType, "/")
+	quality := 0.0
+	specificity := -1
+	for _, acceptedType := range acceptedTypes {
+		rangeSpecificity := -1
+		if acceptedType.value == MediaType {
+			rangeSpecificity = 2
+		} else if acceptedType.value == mainType + "/*" {
+			rangeSpecificity = 1
+		} else if acceptedType.value == "*/*" || acceptedType.value == "*" {
+			rangeSpecificity = 0
+		}
+
+		if rangeSpecificity > specificity {
+			quality = acceptedType.quality
+			specificity = rangeSpecificity
+		}
+	}
+
+	return quality
+}
+
+// Invokes the handler registered for the media type preferred by the client among the media types present in the given collection of handlers, as per the Accept header of the request. When multiple
+// media types are equally preferred, they are considered in alphabetical order. The handler registered with the key "default" is invoked if none of the media types are acceptable to the client. If there is
+// no default handler, a 406 (Not Acceptable) response is sent. The Vary header of the response is updated to indicate that the response depends on the Accept header.
+func (res *HttpResponse) Format(request *HttpRequest, handlers map[string]Handler) error {
+	res.addVary("Accept")
+	offers := make([]string, 0, len(handlers))
+	for mediaType := range handlers {
+		if mediaType != "default" {
+			offers = append(offers, mediaType)
+		}
+	}
+
+	slices.Sort(offers)
+	if selectedType := request.Negotiate(offers...); selectedType != "" {
+		return handlers[selectedType](request, res)
+	}
+
+	if defaultHandler, found := handlers["default"]; found {
+		return defaultHandler(request, res)
+	}
+
+	res.Status(StatusNotAcceptable)
+	return handleError(request, res)
+}
+
+// Returns the language tags present in the Accept-Language header of the request in lower case, ordered from the most preferred to the least preferred. Languages with a quality value of zero
+// (which are not acceptable to the client) are left out. An empty slice is returned if the request does not contain an Accept-Language header.
+func (req *HttpRequest) Languages() []string {
+	languages := make([]string, 0)
+	acceptLanguage, found := req.Headers.Get("Accept-Language")
+	if !found {
+		return languages
+	}
+
+	for _, language := range parseAcceptedValues(acceptLanguage) {
+		if language.quality > 0 {
+			languages = append(languages, language.value)
+		}
+	}
+
+	return languages
+}
+
+// Returns the locale among the given supported locales that best matches the languages preferred by the client, as per the Accept-Language header of the request. The preferred languages are considered
+// in order and for each of them, a supported locale matching it exactly is chosen first, followed by a supported locale matching a less specific form of it (en for en-US) and then a supported locale
+// that is more specific than it (en-GB for en). The first supported locale is returned for the wildcard "*". An empty string is returned if none of the supported locales match the preferred languages.
+func (req *HttpRequest) NegotiateLanguage(supported ...string) string {
+	for _, language := range req.Languages() {
+		if language == "*" {
+			if len(supported) > 0 {
+				return supported[0]
+			}
+			return ""
+		}
+
+		for languageRange := language; languageRange != ""; {
+			for _, locale := range supported {
+				if strings.EqualFold(strings.TrimSpace(locale), languageRange) {
+					return locale
+				}
+			}
+
+			separatorIndex := strings.LastIndex(languageRange, "-")
+			if separatorIndex == -1 {
+				break
+			}
+			languageRange = languageRange[:separatorIndex]
+		}
+
+		for _, locale := range supported {
+			if strings.HasPrefix(strings.ToLower(strings.TrimSpace(locale)), language + "-") {
+				return locale
+			}
+		}
+	}
+
+	return ""
+}
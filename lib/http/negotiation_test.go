@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// Test case to validate the selection of the media type preferred by the client among the given offers, as per the Accept header.
+func Test_Request_Negotiate(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Accept string
+		Offers []string
+		ExpType string
+	} {
+		{ "Request without an Accept header", "", []string{ "application/json", "text/html" }, "application/json" },
+		{ "Exact media type", "text/html", []string{ "application/json", "text/html" }, "text/html" },
+		{ "Media types with quality values", "application/json;q=0.5, text/html;q=0.9", []string{ "application/json", "text/html" }, "text/html" },
+		{ "Wildcard sub-type", "text/*", []string{ "application/json", "text/plain" }, "text/plain" },
+		{ "Specific media range preferred over a wildcard", "*/*;q=0.8, application/json;q=0.1", []string{ "application/json", "text/html" }, "text/html" },
+		{ "Equally preferred offers", "*/*", []string{ "text/html", "application/json" }, "text/html" },
+		{ "Media type that is not acceptable", "application/json;q=0, */*", []string{ "application/json" }, "" },
+		{ "No acceptable offers", "image/png", []string{ "application/json", "text/html" }, "" },
+		{ "Invalid quality value ignored", "text/html;q=abc, application/json", []string{ "text/html", "application/json" }, "application/json" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			if testCase.Accept != "" {
+				testRequest.Headers.Add("Accept", testCase.Accept)
+			}
+
+			selectedType := testRequest.Negotiate(testCase.Offers...)
+			if selectedType != testCase.ExpType {
+				tt.Errorf("Expected the negotiated media type to be [%s], but got [%s]", testCase.ExpType, selectedType)
+			} else {
+				tt.Logf("The negotiated media type [%s] matches the expected media type", selectedType)
+			}
+		})
+	}
+}
+
+// Test case to validate the invocation of the handler registered for the media type preferred by the client.
+func Test_Response_Format(t *testing.T) {
+	newFormatHandler := func(name string) Handler {
+		return func(req *HttpRequest, res *HttpResponse) error {
+			res.Status(StatusOK)
+			res.Body = []byte(name)
+			return nil
+		}
+	}
+
+	testCases := []struct {
+		Name string
+		Accept string
+		WithDefault bool
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "Handler for the preferred media type", "text/html, application/json;q=0.9", false, int(StatusOK), "html" },
+		{ "Handler for a wildcard media range", "application/*", false, int(StatusOK), "json" },
+		{ "Default handler for an unacceptable media type", "image/png", true, int(StatusOK), "default" },
+		{ "No handler for an unacceptable media type", "image/png", false, int(StatusNotAcceptable), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Headers.Add("Accept", testCase.Accept)
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			handlers := map[string]Handler{ "application/json": newFormatHandler("json"), "text/html": newFormatHandler("html") }
+			if testCase.WithDefault {
+				handlers["default"] = newFormatHandler("default")
+			}
+
+			err := testResponse.Format(testRequest, handlers)
+			vary, _ := testResponse.Headers.Get("Vary")
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+			} else if testResponse.StatusCode != testCase.ExpStatus || (testCase.ExpBody != "" && string(testResponse.Body) != testCase.ExpBody) {
+				tt.Errorf("Expected status %d with body [%s], but got status %d with body [%s]", testCase.ExpStatus, testCase.ExpBody, testResponse.StatusCode, string(testResponse.Body))
+			} else if vary != "Accept" {
+				tt.Errorf("Expected the Vary header to be [Accept], but got [%s]", vary)
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate the parsing of the Accept-Language header and the selection of the supported locale that best matches the languages preferred by the client.
+func Test_Request_NegotiateLanguage(t *testing.T) {
+	supportedLocales := []string{ "en-GB", "fr", "pt-BR" }
+	testCases := []struct {
+		Name string
+		AcceptLanguage string
+		ExpLanguages []string
+		ExpLocale string
+	} {
+		{ "Request without an Accept-Language header", "", []string{}, "" },
+		{ "Exact match", "fr", []string{ "fr" }, "fr" },
+		{ "Languages with quality values", "fr;q=0.5, en-GB;q=0.8, de", []string{ "de", "en-gb", "fr" }, "en-GB" },
+		{ "Less specific supported locale", "fr-CA, en;q=0.5", []string{ "fr-ca", "en" }, "fr" },
+		{ "More specific supported locale", "pt, fr;q=0.2", []string{ "pt", "fr" }, "pt-BR" },
+		{ "Language that is not acceptable", "fr;q=0, en-GB;q=0.1", []string{ "en-gb" }, "en-GB" },
+		{ "Wildcard language", "de, *;q=0.1", []string{ "de", "*" }, "en-GB" },
+		{ "No supported locale matches", "de, ja", []string{ "de", "ja" }, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			if testCase.AcceptLanguage != "" {
+				testRequest.Headers.Add("Accept-Language", testCase.AcceptLanguage)
+			}
+
+			languages := testRequest.Languages()
+			locale := testRequest.NegotiateLanguage(supportedLocales...)
+			if !slices.Equal(languages, testCase.ExpLanguages) {
+				tt.Errorf("Expected the preferred languages to be %v, but got %v", testCase.ExpLanguages, languages)
+			} else if locale != testCase.ExpLocale {
+				tt.Errorf("Expected the negotiated locale to be [%s], but got [%s]", testCase.ExpLocale, locale)
+			} else {
+				tt.Logf("The negotiated locale [%s] matches the expected locale", locale)
+			}
+		})
+	}
+}
@@ -0,0 +1,487 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version of the OpenAPI specification followed by the documents generated using GenerateOpenAPI().
+const OPENAPI_VERSION = "3.0.3"
+
+// Structure containing the information about the API, which is added to the OpenAPI document generated for the routes of a web server instance.
+type Info struct {
+	// Title of the API. It must not be empty.
+	Title string
+	// Version of the API (not of the OpenAPI specification), like "1.2.0". It must not be empty.
+	Version string
+	// Description of the API. It is left out of the document if empty.
+	Description string
+}
+
+// Structure containing the documentation of a route, which is added to the OpenAPI document generated for the routes of a web server instance.
+type RouteDoc struct {
+	// Short summary of what the route does.
+	Summary string
+	// Detailed description of the route.
+	Description string
+	// Tags used to group the routes in the OpenAPI document, like the name of the resource.
+	Tags []string
+	// Value of the struct bound by the Validate() middleware of the route, from which the parameters and the JSON request body of the route are documented, along with their validation rules.
+	// The request is not documented if it is nil.
+	Request any
+	// Values of the JSON response bodies sent by the route, with the status code of the response as key. A nil value documents a response without a body.
+	// A default response is documented if the map is empty.
+	Responses map[StatusCode]any
+	// Boolean value to indicate if the route is deprecated and must not be used by new clients.
+	Deprecated bool
+}
+
+// Collection of the schemas of the named struct types used in an OpenAPI document, which are added to its components and referred to from the operations.
+type openAPISchemas struct {
+	// Schemas of the named struct types, with the name of the schema as key.
+	components map[string]any
+	// Name of the schema of every named struct type for which a schema has been added.
+	names map[reflect.Type]string
+}
+
+// Sets the documentation of the route defined last in the web server instance (including the routes defined in route groups), which is added to the OpenAPI document generated using GenerateOpenAPI().
+// An error is returned if no route has been defined yet.
+func (srv *HttpServer) Describe(Doc RouteDoc) error {
+	return srv.innerRouter.describeLastRoute(Doc)
+}
+
+// Generates and returns the OpenAPI 3 document (in JSON) describing the dynamic routes defined in the web server instance, along with the documentation set for them using Describe().
+// Static routes, disabled routes and routes defined for methods which cannot be documented (like CONNECT) are left out. An error is returned if the title or the version of the API is empty,
+// or if the struct given as the request of a route cannot be documented.
+func (srv *HttpServer) GenerateOpenAPI(info Info) ([]byte, error) {
+	return srv.innerRouter.generateOpenAPI(info)
+}
+
+// Defines a GET route at the given route path which serves the OpenAPI 3 document describing the routes of the web server instance. The document is generated for every request,
+// and hence it includes the routes defined after the route has been defined.
+func (srv *HttpServer) OpenAPI(routePath string, info Info) error {
+	err := srv.Get(routePath, func(request *HttpRequest, response *HttpResponse) error {
+		document, err := srv.GenerateOpenAPI(info)
+		if err != nil {
+			return err
+		}
+
+		response.Status(StatusOK)
+		response.Headers.Add("Content-Type", JSON_CONTENT_TYPE)
+		response.Body = document
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return srv.Describe(RouteDoc{ Summary: "OpenAPI document describing the routes of the server", Responses: map[StatusCode]any{ StatusOK: nil } })
+}
+
+// Sets the documentation of the route defined last in the router, which is added to the OpenAPI document. The documentation is retained once the router has been mounted.
+func (rtr *Router) Describe(Doc RouteDoc) error {
+	return rtr.describeLastRoute(Doc)
+}
+
+// Sets the documentation of the route defined last in the router. An error is returned if no route has been defined yet.
+func (rtr *Router) describeLastRoute(Doc RouteDoc) error {
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	if len(rtr.Routes) == 0 {
+		reError := new(RoutingError)
+		reError.RoutePath = ""
+		reError.Message = "describeLastRoute: A route must be defined before its documentation can be set"
+		return reError
+	}
+
+	rtr.Routes[len(rtr.Routes) - 1].Doc = &Doc
+	return nil
+}
+
+// Generates the OpenAPI 3 document describing the dynamic routes of the router.
+func (rtr *Router) generateOpenAPI(info Info) ([]byte, error) {
+	if strings.TrimSpace(info.Title) == "" || strings.TrimSpace(info.Version) == "" {
+		reError := new(RoutingError)
+		reError.RoutePath = ""
+		reError.Message = "generateOpenAPI: Title and version of the API must not be empty"
+		return nil, reError
+	}
+
+	rtr.mutex.RLock()
+	routes := slices.Clone(rtr.Routes)
+	disabledRoutes := make(map[string]bool)
+	for routePath, isDisabled := range rtr.disabledRoutes {
+		disabledRoutes[routePath] = isDisabled
+	}
+	rtr.mutex.RUnlock()
+
+	schemas := &openAPISchemas{ components: make(map[string]any), names: make(map[reflect.Type]string) }
+	paths := make(map[string]any)
+	for _, route := range routes {
+		method := strings.ToLower(route.Method)
+		if route.IsStatic || disabledRoutes[lowerRoute(route.RoutePath)] || !slices.Contains([]string{ "get", "put", "post", "delete", "options", "head", "patch", "trace" }, method) {
+			continue
+		}
+
+		documentPath, operation, err := schemas.getOperation(route)
+		if err != nil {
+			return nil, err
+		}
+
+		pathItem, found := paths[documentPath].(map[string]any)
+		if !found {
+			pathItem = make(map[string]any)
+			paths[documentPath] = pathItem
+		}
+		pathItem[method] = operation
+	}
+
+	documentInfo := map[string]any{ "title": info.Title, "version": info.Version }
+	if info.Description != "" {
+		documentInfo["description"] = info.Description
+	}
+
+	document := map[string]any{ "openapi": OPENAPI_VERSION, "info": documentInfo, "paths": paths }
+	if len(schemas.components) > 0 {
+		document["components"] = map[string]any{ "schemas": schemas.components }
+	}
+
+	return json.Marshal(document)
+}
+
+// Returns the path of the given route in the form used by the OpenAPI document (like "/users/{id}"), along with the operation describing the route.
+func (schemas *openAPISchemas) getOperation(route Route) (string, map[string]any, error) {
+	operation := make(map[string]any)
+	parameters := make([]map[string]any, 0)
+	documentParts := make([]string, 0)
+	for _, routePart := range splitRoute(route.RoutePath) {
+		if !strings.HasPrefix(routePart, ":") && !strings.HasPrefix(routePart, "*") {
+			documentParts = append(documentParts, routePart)
+			continue
+		}
+
+		paramName, constraint, _ := parseRouteParam(strings.TrimPrefix(routePart, "*"))
+		paramSchema := map[string]any{ "type": "string" }
+		if constraint != nil {
+			paramSchema["pattern"] = constraint.String()
+		}
+		documentParts = append(documentParts, "{" + paramName + "}")
+		parameters = append(parameters, map[string]any{ "name": paramName, "in": "path", "required": true, "schema": paramSchema })
+	}
+
+	documentPath := "/" + strings.Join(documentParts, "/")
+	if route.TrailingSlash && documentPath != "/" {
+		documentPath += "/"
+	}
+
+	if route.Name != "" {
+		operation["operationId"] = route.Name
+	}
+
+	responses := map[string]any{ "default": map[string]any{ "description": "Response of the route" } }
+	if route.Doc != nil {
+		if route.Doc.Summary != "" {
+			operation["summary"] = route.Doc.Summary
+		}
+		if route.Doc.Description != "" {
+			operation["description"] = route.Doc.Description
+		}
+		if len(route.Doc.Tags) > 0 {
+			operation["tags"] = route.Doc.Tags
+		}
+		if route.Doc.Deprecated {
+			operation["deprecated"] = true
+		}
+
+		if route.Doc.Request != nil {
+			var err error
+			parameters, err = schemas.addRequest(operation, parameters, route.Doc.Request)
+			if err != nil {
+				reError := new(RoutingError)
+				reError.RoutePath = route.RoutePath
+				reError.Message = fmt.Sprintf("generateOpenAPI: Request of the %s route cannot be documented :: %s", route.Method, err.Error())
+				return "", nil, reError
+			}
+		}
+
+		if len(route.Doc.Responses) > 0 {
+			responses = make(map[string]any)
+			for status, body := range route.Doc.Responses {
+				response := map[string]any{ "description": StatusText(int(status)) }
+				if body != nil {
+					bodySchema, err := schemas.getSchema(reflect.TypeOf(body))
+					if err != nil {
+						reError := new(RoutingError)
+						reError.RoutePath = route.RoutePath
+						reError.Message = fmt.Sprintf("generateOpenAPI: Response %d of the %s route cannot be documented :: %s", status, route.Method, err.Error())
+						return "", nil, reError
+					}
+					response["content"] = map[string]any{ JSON_CONTENT_TYPE: map[string]any{ "schema": bodySchema } }
+				}
+				responses[strconv.Itoa(int(status))] = response
+			}
+		}
+	}
+
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	operation["responses"] = responses
+	return documentPath, operation, nil
+}
+
+// Adds the parameters and the JSON request body of the struct bound by the Validate() middleware to the given operation, and returns the parameters of the operation. The path parameters of the struct
+// replace the ones documented from the route path.
+func (schemas *openAPISchemas) addRequest(operation map[string]any, parameters []map[string]any, request any) ([]map[string]any, error) {
+	requestType := reflect.TypeOf(request)
+	if requestType.Kind() == reflect.Pointer {
+		requestType = requestType.Elem()
+	}
+
+	fields, err := getValidationFields(requestType)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyProperties := make(map[string]any)
+	bodyRequired := make([]string, 0)
+	for _, field := range fields {
+		fieldSchema, err := schemas.getFieldSchema(requestType.Field(field.index).Type, &field)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.source == "body" {
+			bodyProperties[field.name] = fieldSchema
+			if field.required {
+				bodyRequired = append(bodyRequired, field.name)
+			}
+			continue
+		}
+
+		parameter := map[string]any{ "name": field.name, "in": field.source, "required": field.required || field.source == "path", "schema": fieldSchema }
+		parameters = slices.DeleteFunc(parameters, func(existing map[string]any) bool {
+			return field.source == "path" && existing["in"] == "path" && strings.EqualFold(existing["name"].(string), field.name)
+		})
+		parameters = append(parameters, parameter)
+	}
+
+	if len(bodyProperties) > 0 {
+		bodySchema := map[string]any{ "type": "object", "properties": bodyProperties }
+		if len(bodyRequired) > 0 {
+			bodySchema["required"] = bodyRequired
+		}
+		operation["requestBody"] = map[string]any{ "required": len(bodyRequired) > 0, "content": map[string]any{ JSON_CONTENT_TYPE: map[string]any{ "schema": bodySchema } } }
+	}
+
+	return parameters, nil
+}
+
+// Returns the schema of a field of the given type, with the validation rules of the field added to it. The rules are not added to the schemas of struct types, which are referred to from the components.
+func (schemas *openAPISchemas) getFieldSchema(fieldType reflect.Type, field *validationField) (map[string]any, error) {
+	fieldSchema, err := schemas.getSchema(fieldType)
+	if err != nil || fieldSchema["$ref"] != nil {
+		return fieldSchema, err
+	}
+
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	minKey, maxKey := "minimum", "maximum"
+	switch fieldType.Kind() {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		minKey, maxKey = "minItems", "maxItems"
+	}
+
+	if field.min != nil {
+		fieldSchema[minKey] = *field.min
+	}
+	if field.max != nil {
+		fieldSchema[maxKey] = *field.max
+	}
+
+	// The pattern and the allowed values apply to every element of a slice.
+	elemSchema, elemType := fieldSchema, fieldType
+	if items, ok := fieldSchema["items"].(map[string]any); ok && fieldType.Kind() == reflect.Slice {
+		elemSchema, elemType = items, fieldType.Elem()
+	}
+
+	if field.pattern != nil {
+		elemSchema["pattern"] = field.pattern.String()
+	}
+
+	if len(field.enum) > 0 {
+		enumValues := make([]any, 0, len(field.enum))
+		for _, value := range field.enum {
+			enumValues = append(enumValues, getEnumValue(elemType, value))
+		}
+		elemSchema["enum"] = enumValues
+	}
+
+	return fieldSchema, nil
+}
+
+// Converts the given allowed value of a field to the type of the field, so that the allowed values of numeric and boolean fields are documented as numbers and booleans.
+func getEnumValue(fieldType reflect.Type, value string) any {
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	case reflect.Float32, reflect.Float64:
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+
+	return value
+}
+
+// Returns the JSON schema of the values of the given type, as encoded by the encoding/json package. Named struct types are added to the components and referred to, so that recursive types can be documented.
+// Types with a custom JSON encoding (other than time.Time) are documented with an empty schema, which allows any value.
+func (schemas *openAPISchemas) getSchema(valueType reflect.Type) (map[string]any, error) {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	if valueType == reflect.TypeOf(time.Time{}) {
+		return map[string]any{ "type": "string", "format": "date-time" }, nil
+	} else if valueType.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) || reflect.PointerTo(valueType).Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) {
+		return map[string]any{}, nil
+	}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		return map[string]any{ "type": "string" }, nil
+	case reflect.Bool:
+		return map[string]any{ "type": "boolean" }, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return map[string]any{ "type": "integer", "format": "int32" }, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return map[string]any{ "type": "integer", "format": "int64" }, nil
+	case reflect.Float32:
+		return map[string]any{ "type": "number", "format": "float" }, nil
+	case reflect.Float64:
+		return map[string]any{ "type": "number", "format": "double" }, nil
+	case reflect.Slice, reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 && valueType.Kind() == reflect.Slice {
+			// Byte slices are encoded as base64 strings.
+			return map[string]any{ "type": "string", "format": "byte" }, nil
+		}
+		itemSchema, err := schemas.getSchema(valueType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{ "type": "array", "items": itemSchema }, nil
+	case reflect.Map:
+		if valueType.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("Map type %v must have string keys to be encoded as a JSON object", valueType)
+		}
+		valueSchema, err := schemas.getSchema(valueType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{ "type": "object", "additionalProperties": valueSchema }, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+	case reflect.Struct:
+		if valueType.Name() == "" {
+			return schemas.getStructSchema(valueType)
+		}
+
+		if name, found := schemas.names[valueType]; found {
+			return map[string]any{ "$ref": "#/components/schemas/" + name }, nil
+		}
+
+		name := valueType.Name()
+		if _, exists := schemas.components[name]; exists {
+			// Struct types with the same name in different packages are told apart by the name of their package.
+			name = strings.ReplaceAll(valueType.PkgPath(), "/", ".") + "." + name
+		}
+
+		schemas.names[valueType] = name
+		schemas.components[name] = map[string]any{}
+		structSchema, err := schemas.getStructSchema(valueType)
+		if err != nil {
+			return nil, err
+		}
+		schemas.components[name] = structSchema
+		return map[string]any{ "$ref": "#/components/schemas/" + name }, nil
+	default:
+		return nil, fmt.Errorf("Type %v cannot be encoded as JSON", valueType)
+	}
+}
+
+// Returns the JSON schema of the object encoding the values of the given struct type, with the fields named as per their "json" tags and with the validation rules in their "validate" tags.
+// The fields of the embedded structs without a "json" tag are documented as the fields of the struct, as they are encoded by the encoding/json package.
+func (schemas *openAPISchemas) getStructSchema(structType reflect.Type) (map[string]any, error) {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	for index := 0; index < structType.NumField(); index++ {
+		structField := structType.Field(index)
+		jsonName, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if jsonName == "-" || (!structField.IsExported() && !structField.Anonymous) {
+			continue
+		}
+
+		if embeddedType := structField.Type; structField.Anonymous && jsonName == "" {
+			for embeddedType.Kind() == reflect.Pointer {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				embeddedSchema, err := schemas.getStructSchema(embeddedType)
+				if err != nil {
+					return nil, err
+				}
+				for name, property := range embeddedSchema["properties"].(map[string]any) {
+					if _, exists := properties[name]; !exists {
+						properties[name] = property
+					}
+				}
+				if embeddedRequired, ok := embeddedSchema["required"].([]string); ok {
+					required = append(required, embeddedRequired...)
+				}
+				continue
+			} else if !structField.IsExported() {
+				continue
+			}
+		}
+
+		if jsonName == "" {
+			jsonName = structField.Name
+		}
+
+		field := validationField{ name: jsonName }
+		if err := field.parseRules(structField); err != nil {
+			return nil, err
+		}
+
+		fieldSchema, err := schemas.getFieldSchema(structField.Type, &field)
+		if err != nil {
+			return nil, err
+		}
+
+		properties[jsonName] = fieldSchema
+		if field.required {
+			required = append(required, jsonName)
+		}
+	}
+
+	structSchema := map[string]any{ "type": "object", "properties": properties }
+	if len(required) > 0 {
+		structSchema["required"] = required
+	}
+	return structSchema, nil
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// User sent in the responses documented in the test cases.
+type testUser struct {
+	ID int64 `json:"id"`
+	Name string `json:"name" validate:"required,max=50"`
+	Email string `json:"email,omitempty"`
+	Joined time.Time `json:"joined"`
+	Manager *testUser `json:"manager,omitempty"`
+	password string
+}
+
+// Test case to validate the OpenAPI document generated for the routes of a web server instance, including the parameters and the request body documented from the struct bound by the Validate middleware.
+func Test_Server_OpenAPI(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		return res.NoContent()
+	}
+	testServer.Get("/users/:id|int", handler)
+	testServer.Name("getUser")
+	testServer.Describe(RouteDoc{ Summary: "Get a user", Tags: []string{ "users" }, Responses: map[StatusCode]any{ StatusOK: testUser{}, StatusNotFound: nil } })
+	testServer.Post("/stores/:store/orders", handler, Validate(testOrderInput{}))
+	testServer.Describe(RouteDoc{ Summary: "Create an order", Request: testOrderInput{}, Deprecated: true })
+	testServer.Delete("/sessions/*token", handler)
+	testServer.Static("/files", t.TempDir())
+	if err := testServer.OpenAPI("/openapi.json", Info{ Title: "Store API", Version: "1.0.0" }); err != nil {
+		t.Fatalf("Was not expecting an error while defining the OpenAPI route, but got this instead - %v", err)
+	}
+
+	testRequest := newTestRequest(t)
+	testRequest.Method = "GET"
+	testRequest.ResourcePath = "/openapi.json"
+	testResponse := newTestResponse(t, "1.1")
+	testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+	testServer.processRequest(testRequest, testResponse)
+	var document map[string]any
+	if testResponse.StatusCode != int(StatusOK) {
+		t.Fatalf("Expected the OpenAPI document to be sent with the status 200, but got %d instead", testResponse.StatusCode)
+	} else if err := json.Unmarshal(testResponse.Body, &document); err != nil {
+		t.Fatalf("Was not expecting an error while decoding the OpenAPI document, but got this instead - %v", err)
+	}
+
+	testCases := []struct {
+		Name string
+		Path []string
+		ExpValue string
+	} {
+		{ "Information about the API", []string{ "info" }, `{"title":"Store API","version":"1.0.0"}` },
+		{ "Version of the OpenAPI specification", []string{ "openapi" }, `"3.0.3"` },
+		{ "Path parameter with a type constraint", []string{ "paths", "/users/{id}", "get", "parameters" }, `[{"in":"path","name":"id","required":true,"schema":{"pattern":"^(?:-?[0-9]+)$","type":"string"}}]` },
+		{ "Operation ID from the route name", []string{ "paths", "/users/{id}", "get", "operationId" }, `"getUser"` },
+		{ "Tags of the route", []string{ "paths", "/users/{id}", "get", "tags" }, `["users"]` },
+		{ "Response with a body", []string{ "paths", "/users/{id}", "get", "responses", "200" }, `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/testUser"}}},"description":"OK"}` },
+		{ "Response without a body", []string{ "paths", "/users/{id}", "get", "responses", "404" }, `{"description":"Not Found"}` },
+		{ "Schema of a recursive struct type", []string{ "components", "schemas", "testUser" }, `{"properties":{"email":{"type":"string"},"id":{"format":"int64","type":"integer"},"joined":{"format":"date-time","type":"string"},"manager":{"$ref":"#/components/schemas/testUser"},"name":{"maxLength":50,"type":"string"}},"required":["name"],"type":"object"}` },
+		{ "Parameters of the bound struct", []string{ "paths", "/stores/{store}/orders", "post", "parameters" }, `[{"in":"path","name":"store","required":true,"schema":{"pattern":"^[a-z]+$","type":"string"}},{"in":"query","name":"qty","required":true,"schema":{"format":"int64","maximum":10,"minimum":1,"type":"integer"}},{"in":"query","name":"tag","required":false,"schema":{"items":{"enum":["gift","express"],"type":"string"},"maxItems":2,"type":"array"}},{"in":"header","name":"X-Channel","required":true,"schema":{"enum":["web","mobile"],"type":"string"}}]` },
+		{ "Request body of the bound struct", []string{ "paths", "/stores/{store}/orders", "post", "requestBody" }, `{"content":{"application/json":{"schema":{"properties":{"item":{"maxLength":20,"minLength":3,"type":"string"},"note":{"type":"string"}},"required":["item"],"type":"object"}}},"required":true}` },
+		{ "Deprecated route", []string{ "paths", "/stores/{store}/orders", "post", "deprecated" }, `true` },
+		{ "Route without documentation", []string{ "paths", "/sessions/{token}", "delete" }, `{"parameters":[{"in":"path","name":"token","required":true,"schema":{"type":"string"}}],"responses":{"default":{"description":"Response of the route"}}}` },
+		{ "Static route left out", []string{ "paths", "/files" }, `null` },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			var value any = document
+			for _, key := range testCase.Path {
+				object, _ := value.(map[string]any)
+				value = object[key]
+			}
+
+			encodedValue, _ := json.Marshal(value)
+			if string(encodedValue) != testCase.ExpValue {
+				tt.Errorf("Expected the value at %v to be %s, but got %s instead", testCase.Path, testCase.ExpValue, string(encodedValue))
+			} else {
+				tt.Logf("Value at %v is %s as expected", testCase.Path, string(encodedValue))
+			}
+		})
+	}
+}
+
+// Test case to validate the errors returned while generating the OpenAPI document, for an API without a title and for a route whose request cannot be documented.
+func Test_Server_OpenAPIErrors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Info Info
+		Request any
+	} {
+		{ "API without a title", Info{ Version: "1.0.0" }, nil },
+		{ "Request which is not a struct", Info{ Title: "Store API", Version: "1.0.0" }, "not a struct" },
+		{ "Request with a field that cannot be encoded as JSON", Info{ Title: "Store API", Version: "1.0.0" }, struct{ Done chan bool `json:"done"` }{} },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testServer := NewServer()
+			testServer.Post("/orders", func(req *HttpRequest, res *HttpResponse) error { return nil })
+			testServer.Describe(RouteDoc{ Request: testCase.Request })
+			_, err := testServer.GenerateOpenAPI(testCase.Info)
+			if _, ok := err.(*RoutingError); !ok {
+				tt.Errorf("Was expecting a routing error, but got this error instead - %v", err)
+			} else {
+				tt.Logf("Was expecting a routing error and got one - %v", err)
+			}
+		})
+	}
+}
@@ -17,6 +17,21 @@ func (pr Params) Get(key string) ([]string, bool) {
 	return values, true
 }
 
+// Returns all the values in the map for the given key. An empty slice is returned if the key is not present in the collection.
+func (pr Params) GetAll(key string) []string {
+	values, ok := pr.Get(key)
+	if !ok {
+		return []string{}
+	}
+	return values
+}
+
+// Checks if the given key is present in the params collection.
+func (pr Params) Has(key string) bool {
+	_, ok := pr.Get(key)
+	return ok
+}
+
 // Adds the given key-values pair to the params collection.
 func (pr Params) Add(key string, paramValues []string) {
 	key = strings.TrimSpace(key)
@@ -65,4 +65,33 @@ func Test_Params_Get(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Test case to validate the working of checking the presence of a 'key' and fetching all its values from the params collection.
+func Test_Params_HasAndGetAll(t *testing.T) {
+	testParams := make(Params)
+	testParams.Add("Name", []string{ "proteus", "webserver" })
+	testCases := []struct {
+		Name string
+		ParamKey string
+		ExpPresent bool
+		ExpParamValues []string
+	} {
+		{ "Fetching parameter in the collection", "Name", true, []string{ "proteus", "webserver" } },
+		{ "Fetching parameter not in the collection", "Age", false, []string{} },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			if testParams.Has(testCase.ParamKey) != testCase.ExpPresent {
+				tt.Errorf("Expected presence of the key [%s] to be %t, but got %t", testCase.ParamKey, testCase.ExpPresent, testParams.Has(testCase.ParamKey))
+			}
+
+			values := testParams.GetAll(testCase.ParamKey)
+			if slices.Equal(testCase.ExpParamValues, values) {
+				tt.Logf("The returned slice of values [%v], matches the expected slice of values [%v]", values, testCase.ExpParamValues)
+			} else {
+				tt.Errorf("The returned slice of values [%v], does not match the expected slice of values [%v]", values, testCase.ExpParamValues)
+			}
+		})
+	}
+}
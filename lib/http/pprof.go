@@ -0,0 +1,54 @@
+package http
+
+import (
+	nethttp "net/http"
+	"net/http/pprof"
+)
+
+// Adds the routes serving the runtime profiling data of the net/http/pprof package under the given route prefix (like "/debug/pprof"), so that performance problems can be diagnosed on a live server
+// using "go tool pprof". The index of the profiles is served at the prefix, the CPU profile at "profile", the execution trace at "trace" and the named profiles (like heap, goroutine, block and mutex) at their names.
+// If the given authorization function is not nil, it is invoked for every request made to these routes and a 403 (Forbidden) response is sent if it returns false.
+func (srv *HttpServer) EnablePprof(Prefix string, authorize func(request *HttpRequest) bool) error {
+	authorizeProfiling := func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			if authorize != nil && !authorize(request) {
+				response.Status(StatusForbidden)
+				return handleError(request, response)
+			}
+			return next(request, response)
+		}
+	}
+
+	profileHandler := func(request *HttpRequest, response *HttpResponse) error {
+		profileNames, _ := request.Segments.Get("profile")
+		if len(profileNames) == 0 {
+			response.Status(StatusNotFound)
+			return handleError(request, response)
+		}
+		return WrapStdHandler(pprof.Handler(profileNames[0]))(request, response)
+	}
+
+	// The index is defined with a trailing '/', since the links to the profiles in the index are relative to it.
+	routes := []struct {
+		Method string
+		RoutePath string
+		RouteHandler Handler
+	} {
+		{ "GET", cleanRoute(Prefix) + "/", WrapStdHandler(nethttp.HandlerFunc(pprof.Index)) },
+		{ "GET", joinRoute(Prefix, "cmdline"), WrapStdHandler(nethttp.HandlerFunc(pprof.Cmdline)) },
+		{ "GET", joinRoute(Prefix, "profile"), WrapStdHandler(nethttp.HandlerFunc(pprof.Profile)) },
+		{ "GET", joinRoute(Prefix, "symbol"), WrapStdHandler(nethttp.HandlerFunc(pprof.Symbol)) },
+		{ "POST", joinRoute(Prefix, "symbol"), WrapStdHandler(nethttp.HandlerFunc(pprof.Symbol)) },
+		{ "GET", joinRoute(Prefix, "trace"), WrapStdHandler(nethttp.HandlerFunc(pprof.Trace)) },
+		{ "GET", joinRoute(Prefix, ":profile"), profileHandler },
+	}
+
+	for _, route := range routes {
+		err := srv.innerRouter.addDynamicRoute(route.Method, route.RoutePath, route.RouteHandler, authorizeProfiling)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
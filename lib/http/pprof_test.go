@@ -0,0 +1,57 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Test case to validate the routes serving the runtime profiling data, along with the authorization of the requests made to them.
+func Test_Server_EnablePprof(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	err := testServer.EnablePprof("/debug/pprof", func(request *HttpRequest) bool {
+		token, _ := request.Headers.Get("X-Debug-Token")
+		return token == "secret"
+	})
+	if err != nil {
+		t.Fatalf("Was not expecting an error while enabling the profiling routes, but got this instead - %v", err)
+	}
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Token string
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "Index of the profiles", "/debug/pprof/", "secret", int(StatusOK), "goroutine" },
+		{ "Goroutine profile in text format", "/debug/pprof/goroutine?debug=1", "secret", int(StatusOK), "goroutine profile:" },
+		{ "Command line of the program", "/debug/pprof/cmdline", "secret", int(StatusOK), "" },
+		{ "Profile that does not exist", "/debug/pprof/unknown", "secret", int(StatusNotFound), "" },
+		{ "Request that is not authorized", "/debug/pprof/heap", "guess", int(StatusForbidden), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testRequest.parseQueryParams()
+			testRequest.Headers.Add("X-Debug-Token", testCase.Token)
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.end()
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("Expected status code to be %d, but got %d", testCase.ExpStatus, testResponse.StatusCode)
+			} else if !strings.Contains(opBuffer.String(), testCase.ExpBody) {
+				tt.Errorf("Expected the response to contain [%s], but got [%s]", testCase.ExpBody, opBuffer.String())
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
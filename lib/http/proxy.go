@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Parses the request target of the request if it is in absolute form (like "http://example.com/users?page=2"), as sent by clients to a proxy. As per RFC 9112, the host in the request target
+// replaces the value of the Host header and the path (along with the query string) of the request target is used as the resource path. A request target without a path refers to the root path "/".
+func (req *HttpRequest) parseAbsoluteForm() error {
+	lowerTarget := strings.ToLower(req.ResourcePath)
+	if !strings.HasPrefix(lowerTarget, "http://") && !strings.HasPrefix(lowerTarget, "https://") {
+		return nil
+	}
+
+	targetURL, err := url.ParseRequestURI(req.ResourcePath)
+	if err != nil || targetURL.Host == "" || targetURL.User != nil || strings.IndexFunc(targetURL.Host, func(char rune) bool { return !isHostChar(char) }) != -1 {
+		reqError := new(RequestParseError)
+		reqError.Section = "Header"
+		reqError.Value = req.ResourcePath
+		reqError.Message = "Request target in absolute form must contain a valid host, without any user information"
+		reqError.Status = StatusBadRequest
+		return reqError
+	}
+
+	req.Headers["Host"] = []string{ targetURL.Host }
+	req.ResourcePath = targetURL.EscapedPath()
+	if req.ResourcePath == "" {
+		req.ResourcePath = "/"
+	}
+
+	if targetURL.RawQuery != "" || targetURL.ForceQuery {
+		req.ResourcePath += "?" + targetURL.RawQuery
+	}
+
+	return nil
+}
+
+// Sets the handler to be invoked for CONNECT requests whose request target is in authority form (like "example.com:443"), which ask the server to act as a forward proxy by establishing a tunnel to the given host and port.
+// The handler can inspect the request (like the Proxy-Authorization header) and establish the tunnel using Tunnel(), or send an error response instead. The middlewares of the web server instance are executed
+// before the handler. Such requests are rejected with a 501 (Not Implemented) response if no handler has been set or if they are made using HTTP/2. NewTunnelHandler() returns a handler which connects the client to the requested host.
+// CONNECT requests for a resource path are still routed to the routes defined using Connect().
+func (srv *HttpServer) OnConnect(handler Handler) {
+	srv.connectHandler = handler
+}
+
+// Creates the response for a CONNECT request in authority form using the handler set using OnConnect(). A 400 (Bad Request) response is sent if the request target is not of the form host:port.
+func (srv *HttpServer) handleConnect(httpRequest *HttpRequest, httpResponse *HttpResponse) {
+	var err error
+	if srv.connectHandler == nil || httpResponse.http2Writer != nil {
+		// Tunnels are not supported over HTTP/2, as they are established on the client connection.
+		httpResponse.Status(StatusNotImplemented)
+		err = handleError(httpRequest, httpResponse)
+	} else if host, port, splitErr := net.SplitHostPort(httpRequest.ResourcePath); splitErr != nil || host == "" || port == "" {
+		httpResponse.Status(StatusBadRequest)
+		err = handleError(httpRequest, httpResponse)
+	} else {
+		err = srv.invokeHandler(chainMiddlewares(srv.connectHandler, srv.innerRouter.getMiddlewares()), httpRequest, httpResponse)
+	}
+
+	if err != nil {
+		srv.getRequestLogger(httpRequest).Error(err.Error())
+	}
+}
+
+// Establishes the tunnel requested by a CONNECT request, by sending the 200 OK response and returning the client connection, on which the bytes sent by the client can be read and the bytes for the client can be written.
+// Once the tunnel has been established, the response must not be used any further and the connection is closed when the handler returns. The read and write timeouts of the server do not apply to the tunnel.
+// An error is returned if the request is not a CONNECT request made using HTTP/1.1 or the response has already been written.
+func (req *HttpRequest) Tunnel(res *HttpResponse) (net.Conn, error) {
+	if !strings.EqualFold(req.Method, "CONNECT") || res.connection == nil || res.writer == nil || res.isWritten {
+		resErr := new(ResponseError)
+		resErr.Section = "RespWrite"
+		resErr.Value = req.Method
+		resErr.Message = "Tunnel: A tunnel can be established only for a CONNECT request made using HTTP/1.1, before its response has been written"
+		return nil, resErr
+	}
+
+	// The connection watcher must be stopped before the bytes sent through the tunnel can be read from the request byte stream.
+	if req.stopWatching != nil {
+		req.stopWatching()
+	}
+
+	res.isWritten = true
+	res.closeConnection = true
+	res.runBeforeWriteHooks()
+	res.Status(StatusOK)
+	// As per RFC 9110, a successful response to a CONNECT request must not contain the Content-Length or Transfer-Encoding headers.
+	delete(res.Headers, "Content-Length")
+	delete(res.Headers, "Transfer-Encoding")
+	delete(res.Headers, "Connection")
+	err := res.writeStatusLine()
+	if err == nil {
+		err = res.writeHeaders()
+	}
+	if err == nil {
+		err = res.writer.Flush()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.connection.SetDeadline(time.Time{})
+	return &bufferedConnection{ Conn: res.connection, reader: req.reader }, nil
+}
+
+// Returns a handler for CONNECT requests which connects to the requested host using the given dial function and copies the bytes between the client and the host in both directions, until either side
+// closes its connection or the server shuts down. If the dial function is nil, a net.Dialer with a timeout of 30 seconds is used. A 502 (Bad Gateway) response is sent if the host could not be reached.
+func NewTunnelHandler(dial func(ctx context.Context, network string, address string) (net.Conn, error)) Handler {
+	if dial == nil {
+		dialer := &net.Dialer{ Timeout: 30 * time.Second }
+		dial = dialer.DialContext
+	}
+
+	return func(request *HttpRequest, response *HttpResponse) error {
+		upstream, err := dial(request.Context(), "tcp", request.ResourcePath)
+		if err != nil {
+			request.Logger().Warn("Tunnel could not be established as the host could not be reached", "host", request.ResourcePath, "error", err.Error())
+			response.Status(StatusBadGateway)
+			return handleError(request, response)
+		}
+
+		client, err := request.Tunnel(response)
+		if err != nil {
+			upstream.Close()
+			return err
+		}
+
+		var waitGroup sync.WaitGroup
+		copyCompleted := make(chan struct{}, 2)
+		copyBytes := func(destination io.Writer, source io.Reader) {
+			defer waitGroup.Done()
+			io.Copy(destination, source)
+			copyCompleted <- struct{}{}
+		}
+
+		waitGroup.Add(2)
+		go copyBytes(upstream, client)
+		go copyBytes(client, upstream)
+		select {
+		case <-copyCompleted:
+		case <-request.Context().Done():
+		}
+
+		// Closing both the connections unblocks the copy which is still in progress.
+		upstream.Close()
+		client.Close()
+		waitGroup.Wait()
+		return nil
+	}
+}
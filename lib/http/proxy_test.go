@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the parsing of request targets in absolute form, as sent by clients to a proxy, and in authority form, as sent in CONNECT requests.
+func Test_Request_AbsoluteForm(t *testing.T) {
+	testCases := []struct {
+		Name string
+		InputRequest string
+		ExpError bool
+		ExpHost string
+		ExpPath string
+		ExpQpCount int
+	} {
+		{ "Absolute form with a path and query", "GET http://example.com:8080/users/abc?name=sample HTTP/1.1\r\nHost: other.com\r\n\r\n", false, "example.com:8080", "/users/abc", 1 },
+		{ "Absolute form without a path", "GET HTTPS://example.com HTTP/1.1\r\nHost: example.com\r\n\r\n", false, "example.com", "/", 0 },
+		{ "Absolute form with encoded characters", "GET http://example.com/files/a%2Fb/../c%20d HTTP/1.1\r\nHost: example.com\r\n\r\n", false, "example.com", "/files/c d", 0 },
+		{ "Authority form of a CONNECT request", "CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\n", false, "example.com:443", "example.com:443", 0 },
+		{ "Absolute form without a host", "GET http:///users HTTP/1.1\r\nHost: example.com\r\n\r\n", true, "", "", 0 },
+		{ "Absolute form with user information", "GET http://admin@example.com/ HTTP/1.1\r\nHost: example.com\r\n\r\n", true, "", "", 0 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			host, _ := testReq.Headers.Get("Host")
+			if testCase.ExpError {
+				if err == nil {
+					tt.Errorf("Expected an error while parsing the request, but got none")
+				} else {
+					tt.Logf("Received the expected error - %v", err)
+				}
+			} else if err != nil {
+				tt.Errorf("Was not expecting an error while parsing the request and yet received one - %v", err)
+			} else if host != testCase.ExpHost || testReq.ResourcePath != testCase.ExpPath || testReq.Query.Length() != testCase.ExpQpCount {
+				tt.Errorf("Expected host [%s], path [%s] and %d query parameters, but got host [%s], path [%s] and %d query parameters", testCase.ExpHost, testCase.ExpPath, testCase.ExpQpCount, host, testReq.ResourcePath, testReq.Query.Length())
+			} else {
+				tt.Logf("Received host [%s] and path [%s] as expected", host, testReq.ResourcePath)
+			}
+		})
+	}
+}
+
+// Test case to validate the tunnels established for CONNECT requests by a web server instance acting as a forward proxy.
+func Test_Server_OnConnect(t *testing.T) {
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error occurred while creating the upstream listener - %v", err)
+	}
+	defer upstream.Close()
+	go func() {
+		for {
+			connection, err := upstream.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer connection.Close()
+				io.Copy(connection, connection)
+			}()
+		}
+	}()
+
+	closedListener, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddress := closedListener.Addr().String()
+	closedListener.Close()
+	newProxyServer := func(tt *testing.T, handler Handler) *HttpServer {
+		testServer := NewServer()
+		testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+		if handler != nil {
+			testServer.OnConnect(handler)
+		}
+		err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+		if err != nil {
+			tt.Fatalf("Was not expecting an error and yet received one - %v", err)
+		}
+		tt.Cleanup(func() { testServer.Shutdown() })
+		return testServer
+	}
+
+	authorize := func(next Handler) Handler {
+		return func(req *HttpRequest, res *HttpResponse) error {
+			if credentials, _ := req.Headers.Get("Proxy-Authorization"); credentials != "Basic secret" {
+				res.Status(StatusProxyAuthRequired)
+				return handleError(req, res)
+			}
+			return next(req, res)
+		}
+	}
+
+	testCases := []struct {
+		Name string
+		Handler Handler
+		Target string
+		Credentials string
+		ExpStatus string
+		ExpEcho bool
+	} {
+		{ "Tunnel to the requested host", NewTunnelHandler(nil), upstream.Addr().String(), "Basic secret", "HTTP/1.1 200 OK", true },
+		{ "Tunnel rejected by the handler", authorize(NewTunnelHandler(nil)), upstream.Addr().String(), "Basic wrong", "HTTP/1.1 407", false },
+		{ "Host that cannot be reached", NewTunnelHandler(nil), closedAddress, "", "HTTP/1.1 502", false },
+		{ "Request target without a port", NewTunnelHandler(nil), "example.com", "", "HTTP/1.1 400", false },
+		{ "Proxy without a CONNECT handler", nil, upstream.Addr().String(), "", "HTTP/1.1 501", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testServer := newProxyServer(tt, testCase.Handler)
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Error occurred while connecting to the proxy - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(3 * time.Second))
+			// The first bytes to be tunnelled are sent along with the request, to check that the bytes buffered while reading the request are tunnelled as well.
+			connection.Write([]byte("CONNECT " + testCase.Target + " HTTP/1.1\r\nHost: " + testCase.Target + "\r\nProxy-Authorization: " + testCase.Credentials + "\r\n\r\nping"))
+			reader := bufio.NewReader(connection)
+			statusLine, _ := reader.ReadString('\n')
+			responseHead := statusLine
+			for {
+				line, err := reader.ReadString('\n')
+				responseHead += line
+				if err != nil || line == "\r\n" {
+					break
+				}
+			}
+
+			if !strings.HasPrefix(statusLine, testCase.ExpStatus) {
+				tt.Fatalf("Expected the status line to start with [%s], but got [%s]", testCase.ExpStatus, statusLine)
+			}
+
+			if !testCase.ExpEcho {
+				tt.Logf("Received the status line [%s] as expected", strings.TrimSpace(statusLine))
+				return
+			}
+
+			if strings.Contains(responseHead, "Content-Length") || strings.Contains(responseHead, "Transfer-Encoding") {
+				tt.Errorf("Was not expecting the response to the CONNECT request to contain a body length, but got [%q]", responseHead)
+			}
+
+			connection.Write([]byte(" pong"))
+			echo := make([]byte, len("ping pong"))
+			_, err = io.ReadFull(reader, echo)
+			if err != nil || string(echo) != "ping pong" {
+				tt.Errorf("Expected the tunnel to echo [ping pong], but got [%s] with error %v", string(echo), err)
+			} else {
+				tt.Logf("The bytes sent through the tunnel have been echoed as expected")
+			}
+		})
+	}
+}
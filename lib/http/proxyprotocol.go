@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Maximum length (in bytes) of a version 1 PROXY protocol header, including the line break, as defined in the PROXY protocol specification.
+const PROXY_PROTOCOL_V1_MAX_LENGTH = 107
+
+// Signature sent at the start of a version 2 PROXY protocol header.
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// Listener which expects every connection accepted to start with a PROXY protocol (version 1 or 2) header, as sent by layer 4 load balancers (like HAProxy or AWS NLB) to pass on the address of the client.
+type proxyListener struct {
+	net.Listener
+}
+
+// Network connection whose remote address is the address of the client given in the PROXY protocol header sent at the start of the connection. The header is read along with the first read from the connection,
+// so that a slow load balancer does not block the acceptance of other connections.
+type proxyConnection struct {
+	net.Conn
+	// Buffered reader wrapping the network connection, from which the header and then the data sent on the connection are read.
+	reader *bufio.Reader
+	// Used to read the PROXY protocol header only once, along with the first read from the connection.
+	headerOnce sync.Once
+	// Error occurred while reading the PROXY protocol header, which is returned for every read from the connection.
+	headerErr error
+	// Address of the client given in the PROXY protocol header. It is nil if the header has not been read yet or does not contain the address of the client (like for health checks by the load balancer).
+	sourceAddress net.Addr
+	// Boolean value to indicate if the PROXY protocol header has been read.
+	isHeaderRead atomic.Bool
+}
+
+// Returns a listener which reads the PROXY protocol (version 1 or 2) header sent at the start of every connection accepted by the given listener, so that the remote address of the connection (and hence
+// the ClientAddress and RemoteIP() of the requests) is the address of the client rather than the address of the load balancer. Connections which do not start with a valid header are closed.
+// To serve TLS connections using Serve(), the listener must be wrapped before it is wrapped by TLS, since the header is sent before the TLS handshake.
+func NewProxyListener(listener net.Listener) net.Listener {
+	return &proxyListener{ Listener: listener }
+}
+
+// Accepts the next connection from the listener, whose PROXY protocol header is read along with the first read from the connection.
+func (pl *proxyListener) Accept() (net.Conn, error) {
+	connection, err := pl.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proxyConnection{ Conn: connection, reader: bufio.NewReader(connection) }, nil
+}
+
+// Reads the data sent on the connection, after the PROXY protocol header. The header is read first, if it has not been read yet.
+func (pc *proxyConnection) Read(data []byte) (int, error) {
+	pc.headerOnce.Do(pc.readHeader)
+	if pc.headerErr != nil {
+		return 0, pc.headerErr
+	}
+
+	return pc.reader.Read(data)
+}
+
+// Returns the address of the client given in the PROXY protocol header. The address of the load balancer is returned if the header has not been read yet or does not contain the address of the client.
+func (pc *proxyConnection) RemoteAddr() net.Addr {
+	if pc.isHeaderRead.Load() && pc.sourceAddress != nil {
+		return pc.sourceAddress
+	}
+
+	return pc.Conn.RemoteAddr()
+}
+
+// Reads the PROXY protocol header sent at the start of the connection, which is in the text format of version 1 or the binary format of version 2.
+func (pc *proxyConnection) readHeader() {
+	defer pc.isHeaderRead.Store(true)
+	// Only the first byte is peeked to tell the versions apart, since the connection may not contain any more bytes until a response is sent.
+	firstByte, err := pc.reader.Peek(1)
+	if err != nil {
+		pc.headerErr = err
+		return
+	}
+
+	if firstByte[0] == proxyProtocolV2Signature[0] {
+		pc.sourceAddress, pc.headerErr = readProxyHeaderV2(pc.reader)
+	} else if firstByte[0] == 'P' {
+		pc.sourceAddress, pc.headerErr = readProxyHeaderV1(pc.reader)
+	} else {
+		pc.headerErr = newProxyHeaderError("", "Connection does not start with a PROXY protocol header")
+	}
+}
+
+// Reads a version 1 PROXY protocol header (like "PROXY TCP4 192.0.2.1 198.51.100.1 56324 443") and returns the address of the client given in it. A nil address is returned for the "UNKNOWN" protocol.
+func readProxyHeaderV1(reader *bufio.Reader) (net.Addr, error) {
+	headerLine, err := reader.ReadSlice('\n')
+	if err != nil || len(headerLine) > PROXY_PROTOCOL_V1_MAX_LENGTH || !bytes.HasPrefix(headerLine, []byte("PROXY ")) || !bytes.HasSuffix(headerLine, []byte(HEADER_LINE_SEPERATOR)) {
+		return nil, newProxyHeaderError(string(headerLine), "Version 1 header must be a single line of at most 107 bytes, ending with CRLF")
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(headerLine), HEADER_LINE_SEPERATOR), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	} else if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, newProxyHeaderError(string(headerLine), "Version 1 header must contain the protocol, the source and destination addresses and the source and destination ports")
+	}
+
+	sourceAddress, err := netip.ParseAddr(fields[2])
+	sourcePort, portErr := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil || portErr != nil || sourceAddress.Is4() != (fields[1] == "TCP4") {
+		return nil, newProxyHeaderError(string(headerLine), "Version 1 header contains an invalid source address or port")
+	}
+
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(sourceAddress, uint16(sourcePort))), nil
+}
+
+// Reads a version 2 PROXY protocol header and returns the address of the client given in it. A nil address is returned for the LOCAL command (sent by the load balancer for its own connections, like health checks)
+// and for address families other than TCP or UDP over IPv4 and IPv6. The type-length-value fields following the addresses are skipped.
+func readProxyHeaderV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, newProxyHeaderError("", fmt.Sprintf("Error while reading the version 2 header :: %s", err.Error()))
+	} else if !bytes.Equal(header[:12], proxyProtocolV2Signature) {
+		return nil, newProxyHeaderError("", "Version 2 header does not start with the signature of the PROXY protocol")
+	}
+
+	version, command, family := header[12] >> 4, header[12] & 0x0F, header[13] >> 4
+	if version != 2 || command > 1 {
+		return nil, newProxyHeaderError(fmt.Sprintf("%#x", header[12]), "Version 2 header contains an unsupported version or command")
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, newProxyHeaderError("", fmt.Sprintf("Error while reading the addresses of the version 2 header :: %s", err.Error()))
+	}
+
+	var sourceAddress netip.Addr
+	var sourcePort uint16
+	switch {
+	case command == 0:
+		return nil, nil
+	case family == 1 && len(payload) >= 12:
+		sourceAddress = netip.AddrFrom4([4]byte(payload[0:4]))
+		sourcePort = binary.BigEndian.Uint16(payload[8:10])
+	case family == 2 && len(payload) >= 36:
+		sourceAddress = netip.AddrFrom16([16]byte(payload[0:16]))
+		sourcePort = binary.BigEndian.Uint16(payload[32:34])
+	case family == 1 || family == 2:
+		return nil, newProxyHeaderError(fmt.Sprintf("%#x", header[13]), "Version 2 header is too short to contain the addresses of its address family")
+	default:
+		return nil, nil
+	}
+
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(sourceAddress, sourcePort)), nil
+}
+
+// Creates the error returned when the PROXY protocol header sent at the start of a connection is missing or invalid.
+func newProxyHeaderError(Value string, Message string) error {
+	reqError := new(RequestParseError)
+	reqError.Section = "ProxyHeader"
+	reqError.Value = strings.TrimSpace(Value)
+	reqError.Message = Message
+	return reqError
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Helper function to create a version 2 PROXY protocol header with the given command and address family byte, followed by the given addresses.
+func newProxyHeaderV2(command byte, family byte, addresses []byte) string {
+	header := append([]byte{}, proxyProtocolV2Signature...)
+	header = append(header, 0x20 | command, family)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(addresses)))
+	return string(append(header, addresses...))
+}
+
+// Test case to validate that the address of the client is recovered from the PROXY protocol header sent at the start of each connection, and that connections without a valid header are closed.
+func Test_Server_ProxyProtocol(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(lockedBuffer), LevelDebug, TextLogFormat))
+	testServer.Config.ProxyProtocol = true
+	testServer.Get("/ip", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Body = []byte(req.RemoteIP())
+		return nil
+	})
+
+	if err := testServer.ListenAndServeAsync(0, "127.0.0.1"); err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	ipv4Addresses := []byte{ 192, 0, 2, 10, 127, 0, 0, 1, 0xDC, 0x04, 0x1F, 0x90 }
+	ipv6Addresses := make([]byte, 36)
+	copy(ipv6Addresses, net.ParseIP("2001:db8::5"))
+	testCases := []struct {
+		Name string
+		ProxyHeader string
+		ExpResponse string
+	} {
+		{ "Version 1 header for TCP over IPv4", "PROXY TCP4 198.51.100.22 127.0.0.1 35646 8080\r\n", "198.51.100.22" },
+		{ "Version 1 header for TCP over IPv6", "PROXY TCP6 2001:db8::1 ::1 35646 8080\r\n", "2001:db8::1" },
+		{ "Version 1 header with an unknown protocol", "PROXY UNKNOWN\r\n", "127.0.0.1" },
+		{ "Version 2 header for TCP over IPv4", newProxyHeaderV2(0x01, 0x11, ipv4Addresses), "192.0.2.10" },
+		{ "Version 2 header for TCP over IPv6", newProxyHeaderV2(0x01, 0x21, ipv6Addresses), "2001:db8::5" },
+		{ "Version 2 header with the LOCAL command", newProxyHeaderV2(0x00, 0x00, []byte{}), "127.0.0.1" },
+		{ "Version 1 header with an invalid source address", "PROXY TCP4 example.com 127.0.0.1 35646 8080\r\n", "" },
+		{ "Connection without a header", "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while connecting to the server, but got this instead - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(2 * time.Second))
+			connection.Write([]byte(testCase.ProxyHeader + "GET /ip HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"))
+			response, _ := io.ReadAll(connection)
+			_, body, _ := strings.Cut(string(response), "\r\n\r\n")
+			if testCase.ExpResponse == "" && len(response) != 0 {
+				tt.Errorf("Expected the connection to be closed without a response, but got this instead - %s", string(response))
+			} else if testCase.ExpResponse != "" && (!strings.HasPrefix(string(response), "HTTP/1.1 200") || body != testCase.ExpResponse) {
+				tt.Errorf("Expected the client address [%s] in the response, but got this instead - %s", testCase.ExpResponse, string(response))
+			} else {
+				tt.Logf("Received the response [%s] as expected", body)
+			}
+		})
+	}
+}
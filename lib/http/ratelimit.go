@@ -0,0 +1,305 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mkbworks/proteus/lib/config"
+)
+
+// Structure to represent the rate limiting state stored for a single key. The fields used depend on the rate limiting strategy.
+type RateLimitState struct {
+	// Number of tokens left in the bucket. Used by the token bucket strategy.
+	Tokens float64
+	// Number of requests made in the current window. Used by the sliding window strategy.
+	Count int64
+	// Number of requests made in the previous window. Used by the sliding window strategy.
+	PreviousCount int64
+	// Time at which the current window started. Used by the sliding window strategy.
+	WindowStart time.Time
+	// Time at which the state was last updated.
+	UpdatedAt time.Time
+}
+
+// Structure to represent the outcome of recording a request against a rate limit.
+type RateLimitResult struct {
+	// Boolean value to indicate if the request is allowed.
+	Allowed bool
+	// Maximum number of requests allowed by the rate limit.
+	Limit int
+	// Number of requests that can still be made right away.
+	Remaining int
+	// Duration to wait before a new request can be allowed. It is zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// Represents an algorithm used to decide if a request must be allowed, based on the state stored for its key.
+type RateLimitStrategy interface {
+	// Records a request made at the given time against the given state and returns the updated state along with the outcome. The found flag is false if no state was stored for the key.
+	Take(State RateLimitState, Found bool, Now time.Time) (RateLimitState, RateLimitResult)
+	// Returns the duration after which an unused state is equivalent to a fresh state and hence can be evicted from the store.
+	StateTTL() time.Duration
+}
+
+// Represents a store where the rate limiting state of every key is persisted. The store can be shared by multiple server instances (like a Redis backed store) to enforce a distributed rate limit.
+type RateLimitStore interface {
+	// Atomically replaces the state stored against the given key with the state returned by the given function, which is invoked with the current state.
+	// The found flag passed to the function is false if no state is stored for the key (or it has expired). The stored state expires after the given time to live.
+	Update(Key string, TTL time.Duration, apply func(State RateLimitState, Found bool) RateLimitState) error
+}
+
+// Token bucket rate limiting strategy. Each key has a bucket of tokens, which is refilled at a constant rate. Every request takes a token from the bucket and is rejected if the bucket is empty, allowing short bursts up to the capacity of the bucket.
+type TokenBucket struct {
+	// Maximum number of tokens in the bucket, which is the maximum burst of requests allowed.
+	Capacity int
+	// Duration after which a single token is added back to the bucket.
+	RefillInterval time.Duration
+}
+
+// Records a request against the token bucket of the given state and returns the outcome.
+func (tb TokenBucket) Take(State RateLimitState, Found bool, Now time.Time) (RateLimitState, RateLimitResult) {
+	capacity := float64(tb.Capacity)
+	if !Found {
+		State = RateLimitState{ Tokens: capacity, UpdatedAt: Now }
+	}
+
+	if elapsed := Now.Sub(State.UpdatedAt); elapsed > 0 && tb.RefillInterval > 0 {
+		State.Tokens = math.Min(capacity, State.Tokens + float64(elapsed) / float64(tb.RefillInterval))
+	}
+	State.UpdatedAt = Now
+
+	result := RateLimitResult{ Limit: tb.Capacity }
+	if State.Tokens >= 1 {
+		State.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - State.Tokens) * float64(tb.RefillInterval))
+	}
+
+	result.Remaining = int(State.Tokens)
+	return State, result
+}
+
+// Returns the time taken to refill an empty bucket completely.
+func (tb TokenBucket) StateTTL() time.Duration {
+	return time.Duration(tb.Capacity) * tb.RefillInterval
+}
+
+// Sliding window rate limiting strategy. At most Limit requests are allowed in any window of the given duration. The number of requests in the sliding window is
+// estimated from the counts of the current and the previous fixed windows, weighted by the overlap of the sliding window with the previous window.
+type SlidingWindow struct {
+	// Maximum number of requests allowed in the window.
+	Limit int
+	// Duration of the window.
+	Window time.Duration
+}
+
+// Records a request against the sliding window of the given state and returns the outcome.
+func (sw SlidingWindow) Take(State RateLimitState, Found bool, Now time.Time) (RateLimitState, RateLimitResult) {
+	windowStart := Now.Truncate(sw.Window)
+	if !Found {
+		State = RateLimitState{ WindowStart: windowStart }
+	}
+
+	if !State.WindowStart.Equal(windowStart) {
+		if State.WindowStart.Equal(windowStart.Add(-sw.Window)) {
+			State.PreviousCount = State.Count
+		} else {
+			State.PreviousCount = 0
+		}
+		State.Count = 0
+		State.WindowStart = windowStart
+	}
+	State.UpdatedAt = Now
+
+	previousWeight := 1 - float64(Now.Sub(windowStart)) / float64(sw.Window)
+	estimatedCount := float64(State.PreviousCount) * previousWeight + float64(State.Count)
+	result := RateLimitResult{ Limit: sw.Limit }
+	if estimatedCount + 1 <= float64(sw.Limit) {
+		State.Count++
+		result.Allowed = true
+		result.Remaining = int(float64(sw.Limit) - estimatedCount - 1)
+		return State, result
+	}
+
+	// The request can be allowed once enough of the previous window slides out of the sliding window, or else once the current window ends.
+	retryAt := windowStart.Add(sw.Window)
+	excessCount := estimatedCount + 1 - float64(sw.Limit)
+	if State.PreviousCount > 0 && excessCount <= float64(State.PreviousCount) * previousWeight {
+		retryAt = Now.Add(time.Duration(excessCount / float64(State.PreviousCount) * float64(sw.Window)))
+	}
+
+	result.RetryAfter = retryAt.Sub(Now)
+	return State, result
+}
+
+// Returns the duration after which the counts of an unused state no longer affect the sliding window.
+func (sw SlidingWindow) StateTTL() time.Duration {
+	return 2 * sw.Window
+}
+
+// Structure containing the settings of the rate limiting middleware.
+type RateLimitConfig struct {
+	// Strategy used to decide if a request must be allowed.
+	Strategy RateLimitStrategy
+	// Store where the rate limiting state of every key is persisted. If nil, an in-memory store is used.
+	Store RateLimitStore
+	// Function returning the key against which a request is rate limited, like an API key or the authenticated user. If nil, the IP address of the client is used.
+	KeyFunc func(request *HttpRequest) string
+}
+
+// Returns a middleware which rate limits the requests using the strategy and the store in the given configuration. Requests exceeding the rate limit are rejected
+// with a 429 (Too Many Requests) response containing the Retry-After header. The RateLimit-Limit and RateLimit-Remaining headers are added to every response.
+// An error is returned if the configuration has no strategy, or if the limit or the duration of a TokenBucket or SlidingWindow strategy is not positive.
+func RateLimit(config RateLimitConfig) (Middleware, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	store := config.Store
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+
+	keyFunc := config.KeyFunc
+	if keyFunc == nil {
+		keyFunc = getClientIP
+	}
+
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			var result RateLimitResult
+			err := store.Update(keyFunc(request), config.Strategy.StateTTL(), func(State RateLimitState, Found bool) RateLimitState {
+				State, result = config.Strategy.Take(State, Found, time.Now())
+				return State
+			})
+			if err != nil {
+				return err
+			}
+
+			response.Headers.Add("RateLimit-Limit", strconv.Itoa(result.Limit))
+			response.Headers.Add("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				// Retry-After is sent in whole seconds, rounded up so that the client does not retry too early.
+				response.Headers.Add("Retry-After", strconv.FormatInt(int64(math.Ceil(result.RetryAfter.Seconds())), 10))
+				response.Status(StatusTooManyRequests)
+				return handleError(request, response)
+			}
+
+			return next(request, response)
+		}
+	}, nil
+}
+
+// Validates the strategy in the rate limiting configuration, so that an invalid configuration is reported when the middleware is created rather than while processing the requests.
+func (rlc RateLimitConfig) validate() error {
+	var message string
+	switch strategy := rlc.Strategy.(type) {
+	case nil:
+		message = "RateLimit: A rate limiting strategy must be given"
+	case TokenBucket:
+		message = strategy.validate()
+	case *TokenBucket:
+		if strategy == nil {
+			message = "RateLimit: A rate limiting strategy must be given"
+		} else {
+			message = strategy.validate()
+		}
+	case SlidingWindow:
+		message = strategy.validate()
+	case *SlidingWindow:
+		if strategy == nil {
+			message = "RateLimit: A rate limiting strategy must be given"
+		} else {
+			message = strategy.validate()
+		}
+	}
+
+	if message != "" {
+		ce := new(config.ConfigError)
+		ce.Message = message
+		return ce
+	}
+
+	return nil
+}
+
+// Returns the reason why the token bucket strategy is invalid, or an empty string if it is valid.
+func (tb TokenBucket) validate() string {
+	if tb.Capacity <= 0 {
+		return fmt.Sprintf("RateLimit: Capacity of the token bucket must be positive, but is %d", tb.Capacity)
+	} else if tb.RefillInterval <= 0 {
+		return fmt.Sprintf("RateLimit: Refill interval of the token bucket must be positive, but is %v", tb.RefillInterval)
+	}
+
+	return ""
+}
+
+// Returns the reason why the sliding window strategy is invalid, or an empty string if it is valid.
+func (sw SlidingWindow) validate() string {
+	if sw.Limit <= 0 {
+		return fmt.Sprintf("RateLimit: Limit of the sliding window must be positive, but is %d", sw.Limit)
+	} else if sw.Window <= 0 {
+		return fmt.Sprintf("RateLimit: Duration of the sliding window must be positive, but is %v", sw.Window)
+	}
+
+	return ""
+}
+
+// Returns the IP address of the client who made the given request, resolved through the trusted proxies.
+func getClientIP(request *HttpRequest) string {
+	return request.RemoteIP()
+}
+
+// Structure to represent the rate limiting state of a key stored in memory.
+type memoryRateLimitEntry struct {
+	// Rate limiting state of the key.
+	state RateLimitState
+	// Time after which the state expires.
+	expiresAt time.Time
+}
+
+// In-memory rate limit store, which can be used when the rate limit need not be shared across server instances. States which have expired are evicted periodically.
+type MemoryRateLimitStore struct {
+	// Duration between two evictions of the expired states from the store.
+	EvictionInterval time.Duration
+	// Collection of the rate limiting states of all the keys, with the key as map key.
+	entries map[string]memoryRateLimitEntry
+	// Time at which the expired states were last evicted from the store.
+	lastEvictedAt time.Time
+	// Mutex to synchronize access to the states in the store.
+	mutex sync.Mutex
+}
+
+// Replaces the state stored against the given key with the state returned by the given function. Expired states are evicted from the store once every eviction interval.
+func (mrs *MemoryRateLimitStore) Update(Key string, TTL time.Duration, apply func(State RateLimitState, Found bool) RateLimitState) error {
+	mrs.mutex.Lock()
+	defer mrs.mutex.Unlock()
+	currentTime := time.Now()
+	entry, found := mrs.entries[Key]
+	if found && currentTime.After(entry.expiresAt) {
+		found = false
+	}
+
+	mrs.entries[Key] = memoryRateLimitEntry{ state: apply(entry.state, found), expiresAt: currentTime.Add(TTL) }
+	if currentTime.Sub(mrs.lastEvictedAt) >= mrs.EvictionInterval {
+		for entryKey, entry := range mrs.entries {
+			if currentTime.After(entry.expiresAt) {
+				delete(mrs.entries, entryKey)
+			}
+		}
+		mrs.lastEvictedAt = currentTime
+	}
+
+	return nil
+}
+
+// Returns the number of keys (including the expired keys not evicted yet) present in the store.
+func (mrs *MemoryRateLimitStore) Length() int {
+	mrs.mutex.Lock()
+	defer mrs.mutex.Unlock()
+	return len(mrs.entries)
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mkbworks/proteus/lib/config"
+)
+
+// Test case to validate the decisions made by the rate limiting strategies for a sequence of requests.
+func Test_RateLimitStrategy_Take(t *testing.T) {
+	startTime := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		Name string
+		Strategy RateLimitStrategy
+		RequestOffsets []time.Duration
+		ExpAllowed []bool
+		ExpLastRetryAfter time.Duration
+	} {
+		{ "Token bucket allows a burst up to its capacity", TokenBucket{ Capacity: 2, RefillInterval: time.Second }, []time.Duration{ 0, 0, 0 }, []bool{ true, true, false }, time.Second },
+		{ "Token bucket refills the tokens over time", TokenBucket{ Capacity: 2, RefillInterval: time.Second }, []time.Duration{ 0, 0, 1500 * time.Millisecond, 1500 * time.Millisecond }, []bool{ true, true, true, false }, 500 * time.Millisecond },
+		{ "Sliding window rejects requests over the limit", SlidingWindow{ Limit: 2, Window: time.Minute }, []time.Duration{ 0, time.Second, 2 * time.Second }, []bool{ true, true, false }, 58 * time.Second },
+		{ "Sliding window weighs the previous window", SlidingWindow{ Limit: 2, Window: time.Minute }, []time.Duration{ 0, time.Second, 75 * time.Second, 105 * time.Second }, []bool{ true, true, false, true }, 0 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			var state RateLimitState
+			var result RateLimitResult
+			for index, offset := range testCase.RequestOffsets {
+				state, result = testCase.Strategy.Take(state, index > 0, startTime.Add(offset))
+				if result.Allowed != testCase.ExpAllowed[index] {
+					tt.Errorf("Expected request %d to have allowed as %t, but got %t", index + 1, testCase.ExpAllowed[index], result.Allowed)
+				}
+			}
+
+			if result.RetryAfter != testCase.ExpLastRetryAfter {
+				tt.Errorf("Expected the retry duration of the last request to be %v, but got %v", testCase.ExpLastRetryAfter, result.RetryAfter)
+			} else {
+				tt.Logf("The last request has the expected retry duration of %v", result.RetryAfter)
+			}
+		})
+	}
+}
+
+// Test case to validate the rate limiting middleware, with the requests keyed by the client IP address.
+func Test_Server_RateLimit(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	rateLimiter, err := RateLimit(RateLimitConfig{ Strategy: TokenBucket{ Capacity: 1, RefillInterval: time.Hour } })
+	if err != nil {
+		t.Fatalf("Was not expecting an error while creating the rate limiter, but got this instead - %v", err)
+	}
+
+	testServer.Use(rateLimiter)
+	testServer.Get("/limited", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return nil
+	})
+
+	testCases := []struct {
+		Name string
+		ClientAddress string
+		ExpStatus int
+		ExpRetryAfter string
+	} {
+		{ "First request from a client", "10.0.0.1:5000", int(StatusOK), "" },
+		{ "Second request from the same client", "10.0.0.1:5001", int(StatusTooManyRequests), "3600" },
+		{ "First request from another client", "10.0.0.2:5000", int(StatusOK), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/limited"
+			testRequest.ClientAddress = testCase.ClientAddress
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			retryAfter, _ := testResponse.Headers.Get("Retry-After")
+			if testResponse.StatusCode != testCase.ExpStatus || retryAfter != testCase.ExpRetryAfter {
+				tt.Errorf("Received status %d with Retry-After [%s], but expected status %d with Retry-After [%s]", testResponse.StatusCode, retryAfter, testCase.ExpStatus, testCase.ExpRetryAfter)
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate if an invalid rate limiting configuration is rejected when the middleware is created.
+func Test_RateLimit_InvalidConfig(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Config RateLimitConfig
+		ExpectErr bool
+	} {
+		{ "Configuration without a strategy", RateLimitConfig{}, true },
+		{ "Token bucket without any capacity", RateLimitConfig{ Strategy: TokenBucket{ Capacity: 0, RefillInterval: time.Second } }, true },
+		{ "Token bucket without a refill interval", RateLimitConfig{ Strategy: &TokenBucket{ Capacity: 10 } }, true },
+		{ "Sliding window without a duration", RateLimitConfig{ Strategy: SlidingWindow{ Limit: 10, Window: 0 } }, true },
+		{ "Sliding window with a negative limit", RateLimitConfig{ Strategy: SlidingWindow{ Limit: -1, Window: time.Minute } }, true },
+		{ "Nil pointer to a sliding window", RateLimitConfig{ Strategy: (*SlidingWindow)(nil) }, true },
+		{ "Valid sliding window", RateLimitConfig{ Strategy: SlidingWindow{ Limit: 10, Window: time.Minute } }, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			middleware, err := RateLimit(testCase.Config)
+			if testCase.ExpectErr {
+				if _, ok := err.(*config.ConfigError); !ok || middleware != nil {
+					tt.Errorf("Expected a configuration error and no middleware, but got this error instead - %v", err)
+				} else {
+					tt.Logf("Was expecting a configuration error and got one - %v", err)
+				}
+			} else if err != nil || middleware == nil {
+				tt.Errorf("Was not expecting an error and yet got this error - %v", err)
+			} else {
+				tt.Logf("The middleware was created as expected")
+			}
+		})
+	}
+}
+
+// Test case to validate the eviction of expired states from the in-memory rate limit store.
+func Test_MemoryRateLimitStore_Eviction(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	store.EvictionInterval = 0
+	apply := func(State RateLimitState, Found bool) RateLimitState {
+		return State
+	}
+
+	store.Update("expired", -time.Second, apply)
+	store.Update("active", time.Minute, apply)
+	if store.Length() != 1 {
+		t.Errorf("Expected only the active key to be left in the store, but found %d keys", store.Length())
+	}
+}
@@ -2,50 +2,125 @@ package http
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/textproto"
 	"net/url"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"github.com/mkbworks/proteus/lib/fs"
 )
 
+// Represents a value that can validate itself once it has been bound from the request.
+type Validator interface {
+	// Returns an error if the value is not valid.
+	Validate() error
+}
+
 // Structure to represent a HTTP request received by the web server.
 type HttpRequest struct {
 	// HTTP request method like GET, POST, PUT etc.
 	Method string
-	// Resource path requested by the client.
+	// Resource path requested by the client, normalized for routing. Percent-encoded characters are decoded (except '/', '%' and control characters, which are kept encoded so that they are not
+	// confused with the path separator), duplicate slashes are merged and dot-segments (like "." and "..") are resolved.
 	ResourcePath string
+	// Resource path as received from the client, before it was decoded and normalized. It does not contain the query string.
+	RawPath string
+	// Request target exactly as it was sent in the request line. It is in absolute form (like "http://example.com/users") for requests sent to a proxy and in authority form (like "example.com:443") for CONNECT requests.
+	RequestURI string
 	// HTTP version that the request complies with. It is of format <major>.<minor> which refers to the major and minor versions respectively.
 	Version string
 	// Collection of all the request headers received.
 	Headers Headers
 	// Represents the complete contents of the request body.
 	Body []byte
-	// Total length of the request body (in bytes).
+	// Total length of the request body (in bytes). For a request body sent using the chunked transfer coding, it is the length of the decoded body.
 	ContentLength int
+	// Collection of all the trailer fields received after a request body sent using the chunked transfer coding.
+	Trailers Headers
 	// Streamed reader instance to read the HTTP request from the network stream.
 	reader *bufio.Reader
 	// Contains the target file path in case the request is for a static file.
 	staticFilePath string
+	// Contains the static route matched for the request. It is nil if the request is not for a static file.
+	staticRoute *Route
 	// Collection of all query parameters stored as key-values pair.
 	Query Params
+	// Query string present in the request URL, without the leading '?'.
+	RawQuery string
 	// Collection of all path parameter values stored as key-value pair.
 	Segments Params
 	// The IP address and port number of the client who made the request to the server
 	ClientAddress string
+	// Context associated with the request. It is cancelled when the client disconnects, the request has been processed or the server shuts down.
+	ctx context.Context
+	// Session associated with the request. It is nil if sessions have not been enabled for the web server instance.
+	session *Session
+	// Multipart form parsed from the request body. It is nil until ParseMultipart() is called successfully.
+	multipartForm *MultipartForm
+	// Collection of form values parsed from the request body and the query string. It is nil until Form() is called successfully.
+	form Params
+	// Function to stop watching the client connection for disconnection while the request is being processed. It is nil if the connection is not being watched.
+	stopWatching func()
+	// Unique ID assigned to the request by the web server. It is empty until the request has been read completely.
+	requestID string
+	// Logger which adds the request ID to every log entry recorded for the request.
+	logger Logger
+	// Boolean value to indicate if the request body is sent using the chunked transfer coding.
+	isChunked bool
+	// Settings of the web server instance which received the request, used to limit the size of the request head.
+	config *ServerConfig
+	// Details of the TLS connection on which the request has been received. It is nil if the request has not been received over TLS.
+	tlsInfo *TLSInfo
+	// State of the CSRF protection for the request. It is nil if the CSRF middleware has not been added to the route matched.
+	csrf *csrfState
+	// Maximum size (in bytes) of the request body set for the route matching the request. It is zero if the "max_body_size" server default applies.
+	maxBodySize int64
 }
 
 // Initializes the instance of HttpRequest with default values for all its fields. 
 func (req *HttpRequest) initialize() {
 	req.Body = make([]byte, 0)
 	req.Headers = make(Headers)
+	req.Trailers = make(Headers)
 	req.Version = getHighestVersion()
 	req.staticFilePath = ""
-	req.Query = nil
-	req.Segments = nil
+	req.RawPath = ""
+	req.Query = make(Params)
+	req.Segments = make(Params)
+}
+
+// Returns the resource path as received from the client. The normalized resource path is returned if the raw path is not available, like for requests that have not been parsed from a byte stream.
+func (req *HttpRequest) getRawPath() string {
+	if req.RawPath == "" {
+		return req.ResourcePath
+	}
+
+	return req.RawPath
+}
+
+// Returns the context associated with the request. The context is cancelled when the client disconnects, when the request has been processed or when the server shuts down.
+func (req *HttpRequest) Context() context.Context {
+	if req.ctx == nil {
+		return context.Background()
+	}
+	return req.ctx
+}
+
+// Stores the given value against the given key in the request context. This can be used by middlewares to pass data (like authenticated identity or request ID) to the handlers.
+func (req *HttpRequest) SetValue(key any, value any) {
+	req.ctx = context.WithValue(req.Context(), key, value)
+}
+
+// Returns the value stored against the given key in the request context, or nil if no value is stored against the key.
+func (req *HttpRequest) GetValue(key any) any {
+	return req.Context().Value(key)
 }
 
 // Assigns the stream reader field of HttpRequest with a valid request stream.
@@ -55,24 +130,51 @@ func (req *HttpRequest) setReader(reader *bufio.Reader) {
 
 // Reads bytes of data from request byte stream and stores it in individual fields of HttpRequest instance.
 func (req *HttpRequest) read() error {
+	err := req.readHead()
+	if err != nil {
+		return err
+	}
+
+	err = req.validateBodySize()
+	if err != nil {
+		return err
+	}
+
+	return req.readBody()
+}
+
+// Reads the request line and the request headers from the request byte stream and parses the length of the request body declared in the headers.
+func (req *HttpRequest) readHead() error {
 	err := req.readHeader()
 	if err != nil {
 		return err
 	}
 
+	err = req.validateHost()
+	if err != nil {
+		return err
+	}
+
+	err = req.parseAbsoluteForm()
+	if err != nil {
+		return err
+	}
+
 	err = req.parseQueryParams()
 	if err != nil {
 		return err
 	}
 
-	clength, ok := req.Headers.Get("Content-Length")
-	if ok {
-		req.ContentLength, err = strconv.Atoi(clength)
+	transferEncoding, isEncoded := req.Headers.Get("Transfer-Encoding")
+	if isEncoded {
+		err = req.validateTransferEncoding(transferEncoding)
 		if err != nil {
 			return err
 		}
+	}
 
-		err = req.readBody()
+	if contentLengths := req.Headers["Content-Length"]; len(contentLengths) > 0 && !req.isChunked {
+		err = req.parseContentLength(contentLengths)
 		if err != nil {
 			return err
 		}
@@ -81,76 +183,130 @@ func (req *HttpRequest) read() error {
 	return nil
 }
 
-// Reads the values for all request headers and stores them in the HttpRequest instance.
+// Parses the given values of the Content-Length header into the length of the request body. As per RFC 9112, a request whose body length cannot be determined reliably is rejected, since a proxy in front
+// of the server could determine a different length and treat the rest of the body as another request (request smuggling). Hence, the header must be sent exactly once, with a single value made of digits only.
+func (req *HttpRequest) parseContentLength(contentLengths []string) error {
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Value = strings.Join(contentLengths, ",")
+	reqError.Status = StatusBadRequest
+	if len(contentLengths) > 1 || strings.Contains(contentLengths[0], ",") {
+		reqError.Message = "Request must not contain multiple Content-Length values"
+		return reqError
+	}
+
+	clength := strings.TrimSpace(contentLengths[0])
+	contentLength, err := strconv.Atoi(clength)
+	if clength == "" || strings.Trim(clength, "0123456789") != "" || err != nil {
+		reqError.Message = "Content-Length header value must be a non-negative integer"
+		return reqError
+	}
+
+	req.ContentLength = contentLength
+	return nil
+}
+
+// Validates the length of the request body declared in the Content-Length header against the maximum body size applicable to the request. This is done before the request body is read,
+// so that an oversized request body is rejected without being received. The size of a chunked request body is validated while it is being read.
+func (req *HttpRequest) validateBodySize() error {
+	maxBodySize := req.getMaxBodySize()
+	if !req.isChunked && maxBodySize > 0 && int64(req.ContentLength) > maxBodySize {
+		reqError := new(RequestParseError)
+		reqError.Section = "Body"
+		reqError.Value = strconv.Itoa(req.ContentLength)
+		reqError.Message = fmt.Sprintf("Request body size exceeds the maximum allowed size of %d bytes", maxBodySize)
+		reqError.Status = StatusContentTooLarge
+		return reqError
+	}
+
+	return nil
+}
+
+// Returns the maximum size (in bytes) of the request body, which is the limit set for the route matching the request or else the "max_body_size" server default. A non-positive value means that the size is not limited.
+func (req *HttpRequest) getMaxBodySize() int64 {
+	if req.maxBodySize > 0 {
+		return req.maxBodySize
+	}
+
+	return getMaxBodySize()
+}
+
+// Reads the request line and the values for all request headers and stores them in the HttpRequest instance. The request line and the headers are validated as per RFC 9112 and the size of the request line,
+// the total size of the request head and the number of headers are limited by the settings of the web server instance.
 func (req *HttpRequest) readHeader() error {
+	config := req.getConfig()
 	RequestLineProcessed := false
-	HeaderProcessingCompleted := false
+	headerBytes := 0
+	headerCount := 0
 
 	for {
-		message, err := req.reader.ReadString('\n')
+		limit := -1
+		if config.MaxHeaderBytes > 0 {
+			limit = config.MaxHeaderBytes - headerBytes
+		}
+
+		exceededStatus := StatusRequestHeaderFieldsTooLarge
+		if !RequestLineProcessed {
+			exceededStatus = StatusURITooLong
+		}
+
+		message, err := req.readHeadLine(limit, exceededStatus)
 		if err != nil {
-			if len(message) == 0 && err != io.EOF {
+			if _, ok := err.(*RequestParseError); ok {
+				return err
+			} else if len(message) == 0 && !RequestLineProcessed {
+				// The connection was closed or timed out before a new request was sent by the client.
+				return err
+			} else if len(message) == 0 && err != io.EOF {
 				reqError := new(RequestParseError)
 				reqError.Section = "Header"
 				reqError.Message = err.Error()
 				reqError.Value = strings.TrimSpace(message)
+				if isTimeoutError(err) {
+					reqError.Status = StatusRequestTimeout
+				}
 				return reqError
 			} else if len(message) == 0 && err == io.EOF {
 				break
-			}			
+			}
+		}
+
+		if len(message) == 0 && !RequestLineProcessed {
+			// Empty lines received before the request line are ignored.
+			continue
 		}
 
-		message = strings.TrimSuffix(message, HEADER_LINE_SEPERATOR)
-		if len(message) == 0 && !HeaderProcessingCompleted {
-			HeaderProcessingCompleted = true
+		headerBytes += len(message) + len(HEADER_LINE_SEPERATOR)
+		if len(message) == 0 {
 			break
 		} else if !RequestLineProcessed {
-			RequestLineParts := strings.Split(message, REQUEST_LINE_SEPERATOR)
-			if len(RequestLineParts) != 2 && len(RequestLineParts) != 3 {
-				reqError := new(RequestParseError)
-				reqError.Section = "Header"
-				reqError.Message = "Request line should contain either 2 or 3 values, seperated by a single whitespace"
-				reqError.Value = strings.TrimSpace(message)
-				return reqError
-			}
-
-			tempVersion := ""
-			if len(RequestLineParts) == 2 || len(RequestLineParts) == 3 {
-				req.Method = strings.TrimSpace(RequestLineParts[0])
-				req.ResourcePath = strings.TrimSpace(RequestLineParts[1])
-			}
-
-			if len(RequestLineParts) == 2 {
-				tempVersion = "HTTP/0.9"
-			}
-			
-			if len(RequestLineParts) == 3 {
-				tempVersion = strings.TrimSpace(RequestLineParts[2])
+			err := req.parseRequestLine(message, config.MaxURILength)
+			if err != nil {
+				return err
 			}
 
-			tempVersion, found := strings.CutPrefix(tempVersion, "HTTP/")
-			if !found {
-				reqError := new(RequestParseError)
-				reqError.Section = "Header"
-				reqError.Value = strings.TrimSpace(tempVersion)
-				reqError.Message = "Invalid HTTP Version found in header"
-				return reqError
-			}
-			req.Version = strings.TrimSpace(tempVersion) 
 			RequestLineProcessed = true
+			if req.Version == "0.9" {
+				// HTTP/0.9 requests do not contain any headers.
+				break
+			}
 		} else {
-			HeaderKey, HeaderValue, found := strings.Cut(message, HEADER_KEY_VALUE_SEPERATOR)
-			if !found {
+			headerCount++
+			if config.MaxHeaderCount > 0 && headerCount > config.MaxHeaderCount {
 				reqError := new(RequestParseError)
 				reqError.Section = "Header"
-				reqError.Value = strings.TrimSpace(message)
-				reqError.Message = "Invalid header string found among request headers"
+				reqError.Value = strconv.Itoa(headerCount)
+				reqError.Message = fmt.Sprintf("Request contains more than the maximum allowed number of %d headers", config.MaxHeaderCount)
+				reqError.Status = StatusRequestHeaderFieldsTooLarge
 				return reqError
 			}
 
-			HeaderKey = strings.TrimSpace(HeaderKey)
-			HeaderValue = strings.TrimSpace(HeaderValue)
-			err := req.addHeader(HeaderKey, HeaderValue)
+			HeaderKey, HeaderValue, err := parseHeaderLine(message)
+			if err != nil {
+				return err
+			}
+
+			err = req.addHeader(HeaderKey, HeaderValue)
 			if err != nil {
 				return err
 			}
@@ -160,31 +316,341 @@ func (req *HttpRequest) readHeader() error {
 	return nil
 }
 
+// Returns the settings of the web server instance which received the request. If the request has not been received by a web server instance, the settings are initialized from the server defaults.
+func (req *HttpRequest) getConfig() *ServerConfig {
+	if req.config == nil {
+		req.config = newServerConfig()
+	}
+	return req.config
+}
+
+// Reads a single line of the request head and returns it without the line break. If the line is longer than the given number of bytes, an error with the given status is returned.
+// A negative limit means that the length of the line is not limited.
+func (req *HttpRequest) readHeadLine(limit int, exceededStatus StatusCode) (string, error) {
+	line := make([]byte, 0)
+	for {
+		fragment, err := req.reader.ReadSlice('\n')
+		line = append(line, fragment...)
+		if limit >= 0 && len(line) > limit {
+			reqError := new(RequestParseError)
+			reqError.Section = "Header"
+			reqError.Value = fmt.Sprintf("%d bytes", len(line))
+			reqError.Message = "Request head exceeds the maximum allowed size"
+			reqError.Status = exceededStatus
+			return "", reqError
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		} else if err != nil {
+			return string(line), err
+		}
+
+		return strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"), nil
+	}
+}
+
+// Parses the given request line into the request method, the request target and the HTTP version. As per RFC 9112, the values must be separated by a single space, the method must be a token,
+// the request target must not contain any whitespace or control characters and the version must be of the format HTTP/<digit>.<digit>. A request line without the version is considered to be a HTTP/0.9 request.
+func (req *HttpRequest) parseRequestLine(message string, maxURILength int) error {
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Value = message
+	reqError.Status = StatusBadRequest
+
+	RequestLineParts := strings.Split(message, REQUEST_LINE_SEPERATOR)
+	if len(RequestLineParts) != 2 && len(RequestLineParts) != 3 {
+		reqError.Message = "Request line should contain either 2 or 3 values, seperated by a single whitespace"
+		return reqError
+	}
+
+	if !isToken(RequestLineParts[0]) {
+		reqError.Message = "Request method must be a valid token"
+		return reqError
+	}
+
+	if RequestLineParts[1] == "" || strings.IndexFunc(RequestLineParts[1], func(char rune) bool { return char == ' ' || isControlChar(char) }) != -1 {
+		reqError.Message = "Request target must not be empty or contain whitespace or control characters"
+		return reqError
+	}
+
+	if maxURILength > 0 && len(RequestLineParts[1]) > maxURILength {
+		reqError.Value = fmt.Sprintf("%d bytes", len(RequestLineParts[1]))
+		reqError.Message = fmt.Sprintf("Request target exceeds the maximum allowed length of %d bytes", maxURILength)
+		reqError.Status = StatusURITooLong
+		return reqError
+	}
+
+	tempVersion := "HTTP/0.9"
+	if len(RequestLineParts) == 3 {
+		tempVersion = RequestLineParts[2]
+	}
+
+	tempVersion, found := strings.CutPrefix(tempVersion, "HTTP/")
+	if !found || len(tempVersion) != 3 || !isDigit(tempVersion[0]) || tempVersion[1] != '.' || !isDigit(tempVersion[2]) {
+		reqError.Value = RequestLineParts[len(RequestLineParts) - 1]
+		reqError.Message = "Invalid HTTP Version found in header"
+		return reqError
+	}
+
+	req.Method = RequestLineParts[0]
+	req.ResourcePath = RequestLineParts[1]
+	req.RequestURI = RequestLineParts[1]
+	req.Version = tempVersion
+	return nil
+}
+
+// Parses the given header line into the header name and the header value. As per RFC 9112, the header name must be a token that is immediately followed by the colon, the header value must not contain
+// any control characters other than the horizontal tab and header values folded across multiple lines (obs-fold) are rejected.
+func parseHeaderLine(message string) (string, string, error) {
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Value = message
+	reqError.Status = StatusBadRequest
+	if message[0] == ' ' || message[0] == '\t' {
+		reqError.Message = "Header values folded across multiple lines are not allowed"
+		return "", "", reqError
+	}
+
+	HeaderKey, HeaderValue, found := strings.Cut(message, HEADER_KEY_VALUE_SEPERATOR)
+	if !found {
+		reqError.Message = "Invalid header string found among request headers"
+		return "", "", reqError
+	}
+
+	if !isToken(HeaderKey) {
+		reqError.Message = "Header name must be a valid token, without any whitespace before the colon"
+		return "", "", reqError
+	}
+
+	HeaderValue = strings.Trim(HeaderValue, " \t")
+	if strings.IndexFunc(HeaderValue, func(char rune) bool { return char != '\t' && isControlChar(char) }) != -1 {
+		reqError.Message = "Header value must not contain any control characters"
+		return "", "", reqError
+	}
+
+	return HeaderKey, HeaderValue, nil
+}
+
+// Checks if the given character is a control character (as defined in RFC 5234).
+func isControlChar(char rune) bool {
+	return char < 0x20 || char == 0x7F
+}
+
+// Checks if the given byte is an ASCII digit.
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+// Checks if the given byte is an ASCII hexadecimal digit.
+func isHexDigit(char byte) bool {
+	return isDigit(char) || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
+
+// Checks if the given byte is an unreserved character (as defined in RFC 3986), which can be used in a URL without being percent-encoded.
+func isUnreservedChar(char byte) bool {
+	return isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char == '-' || char == '.' || char == '_' || char == '~'
+}
+
+// Validates the Host header of the request. As per RFC 9112, a HTTP/1.1 request must contain exactly one Host header, while for other versions the header is optional. The header value must be a valid host
+// (a registered name, an IPv4 address or an IPv6 address enclosed in brackets), optionally followed by a port number.
+func (req *HttpRequest) validateHost() error {
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Status = StatusBadRequest
+	hostValues, found := req.Headers[textproto.CanonicalMIMEHeaderKey("Host")]
+	if !found {
+		if strings.EqualFold(req.Version, "1.1") {
+			reqError.Value = "Host"
+			reqError.Message = "Host header is required for requests made with HTTP/1.1"
+			return reqError
+		}
+		return nil
+	}
+
+	reqError.Value = strings.Join(hostValues, ",")
+	if len(hostValues) != 1 {
+		reqError.Message = "Request must not contain more than one Host header"
+		return reqError
+	}
+
+	if strings.IndexFunc(hostValues[0], func(char rune) bool { return !isHostChar(char) }) != -1 {
+		reqError.Message = "Host header value must be a valid host, optionally followed by a port number"
+		return reqError
+	}
+
+	return nil
+}
+
+// Checks if the given character can be present in the value of the Host header (as defined in RFC 3986 for the host and port components of a URI).
+func isHostChar(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || strings.ContainsRune("-._~!$&'()*+;=:[]%", char)
+}
+
+// Validates the Transfer-Encoding header of the request, for which only the chunked transfer coding is supported. As per RFC 9112, a request containing both the Transfer-Encoding and the Content-Length headers
+// is rejected, as the two headers can be interpreted differently by intermediaries (request smuggling).
+func (req *HttpRequest) validateTransferEncoding(transferEncoding string) error {
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Value = transferEncoding
+	reqError.Status = StatusBadRequest
+	if !strings.EqualFold(req.Version, "1.1") {
+		reqError.Message = "Transfer-Encoding header is supported only for requests made with HTTP/1.1"
+		return reqError
+	}
+
+	if _, found := req.Headers.Get("Content-Length"); found {
+		reqError.Message = "Request must not contain both the Transfer-Encoding and the Content-Length headers"
+		return reqError
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(transferEncoding), "chunked") {
+		reqError.Message = "Only the chunked transfer coding is supported for the request body"
+		reqError.Status = StatusNotImplemented
+		return reqError
+	}
+
+	req.isChunked = true
+	return nil
+}
+
 // Reads the body from request byte stream and stores them in the HttpRequest instance.
 func (req *HttpRequest) readBody() error {
+	if req.isChunked {
+		return req.readChunkedBody()
+	}
+
 	if req.ContentLength > 0 {
 		req.Body = make([]byte, req.ContentLength)
-		for index := 0; index < req.ContentLength; index++ {
-			bodyByte, err := req.reader.ReadByte()
-			if err != nil {
-				reqError := new(RequestParseError)
-				reqError.Section = "Body"
-				reqError.Value = "Request Body"
-				reqError.Message = err.Error()
-				return reqError
+		_, err := io.ReadFull(req.reader, req.Body)
+		if err != nil {
+			reqError := new(RequestParseError)
+			reqError.Section = "Body"
+			reqError.Value = "Request Body"
+			reqError.Message = err.Error()
+			if isTimeoutError(err) {
+				reqError.Status = StatusRequestTimeout
 			}
-			req.Body[index] = bodyByte
+			return reqError
 		}
 	}
 
 	return nil
 }
 
+// Reads the request body sent using the chunked transfer coding (RFC 9112) from the request byte stream and stores the decoded body in the HttpRequest instance.
+// Chunk extensions are ignored, while the trailer fields sent after the last chunk are stored in the Trailers of the request.
+func (req *HttpRequest) readChunkedBody() error {
+	maxBodySize := req.getMaxBodySize()
+	var body bytes.Buffer
+	for {
+		sizeLine, err := req.readChunkLine()
+		if err != nil {
+			return err
+		}
+
+		// The chunk size must consist of hexadecimal digits only, without a sign or a prefix that another parser in front of the server could interpret differently.
+		sizeValue, _, _ := strings.Cut(sizeLine, ";")
+		sizeValue = strings.TrimRight(sizeValue, " \t")
+		chunkSize, err := strconv.ParseInt(sizeValue, 16, 64)
+		if err != nil || sizeValue == "" || strings.Trim(sizeValue, "0123456789abcdefABCDEF") != "" {
+			return newChunkError(sizeLine, "Chunk size must be a non-negative hexadecimal number", StatusBadRequest)
+		}
+
+		if chunkSize == 0 {
+			break
+		}
+
+		// The size of the chunk is compared with the space left in the body, as adding a size close to the largest 64-bit value to the size of the body overflows.
+		if maxBodySize > 0 && chunkSize > maxBodySize - int64(body.Len()) {
+			return newChunkError(sizeLine, fmt.Sprintf("Request body size exceeds the maximum allowed size of %d bytes", maxBodySize), StatusContentTooLarge)
+		}
+
+		_, err = io.CopyN(&body, req.reader, chunkSize)
+		if err != nil {
+			return newChunkReadError(err)
+		}
+
+		chunkEnd, err := req.readChunkLine()
+		if err != nil {
+			return err
+		}
+
+		if chunkEnd != "" {
+			return newChunkError(chunkEnd, "Chunk data must be followed by a line break", StatusBadRequest)
+		}
+	}
+
+	for {
+		trailerLine, err := req.readChunkLine()
+		if err != nil {
+			return err
+		}
+
+		if trailerLine == "" {
+			break
+		}
+
+		TrailerKey, TrailerValue, found := strings.Cut(trailerLine, HEADER_KEY_VALUE_SEPERATOR)
+		if !found || strings.TrimSpace(TrailerKey) == "" {
+			return newChunkError(trailerLine, "Invalid trailer field found after the request body", StatusBadRequest)
+		}
+
+		req.Trailers.Add(strings.TrimSpace(TrailerKey), strings.TrimSpace(TrailerValue))
+	}
+
+	req.Body = body.Bytes()
+	req.ContentLength = len(req.Body)
+	return nil
+}
+
+// Reads a single line (a chunk size, the end of a chunk or a trailer field) of a chunked request body and returns it without the line break. Lines longer than the buffer of the request reader are rejected.
+func (req *HttpRequest) readChunkLine() (string, error) {
+	line, err := req.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", newChunkError("", "Line in the chunked request body is too long", StatusBadRequest)
+	} else if err != nil {
+		return "", newChunkReadError(err)
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"), nil
+}
+
+// Returns the error raised when the chunked request body contains the given invalid value.
+func newChunkError(Value string, Message string, Status StatusCode) error {
+	reqError := new(RequestParseError)
+	reqError.Section = "Body"
+	reqError.Value = Value
+	reqError.Message = Message
+	reqError.Status = Status
+	return reqError
+}
+
+// Returns the error raised when the chunked request body could not be read from the request byte stream due to the given error.
+func newChunkReadError(err error) error {
+	reqError := new(RequestParseError)
+	reqError.Section = "Body"
+	reqError.Value = "Request Body"
+	reqError.Message = err.Error()
+	if isTimeoutError(err) {
+		reqError.Status = StatusRequestTimeout
+	}
+	return reqError
+}
+
+// Returns a reader to read the contents of the request body.
+func (req *HttpRequest) BodyReader() io.Reader {
+	return bytes.NewReader(req.Body)
+}
+
 // Parses all the query paramaters from the request URL and stores in the HttpRequest instance. 
-// Once the parsing is done, it removes the query parameters string from the Resource Path field.
+// Once the parsing is done, it removes the query parameters string (and fragment, if any) from the Resource Path field and normalizes the path, so that only the normalized path component is used for routing.
+// The path as received is retained in the RawPath field.
 func (req *HttpRequest) parseQueryParams() error {
 	req.Query = make(Params)
-	parsedUrl, err := url.Parse(req.ResourcePath)
+	resourcePath, _, _ := strings.Cut(req.ResourcePath, "#")
+	resourcePath, rawQuery, _ := strings.Cut(resourcePath, "?")
+	queryParams, err := url.ParseQuery(rawQuery)
 	if err != nil {
 		reqError := new(RequestParseError)
 		reqError.Section = "QueryParams"
@@ -193,54 +659,164 @@ func (req *HttpRequest) parseQueryParams() error {
 		return reqError
 	}
 
-	queryParams := parsedUrl.Query()
 	for paramName, paramValues := range queryParams {
 		req.Query.Add(paramName, paramValues)
 	}
 
-	if len(req.Query) > 0 {
-		req.ResourcePath, _, _ = strings.Cut(req.ResourcePath, "?")
+	normalizedPath, err := normalizePath(resourcePath)
+	if err != nil {
+		reqError := new(RequestParseError)
+		reqError.Section = "Header"
+		reqError.Value = resourcePath
+		reqError.Message = err.Error()
+		reqError.Status = StatusBadRequest
+		return reqError
 	}
 
+	req.RawPath = resourcePath
+	req.ResourcePath = normalizedPath
+	req.RawQuery = rawQuery
 	return nil
 }
 
-// Checks if the given HTTP GET request made is a CONDITIONAL GET request.
-func (req *HttpRequest) isConditionalGet(CompleteFilePath string) (bool, error) {
-	if !strings.EqualFold(req.Method, "GET") {
-		return false, nil
+// Decodes the JSON request body into the given target value. The request must have its Content-Type header set to "application/json".
+// If the target implements the Validator interface, its Validate() method is invoked once the body is decoded and the error returned by it, if any, is returned to the caller.
+func (req *HttpRequest) BindJSON(target any) error {
+	contentType, _ := req.Headers.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.EqualFold(mediaType, JSON_CONTENT_TYPE) {
+		reqError := new(RequestParseError)
+		reqError.Section = "Header"
+		reqError.Value = contentType
+		reqError.Message = "Request body can be bound only if its content type is application/json"
+		reqError.Status = StatusUnsupportedMediaType
+		return reqError
 	}
 
-	fileMediaType, exists := getContentType(CompleteFilePath)
-	if !exists {
-		return false, nil
+	err = json.Unmarshal(req.Body, target)
+	if err != nil {
+		reqError := new(RequestParseError)
+		reqError.Section = "Body"
+		reqError.Value = "Request Body"
+		reqError.Message = fmt.Sprintf("Error while decoding request body as JSON :: %s", err.Error())
+		reqError.Status = StatusBadRequest
+		return reqError
+	}
+
+	if validator, ok := target.(Validator); ok {
+		return validator.Validate()
 	}
 
-	file, err := fs.GetFile(CompleteFilePath, fileMediaType, true)
+	return nil
+}
+
+// Returns the collection of form values sent in the request. If the request body has the application/x-www-form-urlencoded content type, it is parsed (only once) and its values are
+// followed by the query parameter values for each key. For any other content type, the form values are the query parameter values along with the multipart form fields, if ParseMultipart() has been called.
+func (req *HttpRequest) Form() (Params, error) {
+	if req.form != nil {
+		return req.form, nil
+	}
+
+	form := make(Params)
+	contentType, _ := req.Headers.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && strings.EqualFold(mediaType, FORM_URLENCODED_CONTENT_TYPE) {
+		bodyValues, err := url.ParseQuery(string(req.Body))
+		if err != nil {
+			reqError := new(RequestParseError)
+			reqError.Section = "Body"
+			reqError.Value = "Request Body"
+			reqError.Message = fmt.Sprintf("Error while parsing request body as a form :: %s", err.Error())
+			reqError.Status = StatusBadRequest
+			return nil, reqError
+		}
+
+		for Name, values := range bodyValues {
+			form.Add(Name, values)
+		}
+	} else if req.multipartForm != nil {
+		for Name, values := range req.multipartForm.Fields {
+			form.Add(Name, values)
+		}
+	}
+
+	for Name, values := range req.Query {
+		form.Add(Name, values)
+	}
+
+	req.form = form
+	return form, nil
+}
+
+// Returns the value of the request header with the given name, which is matched case-insensitively. If the header has been sent more than once, its values are joined by commas.
+// An empty string is returned if the header has not been sent.
+func (req *HttpRequest) Header(Name string) string {
+	value, _ := req.Headers.Get(Name)
+	return strings.TrimSpace(value)
+}
+
+// Returns the elements of the request header with the given name, which is matched case-insensitively, for headers whose value is a comma-separated list (like Accept-Encoding or If-None-Match).
+// The elements are returned in the order they were sent, across all the occurrences of the header, without the surrounding whitespace. Commas within quoted strings do not separate the elements.
+// An empty slice is returned if the header has not been sent.
+func (req *HttpRequest) HeaderValues(Name string) []string {
+	value, _ := req.Headers.Get(Name)
+	return splitHeaderList(value)
+}
+
+// Returns the media type of the request body (in lower case, like "application/json") and its parameters (like the charset), as sent in the Content-Type header.
+// An empty media type and a nil collection of parameters are returned if the header has not been sent or cannot be parsed. The length of the request body is available in the ContentLength field.
+func (req *HttpRequest) ContentType() (string, map[string]string) {
+	mediaType, params, err := mime.ParseMediaType(req.Header("Content-Type"))
 	if err != nil {
-		return false, err
+		return "", nil
+	}
+
+	return mediaType, params
+}
+
+// Checks if the client connection should be kept open once the response for the request has been sent back.
+// HTTP/1.1 connections are persistent unless the client sends "Connection: close", whereas HTTP/1.0 connections are persistent only if the client sends "Connection: keep-alive".
+func (req *HttpRequest) IsKeepAlive() bool {
+	connectionOptions := req.HeaderValues("Connection")
+	for index, option := range connectionOptions {
+		connectionOptions[index] = strings.ToLower(option)
+	}
+
+	switch strings.TrimSpace(req.Version) {
+	case "1.1":
+		return !slices.Contains(connectionOptions, "close")
+	case "1.0":
+		return slices.Contains(connectionOptions, "keep-alive")
+	default:
+		return false
+	}
+}
+
+// Checks if the given file has not been modified based on the conditional headers (If-None-Match and If-Modified-Since) sent in the GET or HEAD request.
+// As per RFC 9110, If-Modified-Since is evaluated only when the request does not contain an If-None-Match header.
+func (req *HttpRequest) isNotModified(file *fs.File, ETag string) bool {
+	if !strings.EqualFold(req.Method, "GET") && !strings.EqualFold(req.Method, "HEAD") {
+		return false
+	}
+
+	IfNoneMatch, ok := req.Headers.Get("If-None-Match")
+	if ok {
+		return isETagMatch(IfNoneMatch, ETag)
 	}
 
 	LastModifiedString, ok := req.Headers.Get("If-Modified-Since")
-	if !ok {
-		return false, nil
+	if !ok || file.LastModifiedAt.IsZero() {
+		// Files without a last modified time (like the files of an embed.FS) can only be validated using their entity tags.
+		return false
 	}
 
-	LastModifiedString = strings.TrimSpace(LastModifiedString)
-	isValid, LastModifiedSince := isHttpDate(LastModifiedString)
+	isValid, LastModifiedSince := isHttpDate(strings.TrimSpace(LastModifiedString))
 	if !isValid {
-		reqError := new(RequestParseError)
-		reqError.Section = "Header"
-		reqError.Value = LastModifiedString
-		reqError.Message = "The given datetime string value must either conform to ANSIC or RFC 1123 format"
-		return false, reqError
-	}
-	
-	if file.LastModifiedAt.After(LastModifiedSince) {
-		return false, nil
+		return false
 	}
 
-	return true, nil
+	// HTTP dates have a resolution of one second, hence the sub-second part of the modified time is ignored.
+	return !file.LastModifiedAt.Truncate(time.Second).After(LastModifiedSince)
 }
 
 // Adds a new key-value pair to the request headers collection.
@@ -254,6 +830,7 @@ func (req *HttpRequest) addHeader(HeaderKey string, HeaderValue string) error {
 			reqError.Section = "Header"
 			reqError.Value = fmt.Sprintf("%s: %s", HeaderKey, HeaderValue)
 			reqError.Message = "The given date header value should be one of either of these formats - RFC 1123 or ANSIC"
+			reqError.Status = StatusBadRequest
 			return reqError
 		}
 	} else {
@@ -4,6 +4,11 @@ import (
 	"testing"
 	"strings"
 	"bufio"
+	"errors"
+	"io"
+	"slices"
+	"time"
+	"github.com/mkbworks/proteus/lib/fs"
 )
 
 // Helper function to create and return a new test instance of HttpRequest.
@@ -28,6 +33,8 @@ func Test_Request_Read(t *testing.T) {
 		{ "HTTP v0.9 GET Request", "GET /user/abc\r\n", "GET", "/user/abc", "0.9", 0, 0 },
 		{ "HTTP v1.0 GET Request", "GET /user/abc HTTP/1.0\r\nHost: example.com\r\n\r\n", "GET", "/user/abc", "1.0", 1, 0 },
 		{ "HTTP v1.0 GET Request with Query Params", "GET /user/abc?name=sample HTTP/1.0\r\nHost: example.com\r\n\r\n", "GET", "/user/abc", "1.0", 1, 1 },
+		{ "HTTP v1.1 GET Request with multi-valued Query Params", "GET /user/abc?name=one&name=two&age=3 HTTP/1.1\r\nHost: example.com\r\n\r\n", "GET", "/user/abc", "1.1", 1, 2 },
+		{ "HTTP v1.1 GET Request with an empty query string", "GET /user/abc? HTTP/1.1\r\nHost: example.com\r\n\r\n", "GET", "/user/abc", "1.1", 1, 0 },
 	}
 
 	for _, testCase := range testCases {
@@ -117,4 +124,521 @@ func Test_Request_AddHeader(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Test case to validate if the persistence of the client connection is determined correctly from the request version and the Connection header.
+func Test_Request_IsKeepAlive(t *testing.T) {
+	testCases := []struct {
+		Name string
+		InputRequest string
+		ExpKeepAlive bool
+	} {
+		{ "HTTP v0.9 GET Request", "GET /user/abc\r\n", false },
+		{ "HTTP v1.0 GET Request without Connection header", "GET /user/abc HTTP/1.0\r\nHost: example.com\r\n\r\n", false },
+		{ "HTTP v1.0 GET Request with keep-alive", "GET /user/abc HTTP/1.0\r\nHost: example.com\r\nConnection: Keep-Alive\r\n\r\n", true },
+		{ "HTTP v1.1 GET Request without Connection header", "GET /user/abc HTTP/1.1\r\nHost: example.com\r\n\r\n", true },
+		{ "HTTP v1.1 GET Request with close", "GET /user/abc HTTP/1.1\r\nHost: example.com\r\nConnection: upgrade, close\r\n\r\n", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			if err != nil {
+				tt.Errorf("The given request could not be parsed. Error :: %s", err.Error())
+				return
+			}
+
+			if testReq.IsKeepAlive() != testCase.ExpKeepAlive {
+				tt.Errorf("Expected keep-alive to be %t for the request, but got %t instead", testCase.ExpKeepAlive, testReq.IsKeepAlive())
+			} else {
+				tt.Logf("Keep-alive value %t matches the expected value %t", testReq.IsKeepAlive(), testCase.ExpKeepAlive)
+			}
+		})
+	}
+}
+
+// Test case to validate the values returned by the request header accessors, for headers sent in any case and more than once.
+func Test_Request_HeaderAccessors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		InputRequest string
+		HeaderName string
+		ExpHeader string
+		ExpValues []string
+		ExpMediaType string
+		ExpCharset string
+	} {
+		{ "Header name in another case", "GET / HTTP/1.1\r\nHost: example.com\r\ncontent-type: Application/JSON; charset=UTF-8\r\n\r\n", "CONTENT-TYPE", "Application/JSON; charset=UTF-8", []string{ "Application/JSON; charset=UTF-8" }, "application/json", "UTF-8" },
+		{ "Header sent more than once", "GET / HTTP/1.1\r\nHost: example.com\r\nAccept-Encoding: gzip, br\r\nAccept-Encoding: zstd\r\n\r\n", "accept-encoding", "gzip, br,zstd", []string{ "gzip", "br", "zstd" }, "", "" },
+		{ "List with commas in a quoted string", "GET / HTTP/1.1\r\nHost: example.com\r\nIf-None-Match: \"a,b\", \"c\"\r\n\r\n", "If-None-Match", `"a,b", "c"`, []string{ `"a,b"`, `"c"` }, "", "" },
+		{ "Header not sent", "GET / HTTP/1.1\r\nHost: example.com\r\nContent-Type: not a media type\r\n\r\n", "Accept", "", []string{}, "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			if err != nil {
+				tt.Errorf("The given request could not be parsed. Error :: %s", err.Error())
+				return
+			}
+
+			mediaType, params := testReq.ContentType()
+			if testReq.Header(testCase.HeaderName) != testCase.ExpHeader {
+				tt.Errorf("The header value [%s] does not match the expected value [%s]", testReq.Header(testCase.HeaderName), testCase.ExpHeader)
+			} else if !slices.Equal(testReq.HeaderValues(testCase.HeaderName), testCase.ExpValues) {
+				tt.Errorf("The header values %q do not match the expected values %q", testReq.HeaderValues(testCase.HeaderName), testCase.ExpValues)
+			} else if mediaType != testCase.ExpMediaType || params["charset"] != testCase.ExpCharset {
+				tt.Errorf("The media type [%s] with charset [%s] does not match the expected media type [%s] with charset [%s]", mediaType, params["charset"], testCase.ExpMediaType, testCase.ExpCharset)
+			} else {
+				tt.Logf("The header values %q and the media type [%s] match the expected values", testReq.HeaderValues(testCase.HeaderName), mediaType)
+			}
+		})
+	}
+}
+
+// Test case to validate if the request body is read based on the Content-Length header and if the maximum body size is enforced.
+func Test_Request_ReadBody(t *testing.T) {
+	originalMaxBodySize := ServerDefaults["max_body_size"]
+	ServerDefaults["max_body_size"] = "16"
+	defer func() {
+		ServerDefaults["max_body_size"] = originalMaxBodySize
+	}()
+
+	testCases := []struct {
+		Name string
+		InputRequest string
+		ExpBody string
+		ExpStatus StatusCode
+	} {
+		{ "Request without a body", "GET /user/abc HTTP/1.1\r\nHost: example.com\r\n\r\n", "", 0 },
+		{ "Request with a body", "POST /user/abc HTTP/1.1\r\nHost: example.com\r\nContent-Length: 11\r\n\r\nhello world", "hello world", 0 },
+		{ "Request with an invalid Content-Length", "POST /user/abc HTTP/1.1\r\nHost: example.com\r\nContent-Length: abc\r\n\r\nhello world", "", StatusBadRequest },
+		{ "Request with a body larger than the maximum size", "POST /user/abc HTTP/1.1\r\nHost: example.com\r\nContent-Length: 17\r\n\r\nhello world again", "", StatusContentTooLarge },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			if testCase.ExpStatus != 0 {
+				reqError, ok := err.(*RequestParseError)
+				if !ok || reqError.Status != testCase.ExpStatus {
+					tt.Errorf("Was expecting a request parse error with status %d, but got this instead - %v", testCase.ExpStatus, err)
+				} else {
+					tt.Logf("Received a request parse error with status %d as expected - %v", reqError.Status, reqError)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("The given request could not be parsed. Error :: %s", err.Error())
+				return
+			}
+
+			body, _ := io.ReadAll(testReq.BodyReader())
+			if string(body) != testCase.ExpBody {
+				tt.Errorf("Expected request body was to be [%s] but got [%s]", testCase.ExpBody, string(body))
+			} else {
+				tt.Logf("Expected request body [%s] matches the returned request body [%s]", testCase.ExpBody, string(body))
+			}
+		})
+	}
+}
+
+// Test case to validate the decoding of request bodies sent using the chunked transfer coding, along with their trailer fields.
+func Test_Request_ReadChunkedBody(t *testing.T) {
+	originalMaxBodySize := ServerDefaults["max_body_size"]
+	ServerDefaults["max_body_size"] = "16"
+	defer func() {
+		ServerDefaults["max_body_size"] = originalMaxBodySize
+	}()
+
+	requestHead := "POST /upload HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n"
+	testCases := []struct {
+		Name string
+		InputRequest string
+		ExpBody string
+		ExpTrailer string
+		ExpStatus StatusCode
+	} {
+		{ "Chunked body", requestHead + "5\r\nhello\r\n6\r\n world\r\n0\r\n\r\n", "hello world", "", 0 },
+		{ "Chunked body with extensions and trailers", requestHead + "5;name=value\r\nhello\r\n0\r\nChecksum: abc123\r\n\r\n", "hello", "abc123", 0 },
+		{ "Chunked body larger than the maximum size", requestHead + "a\r\nhello worl\r\na\r\nd again!!!\r\n0\r\n\r\n", "", "", StatusContentTooLarge },
+		{ "Chunk size overflowing the body size", requestHead + "1\r\nA\r\n7fffffffffffffff\r\nhello\r\n0\r\n\r\n", "", "", StatusContentTooLarge },
+		{ "Invalid chunk size", requestHead + "xyz\r\nhello\r\n0\r\n\r\n", "", "", StatusBadRequest },
+		{ "Chunk data without a line break", requestHead + "2\r\nhello\r\n0\r\n\r\n", "", "", StatusBadRequest },
+		{ "Both Transfer-Encoding and Content-Length", "POST /upload HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\nContent-Length: 5\r\n\r\n0\r\n\r\n", "", "", StatusBadRequest },
+		{ "Unsupported transfer coding", "POST /upload HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: gzip, chunked\r\n\r\n0\r\n\r\n", "", "", StatusNotImplemented },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			if testCase.ExpStatus != 0 {
+				reqError, ok := err.(*RequestParseError)
+				if !ok || reqError.Status != testCase.ExpStatus {
+					tt.Errorf("Was expecting a request parse error with status %d, but got this instead - %v", testCase.ExpStatus, err)
+				} else {
+					tt.Logf("Received a request parse error with status %d as expected - %v", reqError.Status, reqError)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("The given request could not be parsed. Error :: %s", err.Error())
+				return
+			}
+
+			trailer, _ := testReq.Trailers.Get("Checksum")
+			if string(testReq.Body) != testCase.ExpBody || testReq.ContentLength != len(testCase.ExpBody) || trailer != testCase.ExpTrailer {
+				tt.Errorf("Expected the body [%s] with trailer [%s], but got [%s] with trailer [%s]", testCase.ExpBody, testCase.ExpTrailer, string(testReq.Body), trailer)
+			} else {
+				tt.Logf("Expected request body [%s] matches the decoded request body", testCase.ExpBody)
+			}
+		})
+	}
+}
+
+// Test case to validate the rejection of malformed request lines and request headers (including the Host header), along with the limits on the size of the request head.
+func Test_Request_ReadInvalidHead(t *testing.T) {
+	testConfig := new(ServerConfig)
+	testConfig.MaxHeaderBytes = 256
+	testConfig.MaxHeaderCount = 3
+	testConfig.MaxURILength = 32
+	testCases := []struct {
+		Name string
+		InputRequest string
+		ExpStatus StatusCode
+	} {
+		{ "Valid request", "GET /index.html HTTP/1.1\r\nHost: example.com\r\nAccept:\t*/*\r\n\r\n", 0 },
+		{ "Request line with multiple spaces", "GET  /index.html HTTP/1.1\r\nHost: example.com\r\n\r\n", StatusBadRequest },
+		{ "Request line with a trailing space", "GET /index.html HTTP/1.1 \r\nHost: example.com\r\n\r\n", StatusBadRequest },
+		{ "Request method that is not a token", "G(E)T /index.html HTTP/1.1\r\nHost: example.com\r\n\r\n", StatusBadRequest },
+		{ "Request target with a control character", "GET /index\x01.html HTTP/1.1\r\nHost: example.com\r\n\r\n", StatusBadRequest },
+		{ "Malformed HTTP version", "GET /index.html HTTP/1.10\r\nHost: example.com\r\n\r\n", StatusBadRequest },
+		{ "Whitespace before the header colon", "GET /index.html HTTP/1.1\r\nHost : example.com\r\n\r\n", StatusBadRequest },
+		{ "Header value with a control character", "GET /index.html HTTP/1.1\r\nHost: example\x00.com\r\n\r\n", StatusBadRequest },
+		{ "Header value folded across lines", "GET /index.html HTTP/1.1\r\nHost: example.com\r\nX-Note: one\r\n two\r\n\r\n", StatusBadRequest },
+		{ "Invalid date header", "GET /index.html HTTP/1.1\r\nHost: example.com\r\nIf-Modified-Since: yesterday\r\n\r\n", StatusBadRequest },
+		{ "Request target that is too long", "GET /" + strings.Repeat("a", 32) + " HTTP/1.1\r\nHost: example.com\r\n\r\n", StatusURITooLong },
+		{ "Request line larger than the request head", "GET /?" + strings.Repeat("a", 300) + " HTTP/1.1\r\nHost: example.com\r\n\r\n", StatusURITooLong },
+		{ "Request headers that are too large", "GET /index.html HTTP/1.1\r\nHost: example.com\r\nX-Data: " + strings.Repeat("a", 256) + "\r\n\r\n", StatusRequestHeaderFieldsTooLarge },
+		{ "HTTP/1.1 request without a Host header", "GET /index.html HTTP/1.1\r\nAccept: */*\r\n\r\n", StatusBadRequest },
+		{ "HTTP/1.0 request without a Host header", "GET /index.html HTTP/1.0\r\nAccept: */*\r\n\r\n", 0 },
+		{ "Request with multiple Host headers", "GET /index.html HTTP/1.1\r\nHost: example.com\r\nHost: attacker.com\r\n\r\n", StatusBadRequest },
+		{ "Invalid Host header value", "GET /index.html HTTP/1.1\r\nHost: example.com/evil\r\n\r\n", StatusBadRequest },
+		{ "Too many request headers", "GET /index.html HTTP/1.1\r\nHost: example.com\r\nA: 1\r\nB: 2\r\nC: 3\r\n\r\n", StatusRequestHeaderFieldsTooLarge },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.config = testConfig
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			if testCase.ExpStatus == 0 {
+				if err != nil {
+					tt.Errorf("The given request could not be parsed. Error :: %s", err.Error())
+				} else {
+					tt.Logf("The given request has been parsed as expected")
+				}
+				return
+			}
+
+			reqError, ok := err.(*RequestParseError)
+			if !ok || reqError.Status != testCase.ExpStatus {
+				tt.Errorf("Was expecting a request parse error with status %d, but got this instead - %v", testCase.ExpStatus, err)
+			} else {
+				tt.Logf("Received a request parse error with status %d as expected - %v", reqError.Status, reqError)
+			}
+		})
+	}
+}
+
+// Test case to validate the rejection of known request smuggling payloads, which send the length of the request body in a form that a proxy in front of the server could interpret differently.
+func Test_Request_SmugglingPayloads(t *testing.T) {
+	testCases := []struct {
+		Name string
+		InputRequest string
+		ExpStatus StatusCode
+	} {
+		{ "Content-Length followed by Transfer-Encoding (CL.TE)", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 13\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nSMUGGLED", StatusBadRequest },
+		{ "Transfer-Encoding followed by Content-Length (TE.CL)", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\nContent-Length: 3\r\n\r\n8\r\nSMUGGLED\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Duplicate Content-Length headers with different values", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5\r\nContent-Length: 6\r\n\r\nhello!", StatusBadRequest },
+		{ "Duplicate Content-Length headers with the same value", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5\r\nContent-Length: 5\r\n\r\nhello", StatusBadRequest },
+		{ "Content-Length with a list of values", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5, 5\r\n\r\nhello", StatusBadRequest },
+		{ "Content-Length with a sign", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: +5\r\n\r\nhello", StatusBadRequest },
+		{ "Empty Content-Length", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: \r\n\r\nhello", StatusBadRequest },
+		{ "Content-Length folded across lines", "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 0\r\n 5\r\n\r\nhello", StatusBadRequest },
+		{ "Transfer-Encoding folded across lines", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: identity\r\n chunked\r\n\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Transfer-Encoding with whitespace before the colon", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding : chunked\r\n\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Obfuscated transfer coding", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: xchunked\r\n\r\n0\r\n\r\n", StatusNotImplemented },
+		{ "Duplicate Transfer-Encoding headers", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\nTransfer-Encoding: identity\r\n\r\n0\r\n\r\n", StatusNotImplemented },
+		{ "Transfer-Encoding sent with HTTP/1.0", "POST / HTTP/1.0\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Chunk size with a sign", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n+5\r\nhello\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Chunk size with a hexadecimal prefix", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n0x5\r\nhello\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Chunk size with leading whitespace", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n 5\r\nhello\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Chunk size that overflows", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n10000000000000005\r\nhello\r\n0\r\n\r\n", StatusBadRequest },
+		{ "Chunk size with an extension", "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n5 ;name=value\r\nhello\r\n0\r\n\r\n", 0 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testReq.read()
+			if testCase.ExpStatus == 0 {
+				if err != nil {
+					tt.Errorf("The given request could not be parsed. Error :: %s", err.Error())
+				} else {
+					tt.Logf("The given request has been parsed as expected")
+				}
+				return
+			}
+
+			reqError, ok := err.(*RequestParseError)
+			if !ok || reqError.Status != testCase.ExpStatus {
+				tt.Errorf("Was expecting a request parse error with status %d, but got this instead - %v", testCase.ExpStatus, err)
+			} else {
+				tt.Logf("Received a request parse error with status %d as expected - %v", reqError.Status, reqError)
+			}
+		})
+	}
+}
+
+// Structure used as the target for binding the request body in test cases.
+type testBindTarget struct {
+	Name string `json:"name"`
+}
+
+// Validates the test bind target and returns an error if the name is empty.
+func (target *testBindTarget) Validate() error {
+	if target.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	return nil
+}
+
+// Test case to validate the working of binding a JSON request body to a struct.
+func Test_Request_BindJSON(t *testing.T) {
+	testCases := []struct {
+		Name string
+		ContentType string
+		Body string
+		ExpName string
+		ExpErr string
+	} {
+		{ "Valid JSON body", "application/json", `{"name":"proteus"}`, "proteus", "" },
+		{ "Valid JSON body with charset", "application/json; charset=utf-8", `{"name":"proteus"}`, "proteus", "" },
+		{ "Invalid content type", "text/plain", `{"name":"proteus"}`, "", "RequestParseError" },
+		{ "Malformed JSON body", "application/json", `{"name":`, "", "RequestParseError" },
+		{ "JSON body failing validation", "application/json", `{"name":""}`, "", "ValidationError" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.Headers.Add("Content-Type", testCase.ContentType)
+			testReq.Body = []byte(testCase.Body)
+			var target testBindTarget
+			err := testReq.BindJSON(&target)
+			switch testCase.ExpErr {
+			case "RequestParseError":
+				if _, ok := err.(*RequestParseError); !ok {
+					tt.Errorf("Was expecting a request parse error, but got this instead - %v", err)
+				} else {
+					tt.Logf("Received a request parse error as expected - %v", err)
+				}
+			case "ValidationError":
+				if err == nil {
+					tt.Errorf("Was expecting a validation error, but did not get one")
+				} else {
+					tt.Logf("Received a validation error as expected - %v", err)
+				}
+			default:
+				if err != nil {
+					tt.Errorf("Was not expecting an error and yet received one - %v", err)
+				} else if target.Name != testCase.ExpName {
+					tt.Errorf("Bound name [%s] does not match the expected name [%s]", target.Name, testCase.ExpName)
+				} else {
+					tt.Logf("Bound name [%s] matches the expected name [%s]", target.Name, testCase.ExpName)
+				}
+			}
+		})
+	}
+}
+
+// Test case to validate if the conditional request headers are evaluated correctly against the state of a file.
+func Test_Request_IsNotModified(t *testing.T) {
+	lastModified := time.Date(2024, time.March, 10, 12, 30, 45, 500, time.UTC)
+	testFile := &fs.File{ LastModifiedAt: lastModified, Size: 128 }
+	testETag := `W/"80-1"`
+	testCases := []struct {
+		Name string
+		Method string
+		HeaderKey string
+		HeaderValue string
+		ExpNotModified bool
+	} {
+		{ "GET request without conditional headers", "GET", "", "", false },
+		{ "GET request with a matching If-None-Match", "GET", "If-None-Match", `"abc", W/"80-1"`, true },
+		{ "GET request with a wildcard If-None-Match", "GET", "If-None-Match", "*", true },
+		{ "GET request with a different If-None-Match", "GET", "If-None-Match", `"abc"`, false },
+		{ "HEAD request with a matching If-None-Match", "HEAD", "If-None-Match", testETag, true },
+		{ "POST request with a matching If-None-Match", "POST", "If-None-Match", testETag, false },
+		{ "GET request with If-Modified-Since equal to the modified time", "GET", "If-Modified-Since", "Sun, 10 Mar 2024 12:30:45 GMT", true },
+		{ "GET request with If-Modified-Since before the modified time", "GET", "If-Modified-Since", "Sun, 10 Mar 2024 12:30:44 GMT", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.Method = testCase.Method
+			if testCase.HeaderKey != "" {
+				testReq.Headers.Add(testCase.HeaderKey, testCase.HeaderValue)
+			}
+
+			notModified := testReq.isNotModified(testFile, testETag)
+			if notModified != testCase.ExpNotModified {
+				tt.Errorf("Expected the not modified check to return %t, but got %t instead", testCase.ExpNotModified, notModified)
+			} else {
+				tt.Logf("The not modified check returned %t as expected", notModified)
+			}
+		})
+	}
+}
+
+// Test case to validate the working of storing and fetching values from the request context.
+func Test_Request_ContextValues(t *testing.T) {
+	type contextKey string
+	testRequest := newTestRequest(t)
+	if testRequest.Context() == nil {
+		t.Errorf("The request context was expected to be non-nil for a new request")
+		return
+	}
+
+	testRequest.SetValue(contextKey("user"), "proteus")
+	testRequest.SetValue(contextKey("requestId"), 42)
+	testCases := []struct {
+		Name string
+		Key contextKey
+		ExpValue any
+	} {
+		{ "Fetching a string value", "user", "proteus" },
+		{ "Fetching an integer value", "requestId", 42 },
+		{ "Fetching a value not present in the context", "role", nil },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			value := testRequest.GetValue(testCase.Key)
+			if value != testCase.ExpValue {
+				tt.Errorf("The value [%v] fetched from the request context does not match the expected value [%v]", value, testCase.ExpValue)
+			} else {
+				tt.Logf("The value [%v] fetched from the request context matches the expected value [%v]", value, testCase.ExpValue)
+			}
+		})
+	}
+}
+
+// Test case to validate the parsing of form values from the request body and the query string.
+func Test_Request_Form(t *testing.T) {
+	testCases := []struct {
+		Name string
+		ContentType string
+		Body string
+		Query Params
+		ExpValues map[string][]string
+		ExpErr bool
+	} {
+		{ "Urlencoded body merged with the query parameters", FORM_URLENCODED_CONTENT_TYPE + "; charset=utf-8", "name=John+Doe&tags=a&tags=b", Params{ "tags": { "c" }, "page": { "2" } }, map[string][]string{ "name": { "John Doe" }, "tags": { "a", "b", "c" }, "page": { "2" } }, false },
+		{ "Body with a different content type", JSON_CONTENT_TYPE, `{"name":"John"}`, Params{ "page": { "2" } }, map[string][]string{ "page": { "2" }, "name": {} }, false },
+		{ "Malformed urlencoded body", FORM_URLENCODED_CONTENT_TYPE, "name=%zz", Params{}, nil, true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Headers.Add("Content-Type", testCase.ContentType)
+			testRequest.Body = []byte(testCase.Body)
+			testRequest.Query = testCase.Query
+			form, err := testRequest.Form()
+			if testCase.ExpErr {
+				if reqErr, ok := err.(*RequestParseError); !ok || reqErr.Status != StatusBadRequest {
+					tt.Errorf("Expected a request parse error with status 400, but got this instead - %v", err)
+				} else {
+					tt.Logf("Received a request parse error as expected - %v", reqErr)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+				return
+			}
+
+			for key, expValues := range testCase.ExpValues {
+				values := form.GetAll(key)
+				if strings.Join(values, ",") != strings.Join(expValues, ",") {
+					tt.Errorf("The form values %v for [%s] do not match the expected values %v", values, key, expValues)
+				} else {
+					tt.Logf("The form values %v for [%s] match the expected values", values, key)
+				}
+			}
+		})
+	}
+}
+
+// Test case to validate the decoding and normalization of the request path, which is used for routing, while the path as received is retained.
+func Test_Request_NormalizePath(t *testing.T) {
+	testCases := []struct {
+		Name string
+		RequestTarget string
+		ExpError bool
+		ExpPath string
+		ExpRawPath string
+	} {
+		{ "Path without encoded characters", "/users/101?page=2", false, "/users/101", "/users/101" },
+		{ "Percent-encoded space", "/files/my%20notes.txt", false, "/files/my notes.txt", "/files/my%20notes.txt" },
+		{ "Percent-encoded slash kept encoded", "/files/a%2fb", false, "/files/a%2Fb", "/files/a%2fb" },
+		{ "Percent-encoded percent sign kept encoded", "/files/100%25", false, "/files/100%25", "/files/100%25" },
+		{ "Percent-encoded control character kept encoded", "/files/a%0Ab", false, "/files/a%0Ab", "/files/a%0Ab" },
+		{ "Duplicate slashes", "//files///x", false, "/files/x", "//files///x" },
+		{ "Dot-segments", "/files/./docs/../x", false, "/files/x", "/files/./docs/../x" },
+		{ "Dot-segments going above the root", "/../../files/x", false, "/files/x", "/../../files/x" },
+		{ "Percent-encoded dot-segments", "/files/%2e%2E/x", false, "/x", "/files/%2e%2E/x" },
+		{ "Trailing slash retained", "/files/docs/", false, "/files/docs/", "/files/docs/" },
+		{ "Trailing dot-segment", "/files/docs/..", false, "/files/", "/files/docs/.." },
+		{ "Root path", "/", false, "/", "/" },
+		{ "Asterisk request target", "*", false, "*", "*" },
+		{ "Invalid percent-encoding", "/files/a%zz", true, "", "" },
+		{ "Truncated percent-encoding", "/files/a%2", true, "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testReq := newTestRequest(tt)
+			testReq.ResourcePath = testCase.RequestTarget
+			err := testReq.parseQueryParams()
+			if testCase.ExpError {
+				if err == nil {
+					tt.Errorf("Expected an error while normalizing the request path [%s], but got none", testCase.RequestTarget)
+				} else {
+					tt.Logf("Received the expected error - %v", err)
+				}
+			} else if err != nil {
+				tt.Errorf("Was not expecting an error while normalizing the request path [%s] and yet received one - %v", testCase.RequestTarget, err)
+			} else if testReq.ResourcePath != testCase.ExpPath || testReq.RawPath != testCase.ExpRawPath {
+				tt.Errorf("Expected the path [%s] with raw path [%s], but got the path [%s] with raw path [%s]", testCase.ExpPath, testCase.ExpRawPath, testReq.ResourcePath, testReq.RawPath)
+			} else {
+				tt.Logf("Received the path [%s] as expected", testReq.ResourcePath)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/textproto"
+)
+
+// Maximum length of a request ID received from the client for it to be reused by the server.
+const MAX_REQUEST_ID_LENGTH = 128
+
+// Type of the key against which the request ID is stored in the request context.
+type requestIDContextKey struct{}
+
+// Returns the unique ID assigned to the request, which is also sent back to the client in the request ID response header.
+func (req *HttpRequest) RequestID() string {
+	return req.requestID
+}
+
+// Returns the request ID stored in the given context. It returns an empty string if the context does not belong to a request processed by the web server.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// Returns a logger which records the log entries for the request, with the request ID added as a field to every log entry.
+func (req *HttpRequest) Logger() Logger {
+	if req.logger == nil {
+		return newLogger()
+	}
+
+	return req.logger
+}
+
+// Assigns a request ID to the given request and echoes it in the request ID response header, whose name is set in the "request_id_header" server default.
+// The request ID sent by the client in the same header is reused if it is valid, or else a new random ID is generated. The request ID is stored in the request context
+// and is added to all the log entries recorded for the request.
+func (srv *HttpServer) assignRequestID(request *HttpRequest, response *HttpResponse) {
+	headerName := textproto.CanonicalMIMEHeaderKey(getServerDefaults("request_id_header"))
+	requestID, found := request.Headers.Get(headerName)
+	if !found || !isValidRequestID(requestID) {
+		requestID = generateRequestID()
+	}
+
+	request.requestID = requestID
+	request.SetValue(requestIDContextKey{}, requestID)
+	request.logger = &fieldLogger{ logger: srv.eventLogger, fields: []any{ "request_id", requestID } }
+	response.Headers[headerName] = []string{ requestID }
+}
+
+// Checks if the given request ID received from the client can be reused. Only non-empty IDs with at most MAX_REQUEST_ID_LENGTH letters, digits and the characters '-', '_', '.' and ':' are accepted,
+// so that an ID sent by the client cannot inject content into the server logs or the response headers.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > MAX_REQUEST_ID_LENGTH {
+		return false
+	}
+
+	for _, character := range requestID {
+		isAlphanumeric := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') || (character >= '0' && character <= '9')
+		if !isAlphanumeric && character != '-' && character != '_' && character != '.' && character != ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Generates a random (version 4) UUID to be used as the request ID.
+func generateRequestID() string {
+	randomBytes := make([]byte, 16)
+	rand.Read(randomBytes)
+	randomBytes[6] = (randomBytes[6] & 0x0f) | 0x40
+	randomBytes[8] = (randomBytes[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", randomBytes[0:4], randomBytes[4:6], randomBytes[6:8], randomBytes[8:10], randomBytes[10:16])
+}
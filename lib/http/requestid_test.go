@@ -0,0 +1,56 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Test case to validate the assignment of request IDs, with the request ID sent by the client reused only if it is valid.
+func Test_Server_AssignRequestID(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IncomingID string
+		ExpReused bool
+	} {
+		{ "Request without a request ID", "", false },
+		{ "Request with a valid request ID", "5f0c2a1e-trace:01", true },
+		{ "Request with a request ID containing spaces", "abc def", false },
+		{ "Request with a request ID that is too long", strings.Repeat("a", MAX_REQUEST_ID_LENGTH + 1), false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			var logOutput bytes.Buffer
+			testServer := NewServer()
+			testServer.SetLogger(NewLogger(&logOutput, LevelDebug, TextLogFormat))
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/"
+			if testCase.IncomingID != "" {
+				testRequest.Headers["X-Request-Id"] = []string{ testCase.IncomingID }
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.assignRequestID(testRequest, testResponse)
+			requestID := testRequest.RequestID()
+			headerValue, _ := testResponse.Headers.Get("X-Request-ID")
+			if testCase.ExpReused && requestID != testCase.IncomingID {
+				tt.Errorf("Expected the request ID [%s] to be reused, but got [%s]", testCase.IncomingID, requestID)
+			} else if !testCase.ExpReused && (requestID == testCase.IncomingID || len(requestID) != 36) {
+				tt.Errorf("Expected a new request ID to be generated, but got [%s]", requestID)
+			} else if headerValue != requestID || RequestIDFromContext(testRequest.Context()) != requestID {
+				tt.Errorf("Expected the response header [%s] and the context value to match the request ID [%s]", headerValue, requestID)
+			}
+
+			testServer.Log(testRequest, testResponse)
+			if !strings.Contains(logOutput.String(), "request_id=" + requestID) {
+				tt.Errorf("Expected the log entry [%s] to contain the request ID [%s]", logOutput.String(), requestID)
+			} else {
+				tt.Logf("The request ID [%s] has been assigned and logged as expected", requestID)
+			}
+		})
+	}
+}
@@ -2,13 +2,18 @@ package http
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	iofs "io/fs"
+	"net"
+	nethttp "net/http"
 	"net/textproto"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
-	"github.com/mkbworks/proteus/lib/fs"
 )
 
 // Structure to represent a response status code and its associated information.
@@ -37,6 +42,62 @@ type HttpResponse struct {
 	writer *bufio.Writer
 	// Boolean value to indicate if the response created is a test object.
 	isTest bool
+	// Boolean value to indicate if the response has already been written to the response byte stream.
+	isWritten bool
+	// Value of the Accept-Encoding header sent by the client, used to negotiate the compression of the response body.
+	acceptEncoding string
+	// Boolean value to indicate if the response body is being streamed to the client.
+	isStreaming bool
+	// Boolean value to indicate if the response body being streamed uses the chunked transfer encoding.
+	isChunked bool
+	// Length of the response body being streamed, as declared by the handler in the Content-Length header before the response was flushed. It is -1 if the length has not been declared.
+	streamLength int
+	// Maximum size (in bytes) of the response body written using WriteChunk() or Writer() that is buffered before the response is switched to streaming mode. A zero value means that the body is buffered until Flush() is called.
+	bufferSize int
+	// Boolean value to indicate if the client connection must be closed once the response has been sent.
+	closeConnection bool
+	// Boolean value to indicate if the response has been aborted midway, in which case the response is not completed and the client connection is closed.
+	isAborted bool
+	// Collection of functions to be executed just before the status line and headers of the response are written. These can be used to add headers to the response at the last moment.
+	beforeWriteHooks []func(*HttpResponse)
+	// Number of bytes of the response body written to the response byte stream.
+	bodySize int
+	// Collection of custom error handlers registered in the web server instance, with the response status code as key.
+	errorHandlers map[StatusCode]Handler
+	// Request for which the response is being sent, passed to the custom error handlers by the helpers sending an error response (like NotFound()).
+	request *HttpRequest
+	// Boolean value to indicate if the response is for a HEAD request, in which case the response body is never written to the response byte stream.
+	isHeadRequest bool
+	// Value of the Range header sent by the client in a GET request, used to send only a part of a file using SendFile().
+	rangeHeader string
+	// Value of the If-Range header sent by the client, used to check if the requested range of the file can be sent.
+	ifRangeHeader string
+	// Context of the request for which the response is being sent. It is cancelled when the client disconnects.
+	ctx context.Context
+	// Network connection to which the response is written.
+	connection net.Conn
+	// Maximum duration allowed for writing each event of an event stream, after which the write is abandoned. A zero duration means no timeout.
+	writeTimeout time.Duration
+	// Event stream of server-sent events, if the response has been switched to one.
+	eventStream *EventStream
+	// WebSocket connection to which the response has been upgraded, if any.
+	webSocket *WebSocket
+	// Writer of the HTTP/2 stream on which the response is sent. It is nil if the response is sent over HTTP/1.x.
+	http2Writer nethttp.ResponseWriter
+	// File whose contents are copied to the response byte stream as the response body, instead of the Body. It is nil if the response body is held in Body.
+	bodyFile iofs.File
+	// Path of the body file, which is reported in the errors raised while copying its contents.
+	bodyFilePath string
+	// Number of bytes to be copied from the body file to the response byte stream.
+	bodyFileSize int64
+	// HTML templates of the web server instance, which are used to render the response using Render().
+	templates *templateSet
+	// Collection of content types registered in the web server instance, used to find the media type of the files sent in the response.
+	contentTypes map[string]string
+	// Default content type set in the web server instance. The "content_type" server default is used if it is empty.
+	defaultContentType string
+	// Error returned by the handler for which the error response is being sent. It is nil if the handler has not returned an error.
+	httpError *HttpError
 }
 
 // // Initializes the instance of HttpResponse with default values for all its fields.
@@ -49,7 +110,6 @@ func (res *HttpResponse) initialize(version string, isTest bool) {
 	}
 	res.isTest = isTest
 	res.Headers = make(Headers)
-	res.addGeneralHeaders()
 	res.addResponseHeaders()
 }
 
@@ -58,24 +118,37 @@ func (res *HttpResponse) setWriter(writer *bufio.Writer) {
 	res.writer = writer
 }
 
-// Adds all the general HTTP headers to the HttpResponse instance.
+// Adds all the general HTTP headers to the HttpResponse instance. The Date header is added just before the headers are written, so that it carries the time at which the response is sent, unless the handler has set it.
 // Headers are added only if the given HttpResponse object is not a test instance and the response version is not HTTP/0.9.
 func (res *HttpResponse) addGeneralHeaders() {
-	if !strings.EqualFold(res.Version, "0.9") && !res.isTest {
+	if _, found := res.Headers.Get("Date"); !found && !strings.EqualFold(res.Version, "0.9") && !res.isTest {
 		res.Headers.Add("Date", getRfc1123Time())
 	}
 }
 
-// Adds all the default response HTTP headers to the HttpResponse instance. 
+// Adds all the default response HTTP headers to the HttpResponse instance. The Server header is sent with the "server_name" server default, and is omitted if the server default is empty.
 // Headers are added only if the given HttpResponse object is not a test instance and the response version is not HTTP/0.9.
 func (res *HttpResponse) addResponseHeaders() {
-	if !strings.EqualFold(res.Version, "0.9") && !res.isTest {
-		res.Headers.Add("Server", getServerDefaults("server_name"))
+	if serverName := getServerDefaults("server_name"); serverName != "" && !strings.EqualFold(res.Version, "0.9") && !res.isTest {
+		res.Headers.Add("Server", serverName)
+	}
+}
+
+// Replaces the Server header added from the "server_name" server default with the given token, or removes the header if the token is empty. Test instances and HTTP/0.9 responses are not changed, as they have no Server header.
+func (res *HttpResponse) setServerToken(Token string) {
+	if strings.EqualFold(res.Version, "0.9") || res.isTest {
+		return
+	}
+
+	delete(res.Headers, "Server")
+	if Token != "" {
+		res.Headers.Add("Server", Token)
 	}
 }
 
-// Writes bytes of data to response byte stream from the HttpResponse instance.
+// Writes bytes of data to response byte stream from the HttpResponse instance. An error is returned if the response has already been written, so that a second response is never sent for the same request.
 func (res *HttpResponse) write() error {
+	defer res.closeBodyFile()
 	if res.writer == nil {
 		resErr := new(ResponseError)
 		resErr.Section = "RespWrite"
@@ -84,7 +157,22 @@ func (res *HttpResponse) write() error {
 		return resErr
 	}
 
-	var err error
+	if res.isWritten {
+		resErr := new(ResponseError)
+		resErr.Section = "RespWrite"
+		resErr.Value = ""
+		resErr.Message = "Response has already been written to the client"
+		return resErr
+	}
+
+	res.isWritten = true
+	res.runBeforeWriteHooks()
+	res.addGeneralHeaders()
+	err := res.compress()
+	if err != nil {
+		return err
+	}
+
 	if !strings.EqualFold(res.Version, "0.9") {
 		err = res.writeStatusLine()
 		if err != nil {
@@ -99,9 +187,11 @@ func (res *HttpResponse) write() error {
 		}
 	}
 
-	err = res.writeBody()
-	if err != nil {
-		return err
+	if !res.isHeadRequest && !res.isBodyless() {
+		err = res.writeBody()
+		if err != nil {
+			return err
+		}
 	}
 
 	err = res.writer.Flush()
@@ -116,8 +206,27 @@ func (res *HttpResponse) write() error {
 	return nil
 }
 
+// Adds the given function to the collection of functions executed just before the status line and headers of the response are written.
+func (res *HttpResponse) onBeforeWrite(hook func(*HttpResponse)) {
+	res.beforeWriteHooks = append(res.beforeWriteHooks, hook)
+}
+
+// Executes all the functions registered to be executed before the status line and headers of the response are written. Each function is executed only once.
+func (res *HttpResponse) runBeforeWriteHooks() {
+	hooks := res.beforeWriteHooks
+	res.beforeWriteHooks = nil
+	for _, hook := range hooks {
+		hook(res)
+	}
+}
+
 // Writes the HTTP response status line to the response byte stream.
 func (res *HttpResponse) writeStatusLine() error {
+	if res.http2Writer != nil {
+		// The status code of HTTP/2 responses is sent along with the headers.
+		return nil
+	}
+
 	if res.StatusCode == 0 {
 		resErr := new(ResponseError)
 		resErr.Section = "StatusLine"
@@ -148,15 +257,21 @@ func (res *HttpResponse) writeStatusLine() error {
 
 // Writes the HTTP response headers to the response byte stream.
 func (res *HttpResponse) writeHeaders() error {
-	for key, values := range res.Headers {
-		value := strings.Join(values, ",")
-		_, err := res.writer.WriteString(fmt.Sprintf("%s: %s%s", key, value, HEADER_LINE_SEPERATOR))
-		if err != nil {
-			resErr := new(ResponseError)
-			resErr.Section = "Header"
-			resErr.Value = fmt.Sprintf("%s: %s", key, value)
-			resErr.Message = fmt.Sprintf("Error while writing response header :: %s", err.Error())
-			return resErr
+	if res.http2Writer != nil {
+		res.writeHTTP2Headers()
+		return nil
+	}
+
+	for key := range res.Headers {
+		for _, value := range res.Headers.getHeaderLines(key) {
+			_, err := res.writer.WriteString(fmt.Sprintf("%s: %s%s", key, value, HEADER_LINE_SEPERATOR))
+			if err != nil {
+				resErr := new(ResponseError)
+				resErr.Section = "Header"
+				resErr.Value = fmt.Sprintf("%s: %s", key, value)
+				resErr.Message = fmt.Sprintf("Error while writing response header :: %s", err.Error())
+				return resErr
+			}
 		}
 	}
 
@@ -174,6 +289,10 @@ func (res *HttpResponse) writeHeaders() error {
 
 // Writes the response body to the response byte stream.
 func (res *HttpResponse) writeBody() error {
+	if res.bodyFile != nil {
+		return res.writeBodyFile()
+	}
+
 	if len(res.Body) > 0 {
 		ContentType, exists := res.Headers.Get("Content-Type")
 		if exists {
@@ -208,24 +327,324 @@ func (res *HttpResponse) writeBody() error {
 				return resErr
 			}
 		}
+
+		res.bodySize += len(res.Body)
 	}
 
 	return nil
 }
 
-// Adds a new key-value pair to the request headers collection.
-func (res *HttpResponse) AddHeader(HeaderKey string, HeaderValue string) error {
-	if slices.Contains(DateHeaders, textproto.CanonicalMIMEHeaderKey(HeaderKey)) {
-		isValid, _ := isHttpDate(HeaderValue)
-		if isValid {
-			res.Headers.Add(HeaderKey, HeaderValue)
-		} else {
+// Copies the contents of the body file to the response byte stream. The buffered status line and headers are flushed first, so that the contents can be copied directly from the file to the network connection
+// (using sendfile on Linux for TCP connections) without passing through the response buffer. If the file is shorter than its size sent in the Content-Length header, the response is aborted.
+func (res *HttpResponse) writeBodyFile() error {
+	err := res.writer.Flush()
+	if err == nil {
+		var bytesCopied int64
+		bytesCopied, err = io.CopyN(res.writer, res.bodyFile, res.bodyFileSize)
+		res.bodySize += int(bytesCopied)
+	}
+
+	if err != nil {
+		res.isAborted = true
+		res.closeConnection = true
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = res.bodyFilePath
+		resErr.Message = fmt.Sprintf("Error while copying the file contents to the response body :: %s", err.Error())
+		return resErr
+	}
+
+	return nil
+}
+
+// Closes the body file of the response, if any.
+func (res *HttpResponse) closeBodyFile() {
+	if res.bodyFile != nil {
+		res.bodyFile.Close()
+		res.bodyFile = nil
+		res.bodyFilePath = ""
+	}
+}
+
+// Writes the response back to the client if it has not already been written by the route handler.
+// The status defaults to 200 OK and the Content-Length header is computed from the response body, so that the client can determine where the response ends on a persistent connection.
+// A Content-Length set by the handler is replaced, as the response body is complete at this point, except for the length of a file being sent and for the responses to HEAD requests without a body.
+func (res *HttpResponse) end() error {
+	if res.isAborted {
+		return nil
+	}
+
+	if res.eventStream != nil {
+		res.eventStream.Close()
+	}
+
+	if res.isStreaming {
+		return res.endStream()
+	}
+
+	if res.webSocket != nil {
+		res.webSocket.end()
+		return nil
+	}
+
+	if res.isWritten {
+		return nil
+	}
+
+	if res.StatusCode == 0 {
+		res.Status(StatusOK)
+	}
+
+	// Responses with status 1xx (Informational) or 204 (No Content) must not contain a Content-Length header, and a 304 (Not Modified) response carries the Content-Length of the unmodified resource, if any.
+	_, exists := res.Headers.Get("Content-Length")
+	if (res.StatusCode >= 100 && res.StatusCode < 200) || res.StatusCode == int(StatusNoContent) {
+		delete(res.Headers, "Content-Length")
+	} else if !res.isBodyless() && res.bodyFile == nil {
+		// The handler of a HEAD request may declare the length of the body it would have sent, without setting the body itself.
+		if !exists || len(res.Body) > 0 || !res.isHeadRequest {
+			res.Headers["Content-Length"] = []string{ strconv.Itoa(len(res.Body)) }
+		}
+	}
+
+	return res.write()
+}
+
+// Checks if the response status code is one for which a response body is never sent, i.e., 1xx (Informational), 204 (No Content) and 304 (Not Modified).
+func (res *HttpResponse) isBodyless() bool {
+	return (res.StatusCode >= 100 && res.StatusCode < 200) || res.StatusCode == int(StatusNoContent) || res.StatusCode == int(StatusNotModified)
+}
+
+// Writes the given data to the response body. Until the response is switched to streaming mode by Flush(), the data is buffered along with the rest of the response body, so that a response completed
+// by the handler is sent with its Content-Length (and can be compressed) as if the body had been set directly. The response is switched to streaming mode once the buffered body grows beyond the response buffer size
+// (Config.ResponseBufferSize), after which the data written is sent to the client when the buffer of the connection is full or when Flush() is called.
+func (res *HttpResponse) WriteChunk(data []byte) error {
+	if !res.isStreaming {
+		if res.isWritten {
+			return newStreamError()
+		}
+
+		res.Body = append(res.Body, data...)
+		if res.bufferSize <= 0 || len(res.Body) <= res.bufferSize {
+			return nil
+		}
+
+		return res.startStream()
+	}
+
+	return res.writeChunk(data)
+}
+
+// Sends all the buffered response data to the client. If the response is not already being streamed, it switches the response to streaming mode and sends the status line and the headers. Since the response body
+// is not complete at that point, the length of the body is sent in the Content-Length header only if the handler has set it. Otherwise, the chunked transfer encoding is used for HTTP/1.1 clients, while the
+// connection is closed once the response ends for older clients.
+func (res *HttpResponse) Flush() error {
+	err := res.startStream()
+	if err != nil {
+		return err
+	}
+
+	err = res.writer.Flush()
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "RespWrite"
+		resErr.Value = ""
+		resErr.Message = fmt.Sprintf("Writer object could not be flushed :: %s", err.Error())
+		return resErr
+	}
+
+	if res.http2Writer != nil {
+		res.flushHTTP2()
+	}
+
+	return nil
+}
+
+// Returns a writer which writes the data written to it to the response body as in WriteChunk(), so that the response can be written directly by encoders (like json.Encoder or csv.Writer). A large response body
+// is streamed to the client once it grows beyond the response buffer size, without being built completely in memory. Call Flush() to send the data written so far, after which the headers can no longer be changed.
+func (res *HttpResponse) Writer() io.Writer {
+	return &responseWriter{ response: res }
+}
+
+// Structure to represent the writer returned by Writer(), which streams the data written to it as the response body.
+type responseWriter struct {
+	// Response to which the data is written.
+	response *HttpResponse
+}
+
+// Writes the given data to the response body, which is buffered or streamed as in WriteChunk().
+func (writer *responseWriter) Write(data []byte) (int, error) {
+	err := writer.response.WriteChunk(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// Switches the response to streaming mode by writing the status line and the headers to the response byte stream. Any content already present in the response body is written as the first chunk.
+func (res *HttpResponse) startStream() error {
+	if res.isStreaming {
+		return nil
+	}
+
+	if res.writer == nil {
+		resErr := new(ResponseError)
+		resErr.Section = "RespWrite"
+		resErr.Value = ""
+		resErr.Message = "Writer object not initialized"
+		return resErr
+	}
+
+	if res.isWritten {
+		return newStreamError()
+	}
+
+	res.isWritten = true
+	res.isStreaming = true
+	res.runBeforeWriteHooks()
+	res.addGeneralHeaders()
+	if res.StatusCode == 0 {
+		res.Status(StatusOK)
+	}
+
+	res.streamLength = -1
+	if declaredLength, found := res.Headers.Get("Content-Length"); found && !res.isBodyless() {
+		if length, err := strconv.Atoi(strings.TrimSpace(declaredLength)); err == nil && length >= 0 {
+			res.streamLength = length
+		}
+	}
+
+	if res.streamLength < 0 {
+		delete(res.Headers, "Content-Length")
+	}
+
+	if res.streamLength >= 0 {
+		// The length of the response body has been declared by the handler, and hence the client can identify the end of the response without the chunked transfer encoding.
+	} else if res.http2Writer != nil {
+		// HTTP/2 streams are framed by the protocol itself and hence need neither the chunked transfer encoding nor the closing of the connection.
+	} else if strings.EqualFold(res.Version, "1.1") {
+		res.Headers.Add("Transfer-Encoding", "chunked")
+		res.isChunked = true
+	} else {
+		// Without chunked encoding, the client can identify the end of the response only when the connection is closed.
+		res.closeConnection = true
+		if !strings.EqualFold(res.Version, "0.9") {
+			delete(res.Headers, "Connection")
+			res.Headers.Add("Connection", "close")
+		}
+	}
+
+	if !strings.EqualFold(res.Version, "0.9") {
+		err := res.writeStatusLine()
+		if err != nil {
+			return err
+		}
+
+		err = res.writeHeaders()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(res.Body) > 0 {
+		bufferedBody := res.Body
+		res.Body = nil
+		return res.writeChunk(bufferedBody)
+	}
+
+	return nil
+}
+
+// Writes the given data to the response byte stream, framing it as a chunk if the chunked transfer encoding is used.
+func (res *HttpResponse) writeChunk(data []byte) error {
+	if len(data) == 0 || res.isHeadRequest {
+		// A zero length chunk marks the end of the response body and hence is never written here. The response body is not sent for HEAD requests.
+		return nil
+	}
+
+	if res.streamLength >= 0 && res.bodySize + len(data) > res.streamLength {
+		res.isAborted = true
+		res.closeConnection = true
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = strconv.Itoa(res.streamLength)
+		resErr.Message = "Response body written exceeds the length declared in the Content-Length header"
+		return resErr
+	}
+
+	var err error
+	if res.isChunked {
+		_, err = res.writer.WriteString(fmt.Sprintf("%x%s", len(data), HEADER_LINE_SEPERATOR))
+		if err == nil {
+			_, err = res.writer.Write(data)
+		}
+		if err == nil {
+			_, err = res.writer.WriteString(HEADER_LINE_SEPERATOR)
+		}
+	} else {
+		_, err = res.writer.Write(data)
+	}
+
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = "Chunk"
+		resErr.Message = fmt.Sprintf("Error while writing response chunk :: %s", err.Error())
+		return resErr
+	}
+
+	res.bodySize += len(data)
+	return nil
+}
+
+// Ends the response being streamed by writing the last chunk (if the chunked transfer encoding is used) and flushing the buffered data to the client.
+func (res *HttpResponse) endStream() error {
+	if res.streamLength >= 0 && !res.isHeadRequest && res.bodySize < res.streamLength {
+		// The client would wait for the rest of the response body, and hence the connection is closed after sending the data written so far.
+		res.isAborted = true
+		res.closeConnection = true
+		res.writer.Flush()
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = strconv.Itoa(res.streamLength)
+		resErr.Message = fmt.Sprintf("Response body of %d bytes is shorter than the length declared in the Content-Length header", res.bodySize)
+		return resErr
+	}
+
+	if res.isChunked && !res.isHeadRequest {
+		_, err := res.writer.WriteString("0" + HEADER_LINE_SEPERATOR + HEADER_LINE_SEPERATOR)
+		if err != nil {
 			resErr := new(ResponseError)
-			resErr.Section = "Header"
-			resErr.Value = fmt.Sprintf("%s: %s", HeaderKey, HeaderValue)
-			resErr.Message = "Date string must conform to one of these formats - RFC1123 or ANSIC"
+			resErr.Section = "Body"
+			resErr.Value = "Last Chunk"
+			resErr.Message = fmt.Sprintf("Error while writing the last response chunk :: %s", err.Error())
 			return resErr
 		}
+	}
+
+	return res.Flush()
+}
+
+// Returns the error raised when the response is streamed after it has already been written.
+func newStreamError() error {
+	resErr := new(ResponseError)
+	resErr.Section = "RespWrite"
+	resErr.Value = ""
+	resErr.Message = "Response has already been written and cannot be streamed"
+	return resErr
+}
+
+// Adds the given value to the response header with the given name, after the values already present for the header. The header name is canonicalized (like "content-type" to "Content-Type").
+// The values of a header are sent joined by commas in a single header line, except for the headers which cannot be combined (like Set-Cookie and WWW-Authenticate), whose values are sent in separate header lines.
+// An error is returned if the header name is not a valid token, if the value contains control characters, if the value of a date header is not a valid HTTP date or if the headers have already been written.
+func (res *HttpResponse) AddHeader(HeaderKey string, HeaderValue string) error {
+	HeaderKey, err := res.checkHeader("AddHeader", HeaderKey, HeaderValue)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(repeatedHeaders, HeaderKey) {
+		res.Headers[HeaderKey] = append(res.Headers[HeaderKey], strings.TrimSpace(HeaderValue))
 	} else {
 		res.Headers.Add(HeaderKey, HeaderValue)
 	}
@@ -233,40 +652,86 @@ func (res *HttpResponse) AddHeader(HeaderKey string, HeaderValue string) error {
 	return nil
 }
 
-// Sets the status of the HTTP response instance.
-func (res *HttpResponse) Status(status StatusCode) {
-	res.StatusCode = int(status)
-	res.StatusMessage = status.GetStatusMessage()
+// Sets the response header with the given name to the given value, replacing the values already present for the header. The header name is canonicalized as in AddHeader() and the same errors are returned.
+func (res *HttpResponse) SetHeader(HeaderKey string, HeaderValue string) error {
+	HeaderKey, err := res.checkHeader("SetHeader", HeaderKey, HeaderValue)
+	if err != nil {
+		return err
+	}
+
+	delete(res.Headers, HeaderKey)
+	return res.AddHeader(HeaderKey, HeaderValue)
 }
 
-// Send the given file from the local file system as the HTTP response.
-func (res *HttpResponse) SendFile(CompleteFilePath string, OnlyMetadata bool) error {
-	fileMediaType, exists := getContentType(CompleteFilePath)
-	if exists {
-		file, err := fs.GetFile(CompleteFilePath, fileMediaType, OnlyMetadata)
-		if err == nil {
-			res.Headers.Add("Content-Type", fileMediaType)
-			res.Headers.Add("Content-Length", strconv.FormatInt(file.Size, 10))
-			res.Headers.Add("Last-Modified", file.LastModifiedAt.Format(time.RFC1123))
-			if !OnlyMetadata {
-				res.Body = file.Contents
-			}
-			
-			err := res.write()
-			if err != nil {
-				return err
-			}
-		}
+// Removes all the values of the response header with the given name. An error is returned if the headers have already been written.
+func (res *HttpResponse) DelHeader(HeaderKey string) error {
+	HeaderKey = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(HeaderKey))
+	if res.isWritten {
+		return newHeaderWrittenError("DelHeader", HeaderKey)
 	}
 
+	delete(res.Headers, HeaderKey)
 	return nil
 }
 
+// Validates the given response header name and value before the header is modified by the given function, and returns the canonical form of the header name.
+func (res *HttpResponse) checkHeader(FunctionName string, HeaderKey string, HeaderValue string) (string, error) {
+	HeaderKey = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(HeaderKey))
+	if res.isWritten {
+		return HeaderKey, newHeaderWrittenError(FunctionName, HeaderKey)
+	}
+
+	resErr := new(ResponseError)
+	resErr.Section = "Header"
+	resErr.Value = fmt.Sprintf("%s: %s", HeaderKey, HeaderValue)
+	if !isToken(HeaderKey) {
+		resErr.Message = FunctionName + ": Header name must be a valid token"
+		return HeaderKey, resErr
+	} else if strings.IndexFunc(HeaderValue, func(char rune) bool { return isControlChar(char) && char != '\t' }) != -1 {
+		// Line breaks in the value would let the value inject other headers in the response.
+		resErr.Message = FunctionName + ": Header value must not contain control characters"
+		return HeaderKey, resErr
+	} else if slices.Contains(DateHeaders, HeaderKey) {
+		if isValid, _ := isHttpDate(HeaderValue); !isValid {
+			resErr.Message = FunctionName + ": Date string must conform to one of these formats - RFC1123 or ANSIC"
+			return HeaderKey, resErr
+		}
+	}
+
+	return HeaderKey, nil
+}
+
+// Creates the error returned when a response header is modified using the given function after the headers have been written to the client.
+func newHeaderWrittenError(FunctionName string, HeaderKey string) error {
+	resErr := new(ResponseError)
+	resErr.Section = "Header"
+	resErr.Value = HeaderKey
+	resErr.Message = FunctionName + ": Headers cannot be modified once they have been written to the client (like when the response body is being streamed)"
+	return resErr
+}
+
+// Adds the given header name to the Vary header of the response, if it is not already present in the header.
+func (res *HttpResponse) addVary(HeaderName string) {
+	for _, value := range res.Headers["Vary"] {
+		if strings.EqualFold(strings.TrimSpace(value), HeaderName) {
+			return
+		}
+	}
+
+	res.Headers.Add("Vary", HeaderName)
+}
+
+// Sets the status of the HTTP response instance.
+func (res *HttpResponse) Status(status StatusCode) {
+	res.StatusCode = int(status)
+	res.StatusMessage = status.GetStatusMessage()
+}
+
 // Sends a the given error content as response back to the client.
 func (res *HttpResponse) SendError(Content string) error {
 	responseContent := []byte(Content)
-	res.Headers.Add("Content-Type", ERROR_MSG_CONTENT_TYPE)
-	res.Headers.Add("Content-Length", strconv.Itoa(len(responseContent)))
+	res.Headers["Content-Type"] = []string{ ERROR_MSG_CONTENT_TYPE }
+	res.Headers["Content-Length"] = []string{ strconv.Itoa(len(responseContent)) }
 	res.Body = responseContent
 	err := res.write()
 	if err != nil {
@@ -274,4 +739,81 @@ func (res *HttpResponse) SendError(Content string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// Returns the error returned by the handler for which the error response is being sent, so that the error handlers registered using OnError() can show its message.
+// Errors which are not an HttpError are returned as an HttpError with the status 500 (Internal Server Error) and an empty message. It returns nil if the handler has not returned an error.
+func (res *HttpResponse) HttpError() *HttpError {
+	return res.httpError
+}
+
+// Sends a 204 (No Content) response back to the client, discarding any response body and the Content-Type and Content-Length headers set by the handler.
+func (res *HttpResponse) NoContent() error {
+	res.Status(StatusNoContent)
+	res.Body = nil
+	delete(res.Headers, "Content-Type")
+	delete(res.Headers, "Content-Length")
+	return res.write()
+}
+
+// Sends a 404 (Not Found) response back to the client, using the error handler set for the status code in the web server instance (like the one set using NotFound()) or the default ErrorHandler.
+func (res *HttpResponse) NotFound() error {
+	res.Status(StatusNotFound)
+	return handleError(res.request, res)
+}
+
+// Sends a 500 (Internal Server Error) response back to the client, using the error handler set for the status code in the web server instance or the default ErrorHandler. The given error is never sent to the client,
+// but is returned back so that the handler can return it to be logged by the server, like "return res.InternalError(err)". The error occurred while sending the response is returned instead, if any.
+func (res *HttpResponse) InternalError(err error) error {
+	res.Status(StatusInternalServerError)
+	if writeErr := handleError(res.request, res); writeErr != nil {
+		return writeErr
+	}
+
+	return err
+}
+
+// Sends the JSON encoding of the given value as response back to the client with the given status code.
+func (res *HttpResponse) JSON(status StatusCode, v any) error {
+	responseContent, err := json.Marshal(v)
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = JSON_CONTENT_TYPE
+		resErr.Message = fmt.Sprintf("Error while encoding response body as JSON :: %s", err.Error())
+		return resErr
+	}
+
+	res.Status(status)
+	res.Headers["Content-Type"] = []string{ JSON_CONTENT_TYPE }
+	res.Headers["Content-Length"] = []string{ strconv.Itoa(len(responseContent)) }
+	res.Body = responseContent
+	return res.write()
+}
+
+// Redirects the client to the given location using the given redirection status code (301, 302, 303, 307 or 308). An error is returned if the status code is not a redirection status code.
+func (res *HttpResponse) Redirect(status StatusCode, location string) error {
+	switch status {
+	case StatusMovedPermanently, StatusFound, StatusSeeOther, StatusTemporaryRedirect, StatusPermanentRedirect:
+	default:
+		resErr := new(ResponseError)
+		resErr.Section = "StatusLine"
+		resErr.Value = strconv.Itoa(int(status))
+		resErr.Message = "Redirect: Status code given is not a redirection status code"
+		return resErr
+	}
+
+	location = strings.TrimSpace(location)
+	if location == "" || strings.ContainsAny(location, "\r\n") {
+		resErr := new(ResponseError)
+		resErr.Section = "Header"
+		resErr.Value = location
+		resErr.Message = "Redirect: Location given is either empty or contains invalid characters"
+		return resErr
+	}
+
+	// The location is stored without splitting it on commas, since a URL can contain commas.
+	res.Status(status)
+	res.Headers["Location"] = []string{ location }
+	return nil
+}
@@ -2,9 +2,13 @@ package http
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 	"bufio"
 )
 
@@ -16,6 +20,17 @@ func newTestResponse(t testing.TB, version string) *HttpResponse {
 	return testRes
 }
 
+// Helper function to check if the two given response messages are the same, irrespective of the order in which the headers appear in them.
+func isSameResponse(actual string, expected string) bool {
+	actualHead, actualBody, _ := strings.Cut(actual, "\r\n\r\n")
+	expectedHead, expectedBody, _ := strings.Cut(expected, "\r\n\r\n")
+	actualLines := strings.Split(actualHead, "\r\n")
+	expectedLines := strings.Split(expectedHead, "\r\n")
+	slices.Sort(actualLines[1:])
+	slices.Sort(expectedLines[1:])
+	return actualBody == expectedBody && slices.Equal(actualLines, expectedLines)
+}
+
 // Test case to validate the addition of headers to a HTTP response message.
 func Test_Response_AddHeader(t *testing.T) {
 	testResponse := newTestResponse(t, "")
@@ -61,6 +76,70 @@ func Test_Response_AddHeader(t *testing.T) {
 	}
 }
 
+// Test case to validate the header lines sent for the response headers set, added and removed using the header methods of the response, and the errors returned for invalid headers.
+func Test_Response_HeaderMethods(t *testing.T) {
+	testResponse := newTestResponse(t, "1.1")
+	testCases := []struct {
+		Name string
+		Method string
+		InputHeaderKey string
+		InputHeaderValue string
+		ExpHeaderKey string
+		ExpHeaderLines []string
+		ExpError bool
+	} {
+		{ "Header name in lower case", "Add", "x-custom-header", "one", "X-Custom-Header", []string{ "one" }, false },
+		{ "Another value added to a header", "Add", "X-CUSTOM-HEADER", "two", "X-Custom-Header", []string{ "one,two" }, false },
+		{ "Values of a header replaced", "Set", "x-custom-header", "three", "X-Custom-Header", []string{ "three" }, false },
+		{ "Header which cannot be combined", "Add", "www-authenticate", `Basic realm="admin", charset="UTF-8"`, "Www-Authenticate", []string{ `Basic realm="admin", charset="UTF-8"` }, false },
+		{ "Another value added to a header which cannot be combined", "Add", "WWW-Authenticate", `Bearer realm="api"`, "Www-Authenticate", []string{ `Basic realm="admin", charset="UTF-8"`, `Bearer realm="api"` }, false },
+		{ "Header removed", "Del", "x-custom-header", "", "X-Custom-Header", nil, false },
+		{ "Header name which is not a token", "Set", "X Custom", "value", "X Custom", nil, true },
+		{ "Header value with a line break", "Add", "X-Injected", "value\r\nSet-Cookie: session=1", "X-Injected", nil, true },
+		{ "Date header with an invalid date", "Set", "last-modified", "yesterday", "Last-Modified", nil, true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			var err error
+			switch testCase.Method {
+			case "Add":
+				err = testResponse.AddHeader(testCase.InputHeaderKey, testCase.InputHeaderValue)
+			case "Set":
+				err = testResponse.SetHeader(testCase.InputHeaderKey, testCase.InputHeaderValue)
+			case "Del":
+				err = testResponse.DelHeader(testCase.InputHeaderKey)
+			}
+
+			headerLines := testResponse.Headers.getHeaderLines(testCase.ExpHeaderKey)
+			if testCase.ExpError && err == nil {
+				tt.Errorf("Was expecting an error for the header [%s: %s], but did not get one", testCase.InputHeaderKey, testCase.InputHeaderValue)
+			} else if !testCase.ExpError && err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+			} else if !slices.Equal(headerLines, testCase.ExpHeaderLines) {
+				tt.Errorf("The header lines %q do not match the expected header lines %q", headerLines, testCase.ExpHeaderLines)
+			} else {
+				tt.Logf("The header lines %q match the expected header lines", headerLines)
+			}
+		})
+	}
+
+	var opBuffer bytes.Buffer
+	testResponse.setWriter(bufio.NewWriter(&opBuffer))
+	testResponse.Status(StatusUnauthorized)
+	if err := testResponse.end(); err != nil {
+		t.Fatalf("Was not expecting an error while writing the response, but got this instead - %v", err)
+	}
+
+	if strings.Count(opBuffer.String(), "Www-Authenticate: ") != 2 {
+		t.Errorf("Expected the WWW-Authenticate header to be sent in 2 header lines, but got this instead - %s", opBuffer.String())
+	}
+
+	if _, isResponseError := testResponse.SetHeader("X-Late-Header", "value").(*ResponseError); !isResponseError {
+		t.Errorf("Was expecting a response error while setting a header after the response has been written")
+	}
+}
+
 // Test case to validate the working of the response write function.
 func Test_Response_Write(t *testing.T) {
 	testCases := []struct {
@@ -117,4 +196,409 @@ func Test_Response_Write(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Test case to validate the working of the JSON response helper.
+func Test_Response_JSON(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IpValue any
+		IpStatus StatusCode
+		IpContentType string
+		ExpErr string
+		ExpResponse string
+	} {
+		{ "A JSON object response", map[string]string{ "name": "proteus" }, StatusOK, "", "", "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 18\r\n\r\n{\"name\":\"proteus\"}" },
+		{ "A JSON array response", []int{ 1, 2, 3 }, StatusCreated, "", "", "HTTP/1.1 201 Created\r\nContent-Type: application/json\r\nContent-Length: 7\r\n\r\n[1,2,3]" },
+		{ "A JSON response replacing the content type set", []int{ 1, 2, 3 }, StatusOK, "text/plain", "", "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 7\r\n\r\n[1,2,3]" },
+		{ "A value that cannot be encoded", make(chan int), StatusOK, "", "ResponseError", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			if testCase.IpContentType != "" {
+				res.Headers.Add("Content-Type", testCase.IpContentType)
+			}
+
+			err := res.JSON(testCase.IpStatus, testCase.IpValue)
+			if testCase.ExpErr == "ResponseError" {
+				respErr, ok := err.(*ResponseError)
+				if !ok {
+					tt.Errorf("Was expecting a response error, but got this error instead - %v", err)
+				} else {
+					tt.Logf("Was expecting a response error and got one - %v", respErr)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet got this error - %v", err)
+				return
+			}
+
+			if !isSameResponse(opBuffer.String(), testCase.ExpResponse) {
+				tt.Errorf("The expected response [%s] does not match the response written [%s].", testCase.ExpResponse, opBuffer.String())
+			} else {
+				tt.Logf("The expected response [%s] matches the response written [%s].", testCase.ExpResponse, opBuffer.String())
+			}
+		})
+	}
+}
+
+// Test case to validate the status line, the body and the error returned by the helpers which send a response with a specific status code.
+func Test_Response_StatusHelpers(t *testing.T) {
+	handlerErr := errors.New("database is unreachable")
+	testCases := []struct {
+		Name string
+		Send func(*HttpResponse) error
+		ExpStatusLine string
+		ExpBody string
+		ExpErr error
+	} {
+		{ "No content response", func(res *HttpResponse) error { res.Body = []byte("discarded"); return res.NoContent() }, "HTTP/1.1 204 No Content\r\n", "", nil },
+		{ "Not found response", func(res *HttpResponse) error { return res.NotFound() }, "HTTP/1.1 404 Not Found\r\n", StatusNotFound.GetErrorContent(), nil },
+		{ "Internal error response", func(res *HttpResponse) error { return res.InternalError(handlerErr) }, "HTTP/1.1 500 Internal Server Error\r\n", StatusInternalServerError.GetErrorContent(), handlerErr },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			err := testCase.Send(res)
+			if err != testCase.ExpErr {
+				tt.Errorf("Expected the error [%v] to be returned, but got [%v] instead", testCase.ExpErr, err)
+				return
+			}
+
+			_, body, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			if !strings.HasPrefix(opBuffer.String(), testCase.ExpStatusLine) || body != testCase.ExpBody {
+				tt.Errorf("Expected the status line [%s] and the body [%s], but got the response [%s] instead", testCase.ExpStatusLine, testCase.ExpBody, opBuffer.String())
+			} else {
+				tt.Logf("Received the status line [%s] and the body expected", testCase.ExpStatusLine)
+			}
+		})
+	}
+}
+
+// Test case to validate if a response cannot be sent again, once it has been written to the client.
+func Test_Response_WriteTwice(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Send func(*HttpResponse) error
+	} {
+		{ "JSON response after a response", func(res *HttpResponse) error { return res.JSON(StatusOK, []int{ 1 }) } },
+		{ "Error response after a response", func(res *HttpResponse) error { return res.NotFound() } },
+		{ "No content response after a response", func(res *HttpResponse) error { return res.NoContent() } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			err := res.JSON(StatusCreated, map[string]string{ "name": "proteus" })
+			if err != nil {
+				tt.Errorf("Was not expecting an error for the first response and yet got this error - %v", err)
+				return
+			}
+
+			firstResponse := opBuffer.String()
+			err = testCase.Send(res)
+			if _, ok := err.(*ResponseError); !ok {
+				tt.Errorf("Was expecting a response error for the second response, but got this instead - %v", err)
+			} else if opBuffer.String() != firstResponse {
+				tt.Errorf("Was expecting only the first response to be written, but got [%s] instead", opBuffer.String())
+			} else {
+				tt.Logf("The second response was rejected as expected - %v", err)
+			}
+		})
+	}
+}
+
+// Test case to validate the working of writing the response body in chunks, which are buffered until the response is flushed or the buffered body grows beyond the buffer size.
+func Test_Response_WriteChunk(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IpVersion string
+		IpChunks []string
+		Flush bool
+		BufferSize int
+		ContentLength string
+		ExpResponse string
+		ExpClose bool
+	} {
+		{ "A v1.1 response completed without being flushed", "1.1", []string{ "Hello, ", "proteus!" }, false, 0, "", "HTTP/1.1 200 OK\r\nContent-Length: 15\r\n\r\nHello, proteus!", false },
+		{ "A v1.1 chunked response", "1.1", []string{ "Hello, ", "proteus!" }, true, 0, "", "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n7\r\nHello, \r\n8\r\nproteus!\r\n0\r\n\r\n", false },
+		{ "A v1.1 response larger than the buffer size", "1.1", []string{ "Hello, ", "proteus!" }, false, 10, "", "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\nf\r\nHello, proteus!\r\n0\r\n\r\n", false },
+		{ "A v1.1 response flushed with a declared Content-Length", "1.1", []string{ "Hello, ", "proteus!" }, true, 0, "15", "HTTP/1.1 200 OK\r\nContent-Length: 15\r\n\r\nHello, proteus!", false },
+		{ "A v1.0 streamed response", "1.0", []string{ "Hello, ", "proteus!" }, true, 0, "", "HTTP/1.0 200 OK\r\nConnection: close\r\n\r\nHello, proteus!", true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, testCase.IpVersion)
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			res.bufferSize = testCase.BufferSize
+			if testCase.ContentLength != "" {
+				res.Headers.Add("Content-Length", testCase.ContentLength)
+			}
+			for index, chunk := range testCase.IpChunks {
+				err := res.WriteChunk([]byte(chunk))
+				if err == nil && index == 0 && testCase.Flush {
+					err = res.Flush()
+				}
+				if err != nil {
+					tt.Errorf("Was not expecting an error while writing a chunk and yet got this error - %v", err)
+					return
+				}
+			}
+
+			err := res.end()
+			if err != nil {
+				tt.Errorf("Was not expecting an error while ending the response and yet got this error - %v", err)
+				return
+			}
+
+			if !isSameResponse(opBuffer.String(), testCase.ExpResponse) {
+				tt.Errorf("The expected response [%q] does not match the response written [%q].", testCase.ExpResponse, opBuffer.String())
+			} else {
+				tt.Logf("The expected response [%q] matches the response written [%q].", testCase.ExpResponse, opBuffer.String())
+			}
+
+			if res.closeConnection != testCase.ExpClose {
+				tt.Errorf("Expected the connection close flag to be %t, but got %t", testCase.ExpClose, res.closeConnection)
+			}
+		})
+	}
+}
+
+// Test case to validate that a streamed response whose body does not match the length declared in the Content-Length header is aborted, so that the client does not misread the end of the response.
+func Test_Response_StreamContentLength(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IpChunks []string
+		ExpWriteErr bool
+	} {
+		{ "Response body longer than the declared length", []string{ "Hello, ", "proteus!" }, true },
+		{ "Response body shorter than the declared length", []string{ "Hello" }, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			res.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			res.Headers.Add("Content-Length", "10")
+			var err error
+			for index, chunk := range testCase.IpChunks {
+				err = res.WriteChunk([]byte(chunk))
+				if err == nil && index == 0 {
+					err = res.Flush()
+				}
+			}
+
+			if (err != nil) != testCase.ExpWriteErr {
+				tt.Errorf("Expected an error while writing the response body [%t], but got this instead - %v", testCase.ExpWriteErr, err)
+			}
+			if !testCase.ExpWriteErr {
+				err = res.end()
+				if err == nil {
+					tt.Errorf("Expected an error while ending the response, but got nil")
+				}
+			}
+
+			if !res.isAborted || !res.closeConnection {
+				tt.Errorf("Expected the response to be aborted and the connection to be closed, but got the flags %t and %t", res.isAborted, res.closeConnection)
+			} else {
+				tt.Logf("The response has been aborted as expected - %v", err)
+			}
+		})
+	}
+}
+
+// Test case to validate the streaming of the response body written by an encoder using the writer returned by Writer().
+func Test_Response_Writer(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IpVersion string
+		IpValues []any
+		ExpHead string
+		ExpResponse string
+	} {
+		{ "A v1.1 response written by a JSON encoder", "1.1", []any{ map[string]int{ "id": 1 }, []string{ "a" } }, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nTransfer-Encoding: chunked\r\n\r\n", "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nTransfer-Encoding: chunked\r\n\r\n9\r\n{\"id\":1}\n\r\n6\r\n[\"a\"]\n\r\n0\r\n\r\n" },
+		{ "A v1.0 response written by a JSON encoder", "1.0", []any{ 1, 2 }, "HTTP/1.0 200 OK\r\nContent-Type: application/json\r\nConnection: close\r\n\r\n", "HTTP/1.0 200 OK\r\nContent-Type: application/json\r\nConnection: close\r\n\r\n1\n2\n" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, testCase.IpVersion)
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			res.Headers.Add("Content-Type", "application/json")
+			encoder := json.NewEncoder(res.Writer())
+			for index, value := range testCase.IpValues {
+				err := encoder.Encode(value)
+				if err == nil && index == 0 {
+					if opBuffer.Len() != 0 {
+						tt.Errorf("Expected the response to be buffered until it is flushed, but got [%q]", opBuffer.String())
+						return
+					}
+					err = res.Flush()
+				}
+				if err != nil {
+					tt.Errorf("Was not expecting an error while encoding a value and yet got this error - %v", err)
+					return
+				}
+
+				head, _, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+				if index == 0 && !isSameResponse(head + "\r\n\r\n", testCase.ExpHead) {
+					tt.Errorf("Expected the status line and headers [%q] to be flushed by Flush(), but got [%q]", testCase.ExpHead, opBuffer.String())
+					return
+				}
+			}
+
+			err := res.end()
+			if err != nil {
+				tt.Errorf("Was not expecting an error while ending the response and yet got this error - %v", err)
+			} else if !isSameResponse(opBuffer.String(), testCase.ExpResponse) {
+				tt.Errorf("The expected response [%q] does not match the response written [%q].", testCase.ExpResponse, opBuffer.String())
+			} else {
+				tt.Logf("The expected response [%q] matches the response written [%q].", testCase.ExpResponse, opBuffer.String())
+			}
+		})
+	}
+}
+
+// Test case to validate that the response body is suppressed for HEAD requests, while the Content-Length header still reflects the body that would have been sent.
+func Test_Response_HeadRequest(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Stream bool
+		ExpResponse string
+	} {
+		{ "Buffered response body", false, "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 11\r\n\r\n" },
+		{ "Streamed response body", true, "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nTransfer-Encoding: chunked\r\n\r\n" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			res.isHeadRequest = true
+			var opBuffer bytes.Buffer
+			res.setWriter(bufio.NewWriter(&opBuffer))
+			res.Headers.Add("Content-Type", "text/plain")
+			if testCase.Stream {
+				res.WriteChunk([]byte("hello world"))
+				res.Flush()
+			} else {
+				res.Body = []byte("hello world")
+			}
+
+			err := res.end()
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet got this error - %v", err)
+				return
+			}
+
+			if !isSameResponse(opBuffer.String(), testCase.ExpResponse) {
+				tt.Errorf("The expected response [%q] does not match the response written [%q].", testCase.ExpResponse, opBuffer.String())
+			} else {
+				tt.Logf("The expected response [%q] matches the response written [%q].", testCase.ExpResponse, opBuffer.String())
+			}
+		})
+	}
+}
+
+// Test case to validate the headers added automatically to every response, with the Content-Length computed from the buffered response body and the Date sent in GMT.
+func Test_Response_AutomaticHeaders(t *testing.T) {
+	originalServerName := ServerDefaults["server_name"]
+	defer func() {
+		ServerDefaults["server_name"] = originalServerName
+	}()
+
+	testCases := []struct {
+		Name string
+		ServerName string
+		Status StatusCode
+		IsHeadRequest bool
+		ContentLength string
+		Body string
+		ExpHeaders map[string]string
+	} {
+		{ "Buffered response body", "proteus", StatusOK, false, "", "hello world", map[string]string{ "Content-Length": "11", "Server": "proteus" } },
+		{ "Content-Length set by the handler that does not match the body", "proteus", StatusOK, false, "5", "hello world", map[string]string{ "Content-Length": "11" } },
+		{ "Content-Length declared by the handler of a HEAD request", "proteus", StatusOK, true, "42", "", map[string]string{ "Content-Length": "42" } },
+		{ "Content-Length set by the handler for a 204 response", "proteus", StatusNoContent, false, "5", "", map[string]string{ "Content-Length": "" } },
+		{ "Server header suppressed", "", StatusOK, false, "", "hello world", map[string]string{ "Server": "" } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			ServerDefaults["server_name"] = testCase.ServerName
+			res := new(HttpResponse)
+			res.initialize("1.1", false)
+			res.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			res.isHeadRequest = testCase.IsHeadRequest
+			res.Status(testCase.Status)
+			if testCase.ContentLength != "" {
+				res.Headers.Add("Content-Length", testCase.ContentLength)
+			}
+			res.Body = []byte(testCase.Body)
+			err := res.end()
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while ending the response and yet got this error - %v", err)
+			}
+
+			for name, expValue := range testCase.ExpHeaders {
+				if value, _ := res.Headers.Get(name); value != expValue {
+					tt.Errorf("Expected the header %s to be [%s], but got [%s] instead", name, expValue, value)
+				} else {
+					tt.Logf("Header %s is [%s] as expected", name, value)
+				}
+			}
+
+			date, _ := res.Headers.Get("Date")
+			if parsedDate, err := time.Parse(HTTP_DATE_FORMAT, date); err != nil || time.Since(parsedDate) > time.Minute {
+				tt.Errorf("Expected the Date header with the current time in GMT, but got [%s]", date)
+			}
+		})
+	}
+}
+
+// Test case to validate the redirection responses created using Redirect().
+func Test_Response_Redirect(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Status StatusCode
+		Location string
+		ExpErr bool
+	} {
+		{ "Redirect with a see other status", StatusSeeOther, "/login?next=/a,b", false },
+		{ "Redirect with a permanent redirect status", StatusPermanentRedirect, "https://example.com/", false },
+		{ "Redirect with a non-redirection status", StatusOK, "/login", true },
+		{ "Redirect to a location with a new line", StatusSeeOther, "/login\r\nSet-Cookie: a=b", true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			res := newTestResponse(tt, "1.1")
+			err := res.Redirect(testCase.Status, testCase.Location)
+			if testCase.ExpErr {
+				if _, ok := err.(*ResponseError); !ok {
+					tt.Errorf("Was expecting a response error, but got this instead - %v", err)
+				}
+				return
+			}
+
+			location, _ := res.Headers.Get("Location")
+			if err != nil || res.StatusCode != int(testCase.Status) || location != testCase.Location {
+				tt.Errorf("Expected status [%d] and location [%s], but got status [%d], location [%s] and error %v", testCase.Status, testCase.Location, res.StatusCode, location, err)
+			} else {
+				tt.Logf("The response status [%d] and location [%s] match the expected values", res.StatusCode, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Name of the environment variable that contains the file descriptor of the listener socket inherited from the previous process, when the process has been started by Restart().
+	LISTENER_FD_ENV = "PROTEUS_LISTENER_FD"
+	// Name of the environment variable that contains the address of the listener socket inherited from the previous process, so that the socket is used by the server listening at the same port.
+	LISTENER_ADDRESS_ENV = "PROTEUS_LISTENER_ADDRESS"
+)
+
+// Returns the listener socket inherited from the previous process through the file descriptor given in the LISTENER_FD_ENV environment variable, if the socket is bound to the given port number
+// (or the port number is zero). This lets a process running several web server instances hand each inherited socket to the server listening at its port. The environment variables are removed once
+// the listener has been created, so that the socket is inherited only once. A nil listener is returned if the environment variable has not been set or the socket is bound to another port.
+func inheritedListener(PortNumber int) (net.Listener, error) {
+	fdValue, found := os.LookupEnv(LISTENER_FD_ENV)
+	if !found {
+		return nil, nil
+	}
+
+	if _, port, err := net.SplitHostPort(os.Getenv(LISTENER_ADDRESS_ENV)); err == nil && PortNumber != 0 && port != strconv.Itoa(PortNumber) {
+		return nil, nil
+	}
+
+	os.Unsetenv(LISTENER_FD_ENV)
+	os.Unsetenv(LISTENER_ADDRESS_ENV)
+	listenErr := new(ListenError)
+	listenErr.Address = LISTENER_FD_ENV + "=" + fdValue
+	fd, err := strconv.Atoi(strings.TrimSpace(fdValue))
+	if err != nil || fd < 0 {
+		listenErr.Message = "Environment variable must contain the file descriptor of the inherited listener socket"
+		return nil, listenErr
+	}
+
+	file := os.NewFile(uintptr(fd), "proteus-listener")
+	defer file.Close()
+	listener, err := net.FileListener(file)
+	if err != nil {
+		listenErr.Message = fmt.Sprintf("Error occurred while creating the listener from the inherited socket: %s", err.Error())
+		return nil, listenErr
+	}
+
+	return listener, nil
+}
+
+// Restarts the web server without dropping any request, by starting a new process of the current executable (with the same arguments and environment) which inherits the server socket,
+// so that new connections are accepted by the new process as soon as it starts listening. The current process then stops accepting connections, closes the idle connections and waits for
+// the requests in progress to be completed, for up to the given timeout (a zero timeout waits indefinitely), after which the server is shut down and the listen method returns.
+// The new process must set up its server using one of Listen(), ListenAndServeAsync() or ListenTLS(), which use the inherited socket instead of creating a new one.
+// An error is returned if the server is not listening on a TCP socket created by these methods or the new process could not be started, in which case the server continues to run.
+func (srv *HttpServer) Restart(timeout time.Duration) error {
+	srv.socketMutex.Lock()
+	socket, ok := srv.inheritableSocket.(*net.TCPListener)
+	srv.socketMutex.Unlock()
+	listenErr := new(ListenError)
+	listenErr.Address = srv.HostAddress + ":" + strconv.Itoa(srv.PortNumber)
+	if !ok || socket == nil || srv.isDraining.Load() {
+		listenErr.Message = "Restart: Server must be listening on a TCP socket created by Listen(), ListenAndServeAsync() or ListenTLS()"
+		return listenErr
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		listenErr.Message = fmt.Sprintf("Restart: Error occurred while finding the current executable: %s", err.Error())
+		return listenErr
+	}
+
+	socketFile, err := socket.File()
+	if err != nil {
+		listenErr.Message = fmt.Sprintf("Restart: Error occurred while duplicating the server socket: %s", err.Error())
+		return listenErr
+	}
+	defer socketFile.Close()
+
+	environment := make([]string, 0, len(os.Environ()) + 2)
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(variable, LISTENER_FD_ENV + "=") && !strings.HasPrefix(variable, LISTENER_ADDRESS_ENV + "=") {
+			environment = append(environment, variable)
+		}
+	}
+
+	// The files in ExtraFiles are given the file descriptors starting from 3 in the new process, after the standard input, output and error.
+	process := exec.Command(executable, os.Args[1:]...)
+	process.Env = append(environment, LISTENER_FD_ENV + "=3", LISTENER_ADDRESS_ENV + "=" + socket.Addr().String())
+	process.Stdin = os.Stdin
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+	process.ExtraFiles = []*os.File{ socketFile }
+	err = process.Start()
+	if err != nil {
+		listenErr.Message = fmt.Sprintf("Restart: Error occurred while starting the new process: %s", err.Error())
+		return listenErr
+	}
+
+	srv.LogInfo("New process has been started with the server socket and the current process is draining its connections", "pid", process.Process.Pid)
+	srv.drain(timeout)
+	return nil
+}
+
+// Restarts the web server using Restart() with the given timeout, whenever the process receives one of the given signals. If no signals are given, the server is restarted on SIGUSR2
+// (on platforms which support it). The signals are no longer handled once the server shuts down.
+func (srv *HttpServer) RestartOnSignal(timeout time.Duration, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = defaultRestartSignals
+	}
+
+	if len(signals) == 0 {
+		return
+	}
+
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, signals...)
+	go func() {
+		defer signal.Stop(signalChannel)
+		for {
+			select {
+			case <-signalChannel:
+				err := srv.Restart(timeout)
+				if err != nil {
+					srv.LogError(err.Error())
+				} else {
+					return
+				}
+			case <-srv.baseContext.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stops the web server from accepting new connections and closes the idle connections, while the requests in progress are completed. The connections are closed once their current request
+// has been completed. It returns once all the connections have been closed or the given timeout has elapsed, after which the server is shut down.
+func (srv *HttpServer) drain(timeout time.Duration) {
+	srv.drainTimeout.Store(int64(timeout))
+	if !srv.isDraining.CompareAndSwap(false, true) {
+		<-srv.drainCompleted
+		return
+	}
+
+	srv.socketMutex.Lock()
+	if srv.Socket != nil {
+		srv.Socket.Close()
+	}
+	srv.socketMutex.Unlock()
+
+	srv.connectionMutex.Lock()
+	for connection := range srv.idleConnections {
+		// Setting a read deadline in the past unblocks the connection waiting for its next request, which is then closed.
+		connection.SetReadDeadline(time.Unix(1, 0))
+	}
+	srv.connectionMutex.Unlock()
+	<-srv.drainCompleted
+}
+
+// Waits for all the client connections to be closed (or for the drain timeout to elapse) once the server has stopped accepting connections while draining, and then shuts down the server.
+// It does nothing if the server is not draining its connections.
+func (srv *HttpServer) completeDrain() {
+	if !srv.isDraining.Load() {
+		return
+	}
+
+	connectionsClosed := make(chan struct{})
+	go func() {
+		srv.activeConnections.Wait()
+		close(connectionsClosed)
+	}()
+
+	var timeoutElapsed <-chan time.Time
+	if timeout := time.Duration(srv.drainTimeout.Load()); timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutElapsed = timer.C
+	}
+
+	select {
+	case <-connectionsClosed:
+		srv.LogInfo("All the client connections have been closed after draining")
+	case <-timeoutElapsed:
+		srv.LogWarn("Timeout elapsed before all the client connections could be closed after draining")
+	}
+
+	srv.cancelBaseContext()
+	close(srv.drainCompleted)
+}
+
+// Records whether the given client connection is waiting for its next request, so that idle connections can be closed when the server drains its connections. It returns false if the
+// connection must be closed instead of waiting for the next request, as the server is draining its connections.
+func (srv *HttpServer) setIdle(ClientConnection net.Conn, isIdle bool) bool {
+	srv.connectionMutex.Lock()
+	defer srv.connectionMutex.Unlock()
+	if !isIdle {
+		delete(srv.idleConnections, ClientConnection)
+		return true
+	}
+
+	if srv.isDraining.Load() {
+		return false
+	}
+
+	srv.idleConnections[ClientConnection] = struct{}{}
+	return true
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package http
+
+import (
+	"os"
+)
+
+// Signals on which the web server is restarted by RestartOnSignal(), if no signals are given. SIGUSR2 is not available on this platform and hence the signals must be given explicitly.
+var defaultRestartSignals = []os.Signal{}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	nethttp "net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Test case to validate the listener socket inherited from the previous process through the LISTENER_FD_ENV environment variable.
+func Test_Server_InheritedListener(t *testing.T) {
+	parentListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error occurred while creating the listener to be inherited - %v", err)
+	}
+	defer parentListener.Close()
+	socketFile, err := parentListener.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("Error occurred while duplicating the listener socket - %v", err)
+	}
+	// The file descriptor of the duplicated socket is closed by the web server once the listener has been inherited.
+
+	otherListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error occurred while finding a free port - %v", err)
+	}
+	otherPort := otherListener.Addr().(*net.TCPAddr).Port
+	otherListener.Close()
+
+	testCases := []struct {
+		Name string
+		FdValue string
+		PortNumber int
+		ExpError bool
+		ExpInherited bool
+	} {
+		{ "Socket bound to another port", strconv.Itoa(int(socketFile.Fd())), otherPort, false, false },
+		{ "Valid file descriptor of a listener socket", strconv.Itoa(int(socketFile.Fd())), 0, false, true },
+		{ "Value which is not a file descriptor", "listener", 0, true, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			tt.Setenv(LISTENER_FD_ENV, testCase.FdValue)
+			tt.Setenv(LISTENER_ADDRESS_ENV, parentListener.Addr().String())
+			testServer := NewServer()
+			testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+			testServer.Get("/ping", func(req *HttpRequest, res *HttpResponse) error {
+				res.Status(StatusOK)
+				return res.SendError("pong")
+			})
+			err := testServer.ListenAndServeAsync(testCase.PortNumber, "127.0.0.1")
+			// The environment variable is removed once the socket has been claimed by the server, even if the listener could not be created.
+			if _, found := os.LookupEnv(LISTENER_FD_ENV); found == (testCase.ExpInherited || testCase.ExpError) {
+				tt.Errorf("Expected the environment variable to be removed only if the socket has been claimed by the server")
+			}
+
+			if testCase.ExpError {
+				if err == nil {
+					testServer.Shutdown()
+					tt.Errorf("Expected an error while inheriting the listener, but got none")
+				} else {
+					tt.Logf("Received the expected error - %v", err)
+				}
+				return
+			} else if err != nil {
+				tt.Fatalf("Was not expecting an error and yet received one - %v", err)
+			}
+			defer testServer.Shutdown()
+
+			if testCase.ExpInherited != (testServer.Addr().String() == parentListener.Addr().String()) {
+				tt.Fatalf("Expected the socket to be inherited only if it is bound to the port of the server, but the server is listening at %s", testServer.Addr().String())
+			}
+
+			response, err := nethttp.Get("http://" + testServer.Addr().String() + "/ping")
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while sending the request and yet received one - %v", err)
+			}
+			defer response.Body.Close()
+			if response.StatusCode != int(StatusOK) {
+				tt.Errorf("Expected status %d, but got status %d", StatusOK, response.StatusCode)
+			} else {
+				tt.Logf("Request has been served at %s as expected", testServer.Addr().String())
+			}
+		})
+	}
+}
+
+// Test case to validate that the requests in progress are completed and the idle connections are closed, when the web server drains its connections before restarting.
+func Test_Server_Drain(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	requestStarted := make(chan struct{})
+	testServer.Get("/fast", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return res.SendError("fast")
+	})
+	testServer.Get("/slow", func(req *HttpRequest, res *HttpResponse) error {
+		close(requestStarted)
+		time.Sleep(200 * time.Millisecond)
+		res.Status(StatusOK)
+		return res.SendError("slow")
+	})
+	err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	serverAddress := testServer.Addr().String()
+
+	sendRequest := func(connection net.Conn, reader *bufio.Reader, path string) (*nethttp.Response, error) {
+		connection.SetDeadline(time.Now().Add(3 * time.Second))
+		connection.Write([]byte("GET " + path + " HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+		response, err := nethttp.ReadResponse(reader, nil)
+		if err == nil {
+			io.ReadAll(response.Body)
+			response.Body.Close()
+		}
+		return response, err
+	}
+
+	idleConnection, err := net.Dial("tcp", serverAddress)
+	if err != nil {
+		t.Fatalf("Error occurred while connecting to the server - %v", err)
+	}
+	defer idleConnection.Close()
+	idleReader := bufio.NewReader(idleConnection)
+	if _, err := sendRequest(idleConnection, idleReader, "/fast"); err != nil {
+		t.Fatalf("Was not expecting an error while sending the request and yet received one - %v", err)
+	}
+
+	busyConnection, err := net.Dial("tcp", serverAddress)
+	if err != nil {
+		t.Fatalf("Error occurred while connecting to the server - %v", err)
+	}
+	defer busyConnection.Close()
+	busyResponse := make(chan *nethttp.Response, 1)
+	go func() {
+		response, _ := sendRequest(busyConnection, bufio.NewReader(busyConnection), "/slow")
+		busyResponse <- response
+	}()
+
+	<-requestStarted
+	drainStart := time.Now()
+	testServer.drain(2 * time.Second)
+	if time.Since(drainStart) >= 2 * time.Second {
+		t.Errorf("Expected the draining to complete once the connections have been closed, before the timeout elapsed")
+	}
+
+	response := <-busyResponse
+	if response == nil || response.StatusCode != int(StatusOK) || !response.Close {
+		t.Errorf("Expected the request in progress to be completed with the connection being closed, but got %v", response)
+	} else {
+		t.Logf("The request in progress has been completed as expected")
+	}
+
+	idleConnection.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := idleReader.ReadByte(); err != io.EOF {
+		t.Errorf("Expected the idle connection to be closed, but got %v", err)
+	}
+
+	if connection, err := net.DialTimeout("tcp", serverAddress, time.Second); err == nil {
+		connection.Close()
+		t.Errorf("Was not expecting the server to accept new connections after draining")
+	}
+
+	if err := testServer.Restart(time.Second); err == nil {
+		t.Errorf("Expected an error while restarting a server which has already been drained, but got none")
+	}
+}
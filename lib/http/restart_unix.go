@@ -0,0 +1,11 @@
+//go:build unix
+
+package http
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals on which the web server is restarted by RestartOnSignal(), if no signals are given.
+var defaultRestartSignals = []os.Signal{ syscall.SIGUSR2 }
@@ -1,9 +1,16 @@
 package http
 
 import (
+	"fmt"
+	iofs "io/fs"
+	"net/url"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"github.com/mkbworks/proteus/lib/fs"
 )
 
@@ -13,6 +20,8 @@ type Route struct {
 	IsStatic bool
 	// Defined only for static routes. This field contains the target folder path mapped to the given route path. It is assigned an empty string for dynamic routes.
 	StaticFolderPath string
+	// Defined only for static routes defined using StaticFS(). Contains the file system (like an embed.FS) whose files are served by the static route, instead of a folder of the local file system.
+	StaticFS fs.FileSystem
 	// Handler function to be executed for the route paths.
 	RouteHandler Handler
 	// Represents the order in which the route was defined by the users. This also determines the priority of a path being chosen when a request is being processed.
@@ -21,9 +30,38 @@ type Route struct {
 	Method string
 	// Route path being defined for the router
 	RoutePath string
+	// Collection of middlewares to be executed only for this route. These are executed after the middlewares defined for the router.
+	Middlewares []Middleware
+	// Defined only for static routes. Contains the settings applicable to the static route.
+	StaticOptions *StaticOptions
+	// Is true if the route path was defined with a trailing '/'. Used to redirect requests to the defined variant of the route path, when trailing slash redirection is enabled.
+	TrailingSlash bool
+	// Name given to the route, which is used to build the URL of the route using URLFor(). It is empty if the route has not been named.
+	Name string
+	// Name of the handler function given for the route, as reported by the Go runtime (like "main.getUser").
+	HandlerName string
+	// Maximum size (in bytes) of the request body accepted by the route. It is zero if the route accepts request bodies up to the "max_body_size" server default.
+	MaxBodySize int64
+	// Documentation of the route set using Describe(), which is added to the OpenAPI document of the web server instance. It is nil if the route has not been documented.
+	Doc *RouteDoc
 }
 
-// Structure to hold all the routes and the associated routing logic.
+// Structure to describe a single route defined in a web server instance, as returned by Routes().
+type RouteInfo struct {
+	// HTTP method for which the route is defined.
+	Method string
+	// Route path of the route, including its path parameters and wildcard.
+	Pattern string
+	// Is true if the route serves files from a static folder.
+	IsStatic bool
+	// Name of the handler function given for the route, as reported by the Go runtime (like "main.getUser").
+	HandlerName string
+	// Name given to the route using Name(). It is empty if the route has not been named.
+	Name string
+}
+
+// Structure to hold all the routes and the associated routing logic. Routes can be added to (or removed from) the router while requests are being routed, as the routes are guarded by a
+// read-write mutex. The exported fields must not be modified directly once the router is in use.
 type Router struct {
 	// Collection of all routes defined in the router.
 	Routes []Route
@@ -31,24 +69,307 @@ type Router struct {
 	LastSequenceNumber int
 	// Contains the prefix tree representation of all the routes
 	RouteTree *routeTreeNode
+	// Collection of middlewares to be executed for all the routes defined in the router.
+	Middlewares []Middleware
+	// Boolean value to indicate if requests for a dynamic route whose trailing '/' does not match the defined route path must be redirected to the defined route path.
+	RedirectTrailingSlash bool
+	// Read-write mutex to synchronize access to the routes, the route tree and the middlewares, which are read for every request and can be modified while requests are being routed.
+	mutex sync.RWMutex
+	// Collection of the route paths which have been disabled, in the form used to compare route paths. Requests for a disabled route path are not routed to its routes.
+	disabledRoutes map[string]bool
+	// Sequence numbers of the routes defined by the last route registration (like the GET and the HEAD routes of a static route), which are modified by Name() and MaxBodySize().
+	// It is empty if the last route registration has failed, so that these settings are never applied to a route defined earlier.
+	lastRoutes []int
+}
+
+// Adds the given middlewares to the collection of middlewares executed for all the routes in the router.
+func (rtr *Router) use(middlewares ...Middleware) {
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	rtr.Middlewares = append(rtr.Middlewares, middlewares...)
+}
+
+// Returns the collection of middlewares executed for all the routes in the router.
+func (rtr *Router) getMiddlewares() []Middleware {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
+	return rtr.Middlewares
+}
+
+// Enables or disables the redirection of requests whose trailing '/' does not match the route path defined.
+func (rtr *Router) setRedirectTrailingSlash(enabled bool) {
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	rtr.RedirectTrailingSlash = enabled
+}
+
+// Adds the given middlewares to the collection of middlewares executed for all the routes in the router. When the router is mounted in a web server instance, these are executed after the middlewares of the server.
+func (rtr *Router) Use(middlewares ...Middleware) {
+	rtr.use(middlewares...)
+}
+
+// Creates a new GET endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Get(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("GET", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Creates a new HEAD endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Head(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("HEAD", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Creates a new POST endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Post(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("POST", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Creates a new PUT endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Put(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("PUT", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Creates a new PATCH endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Patch(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("PATCH", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Creates a new DELETE endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Delete(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("DELETE", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Creates a new OPTIONS endpoint at the given route path in the router and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (rtr *Router) Options(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	return rtr.addDynamicRoute("OPTIONS", strings.TrimSpace(routePath), handlerFunc, middlewares...)
+}
+
+// Assigns the given name to the route defined last in the router, so that the URL of the route can be built using URLFor() once the router has been mounted.
+func (rtr *Router) Name(RouteName string) error {
+	return rtr.nameLastRoute(RouteName)
+}
+
+// Sets the maximum size (in bytes) of the request body accepted by the route defined last in the router, in place of the "max_body_size" server default. The limit is retained once the router has been mounted.
+func (rtr *Router) MaxBodySize(Size int64) error {
+	return rtr.setLastRouteBodySize(Size)
+}
+
+// Removes the route defined for the given HTTP method and route path (like "/users/:id") from the router, so that the requests for the route path are no longer handled by the route.
+// Routes can be removed while requests are being routed. An error is returned if no route has been defined for the method with the given route path.
+func (rtr *Router) Remove(Method string, RoutePath string) error {
+	return rtr.removeRoute(Method, RoutePath)
 }
 
-// Validates if a given route path is syntactically correct.
+// Attaches all the routes defined in the given router to the current router, with the given prefix added to their route paths. The handlers of the attached routes execute the middlewares of the given router,
+// followed by the middlewares of the route. The names of the routes are retained. Routes defined in the given router after it has been mounted are not attached.
+func (rtr *Router) mount(Prefix string, subRouter *Router) error {
+	if subRouter == nil || subRouter == rtr {
+		reError := new(RoutingError)
+		reError.RoutePath = Prefix
+		reError.Message = "mount: Router to be mounted must not be nil or the router it is mounted in"
+		return reError
+	}
+
+	subRouter.mutex.RLock()
+	subRoutes := slices.Clone(subRouter.Routes)
+	subRouter.mutex.RUnlock()
+	for _, route := range subRoutes {
+		completeRoutePath := joinRoute(Prefix, route.RoutePath)
+		if route.TrailingSlash && completeRoutePath != "/" {
+			completeRoutePath += "/"
+		}
+
+		var mountedRoute Route
+		var err error
+		if route.IsStatic {
+			mountedRoute, err = rtr.newStaticRoute(route.Method, completeRoutePath, route.StaticFolderPath, route.StaticFS, route.StaticOptions)
+		} else {
+			routeHandler := chainMiddlewares(route.RouteHandler, route.Middlewares)
+			mountedRoute, err = rtr.newDynamicRoute(route.Method, completeRoutePath, func(request *HttpRequest, response *HttpResponse) error {
+				// The middlewares of the mounted router are read when the request is handled, so that middlewares added to it after it has been mounted are executed as well.
+				return chainMiddlewares(routeHandler, subRouter.getMiddlewares())(request, response)
+			})
+		}
+
+		if err != nil {
+			return rtr.failRegistration(err)
+		}
+
+		mountedRoute.HandlerName = route.HandlerName
+		mountedRoute.Name = route.Name
+		mountedRoute.MaxBodySize = route.MaxBodySize
+		mountedRoute.Doc = route.Doc
+		err = rtr.insertRoute(mountedRoute)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the collection of HTTP methods for which a route is defined that matches the given request path. An empty collection is returned if no route matches the request path.
+func (rtr *Router) getRouteMethods(RequestPath string) []string {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
+	methods := make([]string, 0)
+	routeInfo := matchRouteInTree(rtr.RouteTree, RequestPath)
+	if routeInfo.RoutePath == "" || rtr.disabledRoutes[lowerRoute(routeInfo.RoutePath)] {
+		return methods
+	}
+
+	for _, route := range rtr.Routes {
+		if isSameRoute(routeInfo.RoutePath, route.RoutePath) && !slices.Contains(methods, route.Method) {
+			methods = append(methods, route.Method)
+		}
+	}
+
+	if slices.Contains(methods, "GET") && !slices.Contains(methods, "HEAD") {
+		methods = append(methods, "HEAD")
+	}
+
+	return methods
+}
+
+// Checks if the given route paths are the same, ignoring the case of all but the constraints of the path parameters.
+func isSameRoute(RoutePath string, OtherRoutePath string) bool {
+	return lowerRoute(RoutePath) == lowerRoute(OtherRoutePath)
+}
+
+// Returns the name of the given handler function, as reported by the Go runtime. An empty string is returned for a nil handler.
+func getHandlerName(handlerFunc Handler) string {
+	if handlerFunc == nil {
+		return ""
+	}
+
+	handlerDetails := runtime.FuncForPC(reflect.ValueOf(handlerFunc).Pointer())
+	if handlerDetails == nil {
+		return ""
+	}
+	return handlerDetails.Name()
+}
+
+// Returns the details of all the routes defined in the router, in the order in which they were defined.
+func (rtr *Router) getRouteInfo() []RouteInfo {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
+	routes := make([]RouteInfo, 0, len(rtr.Routes))
+	for _, route := range rtr.Routes {
+		pattern := route.RoutePath
+		if route.TrailingSlash {
+			pattern += "/"
+		}
+		routes = append(routes, RouteInfo{ Method: route.Method, Pattern: pattern, IsStatic: route.IsStatic, HandlerName: route.HandlerName, Name: route.Name })
+	}
+
+	return routes
+}
+
+// Checks if the given route path ends with a trailing '/'. The root route path "/" is not considered to have a trailing '/'.
+func hasTrailingSlash(RoutePath string) bool {
+	RoutePath = strings.TrimSpace(RoutePath)
+	return len(RoutePath) > 1 && strings.HasSuffix(RoutePath, "/")
+}
+
+// Creates and returns a handler which redirects the request to the variant of the request path with (or without) the trailing '/', depending on the given value.
+// GET and HEAD requests are redirected with a 301 (Moved Permanently) response, while all other requests are redirected with a 308 (Permanent Redirect) response so that the method and the body are retained.
+func newTrailingSlashRedirect(TrailingSlash bool) Handler {
+	return func(request *HttpRequest, response *HttpResponse) error {
+		location := strings.TrimRight(escapePath(request.ResourcePath), "/")
+		if TrailingSlash {
+			location += "/"
+		}
+
+		if request.RawQuery != "" {
+			location += "?" + request.RawQuery
+		}
+
+		status := StatusPermanentRedirect
+		if strings.EqualFold(request.Method, "GET") || strings.EqualFold(request.Method, "HEAD") {
+			status = StatusMovedPermanently
+		}
+
+		return response.Redirect(status, location)
+	}
+}
+
+// Validates if a given route path is syntactically correct. The constraints of the path parameters (as in ':id(regex)' or ':id|type') must be valid regular expressions or supported types.
+// Route parts can contain dots (like "/openapi.json"), but cannot be "." or "..", as request paths are normalized before being routed. Names of the path parameters cannot contain dots.
 func (rtr *Router) validateRoute(routePath string) bool {
-	isRouteValid, err := regexp.MatchString("^/[a-zA-z][a-zA-Z0-9_/:-]*[a-zA-Z0-9]$", routePath)
+	RouteParts := strings.Split(routePath, "/")
+	for index, routePart := range RouteParts {
+		if routePart == "." || routePart == ".." {
+			return false
+		} else if strings.HasPrefix(routePart, ":") {
+			paramName, _, err := parseRouteParam(routePart)
+			if err != nil || strings.Contains(paramName, ".") {
+				return false
+			}
+			RouteParts[index] = ":" + paramName
+		}
+	}
+
+	routePath = strings.Join(RouteParts, "/")
+
+	isRouteValid, err := regexp.MatchString("^(/[a-zA-z][a-zA-Z0-9_/:.-]*[a-zA-Z0-9])?(/\\*[a-zA-Z0-9_]+)?$", routePath)
 	if err != nil {
 		return false
 	}
 
-	if !isRouteValid {
+	if !isRouteValid || routePath == "" {
 		return false
 	}
 	
 	return true
 }
 
+// Checks if a route with the given route path can be added to the router for the given HTTP method. An error is returned if a route with the same route path has already been defined for the method,
+// or if the route path is ambiguous with the route path of an existing route (for any method), as it has a path parameter or wildcard in the same position as a different path parameter or wildcard.
+func (rtr *Router) checkRouteConflict(Method string, RoutePath string) error {
+	for _, route := range rtr.Routes {
+		if isSameRoute(RoutePath, route.RoutePath) && route.Method == Method {
+			reError := new(RoutingError)
+			reError.RoutePath = RoutePath
+			reError.Message = fmt.Sprintf("checkRouteConflict: A route with the same route path has already been defined for the %s method", Method)
+			return reError
+		}
+	}
+
+	if conflictingPart := rtr.RouteTree.findConflict(normalizeRoute(RoutePath)); conflictingPart != "" {
+		reError := new(RoutingError)
+		reError.RoutePath = RoutePath
+		reError.Message = fmt.Sprintf("checkRouteConflict: Route is ambiguous with an existing route containing [%s] in the same position", conflictingPart)
+		return reError
+	}
+
+	return nil
+}
+
 // Adds a new static route and target folder to the static routes collection.
-func (rtr *Router) addStaticRoute(Method string, RoutePath string, TargetPath string) error {
+func (rtr *Router) addStaticRoute(Method string, RoutePath string, TargetPath string, options *StaticOptions) error {
+	routeObj, err := rtr.newStaticRoute(Method, RoutePath, TargetPath, nil, options)
+	if err != nil {
+		return rtr.failRegistration(err)
+	}
+
+	return rtr.insertRoute(routeObj)
+}
+
+// Adds the static routes serving the files in the given target folder, or in the given file system if it is not nil, for the GET and the HEAD methods as a single route registration.
+func (rtr *Router) addStaticRoutes(RoutePath string, TargetPath string, FileSystem iofs.FS, options *StaticOptions) error {
+	routeObjs := make([]Route, 0, 2)
+	for _, method := range []string{ "GET", "HEAD" } {
+		routeObj, err := rtr.newStaticRoute(method, RoutePath, TargetPath, FileSystem, options)
+		if err != nil {
+			return rtr.failRegistration(err)
+		}
+		routeObjs = append(routeObjs, routeObj)
+	}
+
+	return rtr.insertRoute(routeObjs...)
+}
+
+// Creates a new static route serving the files in the given target folder, or in the given file system if it is not nil, after validating the route path and the target folder. The route is not added to the router.
+func (rtr *Router) newStaticRoute(Method string, RoutePath string, TargetPath string, FileSystem iofs.FS, options *StaticOptions) (Route, error) {
 	RoutePath = cleanRoute(RoutePath)
 	TargetPath = strings.TrimSpace(TargetPath)
 	Method = strings.TrimSpace(Method)
@@ -58,42 +379,72 @@ func (rtr *Router) addStaticRoute(Method string, RoutePath string, TargetPath st
 		reError := new(RoutingError)
 		reError.RoutePath = RoutePath
 		reError.Message = "addStaticRoute: Route contains one or more invalid characters"
-		return reError
+		return Route{}, reError
+	}
+	if FileSystem != nil {
+		if PathType, err := fs.GetPathTypeFS(FileSystem, "."); err != nil || PathType != fs.FOLDER_TYPE_PATH {
+			reError := new(RoutingError)
+			reError.RoutePath = RoutePath
+			reError.Message = "addStaticRoute: Root folder of the given file system cannot be read"
+			return Route{}, reError
+		}
+
+		routeObj := Route{
+			IsStatic: true,
+			StaticFS: fs.FromFS(FileSystem),
+			RouteHandler: StaticFileHandler,
+			Method: Method,
+			RoutePath: RoutePath,
+			StaticOptions: options,
+			HandlerName: "StaticFileHandler",
+		}
+
+		return routeObj, nil
 	}
 	isAbsolutePath := filepath.IsAbs(TargetPath)
 	if !isAbsolutePath {
 		reError := new(RoutingError)
 		reError.RoutePath = TargetPath
 		reError.Message = "addStaticRoute: Given target folder path is not an absolute path"
-		return reError
+		return Route{}, reError
 	}
 	PathType, err := fs.GetPathType(TargetPath)
 	if err != nil {
-		return err
+		return Route{}, err
 	}
 	if PathType == fs.FILE_TYPE_PATH {
 		reError := new(RoutingError)
 		reError.RoutePath = TargetPath
 		reError.Message = "Target path given should point to a directory not a file"
-		return reError
+		return Route{}, reError
 	}
-	rtr.LastSequenceNumber++
 	routeObj := Route{
 		IsStatic: true,
 		StaticFolderPath: TargetPath,
 		RouteHandler: StaticFileHandler,
-		SequenceNumber: rtr.LastSequenceNumber,
 		Method: Method,
 		RoutePath: RoutePath,
+		StaticOptions: options,
+		// The static file handler is a function literal, whose name reported by the Go runtime is not meaningful.
+		HandlerName: "StaticFileHandler",
 	}
-	
-	rtr.Routes = append(rtr.Routes, routeObj)
-	addRouteToTree(rtr.RouteTree, RoutePath)
-	return nil
+
+	return routeObj, nil
 }
 
 // Adds a new dynamic route and its associated handler function to the collection of routes defined in the router instance.
-func (rtr *Router) addDynamicRoute(Method string, RoutePath string, handlerFunc Handler) error {
+func (rtr *Router) addDynamicRoute(Method string, RoutePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	routeObj, err := rtr.newDynamicRoute(Method, RoutePath, handlerFunc, middlewares...)
+	if err != nil {
+		return rtr.failRegistration(err)
+	}
+
+	return rtr.insertRoute(routeObj)
+}
+
+// Creates a new dynamic route with the given handler function, after validating the route path. The route is not added to the router.
+func (rtr *Router) newDynamicRoute(Method string, RoutePath string, handlerFunc Handler, middlewares ...Middleware) (Route, error) {
+	TrailingSlash := hasTrailingSlash(RoutePath)
 	RoutePath = cleanRoute(RoutePath)
 	Method = strings.TrimSpace(Method)
 	Method = strings.ToUpper(Method)
@@ -103,26 +454,136 @@ func (rtr *Router) addDynamicRoute(Method string, RoutePath string, handlerFunc
 		reError := new(RoutingError)
 		reError.RoutePath = RoutePath
 		reError.Message = "addDynamicRoute: Route contains one or more invalid characters"
-		return reError
+		return Route{}, reError
 	}
 
-	rtr.LastSequenceNumber++
 	routeObj := Route{
 		IsStatic: false,
 		StaticFolderPath: "",
 		RouteHandler: handlerFunc,
-		SequenceNumber: rtr.LastSequenceNumber,
 		Method: Method,
 		RoutePath: RoutePath,
+		Middlewares: middlewares,
+		TrailingSlash: TrailingSlash,
+		HandlerName: getHandlerName(handlerFunc),
+	}
+
+	return routeObj, nil
+}
+
+// Adds the given routes to the collection of routes and to the route tree as a single route registration, once each of them has been checked for conflicts with the existing routes
+// (and for the uniqueness of its name, if it has been named). None of the routes are added if one of them cannot be added. The sequence numbers of the routes are assigned while the routes are locked,
+// so that routes added concurrently are checked and numbered one at a time.
+func (rtr *Router) insertRoute(routeObjs ...Route) error {
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	rtr.lastRoutes = nil
+	for _, routeObj := range routeObjs {
+		err := rtr.checkRouteConflict(routeObj.Method, routeObj.RoutePath)
+		if err != nil {
+			return err
+		}
+
+		if routeObj.Name != "" {
+			err = rtr.checkRouteName(routeObj.Name, routeObj.RoutePath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	lastRoutes := make([]int, 0, len(routeObjs))
+	for _, routeObj := range routeObjs {
+		rtr.LastSequenceNumber++
+		routeObj.SequenceNumber = rtr.LastSequenceNumber
+		rtr.Routes = append(rtr.Routes, routeObj)
+		addRouteToTree(rtr.RouteTree, routeObj.RoutePath)
+		lastRoutes = append(lastRoutes, routeObj.SequenceNumber)
+	}
+
+	rtr.lastRoutes = lastRoutes
+	return nil
+}
+
+// Records that the last route registration has failed with the given error, so that Name() and MaxBodySize() are not applied to a route defined earlier, and returns the error.
+func (rtr *Router) failRegistration(err error) error {
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	rtr.lastRoutes = nil
+	return err
+}
+
+// Removes the route defined for the given HTTP method and route path from the collection of routes. The route tree does not record the methods of the routes and hence a new route tree is built
+// from the remaining routes, so that the route path is no longer matched unless a route is still defined for it for another method.
+func (rtr *Router) removeRoute(Method string, RoutePath string) error {
+	Method = strings.ToUpper(strings.TrimSpace(Method))
+	RoutePath = cleanRoute(RoutePath)
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	routeIndex := slices.IndexFunc(rtr.Routes, func(route Route) bool {
+		return route.Method == Method && isSameRoute(route.RoutePath, RoutePath)
+	})
+
+	if routeIndex == -1 {
+		reError := new(RoutingError)
+		reError.RoutePath = RoutePath
+		reError.Message = fmt.Sprintf("removeRoute: A route with the given route path has not been defined for the %s method", Method)
+		return reError
+	}
+
+	rtr.Routes = slices.Delete(rtr.Routes, routeIndex, routeIndex + 1)
+	routeTree := createTree()
+	isPathDefined := false
+	for _, route := range rtr.Routes {
+		addRouteToTree(routeTree, route.RoutePath)
+		isPathDefined = isPathDefined || isSameRoute(route.RoutePath, RoutePath)
+	}
+
+	rtr.RouteTree = routeTree
+	if !isPathDefined {
+		// A route defined later for the same route path must not be disabled.
+		delete(rtr.disabledRoutes, lowerRoute(RoutePath))
+	}
+	return nil
+}
+
+// Disables (or enables) the routes defined for the given route path, for all the methods. An error is returned if no route has been defined with the given route path.
+func (rtr *Router) setRouteEnabled(RoutePath string, enabled bool) error {
+	RoutePath = cleanRoute(RoutePath)
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	isPathDefined := slices.ContainsFunc(rtr.Routes, func(route Route) bool {
+		return isSameRoute(route.RoutePath, RoutePath)
+	})
+
+	if !isPathDefined {
+		reError := new(RoutingError)
+		reError.RoutePath = RoutePath
+		reError.Message = "setRouteEnabled: A route with the given route path has not been defined"
+		return reError
+	}
+
+	if enabled {
+		delete(rtr.disabledRoutes, lowerRoute(RoutePath))
+	} else {
+		rtr.disabledRoutes[lowerRoute(RoutePath)] = true
 	}
-	
-	rtr.Routes = append(rtr.Routes, routeObj)
-	addRouteToTree(rtr.RouteTree, RoutePath)
 	return nil
 }
 
+// Checks if the route path matching the given request path has been disabled.
+func (rtr *Router) isRouteDisabled(RequestPath string) bool {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
+	routeInfo := matchRouteInTree(rtr.RouteTree, RequestPath)
+	return routeInfo.RoutePath != "" && rtr.disabledRoutes[lowerRoute(routeInfo.RoutePath)]
+}
+
 // Function that matches a given route with the route tree and fetches the matched route, uses this route to get the corresponding handler (static or dynamic).
+// The handler returned is wrapped with the middlewares defined for the router, followed by the middlewares defined for the matched route.
 func (rtr *Router) matchRoute(request *HttpRequest) (Handler, error) {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
 	routePath := request.ResourcePath
 	routeInfo := matchRouteInTree(rtr.RouteTree, routePath)
 	if routeInfo.RoutePath == "" {
@@ -130,6 +591,11 @@ func (rtr *Router) matchRoute(request *HttpRequest) (Handler, error) {
 		reError.RoutePath = routePath
 		reError.Message = "matchRoute: A match was not found in the router route tree"
 		return nil, reError
+	} else if rtr.disabledRoutes[lowerRoute(routeInfo.RoutePath)] {
+		reError := new(RoutingError)
+		reError.RoutePath = routePath
+		reError.Message = "matchRoute: The matched route has been disabled"
+		return nil, reError
 	}
 
 	if routeInfo.Segments.Length() > 0 {
@@ -138,16 +604,193 @@ func (rtr *Router) matchRoute(request *HttpRequest) (Handler, error) {
 		}
 	}
 
-	var handler Handler
+	handler := rtr.getRouteHandler(request, routeInfo.RoutePath, request.Method)
+	// HEAD requests are answered using the GET route when a HEAD route has not been defined explicitly. The response body is suppressed when the response is written.
+	if handler == nil && strings.EqualFold(request.Method, "HEAD") {
+		handler = rtr.getRouteHandler(request, routeInfo.RoutePath, "GET")
+	}
+
+	if handler == nil {
+		reError := new(RoutingError)
+		reError.RoutePath = routePath
+		reError.Message = "matchRoute: A handler was not found for the matched route"
+		return nil, reError
+	}
+
+	return chainMiddlewares(handler, rtr.Middlewares), nil
+}
+
+// Returns the handler of the route defined for the given matched route path and HTTP method, wrapped with the middlewares defined for the route. If the route is a static route, the path of the requested file
+// and the route are stored in the request. It returns nil if no route has been defined for the route path and the method. The caller must hold the lock of the router.
+func (rtr *Router) getRouteHandler(request *HttpRequest, RoutePath string, Method string) Handler {
 	for _, route := range rtr.Routes {
-		if strings.EqualFold(routeInfo.RoutePath, route.RoutePath) {
-			handler = route.RouteHandler
+		if isSameRoute(RoutePath, route.RoutePath) && strings.EqualFold(Method, route.Method) {
+			handler := chainMiddlewares(route.RouteHandler, route.Middlewares)
+			if rtr.RedirectTrailingSlash && !route.IsStatic && hasTrailingSlash(request.ResourcePath) != route.TrailingSlash {
+				handler = newTrailingSlashRedirect(route.TrailingSlash)
+			}
 			if route.IsStatic {
-				request.staticFilePath = strings.Replace(request.ResourcePath, routeInfo.RoutePath, route.StaticFolderPath, 1)
+				// The file path is left empty for request paths which cannot be mapped to the target folder, which results in a 404 (Not Found) response.
+				request.staticFilePath, _ = route.getStaticFilePath(request.ResourcePath, RoutePath)
+				request.staticRoute = &route
 			}
+			return handler
+		}
+	}
+
+	return nil
+}
+
+// Assigns the given name to the routes defined by the last route registration in the router. An error is returned if no route has been defined yet, if the last route registration has failed
+// or if the name has already been given to another route.
+func (rtr *Router) nameLastRoute(RouteName string) error {
+	RouteName = strings.TrimSpace(RouteName)
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	lastRoutes := rtr.getLastRoutes()
+	if len(lastRoutes) == 0 || RouteName == "" {
+		reError := new(RoutingError)
+		reError.RoutePath = ""
+		reError.Message = "nameLastRoute: A route must have been defined successfully before it can be named with a non-empty name"
+		return reError
+	}
+
+	err := rtr.checkRouteName(RouteName, lastRoutes[0].RoutePath)
+	if err != nil {
+		return err
+	}
+
+	for _, lastRoute := range lastRoutes {
+		lastRoute.Name = RouteName
+	}
+	return nil
+}
+
+// Sets the maximum size of the request body accepted by the routes defined by the last route registration in the router. An error is returned if no route has been defined yet,
+// if the last route registration has failed or if the given size is not positive.
+func (rtr *Router) setLastRouteBodySize(Size int64) error {
+	rtr.mutex.Lock()
+	defer rtr.mutex.Unlock()
+	lastRoutes := rtr.getLastRoutes()
+	if len(lastRoutes) == 0 || Size <= 0 {
+		reError := new(RoutingError)
+		reError.RoutePath = ""
+		reError.Message = "setLastRouteBodySize: A route must have been defined successfully before a positive maximum body size can be set for it"
+		return reError
+	}
+
+	for _, lastRoute := range lastRoutes {
+		lastRoute.MaxBodySize = Size
+	}
+	return nil
+}
+
+// Returns pointers to the routes defined by the last route registration, which are still present in the router. The caller must hold the lock of the router.
+func (rtr *Router) getLastRoutes() []*Route {
+	lastRoutes := make([]*Route, 0, len(rtr.lastRoutes))
+	for index := range rtr.Routes {
+		if slices.Contains(rtr.lastRoutes, rtr.Routes[index].SequenceNumber) {
+			lastRoutes = append(lastRoutes, &rtr.Routes[index])
+		}
+	}
+
+	return lastRoutes
+}
+
+// Returns the maximum size of the request body accepted by the route matching the given HTTP method and request path. It is zero if no route matches or if the matched route uses the server default.
+// This is looked up once the request head has been read, so that the request body is limited while it is being read.
+func (rtr *Router) getMaxBodySize(Method string, RequestPath string) int64 {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
+	routeInfo := matchRouteInTree(rtr.RouteTree, RequestPath)
+	if routeInfo.RoutePath == "" {
+		return 0
+	}
+
+	for _, route := range rtr.Routes {
+		if isSameRoute(routeInfo.RoutePath, route.RoutePath) && strings.EqualFold(Method, route.Method) {
+			return route.MaxBodySize
+		}
+	}
+
+	return 0
+}
+
+// Checks if the given name can be given to a route with the given route path. An error is returned if the name has already been given to a route with another route path.
+func (rtr *Router) checkRouteName(RouteName string, RoutePath string) error {
+	for _, route := range rtr.Routes {
+		if route.Name == RouteName && !isSameRoute(route.RoutePath, RoutePath) {
+			reError := new(RoutingError)
+			reError.RoutePath = RoutePath
+			reError.Message = fmt.Sprintf("nameLastRoute: Route name [%s] has already been given to the route [%s]", RouteName, route.RoutePath)
+			return reError
+		}
+	}
+
+	return nil
+}
+
+// Builds the URL path of the route with the given name by replacing the path parameters and the wildcard in the route path with the values given for them. The values are escaped as required in a URL path,
+// except for the '/'s in the value of the wildcard. An error is returned if no route has the given name, if a value is missing for a path parameter or if a value does not match the constraint of its path parameter.
+func (rtr *Router) buildURL(RouteName string, params map[string]string) (string, error) {
+	rtr.mutex.RLock()
+	defer rtr.mutex.RUnlock()
+	var namedRoute *Route
+	for index := range rtr.Routes {
+		if rtr.Routes[index].Name == strings.TrimSpace(RouteName) {
+			namedRoute = &rtr.Routes[index]
 			break
 		}
 	}
 
-	return handler, nil
-}
\ No newline at end of file
+	if namedRoute == nil {
+		reError := new(RoutingError)
+		reError.RoutePath = RouteName
+		reError.Message = "buildURL: No route has been defined with the given name"
+		return "", reError
+	}
+
+	urlParts := make([]string, 0)
+	for _, routePart := range splitRoute(namedRoute.RoutePath) {
+		if !strings.HasPrefix(routePart, ":") && !strings.HasPrefix(routePart, "*") {
+			urlParts = append(urlParts, routePart)
+			continue
+		}
+
+		paramName, constraint, _ := parseRouteParam(strings.TrimPrefix(routePart, "*"))
+		paramValue, found := "", false
+		for name, value := range params {
+			if strings.EqualFold(name, paramName) {
+				paramValue, found = value, true
+				break
+			}
+		}
+
+		if !found || paramValue == "" {
+			reError := new(RoutingError)
+			reError.RoutePath = namedRoute.RoutePath
+			reError.Message = fmt.Sprintf("buildURL: A value has not been given for the path parameter [%s]", paramName)
+			return "", reError
+		} else if constraint != nil && !constraint.MatchString(paramValue) {
+			reError := new(RoutingError)
+			reError.RoutePath = namedRoute.RoutePath
+			reError.Message = fmt.Sprintf("buildURL: Value [%s] does not match the constraint of the path parameter [%s]", paramValue, paramName)
+			return "", reError
+		}
+
+		if strings.HasPrefix(routePart, "*") {
+			for _, wildcardPart := range strings.Split(strings.Trim(paramValue, "/"), "/") {
+				urlParts = append(urlParts, url.PathEscape(wildcardPart))
+			}
+		} else {
+			urlParts = append(urlParts, url.PathEscape(paramValue))
+		}
+	}
+
+	urlPath := "/" + strings.Join(urlParts, "/")
+	if namedRoute.TrailingSlash && urlPath != "/" {
+		urlPath += "/"
+	}
+
+	return urlPath, nil
+}
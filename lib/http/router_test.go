@@ -1,6 +1,13 @@
 package http
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -15,6 +22,16 @@ func Test_Router_ValidateRoute(t *testing.T) {
 		{ "Valid route containing alphabets and numbers", "/abc/xyz/123", true },
 		{ "Valid route containing hyphen and underscore", "/abc/xyz_123", true },
 		{ "Valid route containing path parameters", "/abc/:name", true },
+		{ "Valid route containing a wildcard segment", "/abc/:name/*path", true },
+		{ "Valid route containing only a wildcard segment", "/*path", true },
+		{ "Invalid route containing a wildcard segment in the middle", "/abc/*path/xyz", false },
+		{ "Valid route containing a path parameter with a regular expression", "/abc/:id([0-9]+)", true },
+		{ "Valid route containing a typed path parameter", "/abc/:id|int/xyz", true },
+		{ "Invalid route containing an unsupported path parameter type", "/abc/:id|decimal", false },
+		{ "Invalid route containing an invalid regular expression", "/abc/:id([0-9+)", false },
+		{ "Valid route containing a dot", "/api/openapi.json", true },
+		{ "Invalid route containing a dot segment", "/abc/../xyz", false },
+		{ "Invalid route containing a path parameter name with a dot", "/abc/:name.json", false },
 		{ "Invalid route containing multiple slashes as prefix", "//pqr/abc/123", false },
 		{ "Invalid route containing multiple slashes as prefix", "/pqr/abc/123/", false },
 	}
@@ -56,7 +73,7 @@ func Test_Router_AddStaticRoute(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(tt *testing.T) {
-			err := testRouter.addStaticRoute(testCase.InputMethod, testCase.InputRoute, testCase.TargetFilePath)
+			err := testRouter.addStaticRoute(testCase.InputMethod, testCase.InputRoute, testCase.TargetFilePath, nil)
 			if testCase.ExpectedErr == "" {
 				if err != nil {
 					tt.Errorf("Was not expecting an error for adding static route to router and yet got this instead - %v", err)
@@ -74,4 +91,669 @@ func Test_Router_AddStaticRoute(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Test case to validate if the router and route middlewares are chained in the correct order when a route is matched.
+func Test_Router_Middlewares(t *testing.T) {
+	testRouter := newRouter()
+	executionOrder := make([]string, 0)
+	newTestMiddleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *HttpRequest, res *HttpResponse) error {
+				executionOrder = append(executionOrder, name)
+				return next(req, res)
+			}
+		}
+	}
+
+	testRouter.use(newTestMiddleware("global-one"), newTestMiddleware("global-two"))
+	err := testRouter.addDynamicRoute("GET", "/users/list", func(req *HttpRequest, res *HttpResponse) error {
+		executionOrder = append(executionOrder, "handler")
+		return nil
+	}, newTestMiddleware("route"))
+	if err != nil {
+		t.Errorf("Was not expecting an error while adding the route, but got this instead - %v", err)
+		return
+	}
+
+	testRequest := newTestRequest(t)
+	testRequest.Method = "GET"
+	testRequest.ResourcePath = "/users/list"
+	handler, err := testRouter.matchRoute(testRequest)
+	if err != nil {
+		t.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+		return
+	}
+
+	handler(testRequest, newTestResponse(t, "1.1"))
+	expectedOrder := "global-one,global-two,route,handler"
+	if strings.Join(executionOrder, ",") != expectedOrder {
+		t.Errorf("The execution order [%s] does not match the expected order [%s]", strings.Join(executionOrder, ","), expectedOrder)
+	} else {
+		t.Logf("The execution order [%s] matches the expected order [%s]", strings.Join(executionOrder, ","), expectedOrder)
+	}
+}
+
+// Test case to validate if the handler matched for a request route corresponds to the HTTP method of the request.
+func Test_Router_MatchRouteMethod(t *testing.T) {
+	testRouter := newRouter()
+	for _, method := range []string{ "GET", "PUT", "PATCH", "DELETE" } {
+		routeMethod := method
+		testRouter.addDynamicRoute(routeMethod, "/users/:id", func(req *HttpRequest, res *HttpResponse) error {
+			res.Headers.Add("Handled-By", routeMethod)
+			return nil
+		})
+	}
+
+	testCases := []struct {
+		Name string
+		Method string
+		ExpHandledBy string
+		ExpectedErr string
+	} {
+		{ "GET request for the route", "GET", "GET", "" },
+		{ "PUT request for the route", "PUT", "PUT", "" },
+		{ "PATCH request for the route", "PATCH", "PATCH", "" },
+		{ "DELETE request for the route", "DELETE", "DELETE", "" },
+		{ "HEAD request answered by the GET route", "HEAD", "GET", "" },
+		{ "POST request for the route", "POST", "", "RoutingError" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = "/users/12"
+			handler, err := testRouter.matchRoute(testRequest)
+			if testCase.ExpectedErr == "RoutingError" {
+				if _, ok := err.(*RoutingError); !ok {
+					tt.Errorf("Expected a routing error while matching the route, but got this instead - %v", err)
+				} else {
+					tt.Logf("Was expecting a routing error and got a routing error as well - %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+				return
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			handler(testRequest, testResponse)
+			handledBy, _ := testResponse.Headers.Get("Handled-By")
+			if handledBy != testCase.ExpHandledBy {
+				tt.Errorf("The request was handled by the %s handler instead of the %s handler", handledBy, testCase.ExpHandledBy)
+			} else {
+				tt.Logf("The request was handled by the %s handler as expected", handledBy)
+			}
+		})
+	}
+}
+
+// Test case to validate the matching of request paths against path parameters constrained by a regular expression or a type, including routes overlapping with each other.
+func Test_Router_MatchConstrainedParams(t *testing.T) {
+	testRouter := newRouter()
+	routePaths := []string{ "/users/:name", "/users/:id|int", "/users/new", "/posts/:slug([a-z]+-[A-Z0-9]+)", "/orders/:id(\\d{4})/items" }
+	for _, routePath := range routePaths {
+		handledBy := routePath
+		err := testRouter.addDynamicRoute("GET", routePath, func(req *HttpRequest, res *HttpResponse) error {
+			res.Headers["Handled-By"] = []string{ handledBy }
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Was not expecting an error while adding the route %s, but got this instead - %v", routePath, err)
+		}
+	}
+
+	testCases := []struct {
+		Name string
+		RequestPath string
+		ExpHandledBy string
+		ExpParam string
+		ExpValue string
+	} {
+		{ "Path matching a typed path parameter", "/users/42", "/users/:id|int", "id", "42" },
+		{ "Path not matching a typed path parameter", "/users/john", "/users/:name", "name", "john" },
+		{ "Path matching a route part alongside path parameters", "/users/new", "/users/new", "", "" },
+		{ "Path matching a case sensitive regular expression", "/posts/hello-ABC1", "/posts/:slug([a-z]+-[A-Z0-9]+)", "slug", "hello-ABC1" },
+		{ "Path not matching a case sensitive regular expression", "/posts/hello-abc1", "", "", "" },
+		{ "Path matching a regular expression followed by a route part", "/orders/2024/items", "/orders/:id(\\d{4})/items", "id", "2024" },
+		{ "Path matching a regular expression partially", "/orders/20245/items", "", "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.RequestPath
+			handler, err := testRouter.matchRoute(testRequest)
+			if testCase.ExpHandledBy == "" {
+				if _, ok := err.(*RoutingError); !ok {
+					tt.Errorf("Expected a routing error while matching the route, but got this instead - %v", err)
+				} else {
+					tt.Logf("Was expecting a routing error and got a routing error as well - %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				tt.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+				return
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			handler(testRequest, testResponse)
+			handledBy, _ := testResponse.Headers.Get("Handled-By")
+			values, _ := testRequest.Segments.Get(testCase.ExpParam)
+			if handledBy != testCase.ExpHandledBy {
+				tt.Errorf("The request was handled by the %s handler instead of the %s handler", handledBy, testCase.ExpHandledBy)
+			} else if testCase.ExpParam != "" && (len(values) != 1 || values[0] != testCase.ExpValue) {
+				tt.Errorf("Expected the path parameter %s to be [%s], but got %v", testCase.ExpParam, testCase.ExpValue, values)
+			} else {
+				tt.Logf("The request was handled by the %s handler as expected", handledBy)
+			}
+		})
+	}
+}
+
+// Test case to validate the detection of duplicate and ambiguous routes when they are added to the router.
+func Test_Router_RouteConflicts(t *testing.T) {
+	testRouter := newRouter()
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		return nil
+	}
+	for _, routePath := range []string{ "/users/:id", "/users/:id|int/posts", "/users/profile", "/files/*path" } {
+		err := testRouter.addDynamicRoute("GET", routePath, handler)
+		if err != nil {
+			t.Fatalf("Was not expecting an error while adding the route %s, but got this instead - %v", routePath, err)
+		}
+	}
+
+	testCases := []struct {
+		Name string
+		Method string
+		RoutePath string
+		ExpectErr bool
+	} {
+		{ "Route defined again for the same method", "GET", "/users/:id", true },
+		{ "Route defined again with a trailing slash", "GET", "/users/profile/", true },
+		{ "Route defined again for a different method", "POST", "/users/:id", false },
+		{ "Path parameter with a different name in the same position", "GET", "/users/:name/comments", true },
+		{ "Path parameter with a different name and the same constraint", "GET", "/users/:userId|int/likes", true },
+		{ "Path parameter with a different constraint in the same position", "GET", "/users/:slug([a-z]+)/likes", false },
+		{ "Route part alongside a path parameter", "GET", "/users/settings", false },
+		{ "Wildcard with a different name in the same position", "GET", "/files/*rest", true },
+		{ "Wildcard alongside a route part", "GET", "/users/profile/*rest", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			err := testRouter.addDynamicRoute(testCase.Method, testCase.RoutePath, handler)
+			if testCase.ExpectErr {
+				if _, ok := err.(*RoutingError); !ok {
+					tt.Errorf("Expected a routing error while adding the route, but got this instead - %v", err)
+				} else {
+					tt.Logf("Was expecting a routing error and got a routing error as well - %v", err)
+				}
+			} else if err != nil {
+				tt.Errorf("Was not expecting an error while adding the route, but got this instead - %v", err)
+			} else {
+				tt.Logf("The route %s was added to the router as expected", testCase.RoutePath)
+			}
+		})
+	}
+}
+
+// Test case to validate that Name() names the routes defined by the last route registration, and fails instead of naming an earlier route when the last registration has failed.
+func Test_Router_NameLastRoute(t *testing.T) {
+	testRouter := newRouter()
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		return nil
+	}
+	if err := testRouter.nameLastRoute("none"); err == nil {
+		t.Errorf("Expected an error while naming a route before any route has been defined, but got none")
+	}
+
+	testRouter.addDynamicRoute("GET", "/users", handler)
+	testCases := []struct {
+		Name string
+		Register func() error
+		RouteName string
+		ExpectErr bool
+	} {
+		{ "Route conflicting with an existing route", func() error { return testRouter.addDynamicRoute("GET", "/users", handler) }, "users.duplicate", true },
+		{ "Route with an invalid route path", func() error { return testRouter.addDynamicRoute("GET", "/users/:id(", handler) }, "users.invalid", true },
+		{ "Static route with a missing target folder", func() error { return testRouter.addStaticRoutes("/missing", filepath.Join(t.TempDir(), "missing"), nil, nil) }, "missing", true },
+		{ "Static route for the GET and HEAD methods", func() error { return testRouter.addStaticRoutes("/assets", t.TempDir(), nil, nil) }, "assets", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			registerErr := testCase.Register()
+			err := testRouter.nameLastRoute(testCase.RouteName)
+			if testCase.ExpectErr {
+				if registerErr == nil || err == nil {
+					tt.Errorf("Expected the registration and the naming of the route to fail, but got the errors %v and %v", registerErr, err)
+				} else {
+					tt.Logf("The route could not be named once its registration failed - %v", err)
+				}
+			} else if registerErr != nil || err != nil {
+				tt.Errorf("Was not expecting an error while defining and naming the route, but got the errors %v and %v", registerErr, err)
+			}
+
+			for _, route := range testRouter.Routes {
+				if route.Name == testCase.RouteName && testCase.ExpectErr {
+					tt.Errorf("Expected no route to be named %s, but the %s route %s was named", testCase.RouteName, route.Method, route.RoutePath)
+				} else if route.RoutePath == "/users" && route.Name != "" {
+					tt.Errorf("Expected the route /users defined earlier to remain unnamed, but it was named %s", route.Name)
+				}
+			}
+		})
+	}
+
+	namedMethods := make([]string, 0)
+	for _, route := range testRouter.Routes {
+		if route.Name == "assets" {
+			namedMethods = append(namedMethods, route.Method)
+		}
+	}
+	if strings.Join(namedMethods, ",") != "GET,HEAD" {
+		t.Errorf("Expected the GET and HEAD routes of the static route to be named, but the routes named were %v", namedMethods)
+	}
+}
+
+// Test case to validate that MaxBodySize() sets the limit of the routes defined by the last route registration, and fails instead of changing the limit of an earlier route when the last registration has failed.
+func Test_Router_LastRouteBodySize(t *testing.T) {
+	testRouter := newRouter()
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		return nil
+	}
+	testRouter.addDynamicRoute("POST", "/upload", handler)
+	testRouter.setLastRouteBodySize(64)
+	testCases := []struct {
+		Name string
+		Register func() error
+		Size int64
+		ExpectErr bool
+	} {
+		{ "Route conflicting with an existing route", func() error { return testRouter.addDynamicRoute("POST", "/upload", handler) }, 1 << 20, true },
+		{ "Route with an invalid route path", func() error { return testRouter.addDynamicRoute("POST", "/files/*", handler) }, 1 << 20, true },
+		{ "Static route with a missing target folder", func() error { return testRouter.addStaticRoutes("/missing", filepath.Join(t.TempDir(), "missing"), nil, nil) }, 1 << 20, true },
+		{ "Route defined after a failed registration", func() error { return testRouter.addDynamicRoute("POST", "/avatar", handler) }, 8, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			registerErr := testCase.Register()
+			err := testRouter.setLastRouteBodySize(testCase.Size)
+			if testCase.ExpectErr {
+				if registerErr == nil || err == nil {
+					tt.Errorf("Expected the registration of the route and the setting of its body size to fail, but got the errors %v and %v", registerErr, err)
+				} else {
+					tt.Logf("The body size could not be set once the registration of the route failed - %v", err)
+				}
+			} else if registerErr != nil || err != nil {
+				tt.Errorf("Was not expecting an error while defining the route and setting its body size, but got the errors %v and %v", registerErr, err)
+			}
+
+			if size := testRouter.getMaxBodySize("POST", "/upload"); size != 64 {
+				tt.Errorf("Expected the maximum body size of the route /upload defined earlier to remain 64, but got %d", size)
+			}
+		})
+	}
+
+	if size := testRouter.getMaxBodySize("POST", "/avatar"); size != 8 {
+		t.Errorf("Expected the maximum body size of the route /avatar to be 8, but got %d", size)
+	}
+}
+
+// Test case to validate the building of URL paths for named routes, using the values given for the path parameters.
+func Test_Router_BuildURL(t *testing.T) {
+	testRouter := newRouter()
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		return nil
+	}
+	namedRoutes := map[string]string{ "user.show": "/users/:id|int", "user.posts": "/users/:id|int/posts/:slug/", "files": "/files/*path", "home": "/home" }
+	for routeName, routePath := range namedRoutes {
+		testRouter.addDynamicRoute("GET", routePath, handler)
+		err := testRouter.nameLastRoute(routeName)
+		if err != nil {
+			t.Fatalf("Was not expecting an error while naming the route %s, but got this instead - %v", routePath, err)
+		}
+	}
+
+	// The route defined last depends on the iteration order of the map, and hence a route with another name is defined last.
+	testRouter.addDynamicRoute("GET", "/about", handler)
+	if err := testRouter.nameLastRoute("user.show"); err == nil {
+		t.Errorf("Expected an error while giving the name of a route to another route, but got none")
+	}
+
+	testCases := []struct {
+		Name string
+		RouteName string
+		Params map[string]string
+		ExpURL string
+		ExpectErr bool
+	} {
+		{ "Route without path parameters", "home", nil, "/home", false },
+		{ "Route with a typed path parameter", "user.show", map[string]string{ "id": "42" }, "/users/42", false },
+		{ "Route with multiple path parameters and a trailing slash", "user.posts", map[string]string{ "id": "42", "slug": "hello world" }, "/users/42/posts/hello%20world/", false },
+		{ "Route with a wildcard", "files", map[string]string{ "path": "docs/read me.txt" }, "/files/docs/read%20me.txt", false },
+		{ "Value not matching the constraint of a path parameter", "user.show", map[string]string{ "id": "john" }, "", true },
+		{ "Value missing for a path parameter", "user.show", map[string]string{}, "", true },
+		{ "Route name that has not been defined", "user.edit", nil, "", true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			urlPath, err := testRouter.buildURL(testCase.RouteName, testCase.Params)
+			if testCase.ExpectErr {
+				if _, ok := err.(*RoutingError); !ok {
+					tt.Errorf("Expected a routing error while building the URL, but got this instead - %v", err)
+				} else {
+					tt.Logf("Was expecting a routing error and got a routing error as well - %v", err)
+				}
+			} else if err != nil {
+				tt.Errorf("Was not expecting an error while building the URL, but got this instead - %v", err)
+			} else if urlPath != testCase.ExpURL {
+				tt.Errorf("Expected the URL to be [%s], but got [%s]", testCase.ExpURL, urlPath)
+			} else {
+				tt.Logf("The URL [%s] built matches the expected URL", urlPath)
+			}
+		})
+	}
+}
+
+// Test case to validate the redirection of requests whose trailing '/' does not match the defined route path.
+func Test_Router_RedirectTrailingSlash(t *testing.T) {
+	testRouter := newRouter()
+	testRouter.RedirectTrailingSlash = true
+	noopHandler := func(req *HttpRequest, res *HttpResponse) error { return nil }
+	testRouter.addDynamicRoute("GET", "/users", noopHandler)
+	testRouter.addDynamicRoute("POST", "/users", noopHandler)
+	testRouter.addDynamicRoute("GET", "/docs/", noopHandler)
+	testRouter.addDynamicRoute("GET", "/docs/:name/:file/", noopHandler)
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		RawQuery string
+		ExpStatus int
+		ExpLocation string
+	} {
+		{ "Request path matching the defined route", "GET", "/users", "", 0, "" },
+		{ "Request path with an extra trailing slash", "GET", "/users/", "page=2", int(StatusMovedPermanently), "/users?page=2" },
+		{ "POST request path with an extra trailing slash", "POST", "/users/", "", int(StatusPermanentRedirect), "/users" },
+		{ "Request path without the trailing slash", "GET", "/docs", "", int(StatusMovedPermanently), "/docs/" },
+		{ "HEAD request path without the trailing slash", "HEAD", "/docs", "", int(StatusMovedPermanently), "/docs/" },
+		{ "Request path with characters to be encoded in the location", "GET", "/docs/my notes/a%2Fb", "", int(StatusMovedPermanently), "/docs/my%20notes/a%2Fb/" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testRequest.RawQuery = testCase.RawQuery
+			handler, err := testRouter.matchRoute(testRequest)
+			if err != nil {
+				tt.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+				return
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			handler(testRequest, testResponse)
+			location, _ := testResponse.Headers.Get("Location")
+			if testResponse.StatusCode != testCase.ExpStatus || location != testCase.ExpLocation {
+				tt.Errorf("Expected status [%d] and location [%s], but got status [%d] and location [%s]", testCase.ExpStatus, testCase.ExpLocation, testResponse.StatusCode, location)
+			} else {
+				tt.Logf("The response status [%d] and location [%s] match the expected values", testResponse.StatusCode, location)
+			}
+		})
+	}
+}
+
+// Test case to validate if a HEAD request to a static route defined only for the GET method is answered using the GET route, without redirecting the request paths of the files.
+func Test_Router_HeadStaticRoute(t *testing.T) {
+	testFolder := t.TempDir()
+	os.WriteFile(filepath.Join(testFolder, "notes.txt"), []byte("notes"), 0644)
+	testRouter := newRouter()
+	testRouter.RedirectTrailingSlash = true
+	err := testRouter.addStaticRoute("GET", "/assets", testFolder, nil)
+	if err != nil {
+		t.Fatalf("Was not expecting an error while adding the static route, but got this instead - %v", err)
+	}
+
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+	} {
+		{ "GET request for a file", "GET", "/assets/notes.txt" },
+		{ "HEAD request for a file", "HEAD", "/assets/notes.txt" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			_, err := testRouter.matchRoute(testRequest)
+			if err != nil {
+				tt.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+			} else if testRequest.staticRoute == nil || testRequest.staticFilePath != filepath.Join(testFolder, "notes.txt") {
+				tt.Errorf("Expected the request to be mapped to the file [%s] of the static route, but got the file [%s]", filepath.Join(testFolder, "notes.txt"), testRequest.staticFilePath)
+			} else {
+				tt.Logf("The request was mapped to the file [%s] of the static route as expected", testRequest.staticFilePath)
+			}
+		})
+	}
+}
+
+// Test case to validate the matching of routes for request paths containing percent-encoded characters, duplicate slashes and dot-segments, along with the decoded path parameter values.
+func Test_Router_NormalizedPath(t *testing.T) {
+	testRouter := newRouter()
+	noopHandler := func(req *HttpRequest, res *HttpResponse) error { return nil }
+	testRouter.addDynamicRoute("GET", "/files/:name", noopHandler)
+	testRouter.addDynamicRoute("GET", "/files/:name/raw", noopHandler)
+	testRouter.addDynamicRoute("GET", "/users/:id|int", noopHandler)
+	testRouter.addDynamicRoute("GET", "/assets/*path", noopHandler)
+	testCases := []struct {
+		Name string
+		RequestTarget string
+		ExpParam string
+		ExpValue string
+	} {
+		{ "Percent-encoded slash within a path parameter", "/files/a%2Fb", "name", "a/b" },
+		{ "Percent-encoded slash followed by a route part", "/files/a%2Fb/raw", "name", "a/b" },
+		{ "Duplicate slashes", "/files//x", "name", "x" },
+		{ "Percent-encoded space", "/files/my%20notes", "name", "my notes" },
+		{ "Dot-segments", "/files/docs/.././x", "name", "x" },
+		{ "Percent-encoded digits matching a constraint", "/users/%31%30", "id", "10" },
+		{ "Percent-encoded slashes within a wildcard", "/assets/css%2Fsite.css/v%201", "path", "css/site.css/v 1" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.RequestTarget
+			err := testRequest.parseQueryParams()
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while normalizing the request path and yet received one - %v", err)
+			}
+
+			_, err = testRouter.matchRoute(testRequest)
+			values, _ := testRequest.Segments.Get(testCase.ExpParam)
+			if err != nil {
+				tt.Errorf("Was not expecting an error while matching the route, but got this instead - %v", err)
+			} else if len(values) != 1 || values[0] != testCase.ExpValue {
+				tt.Errorf("Expected the path parameter [%s] to be [%s], but got %v", testCase.ExpParam, testCase.ExpValue, values)
+			} else if testRequest.RawPath != testCase.RequestTarget {
+				tt.Errorf("Expected the raw path to be [%s], but got [%s]", testCase.RequestTarget, testRequest.RawPath)
+			} else {
+				tt.Logf("The path parameter [%s] has the value [%s] as expected", testCase.ExpParam, values[0])
+			}
+		})
+	}
+}
+
+// Test case to validate the routes of a separately constructed router being attached under a prefix, along with the middlewares and names of the routes.
+func Test_Router_Mount(t *testing.T) {
+	executionOrder := make([]string, 0)
+	newTestMiddleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *HttpRequest, res *HttpResponse) error {
+				executionOrder = append(executionOrder, name)
+				return next(req, res)
+			}
+		}
+	}
+
+	adminRouter := NewRouter()
+	adminRouter.Use(newTestMiddleware("router"))
+	adminRouter.Get("/users/:id", func(req *HttpRequest, res *HttpResponse) error {
+		executionOrder = append(executionOrder, "handler")
+		res.Status(StatusOK)
+		return nil
+	}, newTestMiddleware("route"))
+	adminRouter.Name("admin.user")
+	adminRouter.Post("/settings", func(req *HttpRequest, res *HttpResponse) error {
+		executionOrder = append(executionOrder, "handler")
+		res.Status(StatusCreated)
+		return nil
+	})
+
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Use(newTestMiddleware("server"))
+	err := testServer.Mount("/admin", adminRouter)
+	if err != nil {
+		t.Fatalf("Was not expecting an error while mounting the router, but got this instead - %v", err)
+	}
+
+	if err := testServer.Mount("/admin", adminRouter); err == nil {
+		t.Errorf("Expected an error while mounting the router again under the same prefix, but got none")
+	}
+
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		ExpStatus int
+		ExpOrder string
+	} {
+		{ "Route with a path parameter attached under the prefix", "GET", "/admin/users/7", int(StatusOK), "server,router,route,handler" },
+		{ "Route without middlewares of its own", "POST", "/admin/settings", int(StatusCreated), "server,router,handler" },
+		{ "Route requested without the prefix", "GET", "/users/7", int(StatusNotFound), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			executionOrder = executionOrder[:0]
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("Expected status code to be %d, but got %d", testCase.ExpStatus, testResponse.StatusCode)
+			} else if strings.Join(executionOrder, ",") != testCase.ExpOrder {
+				tt.Errorf("The execution order [%s] does not match the expected order [%s]", strings.Join(executionOrder, ","), testCase.ExpOrder)
+			} else {
+				tt.Logf("Received status %d with the expected execution order", testResponse.StatusCode)
+			}
+		})
+	}
+
+	userURL, err := testServer.URLFor("admin.user", map[string]string{ "id": "7" })
+	if err != nil || userURL != "/admin/users/7" {
+		t.Errorf("Expected the URL of the mounted route to be [/admin/users/7], but got [%s] with error %v", userURL, err)
+	}
+}
+
+// Test case to validate the removal of routes from a router, after which the route path is matched only by the routes still defined for it.
+func Test_Router_Remove(t *testing.T) {
+	testRouter := newRouter()
+	noopHandler := func(req *HttpRequest, res *HttpResponse) error { return nil }
+	testRouter.addDynamicRoute("GET", "/users/:id", noopHandler)
+	testRouter.addDynamicRoute("POST", "/users/:id", noopHandler)
+	testRouter.addDynamicRoute("GET", "/orders/", noopHandler)
+	testRouter.addDynamicRoute("GET", "/reports/:year|int", noopHandler)
+	testCases := []struct {
+		Name string
+		Method string
+		RoutePath string
+		ExpError bool
+		RequestPath string
+		ExpMethods []string
+	} {
+		{ "Route defined for several methods", "post", "/users/:id", false, "/users/42", []string{ "GET", "HEAD" } },
+		{ "Route defined with a trailing slash", "GET", "/orders", false, "/orders/", []string{} },
+		{ "Route removed already", "POST", "/users/:id", true, "/users/42", []string{ "GET", "HEAD" } },
+		{ "Route with a different constraint", "GET", "/reports/:year|uuid", true, "/reports/2024", []string{ "GET", "HEAD" } },
+		{ "Last route for the route path", "GET", "/Users/:id", false, "/users/42", []string{} },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			err := testRouter.Remove(testCase.Method, testCase.RoutePath)
+			methods := testRouter.getRouteMethods(testCase.RequestPath)
+			if testCase.ExpError && err == nil {
+				tt.Errorf("Expected an error while removing the route, but got none")
+			} else if !testCase.ExpError && err != nil {
+				tt.Errorf("Was not expecting an error while removing the route and yet received one - %v", err)
+			} else if strings.Join(methods, ",") != strings.Join(testCase.ExpMethods, ",") {
+				tt.Errorf("Expected the methods %v to be defined for [%s], but got %v", testCase.ExpMethods, testCase.RequestPath, methods)
+			} else {
+				tt.Logf("The methods %v are defined for [%s] as expected", methods, testCase.RequestPath)
+			}
+		})
+	}
+
+	if err := testRouter.addDynamicRoute("GET", "/users/:name", noopHandler); err != nil {
+		t.Errorf("Expected a route to be defined in place of the removed route, but got this instead - %v", err)
+	}
+}
+
+// Test case to validate that routes can be added to and removed from a router while requests are being routed concurrently.
+func Test_Router_ConcurrentRoutes(t *testing.T) {
+	testRouter := newRouter()
+	noopHandler := func(req *HttpRequest, res *HttpResponse) error { return nil }
+	testRouter.addDynamicRoute("GET", "/health", noopHandler)
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		waitGroup.Add(2)
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 50; index++ {
+				routePath := fmt.Sprintf("/worker%d/route%d", worker, index)
+				testRouter.addDynamicRoute("GET", routePath, noopHandler)
+				testRouter.Use(func(next Handler) Handler { return next })
+				if index % 2 == 0 {
+					testRouter.Remove("GET", routePath)
+				}
+			}
+		}()
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 50; index++ {
+				testRequest := newTestRequest(t)
+				testRequest.Method = "GET"
+				testRequest.ResourcePath = "/health"
+				if _, err := testRouter.matchRoute(testRequest); err != nil {
+					t.Errorf("Was not expecting an error while matching the route and yet received one - %v", err)
+				}
+				testRouter.getRouteInfo()
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+	if routeCount := len(testRouter.getRouteInfo()); routeCount != 101 {
+		t.Errorf("Expected 101 routes to be defined once the routes have been added and removed, but got %d routes", routeCount)
+	} else {
+		t.Logf("Received %d routes as expected", routeCount)
+	}
+}
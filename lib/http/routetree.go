@@ -3,6 +3,8 @@ package http
 import (
 	"strings"
 	"fmt"
+	"net/url"
+	"regexp"
 )
 
 // Structure to represent each individual node of the route tree (trie tree).
@@ -11,6 +13,21 @@ type routeTreeNode struct {
 	RoutePart string
 	// A slice containing all the child nodes for the current node in the tree.
 	Children []*routeTreeNode
+	// Name of the path parameter, if the route part is a path parameter (of the form ':name'). It is empty otherwise.
+	ParamName string
+	// Regular expression which the value of the path parameter must match completely, if the route part is a constrained path parameter (of the form ':name(regex)' or ':name|type'). It is nil otherwise.
+	Constraint *regexp.Regexp
+	// Is true if a route ends with the current node in the tree.
+	IsRoute bool
+}
+
+// Collection of the types that can be used to constrain a path parameter (as in ':id|int'), with the regular expression matched by each type.
+var routeParamTypes = map[string]string {
+	"int": "-?[0-9]+",
+	"uint": "[0-9]+",
+	"alpha": "[a-zA-Z]+",
+	"alnum": "[a-zA-Z0-9]+",
+	"uuid": "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}",
 }
 
 // Represents the data returned when a HTTP request route is matched to the routes configured in the router.
@@ -26,6 +43,10 @@ func newRouteTreeNode(RoutePart string) *routeTreeNode {
 	newNode := new(routeTreeNode)
 	newNode.RoutePart = strings.TrimSpace(RoutePart)
 	newNode.Children = make([]*routeTreeNode, 0)
+	if strings.HasPrefix(newNode.RoutePart, ":") {
+		// The route parts are validated when the route is added to the router and hence the constraint is always valid here.
+		newNode.ParamName, newNode.Constraint, _ = parseRouteParam(newNode.RoutePart)
+	}
 	return newNode
 }
 
@@ -37,8 +58,69 @@ func createTree() *routeTreeNode {
 // Normalizes the given route path into a slice of route parts present in the path. 
 // This function also removes any leading or trailing space and '/' before getting the route parts.
 func normalizeRoute(RoutePath string) []string {
+	RoutePath = lowerRoute(RoutePath)
+	return splitRoute(RoutePath)
+}
+
+// Converts the given route path to lower case, except for the constraints of the path parameters, since regular expressions are case sensitive.
+func lowerRoute(RoutePath string) string {
+	RouteParts := strings.Split(RoutePath, "/")
+	for index, routePart := range RouteParts {
+		constraintIndex := strings.Index(routePart, "(")
+		if !strings.HasPrefix(strings.TrimSpace(routePart), ":") || constraintIndex == -1 {
+			RouteParts[index] = strings.ToLower(routePart)
+		} else {
+			RouteParts[index] = strings.ToLower(routePart[:constraintIndex]) + routePart[constraintIndex:]
+		}
+	}
+
+	return strings.Join(RouteParts, "/")
+}
+
+// Parses the given path parameter route part (of the form ':name', ':name(regex)' or ':name|type') and returns the name of the path parameter and the constraint on its value.
+// The constraint returned is nil if the path parameter is not constrained. An error is returned if the regular expression is invalid or the type is not supported.
+func parseRouteParam(RoutePart string) (string, *regexp.Regexp, error) {
+	RoutePart, _ = strings.CutPrefix(strings.TrimSpace(RoutePart), ":")
+	constraintIndex := strings.IndexAny(RoutePart, "(|")
+	if constraintIndex == -1 {
+		return RoutePart, nil, nil
+	}
+
+	paramName := RoutePart[:constraintIndex]
+	var expression string
+	if RoutePart[constraintIndex] == '|' {
+		var found bool
+		expression, found = routeParamTypes[strings.ToLower(RoutePart[constraintIndex + 1:])]
+		if !found {
+			reError := new(RoutingError)
+			reError.RoutePath = RoutePart
+			reError.Message = "parseRouteParam: Path parameter type is not supported"
+			return paramName, nil, reError
+		}
+	} else if strings.HasSuffix(RoutePart, ")") {
+		expression = RoutePart[constraintIndex + 1: len(RoutePart) - 1]
+	} else {
+		reError := new(RoutingError)
+		reError.RoutePath = RoutePart
+		reError.Message = "parseRouteParam: Regular expression of the path parameter is not closed with ')'"
+		return paramName, nil, reError
+	}
+
+	constraint, err := regexp.Compile("^(?:" + expression + ")$")
+	if err != nil {
+		reError := new(RoutingError)
+		reError.RoutePath = RoutePart
+		reError.Message = fmt.Sprintf("parseRouteParam: Regular expression of the path parameter is invalid :: %s", err.Error())
+		return paramName, nil, reError
+	}
+
+	return paramName, constraint, nil
+}
+
+// Splits the given route path into a slice of route parts present in the path, preserving the case of each route part. 
+// This function also removes any leading or trailing space and '/' before getting the route parts.
+func splitRoute(RoutePath string) []string {
 	RoutePath = strings.TrimSpace(RoutePath)
-	RoutePath = strings.ToLower(RoutePath)
 	RoutePath = strings.TrimRight(RoutePath, "/")
 	RoutePath = strings.TrimLeft(RoutePath, "/")
 	RouteParts := strings.Split(RoutePath, "/")
@@ -53,6 +135,17 @@ func normalizeRoute(RoutePath string) []string {
 	return NormalizedParts
 }
 
+// Returns the value of a path parameter from the given part of a normalized request path, by decoding the characters which are kept percent-encoded in the path (like "%2F").
+// The part is returned as it is if it cannot be decoded.
+func unescapePathSegment(PathSegment string) string {
+	value, err := url.PathUnescape(PathSegment)
+	if err != nil {
+		return PathSegment
+	}
+
+	return value
+}
+
 // Inserts the given route path in the route tree.
 func addRouteToTree(RouteTree *routeTreeNode, RoutePath string) {
 	RouteParts := normalizeRoute(RoutePath)
@@ -70,44 +163,30 @@ func getRoutesInTree(root *routeTreeNode) []string {
 }
 
 // Match the given route path with the route tree and fetch all the path parameters. 
+// Each part of the route path is matched against an identical route part first, followed by the constrained path parameters whose constraint matches the part (in the order in which their routes were added),
+// an unconstrained path parameter and a wildcard route part (of the form '*name'), which matches all the remaining parts of the route path and captures them as a single path parameter.
+// If the remaining parts of the route path cannot be matched after choosing a route part, the next route part in this order is tried, so that the request path is matched to a route whenever possible.
+// A route path having more parts than a route (like the path of a file served by a static route) is matched to that route, only if none of the routes match all the parts of the route path.
 // This function returns the pointer to a matchRouteInfo object which contains the original route in the router and the list of all path parameter(s).
 func matchRouteInTree(root *routeTreeNode, RoutePath string) *matchRouteInfo {
 	routeInfo := new(matchRouteInfo)
 	routeInfo.Segments = make(Params)
-	origRouteParts := normalizeRoute(RoutePath)
-	finalRouteParts := make([]string, 0)
-	for next := root; next != nil; {
-		if len(next.Children) > 0 {
-			isFound := false
-			for _, chd := range next.Children {
-				if strings.EqualFold(origRouteParts[0], chd.RoutePart) {
-					finalRouteParts = append(finalRouteParts, origRouteParts[0])
-					if len(origRouteParts) > 1 {
-						origRouteParts = origRouteParts[1:]
-						next = chd
-						isFound = true
-					}
-					break
-				} else if strings.HasPrefix(chd.RoutePart, ":") {
-					paramName, _ := strings.CutPrefix(chd.RoutePart, ":")
-					routeInfo.Segments.Add(paramName, []string { origRouteParts[0] })
-					finalRouteParts = append(finalRouteParts, chd.RoutePart)
-					if len(origRouteParts) > 1 {
-						origRouteParts = origRouteParts[1:]
-						next = chd
-						isFound = true
-					}
-					break
-				}
-			}
+	origRouteParts := splitRoute(RoutePath)
+	if len(origRouteParts) == 0 {
+		routeInfo.RoutePath = ""
+		return routeInfo
+	}
 
-			if !isFound {
-				break
-			}
-		} else {
-			break
-		}
-	}	
+	finalRouteParts, isMatched := root.match(origRouteParts, routeInfo.Segments, false)
+	if !isMatched {
+		routeInfo.Segments = make(Params)
+		finalRouteParts, isMatched = root.match(origRouteParts, routeInfo.Segments, true)
+	}
+
+	if !isMatched {
+		routeInfo.RoutePath = ""
+		return routeInfo
+	}
 
 	routePathMatch := strings.Join(finalRouteParts, "/")
 	routePathMatch = cleanRoute(routePathMatch)
@@ -115,21 +194,104 @@ func matchRouteInTree(root *routeTreeNode, RoutePath string) *matchRouteInfo {
 	return routeInfo
 }
 
+// Recursively matches the given parts of a request path against the child nodes of the route tree node, in the order described in matchRouteInTree, and returns the route parts of the matched route
+// below the node. If IsPartial is true, a route matching only the leading parts is matched as well. The path parameters of the matched route are added to the given collection.
+// The boolean value returned is false if no route matches the given parts.
+func (rtn *routeTreeNode) match(RequestParts []string, Segments Params, IsPartial bool) ([]string, bool) {
+	if len(RequestParts) == 0 {
+		return []string{}, rtn.IsRoute
+	}
+
+	candidates := make([]*routeTreeNode, 0, len(rtn.Children))
+	for _, chd := range rtn.Children {
+		if !strings.HasPrefix(chd.RoutePart, ":") && !strings.HasPrefix(chd.RoutePart, "*") && strings.EqualFold(RequestParts[0], chd.RoutePart) {
+			candidates = append(candidates, chd)
+		}
+	}
+
+	for _, chd := range rtn.Children {
+		if strings.HasPrefix(chd.RoutePart, ":") && chd.Constraint != nil && chd.Constraint.MatchString(unescapePathSegment(RequestParts[0])) {
+			candidates = append(candidates, chd)
+		}
+	}
+
+	for _, chd := range rtn.Children {
+		if strings.HasPrefix(chd.RoutePart, ":") && chd.Constraint == nil {
+			candidates = append(candidates, chd)
+		}
+	}
+
+	for _, chd := range candidates {
+		childParts, isMatched := chd.match(RequestParts[1:], Segments, IsPartial)
+		if isMatched {
+			if chd.ParamName == "" {
+				return append([]string { RequestParts[0] }, childParts...), true
+			}
+
+			Segments.Add(chd.ParamName, []string { unescapePathSegment(RequestParts[0]) })
+			return append([]string { chd.RoutePart }, childParts...), true
+		}
+	}
+
+	for _, chd := range rtn.Children {
+		if strings.HasPrefix(chd.RoutePart, "*") && chd.IsRoute {
+			paramName, _ := strings.CutPrefix(chd.RoutePart, "*")
+			Segments.Add(paramName, []string { unescapePathSegment(strings.Join(RequestParts, "/")) })
+			return []string { chd.RoutePart }, true
+		}
+	}
+
+	// The remaining parts of the request path are left unmatched, as in the case of the files served by a static route.
+	return []string{}, IsPartial && rtn.IsRoute
+}
+
+// Returns the route part present in the route tree that conflicts with the given route parts, as it is a path parameter or a wildcard in the same position which cannot be told apart from the
+// corresponding route part (like ':id' and ':name', ':id|int' and ':userId|int', or '*path' and '*rest'). An empty string is returned if there is no conflict.
+func (rtn *routeTreeNode) findConflict(RouteParts []string) string {
+	if len(RouteParts) == 0 {
+		return ""
+	}
+
+	for _, chd := range rtn.Children {
+		if RouteParts[0] == chd.RoutePart {
+			return chd.findConflict(RouteParts[1:])
+		}
+	}
+
+	for _, chd := range rtn.Children {
+		if strings.HasPrefix(RouteParts[0], "*") && strings.HasPrefix(chd.RoutePart, "*") {
+			return chd.RoutePart
+		} else if strings.HasPrefix(RouteParts[0], ":") && strings.HasPrefix(chd.RoutePart, ":") {
+			// Path parameters with different constraints are not ambiguous, even if both constraints match a request path part (like ':id|int' and ':slug([a-z0-9]+)' for "123"),
+			// as the constrained path parameters are matched in the order in which their routes were added.
+			_, constraint, _ := parseRouteParam(RouteParts[0])
+			if (constraint == nil && chd.Constraint == nil) || (constraint != nil && chd.Constraint != nil && constraint.String() == chd.Constraint.String()) {
+				return chd.RoutePart
+			}
+		}
+	}
+
+	return ""
+}
+
 // Recursively adds the route parts to the route tree by creating nodes in the tree for individual route parts.
 func (rtn *routeTreeNode) insert(RouteParts []string) {
+	if len(RouteParts) == 0 {
+		rtn.IsRoute = true
+		return
+	}
+
 	if len(rtn.Children) == 0 {
 		// If the route node does not have any child nodes of its own
 		newNode := newRouteTreeNode(RouteParts[0])
 		rtn.Children = append(rtn.Children, newNode)
-		if len(RouteParts) > 1 {
-			newNode.insert(RouteParts[1:])
-		}
+		newNode.insert(RouteParts[1:])
 	} else {
 		// If the root node has one or more child nodes
 		cnFound := false
 		var rtnNode *routeTreeNode
 		for _, cl := range rtn.Children {
-			if strings.EqualFold(RouteParts[0], cl.RoutePart) {
+			if RouteParts[0] == cl.RoutePart {
 				cnFound = true
 				rtnNode = cl
 				break
@@ -140,14 +302,10 @@ func (rtn *routeTreeNode) insert(RouteParts []string) {
 			// If none of the child nodes of the root node had the first route part of the given route.
 			rtnNode = newRouteTreeNode(RouteParts[0])
 			rtn.Children = append(rtn.Children, rtnNode)
-			if len(RouteParts) > 1 {
-				rtnNode.insert(RouteParts[1:])
-			}
+			rtnNode.insert(RouteParts[1:])
 		} else {
 			// If one of the child nodes of the root node contained the first route part of the given route.
-			if len(RouteParts) > 1 {
-				rtnNode.insert(RouteParts[1:])
-			}
+			rtnNode.insert(RouteParts[1:])
 		}
 	}
 }
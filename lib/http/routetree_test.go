@@ -95,12 +95,16 @@ func Test_RouteTree_MatchRoute(t *testing.T) {
 	addRouteToTree(root, "/users/list-all")
 	addRouteToTree(root, "/users/:userId/get_name")
 	addRouteToTree(root, "/files/static")
+	addRouteToTree(root, "/assets/logo")
+	addRouteToTree(root, "/assets/*path")
 	testCases := []struct {
 		Name string
 		RequestRoute string
 		MappedRoute string
 		PathParamCount int
 	} {
+		{ "Request route matching a static route part alongside a wildcard", "/assets/logo", "/assets/logo", 0 },
+		{ "Request route matching a wildcard route part", "/assets/js/App.js", "/assets/*path", 1 },
 		{ "Request Route Path with no path parameters", "/users/list-all", "/users/list-all", 0 },
 		{ "Request Route Path with a single path parameter", "/users/6/get_name", "/users/:userId/get_name", 1 },
 		{ "Request route for a static resource", "/files/static/proteus/index.html", "/files/static", 0 },
@@ -122,4 +126,70 @@ func Test_RouteTree_MatchRoute(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Test case to validate if a request route path is matched to a route when the route part matched first in a position does not lead to a matching route.
+func Test_RouteTree_MatchBacktracking(t *testing.T) {
+	root := createTree()
+	addRouteToTree(root, "/user/profile/edit")
+	addRouteToTree(root, "/user/:name/posts")
+	addRouteToTree(root, "/items/:id([0-9]+)/details")
+	addRouteToTree(root, "/items/:slug([a-z0-9]+)/reviews")
+	addRouteToTree(root, "/items/:code/history")
+	addRouteToTree(root, "/docs/latest")
+	addRouteToTree(root, "/docs/*path")
+	testCases := []struct {
+		Name string
+		RequestRoute string
+		MappedRoute string
+		ParamName string
+		ParamValue string
+	} {
+		{ "Route part in the path with a different next route part", "/user/profile/posts", "/user/:name/posts", "name", "profile" },
+		{ "Route part in the path with the same next route part", "/user/profile/edit", "/user/profile/edit", "", "" },
+		{ "Value matching the first constrained path parameter", "/items/123/details", "/items/:id([0-9]+)/details", "id", "123" },
+		{ "Value matching both constrained path parameters", "/items/123/reviews", "/items/:slug([a-z0-9]+)/reviews", "slug", "123" },
+		{ "Value matching a constrained and an unconstrained path parameter", "/items/123/history", "/items/:code/history", "code", "123" },
+		{ "Route part in the path alongside a wildcard", "/docs/latest/index.html", "/docs/*path", "path", "latest/index.html" },
+		{ "Route path not matching any route", "/user/profile/likes", "", "", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			matchInfo := matchRouteInTree(root, testCase.RequestRoute)
+			if !strings.EqualFold(testCase.MappedRoute, matchInfo.RoutePath) {
+				tt.Errorf("The matched route [%s] returned does not match the expected route path [%s]", matchInfo.RoutePath, testCase.MappedRoute)
+			} else {
+				tt.Logf("The matched route [%s] returned matches the expected route path [%s]", matchInfo.RoutePath, testCase.MappedRoute)
+			}
+
+			expectedCount := 0
+			if testCase.ParamName != "" {
+				expectedCount = 1
+				values, ok := matchInfo.Segments.Get(testCase.ParamName)
+				if !ok || len(values) != 1 || values[0] != testCase.ParamValue {
+					tt.Errorf("The value %v of the path parameter [%s] does not match the expected value [%s]", values, testCase.ParamName, testCase.ParamValue)
+				}
+			}
+
+			if matchInfo.Segments.Length() != expectedCount {
+				tt.Errorf("The number of path parameters returned (%d) does not match the expected parameter count (%d).", matchInfo.Segments.Length(), expectedCount)
+			} else {
+				tt.Logf("The number of path parameters returned (%d) matches the expected parameter count (%d).", matchInfo.Segments.Length(), expectedCount)
+			}
+		})
+	}
+}
+
+// Test case to validate if the remaining route path is captured by a wildcard route part, preserving the case of the path.
+func Test_RouteTree_MatchWildcard(t *testing.T) {
+	root := createTree()
+	addRouteToTree(root, "/assets/*path")
+	matchInfo := matchRouteInTree(root, "/assets/js/App.js")
+	values, ok := matchInfo.Segments.Get("path")
+	if !ok || len(values) != 1 || values[0] != "js/App.js" {
+		t.Errorf("The captured wildcard value [%v] does not match the expected value [js/App.js]", values)
+	} else {
+		t.Logf("The captured wildcard value [%s] matches the expected value [js/App.js]", values[0])
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Structure containing the security headers set by the SecureHeaders middleware. A header whose setting is empty (or zero) is not sent. The settings are initialized from the server defaults by NewSecureHeadersConfig().
+type SecureHeadersConfig struct {
+	// Duration for which browsers must access the site only over HTTPS, sent as the max-age of the Strict-Transport-Security header. The header is sent only for requests received over HTTPS.
+	HSTSMaxAge time.Duration
+	// Boolean value to indicate if the Strict-Transport-Security header applies to all the subdomains as well.
+	HSTSIncludeSubdomains bool
+	// Boolean value to indicate if the site consents to be included in the HSTS preload lists of the browsers.
+	HSTSPreload bool
+	// Boolean value to indicate if the X-Content-Type-Options header is sent with "nosniff", so that browsers do not guess a content type other than the one declared.
+	ContentTypeNosniff bool
+	// Value of the X-Frame-Options header, like "DENY" or "SAMEORIGIN", which controls if the responses can be displayed in a frame.
+	FrameOptions string
+	// Value of the Referrer-Policy header, like "strict-origin-when-cross-origin", which controls the referrer information sent along with the requests made from the responses.
+	ReferrerPolicy string
+	// Value of the Content-Security-Policy header, like "default-src 'self'", which restricts the sources from which the content of the responses can be loaded.
+	ContentSecurityPolicy string
+	// Boolean value to indicate if the content security policy is only reported upon violation rather than enforced, by sending it in the Content-Security-Policy-Report-Only header.
+	ContentSecurityPolicyReportOnly bool
+}
+
+// Returns a middleware which sets the security headers in the given configuration on every response. When the middleware is added to both the server and a route (or a router), the configuration of
+// the route replaces that of the server: the headers it does not set are removed from the response, so that a route can relax the policy (like allowing the response to be framed) or tighten it.
+// The headers are set before the handler is invoked, and hence can also be changed by the handler itself.
+func SecureHeaders(config SecureHeadersConfig) Middleware {
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			config.apply(request, response)
+			return next(request, response)
+		}
+	}
+}
+
+// Sets the security headers in the configuration on the given response, and removes the security headers which are not enabled in the configuration.
+func (config *SecureHeadersConfig) apply(request *HttpRequest, response *HttpResponse) {
+	strictTransportSecurity := ""
+	if config.HSTSMaxAge > 0 && isSecureRequest(request) {
+		strictTransportSecurity = "max-age=" + strconv.FormatInt(int64(config.HSTSMaxAge.Seconds()), 10)
+		if config.HSTSIncludeSubdomains {
+			strictTransportSecurity += "; includeSubDomains"
+		}
+
+		if config.HSTSPreload {
+			strictTransportSecurity += "; preload"
+		}
+	}
+
+	contentTypeOptions := ""
+	if config.ContentTypeNosniff {
+		contentTypeOptions = "nosniff"
+	}
+
+	policyHeader, otherPolicyHeader := "Content-Security-Policy", "Content-Security-Policy-Report-Only"
+	if config.ContentSecurityPolicyReportOnly {
+		policyHeader, otherPolicyHeader = otherPolicyHeader, policyHeader
+	}
+
+	response.DelHeader(otherPolicyHeader)
+	headers := [][2]string{
+		{ "Strict-Transport-Security", strictTransportSecurity },
+		{ "X-Content-Type-Options", contentTypeOptions },
+		{ "X-Frame-Options", strings.TrimSpace(config.FrameOptions) },
+		{ "Referrer-Policy", strings.TrimSpace(config.ReferrerPolicy) },
+		{ policyHeader, strings.TrimSpace(config.ContentSecurityPolicy) },
+	}
+
+	for _, header := range headers {
+		if header[1] == "" {
+			response.DelHeader(header[0])
+		} else if err := response.SetHeader(header[0], header[1]); err != nil {
+			request.Logger().Warn("Security header could not be set", "header", header[0], "error", err.Error())
+		}
+	}
+}
+
+// Checks if the given request has been received over HTTPS, either directly or through a trusted proxy which has set the X-Forwarded-Proto header to "https".
+func isSecureRequest(request *HttpRequest) bool {
+	if request.TLS() != nil {
+		return true
+	}
+
+	trustedProxies := request.getConfig().TrustedProxies
+	forwardedProto := request.HeaderValues("X-Forwarded-Proto")
+	if len(trustedProxies) == 0 || len(forwardedProto) == 0 || !isTrustedProxy(getHostname(request.ClientAddress), trustedProxies) {
+		return false
+	}
+
+	// The protocol added by the closest proxy is the last one in the header.
+	return strings.EqualFold(strings.TrimSpace(forwardedProto[len(forwardedProto) - 1]), "https")
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+// Test case to validate the security headers set by the SecureHeaders middleware, including the headers replaced by the middleware added to a route.
+func Test_Server_SecureHeaders(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	if err := testServer.TrustedProxies("10.0.0.0/8"); err != nil {
+		t.Fatalf("Was not expecting an error while setting the trusted proxies, but got this instead - %v", err)
+	}
+
+	serverConfig := NewSecureHeadersConfig()
+	serverConfig.HSTSIncludeSubdomains = true
+	testServer.Use(SecureHeaders(serverConfig))
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Body = []byte("ok")
+		return nil
+	}
+
+	routeConfig := NewSecureHeadersConfig()
+	routeConfig.FrameOptions = ""
+	routeConfig.ContentSecurityPolicy = "frame-ancestors https://partner.example.com"
+	routeConfig.ContentSecurityPolicyReportOnly = true
+	testServer.Get("/page", handler)
+	testServer.Get("/widget", handler, SecureHeaders(routeConfig))
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		ClientAddress string
+		IsTLS bool
+		ForwardedProto string
+		ExpHeaders map[string]string
+	} {
+		{ "Request received over HTTPS", "/page", "203.0.113.7:51234", true, "", map[string]string{ "Strict-Transport-Security": "max-age=31536000; includeSubDomains", "X-Content-Type-Options": "nosniff", "X-Frame-Options": "DENY", "Referrer-Policy": "strict-origin-when-cross-origin", "Content-Security-Policy": "default-src 'self'" } },
+		{ "Request received over plain HTTP", "/page", "203.0.113.7:51234", false, "", map[string]string{ "Strict-Transport-Security": "", "X-Frame-Options": "DENY" } },
+		{ "HTTPS request forwarded by a trusted proxy", "/page", "10.1.2.3:443", false, "https", map[string]string{ "Strict-Transport-Security": "max-age=31536000; includeSubDomains" } },
+		{ "Forwarded protocol sent by a client that is not trusted", "/page", "203.0.113.7:51234", false, "https", map[string]string{ "Strict-Transport-Security": "" } },
+		{ "Route replacing the security headers of the server", "/widget", "203.0.113.7:51234", true, "", map[string]string{ "Strict-Transport-Security": "max-age=31536000", "X-Frame-Options": "", "Content-Security-Policy": "", "Content-Security-Policy-Report-Only": "frame-ancestors https://partner.example.com", "X-Content-Type-Options": "nosniff" } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testRequest.ClientAddress = testCase.ClientAddress
+			if testCase.IsTLS {
+				testRequest.tlsInfo = new(TLSInfo)
+			}
+			if testCase.ForwardedProto != "" {
+				testRequest.Headers.Add("X-Forwarded-Proto", testCase.ForwardedProto)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			for name, expValue := range testCase.ExpHeaders {
+				if value, _ := testResponse.Headers.Get(name); value != expValue {
+					tt.Errorf("Expected the header %s to be [%s], but got [%s] instead", name, expValue, value)
+				} else {
+					tt.Logf("Header %s is [%s] as expected", name, value)
+				}
+			}
+		})
+	}
+}
@@ -1,12 +1,39 @@
 package http
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	iofs "io/fs"
+	"math"
 	"net"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"github.com/mkbworks/proteus/lib/config"
+	"github.com/mkbworks/proteus/lib/fs"
 )
 
+// Maximum size (in bytes) of the body of a request rejected as too large, which is read and discarded before the connection is closed.
+const MAX_BODY_DRAIN_SIZE = 256 << 10
+
+// Maximum duration for which the body of a request rejected as too large is read and discarded before the connection is closed.
+const BODY_DRAIN_TIMEOUT = 2 * time.Second
+
+// Maximum number of connections rejected due to the connection limit which are sent a 503 (Service Unavailable) response at the same time. Connections rejected beyond this number are closed right away,
+// so that a flood of connections cannot grow the number of goroutines and open connections without limit.
+const MAX_CONNECTION_REJECTERS = 32
+
 // Structure to create an instance of a web server.
 type HttpServer struct {
 	// Hostname of the web server instance.
@@ -15,30 +42,506 @@ type HttpServer struct {
 	PortNumber int
 	// Server socket created and bound to the port number.
 	Socket net.Listener
+	// Configurable settings (like timeouts) of the web server instance.
+	Config *ServerConfig
+	// TLS configuration used as the base configuration by ListenTLS. It can be used to customize the cipher suites, protocol versions and client certificate validation. If nil, a default configuration is used.
+	TLSConfig *tls.Config
 	// Router instance that contains all the routes and their associated handlers.
 	innerRouter *Router
 	// Logger instance associated with the Server instance.
-	eventLogger *logger
+	eventLogger Logger
+	// CORS settings of the web server instance. It is nil if CORS has not been enabled using UseCORS().
+	corsConfig *CORSConfig
+	// Access logger recording an entry for every request processed. It is nil if access logging has not been enabled using UseAccessLog().
+	accessLogger *AccessLogger
+	// Collection of custom error handlers registered using NotFound() and OnError(), with the response status code as key.
+	errorHandlers map[StatusCode]Handler
+	// Function set using ErrorTransformer() which maps the errors returned by the handlers (and the panics raised by them) to the error responses. It is nil if no function has been set.
+	errorTransformer func(error) *HttpError
+	// Base context for all the requests processed by the server instance. It is cancelled when the server shuts down.
+	baseContext context.Context
+	// Function to cancel the base context of the server instance.
+	cancelBaseContext context.CancelFunc
+	// Collection of callbacks registered using OnReady(), which are invoked once the server socket has been created.
+	readyCallbacks []func(Address net.Addr)
+	// Mutex to synchronize access to the server socket, which is created by the listen methods and can be read from other goroutines.
+	socketMutex sync.Mutex
+	// HTML templates set using SetTemplates(), which are used to render responses. It is nil if no templates have been set.
+	templates *templateSet
+	// Collection of the HTTP methods enabled by adding a WebDAV endpoint using WebDAV(), which are allowed in addition to the methods of the server configuration for the HTTP versions allowing the PUT method.
+	extensionMethods []string
+	// Collection of hosts set using AllowedHosts(), which the Host header of a request must match. Requests for all hosts are processed if it is empty.
+	allowedHosts []string
+	// Handler invoked for CONNECT requests in authority form, set using OnConnect(). Such requests are rejected if it is nil.
+	connectHandler Handler
+	// Collection of content types registered using AddContentType(), with the file extension as key and the media type as value.
+	contentTypes map[string]string
+	// Default content type set using SetDefaultContentType(). The "content_type" server default is used if it is empty.
+	defaultContentType string
+	// Value of the Server header set using SetServerToken(). The "server_name" server default is used if it is nil, while the Server header is omitted if it points to an empty string.
+	serverToken *string
+	// TCP socket created (or inherited from the previous process) by the listen methods, which is passed to the new process by Restart(). It is not wrapped by TLS, unlike the server socket.
+	inheritableSocket net.Listener
+	// Wait group to track the client connections being handled by the web server instance.
+	activeConnections sync.WaitGroup
+	// Collection of the client connections which are waiting for their next request.
+	idleConnections map[net.Conn]struct{}
+	// Mutex to synchronize access to the collection of idle connections.
+	connectionMutex sync.Mutex
+	// Boolean value to indicate if the web server instance has stopped accepting connections and is waiting for the existing connections to be closed, before shutting down.
+	isDraining atomic.Bool
+	// Maximum duration (in nanoseconds) to wait for the existing connections to be closed while draining. A zero value means that there is no timeout.
+	drainTimeout atomic.Int64
+	// Channel closed once the web server instance has completed draining its connections and has shut down.
+	drainCompleted chan struct{}
+	// Collection of hooks registered using OnRequest(), which are invoked for every request before it is routed.
+	requestHooks []func(*HttpRequest, *HttpResponse, RequestEvent)
+	// Collection of hooks registered using OnResponse(), which are invoked for every request once its response has been sent.
+	responseHooks []func(*HttpRequest, *HttpResponse, ResponseEvent)
+	// Collection of hooks registered using OnConnectionClose(), which are invoked once a client connection has been closed.
+	connectionCloseHooks []func(ConnectionEvent)
+	// Manager of the certificates obtained from an ACME server, which answers the HTTP-01 challenges received by the server instance. It is nil unless the server has been set up using ListenAutoTLS().
+	autoTLS *autoTLSManager
+}
+
+// Adds the given middlewares to the web server instance. These middlewares are executed in the order given, for every request matching a route defined in the server.
+func (srv *HttpServer) Use(middlewares ...Middleware) {
+	srv.innerRouter.use(middlewares...)
+}
+
+// Restricts the requests processed by the web server instance to those whose Host header matches one of the given hosts, to prevent host header injection. A host starting with "*." matches all the
+// subdomains of the domain that follows it. The port number in the Host header is ignored while matching. Requests for any other host (or without a Host header) are rejected with a 400 (Bad Request) response.
+// Requests for all hosts are processed if no hosts are given.
+func (srv *HttpServer) AllowedHosts(hosts []string) {
+	srv.allowedHosts = make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if strings.TrimSpace(host) != "" {
+			srv.allowedHosts = append(srv.allowedHosts, getHostname(strings.TrimSpace(host)))
+		}
+	}
+}
+
+// Enables or disables the redirection of requests whose trailing '/' does not match the route path defined, like a request for /users/ when the route is defined as /users (or vice versa).
+// When disabled (the default), both variants of the request path are handled by the route.
+func (srv *HttpServer) RedirectTrailingSlash(enabled bool) {
+	srv.innerRouter.setRedirectTrailingSlash(enabled)
+}
+
+// Attaches all the routes defined in the given router (like the routes registered by another package) under the given route prefix. The server middlewares are executed first, followed by the middlewares
+// of the router and then the middlewares of the route. Routes defined in the router after it has been mounted are not attached. An error is returned if any of the routes conflicts with an existing route.
+func (srv *HttpServer) Mount(Prefix string, router *Router) error {
+	return srv.innerRouter.mount(Prefix, router)
+}
+
+// Removes the route defined for the given HTTP method and route path (like "/users/:id") from the web server instance, so that a long-running server can take an endpoint offline
+// without restarting. The GET and HEAD routes of a static folder must be removed separately. An error is returned if no route has been defined for the method with the given route path.
+func (srv *HttpServer) Remove(Method string, RoutePath string) error {
+	return srv.innerRouter.removeRoute(Method, RoutePath)
+}
+
+// Disables the routes defined for the given route path (for all the methods), like when the endpoint is under maintenance. The requests for the route path are answered with the status set in
+// Config.DisabledRouteStatus (404 Not Found by default) until the route path is enabled again using Enable(). An error is returned if no route has been defined with the given route path.
+func (srv *HttpServer) Disable(RoutePath string) error {
+	return srv.innerRouter.setRouteEnabled(RoutePath, false)
+}
+
+// Enables the routes defined for the given route path, which have been disabled using Disable(). An error is returned if no route has been defined with the given route path.
+func (srv *HttpServer) Enable(RoutePath string) error {
+	return srv.innerRouter.setRouteEnabled(RoutePath, true)
+}
+
+// Returns the details of all the routes defined in the web server instance (including the routes defined in route groups), in the order in which they were defined.
+// This is useful for tools generating documentation and for debugging requests not matching the expected route.
+func (srv *HttpServer) Routes() []RouteInfo {
+	return srv.innerRouter.getRouteInfo()
+}
+
+// Assigns the given name to the route defined last in the web server instance (including the routes defined in route groups), so that the URL of the route can be built using URLFor().
+// Both the GET and the HEAD routes of a static route are named. An error is returned if no route has been defined yet, if the definition of the last route has failed or if the name has already been given to another route.
+func (srv *HttpServer) Name(RouteName string) error {
+	return srv.innerRouter.nameLastRoute(RouteName)
 }
 
-// Define a static route and map to a static file or folder in the file system.
-func (srv *HttpServer) Static(Route string, TargetPath string) error {
-	err := srv.innerRouter.addStaticRoute("GET", Route, TargetPath)
+// Sets the maximum size (in bytes) of the request body accepted by the route defined last in the web server instance (including the routes defined in route groups), in place of the "max_body_size" server default.
+// The limit can be larger or smaller than the server default, like allowing large uploads on a single route. Requests with a larger body are rejected with a 413 (Content Too Large) response while the body is read.
+// An error is returned if no route has been defined yet, if the definition of the last route has failed or if the given size is not positive.
+func (srv *HttpServer) MaxBodySize(Size int64) error {
+	return srv.innerRouter.setLastRouteBodySize(Size)
+}
+
+// Builds and returns the URL path of the route with the given name, with the path parameters (and the wildcard) in the route path replaced by the given values. This keeps the links to a route
+// consistent when its route path changes. An error is returned if no route has the given name or if a path parameter does not have a valid value.
+func (srv *HttpServer) URLFor(RouteName string, params map[string]string) (string, error) {
+	return srv.innerRouter.buildURL(RouteName, params)
+}
+
+// Sets the handler to be invoked for sending the response when no route matches the request path. The default ErrorHandler is used if no handler has been set.
+func (srv *HttpServer) NotFound(handlerFunc Handler) {
+	srv.OnError(StatusNotFound, handlerFunc)
+}
+
+// Sets the handler to be invoked for sending the error response with the given status code, like a branded HTML page or a JSON error body.
+// The default ErrorHandler is used for all status codes for which a handler has not been set.
+func (srv *HttpServer) OnError(status StatusCode, handlerFunc Handler) {
+	srv.errorHandlers[status] = handlerFunc
+}
+
+// Sets the function which maps the errors returned by the handlers to the error responses sent back to the client, so that domain errors (like sql.ErrNoRows or validation errors) are mapped to a status code
+// in one place instead of in every handler. The function is called with every error which is not an HttpError, including a PanicError for the panics raised by the handlers, and the error response is sent for the status
+// code of the HttpError it returns. A 500 (Internal Server Error) response is sent if it returns nil.
+func (srv *HttpServer) ErrorTransformer(transformer func(error) *HttpError) {
+	srv.errorTransformer = transformer
+}
+
+// Define a static route and map to a static file or folder in the file system. The settings of the static route (like directory listing) can be given as an optional StaticOptions value.
+func (srv *HttpServer) Static(Route string, TargetPath string, options ...StaticOptions) error {
+	var staticOptions *StaticOptions
+	if len(options) > 0 {
+		staticOptions = &options[0]
+	}
+
+	return srv.innerRouter.addStaticRoutes(Route, TargetPath, nil, staticOptions)
+}
+
+// Define a static route serving the files in the given file system, like the assets compiled into the binary using an embed.FS. The files are served with the same content types, caching and byte range
+// support as the files of a static route defined using Static(). A sub-folder of the file system can be served using fs.Sub().
+func (srv *HttpServer) StaticFS(Route string, FileSystem iofs.FS, options ...StaticOptions) error {
+	var staticOptions *StaticOptions
+	if len(options) > 0 {
+		staticOptions = &options[0]
+	}
+
+	if FileSystem == nil {
+		reError := new(RoutingError)
+		reError.RoutePath = Route
+		reError.Message = "StaticFS: File system to be served must not be nil"
+		return srv.innerRouter.failRegistration(reError)
+	}
+
+	return srv.innerRouter.addStaticRoutes(Route, "", FileSystem, staticOptions)
+}
+
+// Defines the static routes present in the given configuration, loaded using LoadConfig(), for the web server instance.
+func (srv *HttpServer) ApplyConfig(fileConfig *config.FileConfig) error {
+	for _, staticRoot := range fileConfig.StaticRoots {
+		err := srv.Static(staticRoot.Route, staticRoot.Directory)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Registers the given media type (like "application/wasm") for the files with the given extension (like ".wasm"), which is sent in the Content-Type header when such files are served. The registered
+// media type replaces the media type configured for the extension, only for the files served by this web server instance, and is retained when the configuration is reloaded.
+// An error is returned if the extension is empty or the media type is not valid.
+func (srv *HttpServer) AddContentType(Extension string, MediaType string) error {
+	Extension = getFileExtension(Extension)
+	if Extension == "" {
+		ce := new(config.ConfigError)
+		ce.Message = "AddContentType: File extension cannot be empty"
+		return ce
+	}
+
+	MediaType, err := parseContentType("AddContentType", MediaType)
 	if err != nil {
 		return err
 	}
 
-	err = srv.innerRouter.addStaticRoute("HEAD", Route, TargetPath)
+	srv.contentTypes[Extension] = MediaType
+	return nil
+}
+
+// Sets the value of the Server header sent in the responses of this web server instance (like "proteus" or "proteus/1.4"), in place of the "server_name" server default. It is retained when the configuration is reloaded.
+// An empty token omits the Server header entirely, for deployments that must not disclose the software (or its version) serving the requests. An error is returned if the token contains control characters.
+func (srv *HttpServer) SetServerToken(Token string) error {
+	Token = strings.TrimSpace(Token)
+	if strings.IndexFunc(Token, isControlChar) != -1 {
+		ce := new(config.ConfigError)
+		ce.Message = fmt.Sprintf("SetServerToken: %q is not a valid value for the Server header", Token)
+		return ce
+	}
+
+	srv.serverToken = &Token
+	return nil
+}
+
+// Sets the media type sent in the Content-Type header for the files whose extension has no media type, either configured or known to the mime package, when they are served by this web server instance.
+// It replaces the "content_type" server default (application/octet-stream by default) and is retained when the configuration is reloaded. An error is returned if the media type is not valid.
+func (srv *HttpServer) SetDefaultContentType(MediaType string) error {
+	MediaType, err := parseContentType("SetDefaultContentType", MediaType)
 	if err != nil {
 		return err
 	}
 
+	srv.defaultContentType = MediaType
 	return nil
 }
 
-// Setup the web server instance to listen for incoming HTTP requests at the given hostname and port number.
-func (srv * HttpServer) Listen(PortNumber int, HostAddress string) {
-	if PortNumber == 0 {
+// Reloads the server defaults and the list of allowed content types from "config.json" and the configuration file last loaded using LoadConfig(), so that new content types and changed
+// defaults (like the maximum body size or the compression settings) take effect for the requests processed from then on, without restarting the server. The settings in ServerConfig are not changed,
+// as they belong to the server instance. The current configuration is kept if an error is returned.
+func (srv *HttpServer) ReloadConfig() error {
+	err := reloadConfig()
+	if err != nil {
+		srv.LogError(fmt.Sprintf("Error occurred while reloading the configuration: %s", err.Error()))
+		return err
+	}
+
+	srv.LogInfo("Configuration has been reloaded")
+	return nil
+}
+
+// Reloads the configuration using ReloadConfig() whenever the process receives one of the given signals, until the server shuts down. If no signals are given, the configuration is reloaded on SIGHUP.
+func (srv *HttpServer) ReloadOnSignal(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{ syscall.SIGHUP }
+	}
+
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, signals...)
+	go func() {
+		defer signal.Stop(signalChannel)
+		for {
+			select {
+			case <-signalChannel:
+				srv.ReloadConfig()
+			case <-srv.baseContext.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Reloads the configuration using ReloadConfig() whenever the configuration file last loaded using LoadConfig() changes, until the server shuts down. The file is checked once every "watch_interval" and
+// the configuration is reloaded once no changes have been detected for "watch_debounce" (both server defaults), so that a file being written in several steps is reloaded only once.
+// An error is returned if no configuration file has been loaded.
+func (srv *HttpServer) WatchConfig() error {
+	configMutex.RLock()
+	path := configFilePath
+	configMutex.RUnlock()
+	if path == "" {
+		ce := new(config.ConfigError)
+		ce.Message = "WatchConfig: A configuration file must be loaded using LoadConfig() before it can be watched"
+		return ce
+	}
+
+	watcher := fs.NewWatcher(getDefaultDuration("watch_interval"), getDefaultDuration("watch_debounce"), func(ChangedPaths []string) {
+		srv.ReloadConfig()
+	})
+	if err := watcher.Add(path); err != nil {
+		return err
+	}
+
+	srv.runWatcher(watcher)
+	return nil
+}
+
+// Starts the given watcher and stops it once the web server instance shuts down.
+func (srv *HttpServer) runWatcher(watcher *fs.Watcher) {
+	watcher.Start()
+	go func() {
+		<-srv.baseContext.Done()
+		watcher.Stop()
+	}()
+}
+
+// Setup the web server instance to listen for incoming HTTP requests at the given hostname and port number. The method blocks until the server is shut down, in which case it returns nil.
+// An error is returned if the server socket could not be created. If the port number is zero, the server listens at a port assigned by the operating system, which can be found using Addr().
+func (srv *HttpServer) Listen(PortNumber int, HostAddress string) error {
+	err := srv.listen(PortNumber, HostAddress, nil)
+	if err != nil {
+		return err
+	}
+
+	srv.serve(srv.Socket)
+	return nil
+}
+
+// Setup the web server instance to listen for incoming HTTP requests at the given hostname and port number, without blocking the caller. The incoming requests are accepted in a separate goroutine
+// until the server is shut down. An error is returned if the server socket could not be created.
+func (srv *HttpServer) ListenAndServeAsync(PortNumber int, HostAddress string) error {
+	err := srv.listen(PortNumber, HostAddress, nil)
+	if err != nil {
+		return err
+	}
+
+	go srv.serve(srv.Socket)
+	return nil
+}
+
+// Setup the web server instance to listen for incoming HTTPS requests at the given hostname and port number. The method blocks until the server is shut down, in which case it returns nil.
+// The certificate and private key are loaded from the given PEM encoded files and added to the TLS configuration of the server instance. If a TLS configuration has been assigned to the server instance, it is used as the base configuration for the listener.
+// An error is returned if the certificate could not be loaded or the server socket could not be created.
+func (srv *HttpServer) ListenTLS(PortNumber int, HostAddress string, CertFile string, KeyFile string) error {
+	var tlsConfig *tls.Config
+	if srv.TLSConfig != nil {
+		tlsConfig = srv.TLSConfig.Clone()
+	} else {
+		tlsConfig = new(tls.Config)
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	CertFile = strings.TrimSpace(CertFile)
+	KeyFile = strings.TrimSpace(KeyFile)
+	if CertFile != "" || KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(CertFile, KeyFile)
+		if err != nil {
+			listenErr := new(ListenError)
+			listenErr.Message = fmt.Sprintf("Error occurred while loading the TLS certificate and key: %s", err.Error())
+			return listenErr
+		}
+
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+
+	if len(tlsConfig.Certificates) == 0 && tlsConfig.GetCertificate == nil && tlsConfig.GetConfigForClient == nil {
+		listenErr := new(ListenError)
+		listenErr.Message = "Error occurred while setting up TLS listener: No certificate has been configured for the server"
+		return listenErr
+	}
+
+	srv.configureHTTP2(tlsConfig)
+	err := srv.listen(PortNumber, HostAddress, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	srv.serve(srv.Socket)
+	return nil
+}
+
+// Creates the server socket listening at the given hostname and port number and assigns it to the web server instance. If a TLS configuration is given, the socket accepts only TLS connections.
+// Once the socket has been created, the port number of the server instance is updated to the port actually bound.
+func (srv *HttpServer) listen(PortNumber int, HostAddress string, tlsConfig *tls.Config) error {
+	serverAddress := srv.setAddress(PortNumber, HostAddress)
+	server, err := inheritedListener(srv.PortNumber)
+	if err == nil && server != nil {
+		srv.LogInfo("Web server is using the socket inherited from the previous process", "address", server.Addr().String())
+	} else if err == nil {
+		server, err = net.Listen("tcp", serverAddress)
+	}
+
+	if _, ok := err.(*ListenError); ok {
+		return err
+	} else if err != nil {
+		listenErr := new(ListenError)
+		listenErr.Address = serverAddress
+		listenErr.Message = fmt.Sprintf("Error occurred while setting up listener socket: %s", err.Error())
+		return listenErr
+	}
+
+	if tcpAddress, ok := server.Addr().(*net.TCPAddr); ok {
+		srv.PortNumber = tcpAddress.Port
+	}
+
+	srv.socketMutex.Lock()
+	srv.inheritableSocket = server
+	srv.socketMutex.Unlock()
+	serverAddress = srv.HostAddress + ":" + strconv.Itoa(srv.PortNumber)
+	if srv.Config.ProxyProtocol {
+		// The PROXY protocol header is sent before the TLS handshake, and hence it must be read before the TLS connection.
+		server = NewProxyListener(server)
+	}
+
+	if tlsConfig != nil {
+		srv.startListening(tls.NewListener(server, tlsConfig), "https://" + serverAddress)
+	} else {
+		srv.startListening(server, "http://" + serverAddress)
+	}
+
+	return nil
+}
+
+// Setup the web server instance to listen for incoming HTTP requests on the Unix domain socket at the given path. A stale socket file left behind at the path is removed before the socket is created.
+// The method blocks until the server is shut down, in which case it returns nil, and the socket file is removed once the server stops listening. An error is returned if the socket could not be created.
+func (srv *HttpServer) ListenUnix(SocketPath string) error {
+	SocketPath = strings.TrimSpace(SocketPath)
+	if fileInfo, err := os.Stat(SocketPath); err == nil && fileInfo.Mode() & os.ModeSocket != 0 {
+		os.Remove(SocketPath)
+	}
+
+	server, err := net.Listen("unix", SocketPath)
+	if err != nil {
+		listenErr := new(ListenError)
+		listenErr.Address = SocketPath
+		listenErr.Message = fmt.Sprintf("Error occurred while setting up Unix domain socket: %s", err.Error())
+		return listenErr
+	}
+
+	srv.HostAddress = SocketPath
+	srv.PortNumber = 0
+	if srv.Config.ProxyProtocol {
+		server = NewProxyListener(server)
+	}
+
+	srv.startListening(server, "unix:" + SocketPath)
+	srv.serve(server)
+	return nil
+}
+
+// Makes the web server instance accept the incoming HTTP requests from the given listener, which has been created by the caller, like a listener inherited through systemd socket activation.
+// If the listener accepts TLS connections, HTTP/2 is negotiated only if the TLS configuration of the listener contains the "h2" protocol. The method blocks until the server is shut down, in which case it returns nil.
+func (srv *HttpServer) Serve(listener net.Listener) error {
+	if listener == nil {
+		listenErr := new(ListenError)
+		listenErr.Message = "Listener to accept the incoming requests from must not be nil"
+		return listenErr
+	}
+
+	if tcpAddress, ok := listener.Addr().(*net.TCPAddr); ok {
+		srv.HostAddress = tcpAddress.IP.String()
+		srv.PortNumber = tcpAddress.Port
+	}
+
+	srv.startListening(listener, listener.Addr().Network() + "://" + listener.Addr().String())
+	srv.serve(listener)
+	return nil
+}
+
+// Assigns the given listener as the server socket of the web server instance and invokes the ready callbacks. The given display address is logged as the address where the server is listening.
+func (srv *HttpServer) startListening(listener net.Listener, DisplayAddress string) {
+	srv.setSocket(listener)
+	srv.LogInfo(fmt.Sprintf("Web server is listening at %s", DisplayAddress))
+	if srv.Config.PrintRoutes {
+		for _, route := range srv.Routes() {
+			srv.LogInfo("Route defined", "method", route.Method, "pattern", route.Pattern, "static", route.IsStatic, "handler", route.HandlerName, "name", route.Name)
+		}
+	}
+	for _, callback := range srv.readyCallbacks {
+		callback(listener.Addr())
+	}
+}
+
+// Assigns the given listener as the server socket of the web server instance.
+func (srv *HttpServer) setSocket(listener net.Listener) {
+	srv.socketMutex.Lock()
+	defer srv.socketMutex.Unlock()
+	srv.Socket = listener
+}
+
+// Returns the network address at which the web server instance is listening, which contains the port assigned by the operating system if the server was set up to listen at port zero.
+// It returns nil if the server is not listening yet.
+func (srv *HttpServer) Addr() net.Addr {
+	srv.socketMutex.Lock()
+	defer srv.socketMutex.Unlock()
+	if srv.Socket == nil {
+		return nil
+	}
+
+	return srv.Socket.Addr()
+}
+
+// Registers a callback to be invoked with the network address of the web server instance, once the server socket has been created and before the incoming requests are accepted.
+func (srv *HttpServer) OnReady(callback func(Address net.Addr)) {
+	srv.readyCallbacks = append(srv.readyCallbacks, callback)
+}
+
+// Assigns the hostname and port number of the web server instance and returns the address where the server must listen for incoming requests. The default hostname is used if the given hostname is empty
+// and the default port number is used if the given port number is DEFAULT_PORT_NUMBER.
+func (srv *HttpServer) setAddress(PortNumber int, HostAddress string) string {
+	if PortNumber == DEFAULT_PORT_NUMBER {
 		srv.PortNumber = getDefaultPort()
 	} else {
 		srv.PortNumber = PortNumber
@@ -50,71 +553,583 @@ func (srv * HttpServer) Listen(PortNumber int, HostAddress string) {
 		srv.HostAddress = strings.TrimSpace(HostAddress)
 	}
 
-	serverAddress := srv.HostAddress + ":" + strconv.Itoa(srv.PortNumber)
-	server, err := net.Listen("tcp", serverAddress)
-	if err != nil {
-		srv.LogError(fmt.Sprintf("Error occurred while setting up listener socket: %s", err.Error()))
-		return
+	return srv.HostAddress + ":" + strconv.Itoa(srv.PortNumber)
+}
+
+// Shuts down the web server instance by closing the server socket. The contexts of all the requests being processed are cancelled.
+func (srv *HttpServer) Shutdown() error {
+	srv.cancelBaseContext()
+	srv.socketMutex.Lock()
+	defer srv.socketMutex.Unlock()
+	if srv.Socket == nil {
+		return nil
 	}
 
-	srv.Socket = server
-	defer srv.Socket.Close()
-	srv.LogInfo(fmt.Sprintf("Web server is listening at http://%s", serverAddress))
+	return srv.Socket.Close()
+}
 
+// Accepts incoming client connections from the given listener and handles each of them in a separate goroutine.
+// If the number of concurrent connections is limited, new connections are either queued or rejected once the limit has been reached, as per the connection limit policy.
+func (srv *HttpServer) serve(listener net.Listener) {
+	defer srv.completeDrain()
+	defer listener.Close()
+	var connectionSlots chan struct{}
+	if srv.Config.MaxConcurrentConnections > 0 {
+		connectionSlots = make(chan struct{}, srv.Config.MaxConcurrentConnections)
+	}
+
+	isQueued := connectionSlots != nil && srv.Config.ConnectionLimitPolicy != RejectConnections
+	rejecterSlots := make(chan struct{}, MAX_CONNECTION_REJECTERS)
 	for {
-		clientConnection, err := srv.Socket.Accept()
+		if isQueued {
+			// A slot is reserved before accepting, so that the pending connections wait in the backlog of the server socket.
+			select {
+			case connectionSlots <- struct{}{}:
+			case <-srv.baseContext.Done():
+				srv.LogInfo("Web server has stopped listening for incoming requests")
+				return
+			}
+		}
+
+		clientConnection, err := listener.Accept()
 		if err != nil {
+			if isQueued {
+				<-connectionSlots
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				srv.LogInfo("Web server has stopped listening for incoming requests")
+				return
+			}
+
 			srv.LogError(fmt.Sprintf("Error occurred while accepting a new client: %s", err.Error()))
 			continue
 		}
 
-		srv.LogInfo(fmt.Sprintf("A new client - %s has connected to the server", clientConnection.RemoteAddr().String()))
-		go srv.handleClient(clientConnection)
+		if connectionSlots != nil && !isQueued {
+			select {
+			case connectionSlots <- struct{}{}:
+			default:
+				srv.LogWarn("Connection limit has been reached and the new client is being rejected", "client", clientConnection.RemoteAddr().String())
+				select {
+				case rejecterSlots <- struct{}{}:
+					go func() {
+						defer func() { <-rejecterSlots }()
+						srv.rejectConnection(clientConnection)
+					}()
+				default:
+					clientConnection.Close()
+				}
+				continue
+			}
+		}
+
+		srv.LogDebug("A new client has connected to the server", "client", clientConnection.RemoteAddr().String())
+		srv.activeConnections.Add(1)
+		go func() {
+			defer srv.activeConnections.Done()
+			if connectionSlots != nil {
+				defer func() { <-connectionSlots }()
+			}
+			srv.handleClient(clientConnection)
+		}()
 	}
 }
 
+// Sends a 503 (Service Unavailable) response with the Retry-After header on the given client connection, which could not be handled as the maximum number of concurrent connections has been reached.
+// The request head is read before the response is sent, so that the client receives the response before the connection is closed. At most MAX_CONNECTION_REJECTERS connections are rejected this way at the same time.
+func (srv *HttpServer) rejectConnection(ClientConnection net.Conn) {
+	defer ClientConnection.Close()
+	ClientConnection.SetDeadline(srv.Config.getHeaderDeadline(time.Now()))
+	httpRequest := newRequest(ClientConnection, bufio.NewReader(ClientConnection))
+	httpRequest.config = srv.Config
+	if httpRequest.readHead() != nil {
+		return
+	}
+
+	retryAfter := int64(math.Ceil(srv.Config.ConnectionRetryAfter.Seconds()))
+	srv.rejectRequest(ClientConnection, httpRequest, StatusServiceUnavailable, "Retry-After", strconv.FormatInt(retryAfter, 10))
+}
+
 // Handles incoming HTTP requests sent from each individual client trying to connect to the web server instance.
+// The connection is kept open for further requests as long as the client wishes to persist it and a new request arrives before the idle timeout elapses.
+// The read, write and header timeouts configured for the server instance are applied as deadlines on the client connection.
 func (srv *HttpServer) handleClient(ClientConnection net.Conn) {
-	defer ClientConnection.Close()
-	httpRequest := newRequest(ClientConnection)
-	err := httpRequest.read()
+	openedAt := time.Now()
+	requestCount := 0
+	defer func() {
+		ClientConnection.Close()
+		srv.runConnectionCloseHooks(ClientConnection, openedAt, requestCount)
+	}()
+
+	if tlsConnection, ok := ClientConnection.(*tls.Conn); ok && srv.Config.HTTP2 {
+		tlsConnection.SetDeadline(srv.Config.getHeaderDeadline(time.Now()))
+		if tlsConnection.Handshake() != nil {
+			return
+		}
+
+		if tlsConnection.ConnectionState().NegotiatedProtocol == HTTP2_ALPN_PROTOCOL {
+			requestCount = srv.serveHTTP2(tlsConnection, nil, nil)
+			return
+		}
+	}
+
+	reader := bufio.NewReader(ClientConnection)
+	isFirstRequest := true
+	for {
+		// Wait for the first byte of the next request until the idle timeout elapses.
+		ClientConnection.SetReadDeadline(getDeadline(time.Now(), srv.Config.IdleTimeout))
+		if !srv.setIdle(ClientConnection, true) {
+			return
+		}
+
+		_, err := reader.Peek(1)
+		srv.setIdle(ClientConnection, false)
+		if err != nil {
+			if reqError, ok := err.(*RequestParseError); ok {
+				// The PROXY protocol header sent at the start of the connection is missing or invalid.
+				srv.LogError(reqError.Error(), "client", ClientConnection.RemoteAddr().String())
+			}
+			return
+		}
+
+		_, isTLSConnection := ClientConnection.(*tls.Conn)
+		if isFirstRequest && srv.Config.HTTP2 && !isTLSConnection && hasHTTP2Preface(reader) {
+			requestCount = srv.serveHTTP2(&bufferedConnection{ Conn: ClientConnection, reader: reader }, nil, nil)
+			return
+		}
+		isFirstRequest = false
+
+		requestStartTime := time.Now()
+		ClientConnection.SetReadDeadline(srv.Config.getHeaderDeadline(requestStartTime))
+		httpRequest := newRequest(ClientConnection, reader)
+		httpRequest.config = srv.Config
+		err = httpRequest.readHead()
+		if err == nil {
+			// The body size limit of the matched route applies before the 100 (Continue) response is sent, so that the client does not send an oversized body.
+			httpRequest.maxBodySize = srv.innerRouter.getMaxBodySize(httpRequest.Method, httpRequest.ResourcePath)
+			err = httpRequest.validateBodySize()
+		}
+
+		if err == nil {
+			err = srv.handleExpectation(ClientConnection, httpRequest)
+		}
+
+		if err == nil {
+			ClientConnection.SetReadDeadline(getDeadline(requestStartTime, srv.Config.ReadTimeout))
+			err = httpRequest.readBody()
+		}
+
+		if err != nil {
+			if reqError, ok := err.(*RequestParseError); ok {
+				srv.LogError(err.Error())
+				if reqError.Status != 0 {
+					srv.rejectRequest(ClientConnection, httpRequest, reqError.Status)
+				}
+				if reqError.Status == StatusContentTooLarge {
+					drainRequestBody(ClientConnection, httpRequest)
+				}
+			}
+			return
+		}
+
+		if settings, ok := httpRequest.getHTTP2Upgrade(); ok && srv.Config.HTTP2 && !isTLSConnection {
+			streamCount, err := srv.upgradeToHTTP2(ClientConnection, reader, httpRequest, settings)
+			requestCount += streamCount
+			if err != nil {
+				srv.LogError(err.Error())
+			}
+			return
+		}
+
+		ClientConnection.SetReadDeadline(time.Time{})
+		responseConnection := newMinRateConn(ClientConnection, srv.Config.MinWriteRate, srv.Config.MinWriteRateGracePeriod)
+		responseConnection.SetWriteDeadline(getDeadline(time.Now(), srv.Config.WriteTimeout))
+		httpResponse := newResponse(responseConnection, httpRequest)
+		requestCount++
+		keepAlive := httpRequest.IsKeepAlive() && (srv.Config.MaxRequestsPerConnection <= 0 || requestCount < srv.Config.MaxRequestsPerConnection) && !srv.isDraining.Load()
+		if keepAlive && strings.EqualFold(httpResponse.Version, "1.0") {
+			httpResponse.Headers.Add("Connection", "keep-alive")
+		} else if !keepAlive && !strings.EqualFold(httpResponse.Version, "0.9") {
+			httpResponse.Headers.Add("Connection", "close")
+		}
+
+		if keepAlive && !strings.EqualFold(httpResponse.Version, "0.9") {
+			httpResponse.onBeforeWrite(func(res *HttpResponse) {
+				// The server may have started draining its connections while the request was being processed.
+				if srv.isDraining.Load() {
+					res.Headers["Connection"] = []string{ "close" }
+					res.closeConnection = true
+				}
+			})
+		}
+
+		requestContext, cancelRequestContext := context.WithCancel(srv.baseContext)
+		httpRequest.ctx = requestContext
+		httpResponse.ctx = requestContext
+		httpResponse.writeTimeout = srv.Config.WriteTimeout
+		srv.assignRequestID(httpRequest, httpResponse)
+		stopWatching := watchConnection(ClientConnection, reader, cancelRequestContext)
+		httpRequest.stopWatching = stopWatching
+		srv.runRequestHooks(httpRequest, httpResponse, requestStartTime)
+		srv.processRequest(httpRequest, httpResponse)
+		err = httpResponse.end()
+		if cleanupErr := httpRequest.cleanupMultipartForm(); cleanupErr != nil {
+			srv.getRequestLogger(httpRequest).Error(cleanupErr.Error())
+		}
+		stopWatching()
+		cancelRequestContext()
+		ClientConnection.SetWriteDeadline(time.Time{})
+		srv.logAccess(httpRequest, httpResponse, requestStartTime)
+		srv.runResponseHooks(httpRequest, httpResponse, requestStartTime, err)
+		if err != nil {
+			srv.getRequestLogger(httpRequest).Error(err.Error())
+			return
+		}
+
+		srv.Log(httpRequest, httpResponse)
+		if !keepAlive || httpResponse.closeConnection {
+			return
+		}
+	}
+}
+
+// Handles the Expect header of the given request, whose head has been read. For a "100-continue" expectation, the ExpectContinue function of the server configuration (if any) decides if the request body
+// is accepted, in which case the 100 (Continue) interim response is sent so that the client starts sending the body. An error with the status to be sent back is returned if the request is rejected
+// or if the server does not support the expectation. The Expect header is ignored for HTTP/1.0 requests, as required by RFC 9110.
+func (srv *HttpServer) handleExpectation(ClientConnection net.Conn, httpRequest *HttpRequest) error {
+	expectation, found := httpRequest.Headers.Get("Expect")
+	if !found || !strings.EqualFold(httpRequest.Version, "1.1") {
+		return nil
+	}
+
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Value = expectation
+	if !strings.EqualFold(strings.TrimSpace(expectation), "100-continue") {
+		reqError.Message = "Expectation given in the Expect header is not supported by the server"
+		reqError.Status = StatusExpectationFailed
+		return reqError
+	}
+
+	if srv.Config.ExpectContinue != nil {
+		if status := srv.Config.ExpectContinue(httpRequest); status != StatusContinue {
+			reqError.Message = fmt.Sprintf("Request body has been rejected with status %d before it was sent", status)
+			reqError.Status = status
+			return reqError
+		}
+	}
+
+	if httpRequest.ContentLength == 0 && !httpRequest.isChunked {
+		// There is no request body to wait for, and hence the interim response is not needed.
+		return nil
+	}
+
+	ClientConnection.SetWriteDeadline(getDeadline(time.Now(), srv.Config.WriteTimeout))
+	_, err := ClientConnection.Write([]byte("HTTP/1.1 100 " + StatusContinue.GetStatusMessage() + HEADER_LINE_SEPERATOR + HEADER_LINE_SEPERATOR))
+	ClientConnection.SetWriteDeadline(time.Time{})
+	if err != nil {
+		reqError.Message = fmt.Sprintf("Error occurred while sending the 100 (Continue) response: %s", err.Error())
+		reqError.Status = 0
+		return reqError
+	}
+
+	return nil
+}
+
+// Watches the given client connection for disconnection while a request is being processed and invokes the given cancel function if the client disconnects.
+// It returns a function which must be invoked to stop watching the connection, before the next request is read from the connection.
+func watchConnection(ClientConnection net.Conn, reader *bufio.Reader, cancel context.CancelFunc) func() {
+	watchCompleted := make(chan struct{})
+	isStopped := new(atomic.Bool)
+	go func() {
+		defer close(watchCompleted)
+		_, err := reader.Peek(1)
+		if err != nil && !isStopped.Load() {
+			cancel()
+		}
+	}()
+
+	return func() {
+		isStopped.Store(true)
+		// Setting a read deadline in the past unblocks the pending read, so that the watcher goroutine can complete.
+		ClientConnection.SetReadDeadline(time.Unix(1, 0))
+		<-watchCompleted
+		ClientConnection.SetReadDeadline(time.Time{})
+	}
+}
+
+// Sends an error response with the given status back to the client for a request that could not be read completely or could not be processed. The client connection is not reused once the response is sent.
+// Additional response headers can be given as alternating name-value pairs.
+func (srv *HttpServer) rejectRequest(ClientConnection net.Conn, httpRequest *HttpRequest, status StatusCode, headers ...string) {
+	httpResponse := newResponse(newMinRateConn(ClientConnection, srv.Config.MinWriteRate, srv.Config.MinWriteRateGracePeriod), httpRequest)
+	srv.attachResponse(httpResponse)
+	if !strings.EqualFold(httpResponse.Version, "0.9") {
+		httpResponse.Headers.Add("Connection", "close")
+		for index := 0; index + 1 < len(headers); index += 2 {
+			httpResponse.Headers.Add(headers[index], headers[index + 1])
+		}
+	}
+
+	httpResponse.Status(status)
+	err := handleError(httpRequest, httpResponse)
 	if err != nil {
 		srv.LogError(err.Error())
 		return
 	}
 
-	httpResponse := newResponse(ClientConnection, httpRequest)
+	srv.Log(httpRequest, httpResponse)
+}
+
+// Reads and discards the rest of the body of a request rejected as too large, so that the client still sending the body receives the error response, instead of the connection being reset when it is closed
+// with unread data. Bodies larger than MAX_BODY_DRAIN_SIZE are not drained, nor are the bodies of requests expecting a 100 (Continue) response, since the client may not send them.
+func drainRequestBody(ClientConnection net.Conn, httpRequest *HttpRequest) {
+	drainSize := int64(MAX_BODY_DRAIN_SIZE)
+	if !httpRequest.isChunked {
+		if int64(httpRequest.ContentLength) > drainSize {
+			return
+		}
+		drainSize = int64(httpRequest.ContentLength)
+	}
+
+	if _, found := httpRequest.Headers.Get("Expect"); found || drainSize == 0 {
+		return
+	}
+
+	ClientConnection.SetReadDeadline(getDeadline(time.Now(), BODY_DRAIN_TIMEOUT))
+	io.CopyN(io.Discard, httpRequest.reader, drainSize)
+}
+
+// Assigns the settings of the web server instance used while creating a response (like the custom error handlers and the registered content types) to the given response.
+func (srv *HttpServer) attachResponse(httpResponse *HttpResponse) {
+	httpResponse.errorHandlers = srv.errorHandlers
+	httpResponse.templates = srv.templates
+	httpResponse.contentTypes = srv.contentTypes
+	httpResponse.defaultContentType = srv.defaultContentType
+	httpResponse.bufferSize = srv.Config.ResponseBufferSize
+	if srv.serverToken != nil {
+		httpResponse.setServerToken(*srv.serverToken)
+	}
+}
 
-	if !isMethodAllowed(httpResponse.Version, strings.ToUpper(strings.TrimSpace(httpRequest.Method))) {
+// Routes the given HTTP request to its matching handler and invokes the handler to create the response.
+func (srv *HttpServer) processRequest(httpRequest *HttpRequest, httpResponse *HttpResponse) {
+	httpRequest.config = srv.Config
+	srv.attachResponse(httpResponse)
+	httpResponse.request = httpRequest
+	if srv.autoTLS != nil && strings.HasPrefix(httpRequest.ResourcePath, ACME_CHALLENGE_PATH) {
+		err := srv.autoTLS.handleChallenge(httpRequest, httpResponse)
+		if err != nil {
+			srv.getRequestLogger(httpRequest).Error(err.Error())
+		}
+	} else if !srv.isHostAllowed(httpRequest) {
+		srv.getRequestLogger(httpRequest).Warn("Request rejected as its host is not allowed", "path", httpRequest.ResourcePath, "host", strings.Join(httpRequest.Headers["Host"], ","))
+		httpResponse.Status(StatusBadRequest)
+		err := handleError(httpRequest, httpResponse)
+		if err != nil {
+			srv.getRequestLogger(httpRequest).Error(err.Error())
+		}
+	} else if !srv.isMethodAllowed(httpResponse.Version, strings.ToUpper(strings.TrimSpace(httpRequest.Method))) {
 		httpResponse.Status(StatusMethodNotAllowed)
-		err = ErrorHandler(httpRequest, httpResponse)
+		httpResponse.Headers.Add("Allow", srv.getAllowedMethods(httpResponse.Version))
+		err := handleError(httpRequest, httpResponse)
 		if err != nil {
-			srv.LogError(err.Error())
+			srv.getRequestLogger(httpRequest).Error(err.Error())
 		}
+	} else if strings.EqualFold(httpRequest.Method, "CONNECT") && !strings.HasPrefix(httpRequest.ResourcePath, "/") {
+		srv.handleConnect(httpRequest, httpResponse)
+	} else if srv.corsConfig != nil && isPreflightRequest(httpRequest) && len(srv.innerRouter.getRouteMethods(httpRequest.ResourcePath)) > 0 {
+		srv.corsConfig.handlePreflight(httpRequest, httpResponse, srv.innerRouter.getRouteMethods(httpRequest.ResourcePath))
 	} else {
 		routeHandler, err := srv.innerRouter.matchRoute(httpRequest)
 		if err != nil {
-			srv.LogError(err.Error())
-			httpResponse.Status(StatusNotFound)
-			err = ErrorHandler(httpRequest, httpResponse)
-			if err != nil {
-				srv.LogError(err.Error())
-			}
+			srv.handleUnmatchedRequest(httpRequest, httpResponse, err)
 		} else {
-			err = routeHandler(httpRequest, httpResponse)
+			err = srv.invokeHandler(routeHandler, httpRequest, httpResponse)
 			if err != nil {
-				srv.LogError(err.Error())
+				srv.getRequestLogger(httpRequest).Error(err.Error())
 			}
 		}
 	}
+}
 
-	srv.Log(httpRequest, httpResponse)
+// Checks if the given HTTP method is supported by the web server instance for the given version, either as per the server configuration or as a method enabled using WebDAV().
+func (srv *HttpServer) isMethodAllowed(version string, requestMethod string) bool {
+	if isMethodAllowed(version, requestMethod) {
+		return true
+	}
+
+	return slices.Contains(srv.extensionMethods, requestMethod) && isMethodAllowed(version, "PUT")
+}
+
+// Gets the list of HTTP methods supported by the web server instance for the given HTTP version, including the methods enabled using WebDAV().
+func (srv *HttpServer) getAllowedMethods(version string) string {
+	allowedMethods := getAllowedMethods(version)
+	if !isMethodAllowed(version, "PUT") {
+		return allowedMethods
+	}
+
+	for _, method := range srv.extensionMethods {
+		if !isMethodAllowed(version, method) {
+			allowedMethods += ", " + method
+		}
+	}
+
+	return allowedMethods
+}
+
+// Enables the given HTTP methods for the web server instance, in addition to the methods allowed as per the server configuration.
+func (srv *HttpServer) enableMethods(methods ...string) {
+	for _, method := range methods {
+		if !slices.Contains(srv.extensionMethods, method) {
+			srv.extensionMethods = append(srv.extensionMethods, method)
+		}
+	}
+}
+
+// Checks if the Host header of the given request matches one of the hosts set using AllowedHosts(). All requests are allowed if no hosts have been set.
+func (srv *HttpServer) isHostAllowed(httpRequest *HttpRequest) bool {
+	if len(srv.allowedHosts) == 0 {
+		return true
+	}
+
+	hostValues := httpRequest.Headers["Host"]
+	if len(hostValues) != 1 {
+		return false
+	}
+
+	hostname := getHostname(hostValues[0])
+	for _, allowedHost := range srv.allowedHosts {
+		if domain, isWildcard := strings.CutPrefix(allowedHost, "*."); isWildcard && strings.HasSuffix(hostname, "." + domain) {
+			return true
+		} else if hostname == allowedHost {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Creates the response for a request for which no handler could be matched. If no route matches the request path, a 404 (Not Found) response is sent.
+// If a route matches the request path but not the request method, an OPTIONS request is answered with a 204 (No Content) response and any other request with a 405 (Method Not Allowed) response.
+// In both cases, the Allow header contains the methods for which the route has been defined. A TRACE request is answered with an echo of the request instead, if it has been enabled in the server settings.
+// A request matching a disabled route path is answered with the status set in the server settings, as if the route was not defined when the status is 404 (Not Found).
+func (srv *HttpServer) handleUnmatchedRequest(httpRequest *HttpRequest, httpResponse *HttpResponse, routingErr error) {
+	if srv.Config.DisabledRouteStatus != 0 && srv.Config.DisabledRouteStatus != StatusNotFound && srv.innerRouter.isRouteDisabled(httpRequest.ResourcePath) {
+		httpResponse.Status(srv.Config.DisabledRouteStatus)
+		err := handleError(httpRequest, httpResponse)
+		if err != nil {
+			srv.getRequestLogger(httpRequest).Error(err.Error())
+		}
+		return
+	}
+
+	if srv.Config.EnableTrace && strings.EqualFold(httpRequest.Method, "TRACE") {
+		srv.handleTrace(httpRequest, httpResponse)
+		return
+	}
+
+	if strings.EqualFold(httpRequest.Method, "OPTIONS") && strings.TrimSpace(httpRequest.ResourcePath) == "*" {
+		// An OPTIONS request for "*" refers to the server as a whole rather than a specific resource.
+		httpResponse.Status(StatusNoContent)
+		httpResponse.Headers.Add("Allow", srv.getAllowedMethods(httpResponse.Version))
+		return
+	}
+
+	routeMethods := srv.innerRouter.getRouteMethods(httpRequest.ResourcePath)
+	if len(routeMethods) == 0 {
+		srv.getRequestLogger(httpRequest).Error(routingErr.Error())
+		httpResponse.Status(StatusNotFound)
+		err := handleError(httpRequest, httpResponse)
+		if err != nil {
+			srv.getRequestLogger(httpRequest).Error(err.Error())
+		}
+		return
+	}
+
+	if !slices.Contains(routeMethods, "OPTIONS") {
+		routeMethods = append(routeMethods, "OPTIONS")
+	}
+
+	if srv.Config.EnableTrace && !slices.Contains(routeMethods, "TRACE") {
+		routeMethods = append(routeMethods, "TRACE")
+	}
+
+	httpResponse.Headers.Add("Allow", strings.Join(routeMethods, ", "))
+	if strings.EqualFold(httpRequest.Method, "OPTIONS") {
+		httpResponse.Status(StatusNoContent)
+		return
+	}
+
+	httpResponse.Status(StatusMethodNotAllowed)
+	err := handleError(httpRequest, httpResponse)
+	if err != nil {
+		srv.getRequestLogger(httpRequest).Error(err.Error())
+	}
 }
 
-// Creates a new GET endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Get(routePath string, handlerFunc Handler) error {
+// Invokes the given handler for the given request and recovers from any panic raised by the handler. An error returned by the handler before writing the response is sent as an error response.
+// When a panic is recovered, the stack trace is logged and the error response for a PanicError is sent back to the client, which is a 500 (Internal Server Error) response unless mapped otherwise using ErrorTransformer(). If the response has already been written (partially or completely), the client connection is closed instead.
+func (srv *HttpServer) invokeHandler(handler Handler, httpRequest *HttpRequest, httpResponse *HttpResponse) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		srv.getRequestLogger(httpRequest).Error("Panic occurred while processing the request", "path", httpRequest.ResourcePath, "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		if httpResponse.isWritten {
+			httpResponse.isAborted = true
+			httpResponse.closeConnection = true
+			return
+		}
+
+		// The panic has already been logged along with the stack trace, and hence only the error occurred while sending the error response is returned.
+		_, err = srv.sendHandlerError(httpRequest, httpResponse, &PanicError{ Value: recovered })
+	}()
+
+	err = handler(httpRequest, httpResponse)
+	if err != nil && !httpResponse.isWritten && !httpResponse.isStreaming {
+		httpError, writeErr := srv.sendHandlerError(httpRequest, httpResponse, err)
+		if writeErr != nil {
+			return writeErr
+		}
+
+		// Errors with a 4xx status code are caused by the client rather than by a fault of the server, and hence are not logged.
+		if httpError.Status < StatusInternalServerError {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Sends the error response for the error returned by a handler which has not written the response, using the error handler registered for its status code. The status code is taken from the error
+// if it is an HttpError (or wraps one), or else from the HttpError returned by the function set using ErrorTransformer(). A 500 (Internal Server Error) response is sent if neither gives a 4xx or 5xx status code.
+// It returns the HttpError for which the response has been sent, along with the error occurred while sending the response, if any.
+func (srv *HttpServer) sendHandlerError(httpRequest *HttpRequest, httpResponse *HttpResponse, err error) (*HttpError, error) {
+	var httpError *HttpError
+	if !errors.As(err, &httpError) && srv.errorTransformer != nil {
+		httpError = srv.errorTransformer(err)
+	}
+
+	if httpError == nil || httpError.Status < 400 || httpError.Status > 599 {
+		httpError = &HttpError{ Status: StatusInternalServerError, Err: err }
+	}
+
+	// The body and its headers set by the handler before returning the error are replaced by the error response.
+	httpResponse.Body = nil
+	delete(httpResponse.Headers, "Content-Type")
+	delete(httpResponse.Headers, "Content-Length")
+	httpResponse.Status(httpError.Status)
+	httpResponse.httpError = httpError
+	return httpError, handleError(httpRequest, httpResponse)
+}
+
+// Creates a new GET endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Get(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("GET", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("GET", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -122,10 +1137,10 @@ func (srv *HttpServer) Get(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new HEAD endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Head(routePath string, handlerFunc Handler) error {
+// Creates a new HEAD endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Head(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("HEAD", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("HEAD", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -133,10 +1148,10 @@ func (srv *HttpServer) Head(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new POST endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Post(routePath string, handlerFunc Handler) error {
+// Creates a new POST endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Post(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("POST", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("POST", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -144,10 +1159,10 @@ func (srv *HttpServer) Post(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new PUT endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Put(routePath string, handlerFunc Handler) error {
+// Creates a new PUT endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Put(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("PUT", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("PUT", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -155,10 +1170,10 @@ func (srv *HttpServer) Put(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new DELETE endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Delete(routePath string, handlerFunc Handler) error {
+// Creates a new DELETE endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Delete(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("DELETE", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("DELETE", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -166,10 +1181,10 @@ func (srv *HttpServer) Delete(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new TRACE endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Trace(routePath string, handlerFunc Handler) error {
+// Creates a new PATCH endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Patch(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("TRACE", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("PATCH", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -177,10 +1192,10 @@ func (srv *HttpServer) Trace(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new OPTIONS endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Options(routePath string, handlerFunc Handler) error {
+// Creates a new TRACE endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Trace(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("OPTIONS", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("TRACE", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -188,10 +1203,10 @@ func (srv *HttpServer) Options(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
-// Creates a new CONNECT endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user.
-func (srv *HttpServer) Connect(routePath string, handlerFunc Handler) error {
+// Creates a new OPTIONS endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Options(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
 	routePath = strings.TrimSpace(routePath)
-	err := srv.innerRouter.addDynamicRoute("CONNECT", routePath, handlerFunc)
+	err := srv.innerRouter.addDynamicRoute("OPTIONS", routePath, handlerFunc, middlewares...)
 	if err != nil {
 		return err
 	}
@@ -199,24 +1214,66 @@ func (srv *HttpServer) Connect(routePath string, handlerFunc Handler) error {
 	return nil
 }
 
+// Creates a new CONNECT endpoint at the given route path and sets the handler function to be invoked when the route is requested by the user. Middlewares given are executed only for this route.
+func (srv *HttpServer) Connect(routePath string, handlerFunc Handler, middlewares ...Middleware) error {
+	routePath = strings.TrimSpace(routePath)
+	err := srv.innerRouter.addDynamicRoute("CONNECT", routePath, handlerFunc, middlewares...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Replaces the logger used by the web server instance with the given logger.
+func (srv *HttpServer) SetLogger(eventLogger Logger) {
+	srv.eventLogger = eventLogger
+}
+
 // Logs the given message as an error in the server logs.
-func (srv *HttpServer) LogError(message string) {
+func (srv *HttpServer) LogError(message string, fields ...any) {
 	message = strings.TrimSpace(message)
-	srv.eventLogger.logError(message)
+	srv.eventLogger.Error(message, fields...)
+}
+
+// Logs the given message as a warning in the server logs.
+func (srv *HttpServer) LogWarn(message string, fields ...any) {
+	message = strings.TrimSpace(message)
+	srv.eventLogger.Warn(message, fields...)
 }
 
 // Logs the given message as an information in the server logs.
-func (srv *HttpServer) LogInfo(message string) {
+func (srv *HttpServer) LogInfo(message string, fields ...any) {
+	message = strings.TrimSpace(message)
+	srv.eventLogger.Info(message, fields...)
+}
+
+// Logs the given message as a debug message in the server logs.
+func (srv *HttpServer) LogDebug(message string, fields ...any) {
 	message = strings.TrimSpace(message)
-	srv.eventLogger.logInfo(message)
+	srv.eventLogger.Debug(message, fields...)
 }
 
-// Logs the status for a HTTP request to the server logger.
+// Logs the status for a HTTP request to the server logger. Requests resulting in client errors are logged as warnings and requests resulting in server errors are logged as errors.
 func (srv *HttpServer) Log(request *HttpRequest, response *HttpResponse) {
-	logMsg := fmt.Sprintf("  %s  %s  %s  HTTP/%s  %d  %s", request.ClientAddress, request.Method, request.ResourcePath, request.Version, response.StatusCode, response.StatusMessage)
-	if response.StatusCode < 400 {
-		srv.eventLogger.logInfo(logMsg)
+	fields := []any{ "client", request.ClientAddress, "method", request.Method, "path", request.ResourcePath, "version", "HTTP/" + request.Version, "status", response.StatusCode }
+	logMsg := fmt.Sprintf("%s %s %d %s", request.Method, request.ResourcePath, response.StatusCode, response.StatusMessage)
+	eventLogger := srv.getRequestLogger(request)
+	if response.StatusCode >= 500 {
+		eventLogger.Error(logMsg, fields...)
+	} else if response.StatusCode >= 400 {
+		eventLogger.Warn(logMsg, fields...)
 	} else {
-		srv.eventLogger.logError(logMsg)
+		eventLogger.Info(logMsg, fields...)
 	}
-}
\ No newline at end of file
+}
+
+// Returns the logger to be used for the log entries of the given request. It is the request logger, which adds the request ID to the log entries, if a request ID has been assigned to the request
+// or else the logger of the web server instance.
+func (srv *HttpServer) getRequestLogger(request *HttpRequest) Logger {
+	if request.logger == nil {
+		return srv.eventLogger
+	}
+
+	return request.logger
+}
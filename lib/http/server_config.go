@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Represents the action taken for a new client connection when the maximum number of concurrent connections has been reached.
+type ConnectionLimitPolicy string
+
+const (
+	// New connections are not accepted until one of the existing connections is closed. The pending connections wait in the backlog of the server socket.
+	QueueConnections ConnectionLimitPolicy = "queue"
+	// New connections are accepted and sent a 503 (Service Unavailable) response with the Retry-After header, before being closed. Once MAX_CONNECTION_REJECTERS connections are being rejected, new connections are closed without a response.
+	RejectConnections ConnectionLimitPolicy = "reject"
+)
+
+// Structure to hold the configurable settings of a web server instance. The settings are initialized from the server defaults when the server instance is created and can be modified before the server starts listening.
+type ServerConfig struct {
+	// Maximum duration allowed for reading an entire request, including the body. A zero value means that there is no timeout.
+	ReadTimeout time.Duration
+	// Maximum duration allowed for processing a request and writing its response, starting from the time the request has been read. A zero value means that there is no timeout.
+	WriteTimeout time.Duration
+	// Maximum duration to wait for the next request on a persistent connection. A zero value means that there is no timeout.
+	IdleTimeout time.Duration
+	// Maximum duration allowed for reading the request line and the request headers, starting from the time the first byte of the request is received. A zero value means that there is no timeout.
+	HeaderTimeout time.Duration
+	// Minimum rate (in bytes per second) at which a client must read the responses. The connection of a client reading slower (like in a slow-read attack) is closed, so that it cannot hold on to the resources
+	// of the server until the write timeout elapses. Every write to the connection is allowed the grace period, plus the time needed to send the bytes written at the minimum rate. A zero value disables the check.
+	MinWriteRate int64
+	// Duration allowed for every write to the connection of a client, in addition to the time needed to send the bytes written at the minimum write rate. For HTTP/2 connections, it is the maximum duration
+	// for which no bytes can be written to the client when the minimum write rate is enabled.
+	MinWriteRateGracePeriod time.Duration
+	// Maximum size (in bytes) of the response body written using WriteChunk() or Writer() that is buffered, before the response is switched to streaming mode without waiting for Flush().
+	// A zero value means that the response body is buffered until the handler calls Flush() or returns.
+	ResponseBufferSize int
+	// Boolean value to indicate if HTTP/2 is enabled. When enabled, HTTP/2 is negotiated using ALPN on TLS connections, while on cleartext connections it is used either with prior knowledge or by upgrading an HTTP/1.1 request (h2c).
+	HTTP2 bool
+	// Maximum number of client connections handled concurrently. A zero value means that the number of connections is not limited.
+	MaxConcurrentConnections int
+	// Action taken for a new client connection when the maximum number of concurrent connections has been reached.
+	ConnectionLimitPolicy ConnectionLimitPolicy
+	// Duration sent in the Retry-After header of the 503 (Service Unavailable) response, when a connection is rejected due to the connection limit.
+	ConnectionRetryAfter time.Duration
+	// Maximum number of HTTP/1.x requests served on a single persistent connection, after which the connection is closed. A zero value means that the number of requests is not limited.
+	MaxRequestsPerConnection int
+	// Maximum size (in bytes) of the request line and the request headers of a HTTP/1.x request. Requests exceeding the size are rejected with a 431 (Request Header Fields Too Large) response, or with a 414 (URI Too Long)
+	// response if the request line alone exceeds the size. A zero value means that the size is not limited.
+	MaxHeaderBytes int
+	// Maximum number of headers allowed in a HTTP/1.x request. Requests with more headers are rejected with a 431 (Request Header Fields Too Large) response. A zero value means that the number of headers is not limited.
+	MaxHeaderCount int
+	// Maximum length (in bytes) of the request target of a HTTP/1.x request. Requests with a longer target are rejected with a 414 (URI Too Long) response. A zero value means that the length is not limited.
+	MaxURILength int
+	// Optional function invoked for HTTP/1.1 requests sent with the "Expect: 100-continue" header, before the request body is read. It can inspect the request line and headers (like the
+	// Content-Length or the Authorization header) and returns StatusContinue to accept the request body, or an error status to reject the request without reading its body. If nil, the request body is always accepted.
+	ExpectContinue func(request *HttpRequest) StatusCode
+	// Boolean value to indicate if the routes defined in the web server instance must be logged when the server starts listening, which is useful to debug requests not matching the expected route.
+	PrintRoutes bool
+	// Boolean value to indicate if TRACE requests for which no route has been defined are answered by echoing the request received, as a "message/http" response. It is disabled by default,
+	// since the echo can expose the headers added by proxies along the request chain.
+	EnableTrace bool
+	// Status of the response sent for the requests matching a route path disabled using Disable(). The default status 404 (Not Found) responds as if the route was not defined, while a status
+	// like 503 (Service Unavailable) can be used to indicate that the route has been taken offline for maintenance.
+	DisabledRouteStatus StatusCode
+	// Network prefixes of the proxies (like load balancers) whose forwarding headers are trusted to contain the address of the client, as set using TrustedProxies(). The forwarding headers are ignored if it is empty.
+	TrustedProxies []netip.Prefix
+	// Boolean value to indicate if every connection accepted by the listen methods starts with a PROXY protocol (version 1 or 2) header, sent by a layer 4 load balancer to pass on the address of the client.
+	// Connections without a valid header are closed. It must be enabled only when all the connections are received through such a load balancer.
+	ProxyProtocol bool
+	// URL of the directory of the ACME server from which ListenAutoTLS() obtains the certificates. It can be changed to the staging directory of Let's Encrypt while testing, to avoid its rate limits.
+	ACMEDirectoryURL string
+	// Email address registered as the contact of the ACME account, to which the ACME server can send notices like certificate expiry warnings. No contact is registered if it is empty.
+	ACMEEmail string
+	// Duration before the expiry of a certificate obtained by ListenAutoTLS() at which it is renewed.
+	ACMERenewBefore time.Duration
+}
+
+// Returns the time after which reading the request headers, started at the given time, must time out. Both the read timeout and the header timeout are taken into account.
+func (cfg *ServerConfig) getHeaderDeadline(start time.Time) time.Time {
+	timeout := cfg.HeaderTimeout
+	if cfg.ReadTimeout > 0 && (timeout == 0 || cfg.ReadTimeout < timeout) {
+		timeout = cfg.ReadTimeout
+	}
+
+	return getDeadline(start, timeout)
+}
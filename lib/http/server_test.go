@@ -0,0 +1,1033 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test case to validate if a panic raised by a route handler is recovered and converted into an error response.
+func Test_Server_InvokeHandlerRecovery(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testCases := []struct {
+		Name string
+		StreamBeforePanic bool
+		ExpStatus int
+		ExpAborted bool
+	} {
+		{ "Panic before the response is written", false, int(StatusInternalServerError), false },
+		{ "Panic after the response has started streaming", true, int(StatusOK), true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.ResourcePath = "/panic"
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			handler := func(req *HttpRequest, res *HttpResponse) error {
+				if testCase.StreamBeforePanic {
+					res.WriteChunk([]byte("partial"))
+					res.Flush()
+				}
+				panic("handler failure")
+			}
+
+			testServer.invokeHandler(handler, testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else {
+				tt.Logf("The response status [%d] matches the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			}
+
+			if testResponse.isAborted != testCase.ExpAborted {
+				tt.Errorf("Expected the response aborted flag to be %t, but got %t", testCase.ExpAborted, testResponse.isAborted)
+			}
+
+			if !testCase.ExpAborted && !strings.HasPrefix(opBuffer.String(), "HTTP/1.1 500") {
+				tt.Errorf("Expected a 500 response to be written, but got [%s] instead", opBuffer.String())
+			}
+		})
+	}
+}
+
+// Test case to validate the custom error handlers registered using NotFound() and OnError(), with the default error handler used as fallback.
+func Test_Server_ErrorHandlers(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.NotFound(func(req *HttpRequest, res *HttpResponse) error {
+		return res.JSON(StatusNotFound, map[string]string{ "error": "not found" })
+	})
+	testServer.OnError(StatusInternalServerError, func(req *HttpRequest, res *HttpResponse) error {
+		res.Headers.Add("Content-Type", "text/html")
+		res.Body = []byte("<h1>Something went wrong</h1>")
+		return nil
+	})
+	testServer.Get("/panic", func(req *HttpRequest, res *HttpResponse) error {
+		panic("handler failure")
+	})
+	testServer.Get("/users/:id", func(req *HttpRequest, res *HttpResponse) error {
+		return res.NotFound()
+	})
+	testServer.Get("/failure", func(req *HttpRequest, res *HttpResponse) error {
+		return res.InternalError(errors.New("database is unreachable"))
+	})
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "Custom not found handler", "GET", "/missing", int(StatusNotFound), `{"error":"not found"}` },
+		{ "Custom internal server error handler", "GET", "/panic", int(StatusInternalServerError), "<h1>Something went wrong</h1>" },
+		{ "Custom not found handler used by NotFound()", "GET", "/users/42", int(StatusNotFound), `{"error":"not found"}` },
+		{ "Custom internal server error handler used by InternalError()", "GET", "/failure", int(StatusInternalServerError), "<h1>Something went wrong</h1>" },
+		{ "Default handler for method not allowed", "PROPFIND", "/panic", int(StatusMethodNotAllowed), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body [%s] to contain [%s]", string(testResponse.Body), testCase.ExpBody)
+			} else {
+				tt.Logf("The response status [%d] and body match the expected values", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate the error responses sent for the errors returned by the route handlers, including the errors logged by the server and the message shown by a custom error handler.
+func Test_Server_HandlerErrors(t *testing.T) {
+	var logBuffer lockedBuffer
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(&logBuffer, LevelDebug, TextLogFormat))
+	testServer.OnError(StatusConflict, func(req *HttpRequest, res *HttpResponse) error {
+		return res.JSON(StatusConflict, map[string]string{ "error": res.HttpError().Message })
+	})
+	testServer.Get("/users/:id", func(req *HttpRequest, res *HttpResponse) error {
+		res.Headers.Add("Content-Type", JSON_CONTENT_TYPE)
+		return NewError(StatusNotFound, "user <42> not found")
+	})
+	testServer.Post("/users", func(req *HttpRequest, res *HttpResponse) error {
+		return fmt.Errorf("creating the user failed: %w", NewError(StatusConflict, "user already exists"))
+	})
+	testServer.Get("/reports", func(req *HttpRequest, res *HttpResponse) error {
+		return errors.New("database is unreachable")
+	})
+	testServer.Get("/invalid", func(req *HttpRequest, res *HttpResponse) error {
+		return &HttpError{ Status: StatusOK, Message: "not an error status" }
+	})
+	testServer.Get("/written", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Headers.Add("Content-Type", "text/plain")
+		res.Body = []byte("sent")
+		if err := res.write(); err != nil {
+			return err
+		}
+		return errors.New("cleanup failed after the response was sent")
+	})
+
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		ExpStatus int
+		ExpBody string
+		ExpLog string
+	} {
+		{ "HttpError with a client error status code", "GET", "/users/42", int(StatusNotFound), "user &lt;42&gt; not found", "" },
+		{ "Wrapped HttpError sent by a custom error handler", "POST", "/users", int(StatusConflict), `{"error":"user already exists"}`, "" },
+		{ "Error which is not an HttpError", "GET", "/reports", int(StatusInternalServerError), StatusInternalServerError.GetErrorContent(), "database is unreachable" },
+		{ "HttpError without an error status code", "GET", "/invalid", int(StatusInternalServerError), StatusInternalServerError.GetErrorContent(), "not an error status" },
+		{ "Error returned after the response has been written", "GET", "/written", int(StatusOK), "sent", "cleanup failed" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			logStart := len(logBuffer.String())
+			testServer.processRequest(testRequest, testResponse)
+			logged := logBuffer.String()[logStart:]
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body [%s] to contain [%s]", string(testResponse.Body), testCase.ExpBody)
+			} else if contentType, _ := testResponse.Headers.Get("Content-Type"); len(testResponse.Headers["Content-Type"]) != 1 {
+				tt.Errorf("Expected a single Content-Type header, but got %v instead", testResponse.Headers["Content-Type"])
+			} else if (testCase.ExpLog == "" && logged != "") || !strings.Contains(logged, testCase.ExpLog) {
+				tt.Errorf("Expected the log entries [%s] to contain [%s]", logged, testCase.ExpLog)
+			} else {
+				tt.Logf("The response status [%d] with the content type [%s] and body match the expected values", testResponse.StatusCode, contentType)
+			}
+		})
+	}
+}
+
+// Test case to validate the mapping of the errors returned by the handlers and the panics raised by them to the error responses, using the function set using ErrorTransformer().
+func Test_Server_ErrorTransformer(t *testing.T) {
+	errNoRows := errors.New("no rows in result set")
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.ErrorTransformer(func(err error) *HttpError {
+		var parseErr *strconv.NumError
+		if errors.Is(err, errNoRows) {
+			return &HttpError{ Status: StatusNotFound, Message: "record not found", Err: err }
+		} else if errors.As(err, &parseErr) {
+			return &HttpError{ Status: StatusUnprocessableContent, Message: "invalid number " + parseErr.Num, Err: err }
+		}
+		return nil
+	})
+
+	testCases := []struct {
+		Name string
+		Handler Handler
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "Sentinel error mapped to a status code", func(req *HttpRequest, res *HttpResponse) error { return fmt.Errorf("loading the order: %w", errNoRows) }, int(StatusNotFound), "record not found" },
+		{ "Error type mapped to a status code", func(req *HttpRequest, res *HttpResponse) error { _, err := strconv.Atoi("4x2"); return err }, int(StatusUnprocessableContent), "invalid number 4x2" },
+		{ "HttpError sent without being transformed", func(req *HttpRequest, res *HttpResponse) error { return NewError(StatusConflict, "order already exists") }, int(StatusConflict), "order already exists" },
+		{ "Error which is not mapped", func(req *HttpRequest, res *HttpResponse) error { return errors.New("disk is full") }, int(StatusInternalServerError), StatusInternalServerError.GetErrorContent() },
+		{ "Panic with a mapped error", func(req *HttpRequest, res *HttpResponse) error { panic(errNoRows) }, int(StatusNotFound), "record not found" },
+		{ "Panic with a value which is not mapped", func(req *HttpRequest, res *HttpResponse) error { panic("handler failure") }, int(StatusInternalServerError), StatusInternalServerError.GetErrorContent() },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.ResourcePath = "/orders/42"
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.attachResponse(testResponse)
+			testServer.invokeHandler(testCase.Handler, testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body [%s] to contain [%s]", string(testResponse.Body), testCase.ExpBody)
+			} else {
+				tt.Logf("The response status [%d] and body match the expected values", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate the automatic responses sent for OPTIONS requests and for requests made with a method not defined for the matched route.
+func Test_Server_AutomaticOptions(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Get("/users/:id", func(req *HttpRequest, res *HttpResponse) error { return nil })
+	testServer.Post("/users/:id", func(req *HttpRequest, res *HttpResponse) error { return nil })
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		ExpStatus int
+		ExpAllow string
+	} {
+		{ "OPTIONS request for a defined route", "OPTIONS", "/users/10", int(StatusNoContent), "GET, POST, HEAD, OPTIONS" },
+		{ "Method not defined for the route", "DELETE", "/users/10", int(StatusMethodNotAllowed), "GET, POST, HEAD, OPTIONS" },
+		{ "OPTIONS request for the server", "OPTIONS", "*", int(StatusNoContent), getAllowedMethods("1.1") },
+		{ "Request for an undefined route", "DELETE", "/orders", int(StatusNotFound), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			allow, _ := testResponse.Headers.Get("Allow")
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if allow != testCase.ExpAllow {
+				tt.Errorf("The Allow header [%s] does not match the expected value [%s]", allow, testCase.ExpAllow)
+			} else {
+				tt.Logf("The response status [%d] and the Allow header [%s] match the expected values", testResponse.StatusCode, allow)
+			}
+		})
+	}
+}
+
+// Test case to validate that only the requests whose Host header matches one of the allowed hosts are processed.
+func Test_Server_AllowedHosts(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.AllowedHosts([]string{ "example.com", "*.example.org", "[::1]" })
+	testServer.Get("/hosts", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return nil
+	})
+	testCases := []struct {
+		Name string
+		Host string
+		ExpStatus int
+	} {
+		{ "Allowed host", "example.com", int(StatusOK) },
+		{ "Allowed host with a port number", "EXAMPLE.com:8080", int(StatusOK) },
+		{ "Subdomain of a wildcard host", "api.example.org", int(StatusOK) },
+		{ "Domain of a wildcard host", "example.org", int(StatusBadRequest) },
+		{ "Allowed IPv6 address", "[::1]:8080", int(StatusOK) },
+		{ "Host that is not allowed", "attacker.com", int(StatusBadRequest) },
+		{ "Host ending with an allowed host", "attackerexample.com", int(StatusBadRequest) },
+		{ "Request without a Host header", "", int(StatusBadRequest) },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/hosts"
+			if testCase.Host != "" {
+				testRequest.Headers.Add("Host", testCase.Host)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else {
+				tt.Logf("The response status [%d] matches the expected status", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate that the listen methods return an error when the server socket cannot be created, and that the asynchronous listener accepts requests without blocking.
+func Test_Server_ListenErrors(t *testing.T) {
+	firstServer := NewServer()
+	firstServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	firstServer.Get("/ping", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return nil
+	})
+	var readyAddress net.Addr
+	firstServer.OnReady(func(Address net.Addr) {
+		readyAddress = Address
+	})
+	err := firstServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer firstServer.Shutdown()
+
+	serverAddress := firstServer.Addr()
+	if serverAddress == nil || readyAddress == nil || serverAddress.String() != readyAddress.String() || firstServer.PortNumber == 0 {
+		t.Fatalf("Expected the ready callback and Addr() to return the bound address, but got [%v] and [%v]", readyAddress, serverAddress)
+	}
+
+	connection, err := net.Dial("tcp", serverAddress.String())
+	if err != nil {
+		t.Fatalf("Error occurred while connecting to the asynchronous listener - %v", err)
+	}
+	connection.Write([]byte("GET /ping HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"))
+	statusLine, _ := bufio.NewReader(connection).ReadString('\n')
+	connection.Close()
+	if !strings.HasPrefix(statusLine, "HTTP/1.1 200") {
+		t.Errorf("Expected a 200 (OK) response from the asynchronous listener, but got [%s]", statusLine)
+	}
+
+	secondServer := NewServer()
+	secondServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testCases := []struct {
+		Name string
+		Listen func() error
+	} {
+		{ "Listen on a port already in use", func() error { return secondServer.Listen(firstServer.PortNumber, "127.0.0.1") } },
+		{ "Asynchronous listen on a port already in use", func() error { return secondServer.ListenAndServeAsync(firstServer.PortNumber, "127.0.0.1") } },
+		{ "TLS listen without a certificate", func() error { return secondServer.ListenTLS(0, "127.0.0.1", "", "") } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			err := testCase.Listen()
+			if _, ok := err.(*ListenError); !ok {
+				tt.Errorf("Expected a ListenError, but got this instead - %v", err)
+			} else {
+				tt.Logf("Received the expected error - %v", err)
+			}
+		})
+	}
+}
+
+// Test case to validate that the web server instance accepts requests on a Unix domain socket and on a listener created by the caller.
+func Test_Server_CustomListeners(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "proteus.sock")
+	testCases := []struct {
+		Name string
+		Network string
+		Address string
+		Listen func(srv *HttpServer) error
+	} {
+		{ "Unix domain socket", "unix", socketPath, func(srv *HttpServer) error { return srv.ListenUnix(socketPath) } },
+		{ "Listener created by the caller", "tcp", "", func(srv *HttpServer) error {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return err
+			}
+			return srv.Serve(listener)
+		} },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testServer := NewServer()
+			testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+			testServer.Get("/ping", func(req *HttpRequest, res *HttpResponse) error {
+				res.Status(StatusOK)
+				return nil
+			})
+			readyAddress := make(chan net.Addr, 1)
+			testServer.OnReady(func(Address net.Addr) {
+				readyAddress <- Address
+			})
+			listenCompleted := make(chan error, 1)
+			go func() {
+				listenCompleted <- testCase.Listen(testServer)
+			}()
+
+			var serverAddress net.Addr
+			select {
+			case serverAddress = <-readyAddress:
+			case err := <-listenCompleted:
+				tt.Fatalf("Was not expecting an error and yet received one - %v", err)
+			}
+
+			connection, err := net.Dial(testCase.Network, serverAddress.String())
+			if err != nil {
+				tt.Fatalf("Error occurred while connecting to the server - %v", err)
+			}
+			connection.Write([]byte("GET /ping HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"))
+			statusLine, _ := bufio.NewReader(connection).ReadString('\n')
+			connection.Close()
+			testServer.Shutdown()
+			err = <-listenCompleted
+			if !strings.HasPrefix(statusLine, "HTTP/1.1 200") || err != nil {
+				tt.Errorf("Expected a 200 (OK) response and a clean shutdown, but got [%s] and [%v]", statusLine, err)
+			} else {
+				tt.Logf("Received the expected response [%s]", strings.TrimSpace(statusLine))
+			}
+		})
+	}
+}
+
+// Test case to validate the limits on the number of concurrent connections and on the number of requests served on a single connection.
+func Test_Server_ConnectionLimits(t *testing.T) {
+	pingRequest := "GET /ping HTTP/1.1\r\nHost: localhost\r\n\r\n"
+	newLimitedServer := func(tt *testing.T, policy ConnectionLimitPolicy) *HttpServer {
+		testServer := NewServer()
+		testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+		testServer.Config.MaxConcurrentConnections = 1
+		testServer.Config.ConnectionLimitPolicy = policy
+		testServer.Config.ConnectionRetryAfter = 2 * time.Second
+		testServer.Config.MaxRequestsPerConnection = 2
+		testServer.Get("/ping", func(req *HttpRequest, res *HttpResponse) error {
+			res.Status(StatusOK)
+			return nil
+		})
+		err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+		if err != nil {
+			tt.Fatalf("Was not expecting an error and yet received one - %v", err)
+		}
+		tt.Cleanup(func() { testServer.Shutdown() })
+		return testServer
+	}
+
+	// Sends the ping request on the given connection and returns the response head.
+	sendPing := func(connection net.Conn, reader *bufio.Reader) string {
+		connection.SetDeadline(time.Now().Add(2 * time.Second))
+		connection.Write([]byte(pingRequest))
+		var head strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			head.WriteString(line)
+			if err != nil || line == "\r\n" {
+				return head.String()
+			}
+		}
+	}
+
+	t.Run("Connection rejected when the limit is reached", func(tt *testing.T) {
+		testServer := newLimitedServer(tt, RejectConnections)
+		firstConnection, _ := net.Dial("tcp", testServer.Addr().String())
+		defer firstConnection.Close()
+		sendPing(firstConnection, bufio.NewReader(firstConnection))
+		secondConnection, _ := net.Dial("tcp", testServer.Addr().String())
+		defer secondConnection.Close()
+		response := sendPing(secondConnection, bufio.NewReader(secondConnection))
+		if !strings.HasPrefix(response, "HTTP/1.1 503") || !strings.Contains(response, "Retry-After: 2") {
+			tt.Errorf("Expected a 503 (Service Unavailable) response with Retry-After, but got [%s]", response)
+		}
+	})
+
+	t.Run("Excess connections closed once the rejecters are busy", func(tt *testing.T) {
+		testServer := newLimitedServer(tt, RejectConnections)
+		firstConnection, _ := net.Dial("tcp", testServer.Addr().String())
+		defer firstConnection.Close()
+		sendPing(firstConnection, bufio.NewReader(firstConnection))
+		// The excess connections never send a request, so that each rejecter waits for the request head until the header timeout elapses.
+		excessCount := MAX_CONNECTION_REJECTERS + 40
+		connections := make([]net.Conn, 0, excessCount)
+		for index := 0; index < excessCount; index++ {
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while opening an excess connection, but got this instead - %v", err)
+			}
+			defer connection.Close()
+			connections = append(connections, connection)
+		}
+
+		var closedCount atomic.Int64
+		var waitGroup sync.WaitGroup
+		for _, connection := range connections {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				connection.SetReadDeadline(time.Now().Add(time.Second))
+				if _, err := connection.Read(make([]byte, 1)); err == io.EOF {
+					closedCount.Add(1)
+				}
+			}()
+		}
+
+		waitGroup.Wait()
+		if closedCount.Load() < int64(excessCount - MAX_CONNECTION_REJECTERS) {
+			tt.Errorf("Expected at least %d of the %d excess connections to be closed right away, but only %d were closed", excessCount - MAX_CONNECTION_REJECTERS, excessCount, closedCount.Load())
+		} else {
+			tt.Logf("%d of the %d excess connections were closed right away as expected", closedCount.Load(), excessCount)
+		}
+	})
+
+	t.Run("Connection queued until a slot is free", func(tt *testing.T) {
+		testServer := newLimitedServer(tt, QueueConnections)
+		firstConnection, _ := net.Dial("tcp", testServer.Addr().String())
+		sendPing(firstConnection, bufio.NewReader(firstConnection))
+		secondConnection, _ := net.Dial("tcp", testServer.Addr().String())
+		defer secondConnection.Close()
+		secondReader := bufio.NewReader(secondConnection)
+		secondConnection.Write([]byte(pingRequest))
+		secondConnection.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		_, err := secondReader.ReadByte()
+		if err == nil {
+			tt.Errorf("Expected the second connection to be queued, but it received a response")
+		}
+
+		firstConnection.Close()
+		secondConnection.SetReadDeadline(time.Now().Add(2 * time.Second))
+		statusLine, _ := secondReader.ReadString('\n')
+		if !strings.HasPrefix(statusLine, "HTTP/1.1 200") {
+			tt.Errorf("Expected the queued connection to be served once a slot is free, but got [%s]", statusLine)
+		}
+	})
+
+	t.Run("Connection closed after the maximum number of requests", func(tt *testing.T) {
+		testServer := newLimitedServer(tt, RejectConnections)
+		connection, _ := net.Dial("tcp", testServer.Addr().String())
+		defer connection.Close()
+		reader := bufio.NewReader(connection)
+		firstResponse := sendPing(connection, reader)
+		secondResponse := sendPing(connection, reader)
+		if strings.Contains(firstResponse, "Connection: close") || !strings.Contains(secondResponse, "Connection: close") {
+			tt.Errorf("Expected only the second response to close the connection, but got [%s] and [%s]", firstResponse, secondResponse)
+		}
+	})
+}
+
+// Test case to validate the handling of the Expect request header, with the request body accepted or rejected before it is sent.
+func Test_Server_ExpectContinue(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Config.ExpectContinue = func(request *HttpRequest) StatusCode {
+		if _, found := request.Headers.Get("Authorization"); !found {
+			return StatusUnauthorized
+		}
+		return StatusContinue
+	}
+	testServer.Post("/upload", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Body = req.Body
+		return nil
+	})
+	err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	testCases := []struct {
+		Name string
+		Headers string
+		ExpInterimResponse bool
+		ExpStatusLine string
+	} {
+		{ "Request body accepted", "Expect: 100-continue\r\nAuthorization: Bearer token\r\n", true, "HTTP/1.1 200 OK" },
+		{ "Request body rejected by the ExpectContinue function", "Expect: 100-continue\r\n", false, "HTTP/1.1 401 Unauthorized" },
+		{ "Unsupported expectation", "Expect: 200-ok\r\nAuthorization: Bearer token\r\n", false, "HTTP/1.1 417 Expectation Failed" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Error occurred while connecting to the server - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(2 * time.Second))
+			reader := bufio.NewReader(connection)
+			connection.Write([]byte("POST /upload HTTP/1.1\r\nHost: localhost\r\nContent-Length: 5\r\n" + testCase.Headers + "\r\n"))
+			statusLine, _ := reader.ReadString('\n')
+			if testCase.ExpInterimResponse {
+				if strings.TrimSpace(statusLine) != "HTTP/1.1 100 Continue" {
+					tt.Fatalf("Expected the 100 (Continue) interim response, but got [%s]", statusLine)
+				}
+				reader.ReadString('\n')
+				connection.Write([]byte("hello"))
+				statusLine, _ = reader.ReadString('\n')
+			}
+
+			if strings.TrimSpace(statusLine) != testCase.ExpStatusLine {
+				tt.Errorf("Expected the status line [%s], but got [%s]", testCase.ExpStatusLine, statusLine)
+			} else {
+				tt.Logf("Received the expected status line [%s]", testCase.ExpStatusLine)
+			}
+		})
+	}
+}
+
+// Test case to validate that the maximum body size set for a route replaces the server default, both for request bodies with a Content-Length and for chunked request bodies.
+func Test_Server_RouteMaxBodySize(t *testing.T) {
+	originalMaxBodySize := ServerDefaults["max_body_size"]
+	ServerDefaults["max_body_size"] = "16"
+	defer func() {
+		ServerDefaults["max_body_size"] = originalMaxBodySize
+	}()
+
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	echoHandler := func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Body = req.Body
+		return nil
+	}
+	testServer.Post("/upload", echoHandler)
+	if err := testServer.MaxBodySize(64); err != nil {
+		t.Fatalf("Was not expecting an error while setting the maximum body size, but got this instead - %v", err)
+	}
+	testServer.Post("/avatar/:id", echoHandler)
+	testServer.MaxBodySize(8)
+	testServer.Post("/comments", echoHandler)
+	if err := testServer.MaxBodySize(0); err == nil {
+		t.Errorf("Expected an error while setting a maximum body size of zero, but got nil")
+	}
+	err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	largeBody := strings.Repeat("a", 40)
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Headers string
+		Body string
+		ExpStatusLine string
+	} {
+		{ "Body larger than the server default sent to a route with a larger limit", "/upload", "Content-Length: 40\r\n", largeBody, "HTTP/1.1 200 OK" },
+		{ "Body larger than the server default sent to a route without a limit", "/comments", "Content-Length: 40\r\n", largeBody, "HTTP/1.1 413 Content Too Large" },
+		{ "Body smaller than the server default sent to a route with a smaller limit", "/avatar/42", "Content-Length: 10\r\n", "aaaaaaaaaa", "HTTP/1.1 413 Content Too Large" },
+		{ "Chunked body within the limit of the route", "/upload", "Transfer-Encoding: chunked\r\n", "28\r\n" + largeBody + "\r\n0\r\n\r\n", "HTTP/1.1 200 OK" },
+		{ "Chunked body exceeding the limit of the route", "/avatar/42", "Transfer-Encoding: chunked\r\n", "a\r\naaaaaaaaaa\r\n0\r\n\r\n", "HTTP/1.1 413 Content Too Large" },
+		{ "Body exceeding the limit of the route rejected before the 100 (Continue) response", "/avatar/42", "Content-Length: 40\r\nExpect: 100-continue\r\n", "", "HTTP/1.1 413 Content Too Large" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Error occurred while connecting to the server - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(3 * time.Second))
+			connection.Write([]byte("POST " + testCase.ResourcePath + " HTTP/1.1\r\nHost: localhost\r\n" + testCase.Headers + "\r\n" + testCase.Body))
+			statusLine, _ := bufio.NewReader(connection).ReadString('\n')
+			if strings.TrimSpace(statusLine) != testCase.ExpStatusLine {
+				tt.Errorf("Expected the status line [%s], but got [%s]", testCase.ExpStatusLine, statusLine)
+			} else {
+				tt.Logf("Received the expected status line [%s]", testCase.ExpStatusLine)
+			}
+		})
+	}
+}
+
+// Test case to validate that the connection is closed after a request whose body length is ambiguous, so that the request smuggled in its body is never processed.
+func Test_Server_SmuggledRequest(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	var isSmuggled atomic.Bool
+	testServer.Post("/", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return nil
+	})
+	testServer.Get("/admin", func(req *HttpRequest, res *HttpResponse) error {
+		isSmuggled.Store(true)
+		res.Status(StatusOK)
+		return nil
+	})
+	err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	smuggledRequest := "GET /admin HTTP/1.1\r\nHost: localhost\r\n\r\n"
+	testCases := []struct {
+		Name string
+		Payload string
+	} {
+		{ "Content-Length followed by Transfer-Encoding", "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 5\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n" + smuggledRequest },
+		{ "Duplicate Content-Length headers", "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 0\r\nContent-Length: 41\r\n\r\n" + smuggledRequest },
+		{ "Content-Length folded across lines", "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 0\r\n 41\r\n\r\n" + smuggledRequest },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Error occurred while connecting to the server - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(3 * time.Second))
+			connection.Write([]byte(testCase.Payload))
+			response, _ := io.ReadAll(connection)
+			statusCount := strings.Count(string(response), "HTTP/1.1 ")
+			if !strings.HasPrefix(string(response), "HTTP/1.1 400") || statusCount != 1 || isSmuggled.Load() {
+				tt.Errorf("Expected a single 400 (Bad Request) response without the smuggled request being processed, but got %d responses [%s]", statusCount, string(response))
+			} else {
+				tt.Logf("Received a single 400 (Bad Request) response and the connection was closed as expected")
+			}
+		})
+	}
+}
+
+// Test case to validate that the connection of a client reading the response slower than the minimum write rate is closed, while a client reading at a faster rate receives the complete response.
+func Test_Server_MinWriteRate(t *testing.T) {
+	logBuffer := new(lockedBuffer)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(logBuffer, LevelDebug, TextLogFormat))
+	testServer.Config.MinWriteRate = 16 << 20
+	testServer.Config.MinWriteRateGracePeriod = 500 * time.Millisecond
+	responseBody := bytes.Repeat([]byte("a"), 32 << 20)
+	testServer.Get("/download", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		res.Body = responseBody
+		return nil
+	})
+	err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	testCases := []struct {
+		Name string
+		ReadDelay time.Duration
+		ExpComplete bool
+	} {
+		{ "Client reading the response at once", 0, true },
+		{ "Client not reading the response in time", 1500 * time.Millisecond, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			connection, err := net.Dial("tcp", testServer.Addr().String())
+			if err != nil {
+				tt.Fatalf("Error occurred while connecting to the server - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(10 * time.Second))
+			connection.Write([]byte("GET /download HTTP/1.1\r\nHost: localhost\r\nAccept-Encoding: identity\r\nConnection: close\r\n\r\n"))
+			time.Sleep(testCase.ReadDelay)
+			received, _ := io.Copy(io.Discard, connection)
+			isComplete := received > int64(len(responseBody))
+			if isComplete != testCase.ExpComplete {
+				tt.Errorf("Expected the complete response to be received [%t], but received %d bytes", testCase.ExpComplete, received)
+			} else {
+				tt.Logf("Received %d bytes as expected", received)
+			}
+		})
+	}
+
+	if !strings.Contains(logBuffer.String(), "slower than the minimum write rate") {
+		t.Errorf("Expected the slow client to be logged, but got the logs [%s]", logBuffer.String())
+	}
+}
+
+// Test case to validate the Server header sent with the token set using SetServerToken(), including the omission of the header for an empty token.
+func Test_Server_SetServerToken(t *testing.T) {
+	testCases := []struct {
+		Name string
+		SetToken bool
+		Token string
+		ExpErr bool
+		ExpServer []string
+	} {
+		{ "Token taken from the server default", false, "", false, []string{ getServerDefaults("server_name") } },
+		{ "Custom token with a version", true, "acme/2.0", false, []string{ "acme/2.0" } },
+		{ "Empty token omitting the Server header", true, "", false, nil },
+		{ "Token with a line break", true, "acme\r\nX-Injected: true", true, []string{ getServerDefaults("server_name") } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testServer := NewServer()
+			testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+			testServer.Get("/", func(req *HttpRequest, res *HttpResponse) error {
+				res.Status(StatusOK)
+				return nil
+			})
+			if testCase.SetToken {
+				err := testServer.SetServerToken(testCase.Token)
+				if (err != nil) != testCase.ExpErr {
+					tt.Fatalf("Expected an error while setting the server token [%t], but got this instead - %v", testCase.ExpErr, err)
+				}
+			}
+
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/"
+			testResponse := new(HttpResponse)
+			testResponse.initialize("1.1", false)
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if !slices.Equal(testResponse.Headers["Server"], testCase.ExpServer) {
+				tt.Errorf("Expected the Server header to be %v, but got %v instead", testCase.ExpServer, testResponse.Headers["Server"])
+			} else {
+				tt.Logf("Server header is %v as expected", testResponse.Headers["Server"])
+			}
+		})
+	}
+}
+
+// Handler used to validate the name of the handler reported for a route.
+func routeInfoTestHandler(req *HttpRequest, res *HttpResponse) error {
+	res.Status(StatusOK)
+	return nil
+}
+
+// Test case to validate the details of the routes returned by Routes() and the logging of the routes when the server starts listening.
+func Test_Server_Routes(t *testing.T) {
+	testServer := NewServer()
+	var logBuffer bytes.Buffer
+	testServer.SetLogger(NewLogger(&logBuffer, LevelDebug, TextLogFormat))
+	testServer.Config.PrintRoutes = true
+	testServer.Get("/users/:id|int", routeInfoTestHandler)
+	testServer.Name("user.show")
+	testServer.Group("/api", func(next Handler) Handler { return next }).Post("/orders/", routeInfoTestHandler)
+	testServer.Static("/files", t.TempDir())
+	expectedRoutes := []RouteInfo{
+		{ Method: "GET", Pattern: "/users/:id|int", IsStatic: false, HandlerName: "github.com/mkbworks/proteus/lib/http.routeInfoTestHandler", Name: "user.show" },
+		{ Method: "POST", Pattern: "/api/orders/", IsStatic: false, HandlerName: "github.com/mkbworks/proteus/lib/http.routeInfoTestHandler", Name: "" },
+		{ Method: "GET", Pattern: "/files", IsStatic: true, HandlerName: "StaticFileHandler", Name: "" },
+		{ Method: "HEAD", Pattern: "/files", IsStatic: true, HandlerName: "StaticFileHandler", Name: "" },
+	}
+
+	routes := testServer.Routes()
+	if !slices.Equal(routes, expectedRoutes) {
+		t.Errorf("Expected the routes to be %v, but got %v", expectedRoutes, routes)
+	} else {
+		t.Logf("The routes returned match the expected routes")
+	}
+
+	isReady := make(chan struct{})
+	testServer.OnReady(func(Address net.Addr) {
+		close(isReady)
+	})
+	err := testServer.ListenAndServeAsync(0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+	defer testServer.Shutdown()
+
+	<-isReady
+	if !strings.Contains(logBuffer.String(), "pattern=/users/:id|int") || !strings.Contains(logBuffer.String(), "pattern=/api/orders/") {
+		t.Errorf("Expected the routes to be logged when the server starts listening, but got [%s]", logBuffer.String())
+	} else {
+		t.Logf("The routes have been logged when the server started listening")
+	}
+}
+
+// Buffer which can be written by the loggers of the web server instances while it is being read by the test.
+type lockedBuffer struct {
+	buffer bytes.Buffer
+	mutex sync.Mutex
+}
+
+// Appends the given bytes to the buffer.
+func (lb *lockedBuffer) Write(data []byte) (int, error) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.Write(data)
+}
+
+// Returns the contents of the buffer.
+func (lb *lockedBuffer) String() string {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.String()
+}
+
+// Test case to validate that several web server instances can run in the same process, each with its own routes, settings, logger and content types, and that shutting down one of them
+// does not affect the others.
+func Test_Server_MultipleInstances(t *testing.T) {
+	publicLogs := new(lockedBuffer)
+	adminLogs := new(lockedBuffer)
+	publicServer := NewServer()
+	publicServer.SetLogger(NewLogger(publicLogs, LevelInfo, TextLogFormat))
+	adminServer := NewServer()
+	adminServer.SetLogger(NewLogger(adminLogs, LevelInfo, TextLogFormat))
+	adminServer.Config.EnableTrace = true
+	adminServer.AddContentType(".proteus", "application/x-proteus")
+	filePath := filepath.Join(t.TempDir(), "module.proteus")
+	os.WriteFile(filePath, []byte("proteus\x00"), 0644)
+	sendModule := func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return res.SendFile(filePath)
+	}
+	publicServer.Get("/public", sendModule)
+	adminServer.Get("/admin", sendModule)
+	for _, testServer := range []*HttpServer{ publicServer, adminServer } {
+		if err := testServer.ListenAndServeAsync(0, "127.0.0.1"); err != nil {
+			t.Fatalf("Was not expecting an error and yet received one - %v", err)
+		}
+	}
+	defer adminServer.Shutdown()
+
+	sendRequest := func(testServer *HttpServer, request string) string {
+		connection, err := net.Dial("tcp", testServer.Addr().String())
+		if err != nil {
+			return err.Error()
+		}
+		defer connection.Close()
+		connection.SetDeadline(time.Now().Add(2 * time.Second))
+		connection.Write([]byte(request))
+		response := new(bytes.Buffer)
+		response.ReadFrom(connection)
+		return response.String()
+	}
+
+	testCases := []struct {
+		Name string
+		Server *HttpServer
+		Request string
+		ExpResponse string
+	} {
+		{ "Route of the public server", publicServer, "GET /public HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n", "Content-Type: application/octet-stream" },
+		{ "Route of the admin server requested from the public server", publicServer, "GET /admin HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n", "HTTP/1.1 404" },
+		{ "Content type registered in the admin server", adminServer, "GET /admin HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n", "Content-Type: application/x-proteus" },
+		{ "TRACE enabled only in the admin server", publicServer, "TRACE /public HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n", "HTTP/1.1 405" },
+		{ "TRACE request for the admin server", adminServer, "TRACE /admin HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n", "Content-Type: message/http" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			response := sendRequest(testCase.Server, testCase.Request)
+			if !strings.Contains(response, testCase.ExpResponse) {
+				tt.Errorf("Expected the response to contain [%s], but got [%q]", testCase.ExpResponse, response)
+			} else {
+				tt.Logf("Received the expected response containing [%s]", testCase.ExpResponse)
+			}
+		})
+	}
+
+	publicServer.Shutdown()
+	if response := sendRequest(adminServer, "GET /admin HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"); !strings.HasPrefix(response, "HTTP/1.1 200") {
+		t.Errorf("Expected the admin server to keep serving requests after the public server has shut down, but got [%q]", response)
+	}
+
+	if strings.Contains(adminLogs.String(), "/public") || !strings.Contains(publicLogs.String(), "/public") {
+		t.Errorf("Expected each server to log only its own requests")
+	}
+}
+
+// Test case to validate the responses sent for the routes removed, disabled and enabled again while the web server instance is running.
+func Test_Server_DisableRoutes(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Get("/users/:id", func(req *HttpRequest, res *HttpResponse) error { res.Status(StatusOK); return nil })
+	testServer.Post("/users/:id", func(req *HttpRequest, res *HttpResponse) error { res.Status(StatusCreated); return nil })
+	testServer.Get("/orders", func(req *HttpRequest, res *HttpResponse) error { res.Status(StatusOK); return nil })
+	testCases := []struct {
+		Name string
+		Change func() error
+		Method string
+		ResourcePath string
+		ExpStatus int
+	} {
+		{ "Route path disabled with the default status", func() error { return testServer.Disable("/users/:id") }, "GET", "/users/10", int(StatusNotFound) },
+		{ "Other methods of a disabled route path", nil, "POST", "/users/10", int(StatusNotFound) },
+		{ "Route path which has not been disabled", nil, "GET", "/orders", int(StatusOK) },
+		{ "Route path disabled with a configured status", func() error { testServer.Config.DisabledRouteStatus = StatusServiceUnavailable; return nil }, "GET", "/users/10", int(StatusServiceUnavailable) },
+		{ "Route path enabled again", func() error { return testServer.Enable("/USERS/:id") }, "POST", "/users/10", int(StatusCreated) },
+		{ "Method removed from a route path", func() error { return testServer.Remove("POST", "/users/:id") }, "POST", "/users/10", int(StatusMethodNotAllowed) },
+		{ "Method not removed from a route path", nil, "GET", "/users/10", int(StatusOK) },
+		{ "Route path removed completely", func() error { return testServer.Remove("GET", "/orders") }, "GET", "/orders", int(StatusNotFound) },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			if testCase.Change != nil {
+				if err := testCase.Change(); err != nil {
+					tt.Fatalf("Was not expecting an error while changing the routes, but got this instead - %v", err)
+				}
+			}
+
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else {
+				tt.Logf("The response status [%d] matches the expected status", testResponse.StatusCode)
+			}
+		})
+	}
+
+	for _, routePath := range []string{ "/orders", "/accounts" } {
+		if err := testServer.Disable(routePath); err == nil {
+			t.Errorf("Was expecting an error while disabling the undefined route path [%s], but did not get one", routePath)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Represents a backend where the session data is stored. Implement this interface to store sessions in external backends like Redis or a database.
+type SessionStore interface {
+	// Returns the values stored for the session with the given ID. The boolean value returned is false if the session does not exist or has expired.
+	Load(ID string) (map[string]any, bool, error)
+	// Stores the given values for the session with the given ID.
+	Save(ID string, Values map[string]any) error
+	// Deletes the session with the given ID from the store.
+	Delete(ID string) error
+}
+
+// Structure to represent a user session, whose ID is sent back and forth between the server and the client as a cookie.
+type Session struct {
+	// Unique identifier of the session.
+	ID string
+	// Collection of values stored in the session.
+	values map[string]any
+	// Boolean value to indicate if the session was created for the current request.
+	isNew bool
+	// Boolean value to indicate if the session values have been modified while processing the current request.
+	isModified bool
+	// Boolean value to indicate if the session has been destroyed while processing the current request.
+	isDestroyed bool
+	// Mutex to synchronize access to the session values.
+	mutex sync.Mutex
+}
+
+// Returns the value stored in the session for the given key. The function also returns a boolean value to indicate if the key was found in the session.
+func (ses *Session) Get(key string) (any, bool) {
+	ses.mutex.Lock()
+	defer ses.mutex.Unlock()
+	value, ok := ses.values[key]
+	return value, ok
+}
+
+// Stores the given value in the session against the given key.
+func (ses *Session) Set(key string, value any) {
+	ses.mutex.Lock()
+	defer ses.mutex.Unlock()
+	ses.values[key] = value
+	ses.isModified = true
+}
+
+// Removes the value stored in the session for the given key.
+func (ses *Session) Delete(key string) {
+	ses.mutex.Lock()
+	defer ses.mutex.Unlock()
+	delete(ses.values, key)
+	ses.isModified = true
+}
+
+// Destroys the session by removing all its values. The session is deleted from the store and the session cookie is removed from the client once the request has been processed.
+func (ses *Session) Destroy() {
+	ses.mutex.Lock()
+	defer ses.mutex.Unlock()
+	ses.values = make(map[string]any)
+	ses.isDestroyed = true
+}
+
+// Returns the session associated with the request. If sessions have not been enabled for the server instance using UseSessions(), nil is returned.
+func (req *HttpRequest) Session() *Session {
+	return req.session
+}
+
+// Enables session management for all the routes defined in the web server instance, using the given store to persist the session data.
+// The session ID is sent to the client in a cookie, whose name is taken from the "session_cookie_name" server default. A new session is created for clients which do not send a valid session cookie
+// and the cookie is sent to the client only once a value has been stored in the session. The cookie is marked as Secure when the request has been received over HTTPS (directly or through a trusted proxy).
+func (srv *HttpServer) UseSessions(store SessionStore) {
+	srv.Use(newSessionMiddleware(store, getServerDefaults("session_cookie_name")))
+}
+
+// Creates and returns a middleware which loads the session for each request from the given store and saves it back once the request has been processed.
+func newSessionMiddleware(store SessionStore, CookieName string) Middleware {
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			session := new(Session)
+			if cookie, found := request.Cookie(CookieName); found {
+				values, exists, err := store.Load(cookie.Value)
+				if err != nil {
+					return err
+				}
+
+				if exists {
+					session.ID = cookie.Value
+					session.values = values
+				}
+			}
+
+			if session.values == nil {
+				ID, err := generateSessionID()
+				if err != nil {
+					return err
+				}
+
+				session.ID = ID
+				session.values = make(map[string]any)
+				session.isNew = true
+			}
+
+			request.session = session
+			response.onBeforeWrite(func(res *HttpResponse) {
+				session.mutex.Lock()
+				defer session.mutex.Unlock()
+				sessionCookie := Cookie{ Name: CookieName, Value: session.ID, Path: "/", Secure: isSecureRequest(request), HttpOnly: true, SameSite: SameSiteLax }
+				if session.isDestroyed && !session.isNew {
+					sessionCookie.Value = ""
+					sessionCookie.MaxAge = -1
+					res.SetCookie(sessionCookie)
+				} else if session.isNew && !session.isDestroyed && len(session.values) > 0 {
+					res.SetCookie(sessionCookie)
+				}
+			})
+
+			handlerErr := next(request, response)
+			session.mutex.Lock()
+			defer session.mutex.Unlock()
+			var err error
+			if session.isDestroyed {
+				err = store.Delete(session.ID)
+			} else if session.isModified && (!session.isNew || len(session.values) > 0) {
+				err = store.Save(session.ID, maps.Clone(session.values))
+			}
+
+			if handlerErr != nil {
+				return handlerErr
+			}
+
+			return err
+		}
+	}
+}
+
+// Generates a new random session ID.
+func generateSessionID() (string, error) {
+	randomBytes := make([]byte, 32)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// Structure to represent an entry in the in-memory session store.
+type memorySessionEntry struct {
+	// Collection of values stored in the session.
+	values map[string]any
+	// Time at which the session expires.
+	expiresAt time.Time
+}
+
+// An in-memory implementation of SessionStore, where sessions expire once they have not been loaded or saved for the configured time to live.
+type MemorySessionStore struct {
+	// Duration for which a session is retained after it was last loaded or saved.
+	TTL time.Duration
+	// Collection of all the sessions in the store, with the session ID as key.
+	sessions map[string]memorySessionEntry
+	// Time at which the expired sessions were last evicted from the store.
+	lastEvictedAt time.Time
+	// Mutex to synchronize access to the sessions in the store.
+	mutex sync.Mutex
+}
+
+// Returns the values stored for the session with the given ID and extends the expiry of the session, so that a session expires only once it is no longer used. Expired sessions are treated as non-existent.
+func (mss *MemorySessionStore) Load(ID string) (map[string]any, bool, error) {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	entry, ok := mss.sessions[ID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(mss.sessions, ID)
+		return nil, false, nil
+	}
+
+	entry.expiresAt = time.Now().Add(mss.TTL)
+	mss.sessions[ID] = entry
+	return maps.Clone(entry.values), true, nil
+}
+
+// Stores the given values for the session with the given ID and extends the expiry of the session. Expired sessions are evicted from the store once every time to live interval.
+func (mss *MemorySessionStore) Save(ID string, Values map[string]any) error {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	currentTime := time.Now()
+	mss.sessions[ID] = memorySessionEntry{ values: Values, expiresAt: currentTime.Add(mss.TTL) }
+	if currentTime.Sub(mss.lastEvictedAt) >= mss.TTL {
+		for sessionID, entry := range mss.sessions {
+			if currentTime.After(entry.expiresAt) {
+				delete(mss.sessions, sessionID)
+			}
+		}
+		mss.lastEvictedAt = currentTime
+	}
+
+	return nil
+}
+
+// Deletes the session with the given ID from the store.
+func (mss *MemorySessionStore) Delete(ID string) error {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	delete(mss.sessions, ID)
+	return nil
+}
+
+// Returns the number of sessions (including the expired sessions not evicted yet) present in the store.
+func (mss *MemorySessionStore) Length() int {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	return len(mss.sessions)
+}
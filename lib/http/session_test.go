@@ -0,0 +1,104 @@
+package http
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the creation, loading and destruction of sessions using the in-memory session store.
+func Test_Session_Lifecycle(t *testing.T) {
+	store := NewMemorySessionStore(time.Minute)
+	sessionMiddleware := newSessionMiddleware(store, "test_session")
+	sessionCookie := ""
+	var loadedValue any
+	testCases := []struct {
+		Name string
+		HandlerFunc Handler
+		IsTLS bool
+		ExpectedCookie string
+		ExpectedStoreLength int
+	} {
+		{ "Session without any values", func(req *HttpRequest, res *HttpResponse) error { return nil }, false, "", 0 },
+		{ "Session with a value stored", func(req *HttpRequest, res *HttpResponse) error { req.Session().Set("user", "john"); return nil }, false, "test_session=", 1 },
+		{ "Session loaded from the cookie", func(req *HttpRequest, res *HttpResponse) error { loadedValue, _ = req.Session().Get("user"); return nil }, false, "", 1 },
+		{ "Session destroyed", func(req *HttpRequest, res *HttpResponse) error { req.Session().Destroy(); return nil }, false, "Max-Age=0", 0 },
+		{ "Session created over HTTPS", func(req *HttpRequest, res *HttpResponse) error { req.Session().Set("user", "jane"); return nil }, true, "; Secure", 1 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			if sessionCookie != "" {
+				testRequest.Headers.Add("Cookie", sessionCookie)
+			}
+			if testCase.IsTLS {
+				testRequest.tlsInfo = new(TLSInfo)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			err := sessionMiddleware(testCase.HandlerFunc)(testRequest, testResponse)
+			if err != nil {
+				tt.Errorf("Was not expecting an error and yet received one - %v", err)
+				return
+			}
+
+			testResponse.runBeforeWriteHooks()
+			setCookies := testResponse.Headers[SET_COOKIE_HEADER]
+			if testCase.ExpectedCookie == "" && len(setCookies) != 0 {
+				tt.Errorf("Was not expecting a Set-Cookie header, but got %v", setCookies)
+			} else if testCase.ExpectedCookie != "" && (len(setCookies) != 1 || !strings.Contains(setCookies[0], testCase.ExpectedCookie)) {
+				tt.Errorf("Expected a Set-Cookie header containing %s, but got %v", testCase.ExpectedCookie, setCookies)
+			}
+
+			if len(setCookies) == 1 && !testCase.IsTLS && strings.Contains(setCookies[0], "; Secure") {
+				tt.Errorf("Was not expecting the session cookie to be secure for a plain HTTP request, but got %v", setCookies)
+			}
+
+			if len(setCookies) == 1 && sessionCookie == "" {
+				sessionCookie, _, _ = strings.Cut(setCookies[0], ";")
+			}
+
+			if store.Length() != testCase.ExpectedStoreLength {
+				tt.Errorf("Expected %d sessions in the store, but got %d", testCase.ExpectedStoreLength, store.Length())
+			}
+		})
+	}
+
+	if loadedValue != "john" {
+		t.Errorf("Expected the session value loaded from the store to be john, but got %v", loadedValue)
+	}
+}
+
+// Test case to validate the expiry and eviction of sessions in the in-memory session store.
+func Test_MemorySessionStore_Expiry(t *testing.T) {
+	store := NewMemorySessionStore(50 * time.Millisecond)
+	err := store.Save("first", map[string]any{ "key": "value" })
+	if err != nil {
+		t.Fatalf("Was not expecting an error and yet received one - %v", err)
+	}
+
+	if _, ok, _ := store.Load("first"); !ok {
+		t.Errorf("Expected session to be loaded before it expires")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	store.Save("second", map[string]any{ "key": "value" })
+	if store.Length() != 1 {
+		t.Errorf("Expected the expired session to be evicted, but the store has %d sessions", store.Length())
+	}
+
+	if _, ok, _ := store.Load("first"); ok {
+		t.Errorf("Was not expecting an expired session to be loaded")
+	}
+
+	// Each load extends the expiry of the session, so that a session in use does not expire.
+	store = NewMemorySessionStore(100 * time.Millisecond)
+	store.Save("active", map[string]any{ "key": "value" })
+	for attempt := 1; attempt <= 3; attempt++ {
+		time.Sleep(60 * time.Millisecond)
+		if _, ok, _ := store.Load("active"); !ok {
+			t.Errorf("Expected the session to be loaded after %d loads, as each load extends its expiry", attempt)
+		}
+	}
+}
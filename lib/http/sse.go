@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Media type of the responses streaming server-sent events.
+const EVENT_STREAM_CONTENT_TYPE = "text/event-stream"
+
+// Structure to represent a single server-sent event.
+type Event struct {
+	// Identifier of the event, which is sent back by the client in the Last-Event-ID header when it reconnects. If empty, the id field is not sent.
+	ID string
+	// Name of the event. If empty, the client dispatches the event as a "message" event.
+	Name string
+	// Data of the event. Data containing multiple lines is sent as multiple data fields.
+	Data string
+	// Time for which the client must wait before reconnecting, if the connection is lost. If zero, the retry field is not sent.
+	Retry time.Duration
+}
+
+// Returns the serialized form of the event as per the server-sent events specification.
+func (evt *Event) String() string {
+	var eventBuilder strings.Builder
+	if evt.ID != "" {
+		eventBuilder.WriteString("id: " + removeLineBreaks(evt.ID) + "\n")
+	}
+
+	if evt.Name != "" {
+		eventBuilder.WriteString("event: " + removeLineBreaks(evt.Name) + "\n")
+	}
+
+	if evt.Retry > 0 {
+		eventBuilder.WriteString("retry: " + strconv.FormatInt(evt.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	Data := strings.ReplaceAll(evt.Data, "\r\n", "\n")
+	for _, dataLine := range strings.Split(strings.ReplaceAll(Data, "\r", "\n"), "\n") {
+		eventBuilder.WriteString("data: " + dataLine + "\n")
+	}
+
+	eventBuilder.WriteString("\n")
+	return eventBuilder.String()
+}
+
+// Removes all the line breaks from the given value, since a field of an event must fit in a single line.
+func removeLineBreaks(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// Structure to represent a stream of server-sent events sent as the response to a request. Each event is flushed to the client as soon as it is sent.
+type EventStream struct {
+	// Response through which the events are streamed.
+	response *HttpResponse
+	// Context of the request, which is cancelled when the client disconnects.
+	ctx context.Context
+	// Channel closed when the event stream is closed, to stop sending heartbeats.
+	closed chan struct{}
+	// Boolean value to indicate if the event stream has been closed.
+	isClosed bool
+	// Mutex to synchronize the writing of events and heartbeats.
+	mutex sync.Mutex
+}
+
+// Switches the response to a stream of server-sent events and returns the event stream. The Content-Type of the response is set to text/event-stream and
+// a heartbeat comment is sent once every interval configured in the "sse_heartbeat_interval" server default, so that idle connections are not dropped by proxies.
+func (res *HttpResponse) EventStream() (*EventStream, error) {
+	if res.eventStream != nil {
+		return res.eventStream, nil
+	}
+
+	delete(res.Headers, "Content-Type")
+	res.Headers.Add("Content-Type", EVENT_STREAM_CONTENT_TYPE)
+	res.Headers.Add("Cache-Control", "no-cache")
+	err := res.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	stream := new(EventStream)
+	stream.response = res
+	stream.ctx = res.ctx
+	if stream.ctx == nil {
+		stream.ctx = context.Background()
+	}
+	stream.closed = make(chan struct{})
+	res.eventStream = stream
+	if interval := getDefaultDuration("sse_heartbeat_interval"); interval > 0 {
+		go stream.sendHeartbeats(interval)
+	}
+
+	return stream, nil
+}
+
+// Sends an event with the given name and data to the client.
+func (stream *EventStream) SendEvent(Name string, Data string) error {
+	return stream.Send(Event{ Name: Name, Data: Data })
+}
+
+// Sends the given event to the client. An error is returned if the client has disconnected or the event stream has been closed.
+func (stream *EventStream) Send(event Event) error {
+	return stream.write(event.String())
+}
+
+// Sends a comment to the client, which is ignored by the client but keeps the connection active.
+func (stream *EventStream) SendComment(Comment string) error {
+	return stream.write(": " + removeLineBreaks(Comment) + "\n\n")
+}
+
+// Returns a channel which is closed when the client disconnects or the request is cancelled.
+func (stream *EventStream) Done() <-chan struct{} {
+	return stream.ctx.Done()
+}
+
+// Closes the event stream and stops sending heartbeats. The event stream is closed automatically once the handler returns.
+func (stream *EventStream) Close() {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	if !stream.isClosed {
+		stream.isClosed = true
+		close(stream.closed)
+	}
+}
+
+// Writes the given serialized event to the response and flushes it to the client.
+func (stream *EventStream) write(Content string) error {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	if stream.isClosed {
+		resErr := new(ResponseError)
+		resErr.Section = "Body"
+		resErr.Value = "Event Stream"
+		resErr.Message = "Event stream has already been closed"
+		return resErr
+	}
+
+	if err := stream.ctx.Err(); err != nil {
+		return err
+	}
+
+	// The write deadline set for the response is renewed for every event, since an event stream stays open for much longer than a regular response.
+	if stream.response.connection != nil {
+		stream.response.connection.SetWriteDeadline(getDeadline(time.Now(), stream.response.writeTimeout))
+	}
+
+	err := stream.response.WriteChunk([]byte(Content))
+	if err != nil {
+		return err
+	}
+
+	return stream.response.Flush()
+}
+
+// Sends a heartbeat comment once every given interval, until the event stream is closed or the client disconnects.
+func (stream *EventStream) sendHeartbeats(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if stream.SendComment("heartbeat") != nil {
+				return
+			}
+		case <-stream.closed:
+			return
+		case <-stream.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the serialization of server-sent events.
+func Test_Event_String(t *testing.T) {
+	testCases := []struct {
+		Name string
+		InputEvent Event
+		ExpOutput string
+	} {
+		{ "Event with only data", Event{ Data: "hello" }, "data: hello\n\n" },
+		{ "Event with name and data", Event{ Name: "update", Data: "42" }, "event: update\ndata: 42\n\n" },
+		{ "Event with multi-line data", Event{ Data: "line one\r\nline two\nline three" }, "data: line one\ndata: line two\ndata: line three\n\n" },
+		{ "Event with all the fields", Event{ ID: "7", Name: "tick", Data: "now", Retry: 3 * time.Second }, "id: 7\nevent: tick\nretry: 3000\ndata: now\n\n" },
+		{ "Event name with line breaks", Event{ Name: "bad\nname", Data: "x" }, "event: badname\ndata: x\n\n" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			output := testCase.InputEvent.String()
+			if output != testCase.ExpOutput {
+				tt.Errorf("The serialized event %q does not match the expected value %q", output, testCase.ExpOutput)
+			} else {
+				tt.Logf("The serialized event matches the expected value %q", output)
+			}
+		})
+	}
+}
+
+// Test case to validate streaming server-sent events to the client.
+func Test_Response_EventStream(t *testing.T) {
+	testResponse := newTestResponse(t, "1.1")
+	var opBuffer bytes.Buffer
+	testResponse.setWriter(bufio.NewWriter(&opBuffer))
+	requestContext, cancelRequestContext := context.WithCancel(context.Background())
+	testResponse.ctx = requestContext
+	stream, err := testResponse.EventStream()
+	if err != nil {
+		t.Fatalf("Was not expecting an error while creating the event stream and yet received one - %v", err)
+	}
+
+	head, _, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+	if !strings.Contains(head, "Content-Type: " + EVENT_STREAM_CONTENT_TYPE) || !strings.Contains(head, "Transfer-Encoding: chunked") {
+		t.Errorf("The response headers [%s] do not contain the expected event stream headers", head)
+	}
+
+	err = stream.SendEvent("greeting", "hello")
+	if err != nil {
+		t.Fatalf("Was not expecting an error while sending the event and yet received one - %v", err)
+	}
+
+	// Every event must be flushed to the client as soon as it is sent.
+	if !strings.Contains(opBuffer.String(), "event: greeting\ndata: hello\n\n") {
+		t.Errorf("Expected the event to be flushed to the client, but the output is [%s]", opBuffer.String())
+	}
+
+	cancelRequestContext()
+	select {
+	case <-stream.Done():
+		t.Logf("The event stream reported the client disconnection as expected")
+	case <-time.After(time.Second):
+		t.Errorf("Expected the event stream to be done once the request context was cancelled")
+	}
+
+	if err = stream.SendEvent("greeting", "again"); err == nil {
+		t.Errorf("Expected an error while sending an event after the client disconnected")
+	}
+
+	testResponse.end()
+	if !strings.HasSuffix(opBuffer.String(), "0\r\n\r\n") {
+		t.Errorf("Expected the event stream to be ended with the last chunk, but the output is [%s]", opBuffer.String())
+	}
+
+	if err = stream.SendComment("closed"); err == nil {
+		t.Errorf("Expected an error while sending a comment after the event stream was closed")
+	}
+}
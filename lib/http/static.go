@@ -0,0 +1,383 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	iofs "io/fs"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/mkbworks/proteus/lib/fs"
+)
+
+// Structure to contain the settings of a static route.
+type StaticOptions struct {
+	// Boolean value to indicate if a HTML listing of the folder contents must be sent when a folder is requested. If false, a 404 (Not Found) response is sent for folder requests.
+	DirectoryListing bool
+	// Template used to render the folder listing, which is executed with a DirectoryListing value. If nil, the default template is used.
+	ListingTemplate *template.Template
+	// Collection of index file names (like index.html) searched for in order when a folder is requested. The first index file found in the folder is sent as response.
+	Index []string
+	// Cache from which the contents of small files are served, instead of reading them from the file system for every request. If nil, the files are not cached.
+	Cache *StaticFileCache
+	// Duration for which clients can use a file without checking with the server if it has changed, sent as the max-age directive of the Cache-Control header along with the Expires header.
+	// If zero, the max-age directive is not sent.
+	MaxAge time.Duration
+	// Boolean value to indicate if the files never change once published (like assets with a content hash in their names), which adds the immutable directive to the Cache-Control header.
+	Immutable bool
+	// Boolean value to indicate if the files must not be stored by clients or shared caches, in which case the Cache-Control header is set to no-store and the other cache settings are ignored.
+	NoStore bool
+	// Boolean value to indicate if symbolic links pointing to files or folders outside the target folder of the static route must not be followed, in which case a 404 (Not Found) response is sent for them.
+	// Symbolic links within the target folder are always followed.
+	DenySymlinksOutsideRoot bool
+	// Boolean value to indicate if the precompressed versions of the files (named after the file with the ".br" or ".gz" extension appended, like app.js.gz) must be sent to the clients accepting
+	// their content encoding, with the media type of the original file. Files without a precompressed version are sent as usual.
+	Precompressed bool
+	// Path of the entry point of a single-page application (like index.html), relative to the target folder of the static route. If set, it is sent with a 200 (OK) response for the request paths
+	// which do not match any file in the target folder (and for folders without an index file when directory listing is disabled), so that the application can handle those paths using client-side routing.
+	SPAFallback string
+}
+
+// Collection of the content encodings of the precompressed files searched for by the static routes, in their order of preference, along with the extensions of the precompressed files.
+var precompressedExtensions = []struct {
+	// Content encoding of the precompressed file.
+	Encoding string
+	// Extension appended to the name of the original file.
+	Extension string
+} {
+	{ BROTLI_CONTENT_ENCODING, ".br" },
+	{ GZIP_CONTENT_ENCODING, ".gz" },
+}
+
+// Returns the complete path of the first index file (as configured in the given static options) present in the given folder of the given file system.
+// The boolean value returned is false if none of the index files are present.
+func resolveIndexFile(FileSystem fs.FileSystem, FolderPath string, options *StaticOptions) (string, bool) {
+	if options == nil {
+		return "", false
+	}
+
+	for _, indexFile := range options.Index {
+		indexFile = strings.TrimSpace(indexFile)
+		if indexFile == "" || strings.ContainsAny(indexFile, "/\\") {
+			continue
+		}
+
+		indexFilePath := joinFilePath(FileSystem, FolderPath, indexFile)
+		if PathType, err := fs.GetPathTypeFS(FileSystem, indexFilePath); err == nil && PathType == fs.FILE_TYPE_PATH {
+			return indexFilePath, true
+		}
+	}
+
+	return "", false
+}
+
+// Adds the Cache-Control and Expires headers to the response sent for a file, as configured in the static options.
+func (options *StaticOptions) addCacheHeaders(response *HttpResponse) {
+	if options == nil {
+		return
+	}
+
+	if options.NoStore {
+		response.Headers.Add("Cache-Control", "no-store")
+		return
+	}
+
+	directives := make([]string, 0)
+	if options.MaxAge > 0 {
+		directives = append(directives, "public", "max-age=" + strconv.FormatInt(int64(options.MaxAge / time.Second), 10))
+		response.Headers["Expires"] = []string{ time.Now().Add(options.MaxAge).UTC().Format(HTTP_DATE_FORMAT) }
+	}
+
+	if options.Immutable {
+		directives = append(directives, "immutable")
+	}
+
+	if len(directives) > 0 {
+		response.Headers.Add("Cache-Control", strings.Join(directives, ", "))
+	}
+}
+
+// Structure to represent the contents of a folder rendered in a directory listing.
+type DirectoryListing struct {
+	// Request path of the folder being listed.
+	Path string
+	// URL path of the parent folder, with each of its segments percent-encoded so that it can be used as a link. It is empty if the folder being listed is the root folder of the static route.
+	ParentPath string
+	// Collection of entries present in the folder.
+	Entries []DirectoryListingEntry
+}
+
+// Structure to represent a single entry (file or folder) in a directory listing.
+type DirectoryListingEntry struct {
+	// Base name of the entry.
+	Name string
+	// URL path of the entry, with each of its segments percent-encoded so that it can be used as a link (like "/files/a%20b%3F.txt" for the file "a b?.txt").
+	Path string
+	// Is true if the entry is a folder.
+	IsDir bool
+	// Size of the entry in bytes. It is zero for folders.
+	Size int64
+	// Time at which the entry was last modified.
+	ModifiedAt time.Time
+}
+
+// Returns the size of the entry in a human readable form. A "-" is returned for folders.
+func (entry DirectoryListingEntry) FormattedSize() string {
+	if entry.IsDir {
+		return "-"
+	}
+
+	units := []string{ "B", "KB", "MB", "GB", "TB" }
+	size := float64(entry.Size)
+	unitIndex := 0
+	for size >= 1024 && unitIndex < len(units) - 1 {
+		size /= 1024
+		unitIndex++
+	}
+
+	if unitIndex == 0 {
+		return strconv.FormatInt(entry.Size, 10) + " B"
+	}
+
+	return strconv.FormatFloat(size, 'f', 1, 64) + " " + units[unitIndex]
+}
+
+// Default template used to render the directory listings.
+var defaultListingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Index of {{.Path}}</title>
+</head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Last Modified</th></tr>
+{{- if .ParentPath}}
+<tr><td><a href="{{.ParentPath}}">../</a></td><td>-</td><td>-</td></tr>
+{{- end}}
+{{- range .Entries}}
+<tr><td><a href="{{.Path}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td><td>{{.FormattedSize}}</td><td>{{.ModifiedAt.UTC.Format "02-Jan-2006 15:04"}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// Returns the HTML listing of the contents of the given folder in the given file system, rendered using the template configured in the given static options. Hidden entries (whose names start with a '.')
+// are not listed.
+func renderDirectoryListing(FileSystem fs.FileSystem, FolderPath string, RequestPath string, IsRootFolder bool, options *StaticOptions) ([]byte, error) {
+	dirEntries, err := fs.ListDirectoryFS(FileSystem, FolderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	RequestPath = "/" + strings.Trim(RequestPath, "/")
+	listing := DirectoryListing{ Path: RequestPath, Entries: make([]DirectoryListingEntry, 0, len(dirEntries)) }
+	// The names of the entries can contain characters like '?', '#' or '%', which change the meaning of the link unless they are percent-encoded.
+	escapedPath := escapePathSegments(RequestPath)
+	if !IsRootFolder {
+		listing.ParentPath = path.Dir(escapedPath)
+	}
+
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name, ".") {
+			continue
+		}
+
+		listing.Entries = append(listing.Entries, DirectoryListingEntry{
+			Name: dirEntry.Name,
+			Path: path.Join(escapedPath, url.PathEscape(dirEntry.Name)),
+			IsDir: dirEntry.IsDir,
+			Size: dirEntry.Size,
+			ModifiedAt: dirEntry.LastModifiedAt,
+		})
+	}
+
+	listingTemplate := defaultListingTemplate
+	if options != nil && options.ListingTemplate != nil {
+		listingTemplate = options.ListingTemplate
+	}
+
+	var listingContent bytes.Buffer
+	err = listingTemplate.Execute(&listingContent, listing)
+	if err != nil {
+		return nil, err
+	}
+
+	return listingContent.Bytes(), nil
+}
+
+// Returns the given normalized request path with each of its segments percent-encoded, so that the characters decoded while normalizing the path (like ' ', '?' or '#') are encoded again
+// and the characters kept encoded (like '%') are not encoded twice.
+func escapePathSegments(RequestPath string) string {
+	pathSegments := strings.Split(RequestPath, "/")
+	for index, pathSegment := range pathSegments {
+		if decodedSegment, err := url.PathUnescape(pathSegment); err == nil {
+			pathSegment = decodedSegment
+		}
+		pathSegments[index] = url.PathEscape(pathSegment)
+	}
+
+	return strings.Join(pathSegments, "/")
+}
+
+// Returns the path of the file or folder in the file system requested using the given request path, from the static route matching the given route path. The request path is percent-decoded
+// and its dot-segments are resolved within the target folder of the static route, so that the path returned always lies within the target folder. The boolean value returned is false if the
+// request path cannot be decoded or contains a null byte. For static routes serving a file system given using StaticFS(), the slash-separated path within the file system (with "." for its root folder) is returned.
+func (route *Route) getStaticFilePath(RequestPath string, MatchedPath string) (string, bool) {
+	relativePath, err := url.PathUnescape(strings.Replace(RequestPath, MatchedPath, "", 1))
+	if err != nil || strings.ContainsRune(relativePath, 0) {
+		return "", false
+	}
+
+	if route.StaticFS != nil {
+		filePath := strings.TrimPrefix(path.Clean("/" + relativePath), "/")
+		if filePath == "" {
+			return ".", true
+		}
+		return filePath, iofs.ValidPath(filePath)
+	}
+
+	// The relative path is cleaned as a rooted path, so that ".." segments cannot go above the target folder.
+	filePath := filepath.Join(route.StaticFolderPath, filepath.FromSlash(path.Clean("/" + relativePath)))
+	if !isWithinFolder(route.StaticFolderPath, filePath) {
+		// Paths which are not separated by '/' (like paths containing '\' on Windows) can still lead outside the target folder once joined.
+		return "", false
+	}
+
+	return filePath, true
+}
+
+// Checks if the symbolic links in the given path can be followed as per the settings of the static route. Symbolic links leading outside the target folder of the static route are not followed
+// if the static route has been set to deny them. Paths which do not exist are allowed, as they are answered with a 404 (Not Found) response anyway. The file systems given using StaticFS() resolve their own links.
+func (route *Route) isSymlinkAllowed(CompleteFilePath string) bool {
+	if route.StaticFS != nil || route.StaticOptions == nil || !route.StaticOptions.DenySymlinksOutsideRoot {
+		return true
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(route.StaticFolderPath)
+	if err != nil {
+		return false
+	}
+
+	resolvedPath, err := filepath.EvalSymlinks(CompleteFilePath)
+	if err != nil {
+		return true
+	}
+
+	return isWithinFolder(resolvedRoot, resolvedPath)
+}
+
+// Checks if the given path is the given folder or lies within it.
+func isWithinFolder(FolderPath string, CompletePath string) bool {
+	relativePath, err := filepath.Rel(FolderPath, CompletePath)
+	if err != nil {
+		return false
+	}
+
+	return relativePath != ".." && !strings.HasPrefix(relativePath, ".." + string(filepath.Separator)) && !filepath.IsAbs(relativePath)
+}
+
+// Sends the HTML listing of the folder requested in the given static route request as response. A 404 (Not Found) response is sent if directory listing is not enabled for the static route.
+func sendDirectoryListing(request *HttpRequest, response *HttpResponse) error {
+	staticRoute := request.staticRoute
+	if staticRoute == nil || staticRoute.StaticOptions == nil || !staticRoute.StaticOptions.DirectoryListing {
+		response.Status(StatusNotFound)
+		return handleError(request, response)
+	}
+
+	IsRootFolder := filepath.Clean(request.staticFilePath) == filepath.Clean(staticRoute.StaticFolderPath)
+	if staticRoute.StaticFS != nil {
+		IsRootFolder = request.staticFilePath == "."
+	}
+
+	listingContent, err := renderDirectoryListing(staticRoute.getFileSystem(), request.staticFilePath, request.ResourcePath, IsRootFolder, staticRoute.StaticOptions)
+	if err != nil {
+		return err
+	}
+
+	response.Status(StatusOK)
+	response.Headers.Add("Content-Type", "text/html; charset=utf-8")
+	response.Body = listingContent
+	return nil
+}
+
+// Returns the cache key of the file available at the given path, served by the static route. Files from the local file system are identified by their complete paths, while files from a file system given
+// using StaticFS() are identified by the route path along with their path within the file system, as different file systems can contain files with the same path.
+func (route *Route) getCacheKey(CompleteFilePath string) string {
+	if route != nil && route.StaticFS != nil {
+		return "fs:" + route.RoutePath + ":" + CompleteFilePath
+	}
+
+	return CompleteFilePath
+}
+
+// Returns the path and the content encoding of the precompressed version of the file available at the given path, to be sent instead of the file as per the Accept-Encoding header of the given response.
+// The boolean value returned is false if precompressed files are not enabled for the static route or if the client does not accept the content encoding of any precompressed version present.
+// The Vary header of the response is updated if a precompressed version is present, as the file sent then depends on the Accept-Encoding header.
+func (route *Route) getPrecompressedFile(FileSystem fs.FileSystem, CompleteFilePath string, response *HttpResponse) (string, string, bool) {
+	if route == nil || route.StaticOptions == nil || !route.StaticOptions.Precompressed || strings.EqualFold(response.Version, "0.9") {
+		return "", "", false
+	}
+
+	availableEncodings := make([]string, 0, len(precompressedExtensions))
+	filePaths := make(map[string]string)
+	for _, precompressed := range precompressedExtensions {
+		filePath := CompleteFilePath + precompressed.Extension
+		if PathType, err := fs.GetPathTypeFS(FileSystem, filePath); err == nil && PathType == fs.FILE_TYPE_PATH && route.isSymlinkAllowed(filePath) {
+			availableEncodings = append(availableEncodings, precompressed.Encoding)
+			filePaths[precompressed.Encoding] = filePath
+		}
+	}
+
+	if len(availableEncodings) == 0 {
+		return "", "", false
+	}
+
+	response.addVary("Accept-Encoding")
+	encoding := selectEncoding(response.acceptEncoding, availableEncodings)
+	if encoding == "" {
+		return "", "", false
+	}
+
+	return filePaths[encoding], encoding, true
+}
+
+// Returns the complete path of the single-page application entry point configured in the static options of the static route, in the given file system.
+// The boolean value returned is false if no entry point has been configured or if it is not a file present in the target folder of the static route.
+func (route *Route) getFallbackFilePath(FileSystem fs.FileSystem) (string, bool) {
+	if route == nil || route.StaticOptions == nil {
+		return "", false
+	}
+
+	// The entry point is cleaned as a rooted path, so that it always lies within the target folder.
+	fallbackFile := strings.TrimPrefix(path.Clean("/" + filepath.ToSlash(strings.TrimSpace(route.StaticOptions.SPAFallback))), "/")
+	if fallbackFile == "" {
+		return "", false
+	}
+
+	rootFolderPath := route.StaticFolderPath
+	if route.StaticFS != nil {
+		rootFolderPath = "."
+	}
+
+	fallbackFilePath := joinFilePath(FileSystem, rootFolderPath, fallbackFile)
+	if PathType, err := fs.GetPathTypeFS(FileSystem, fallbackFilePath); err != nil || PathType != fs.FILE_TYPE_PATH || !route.isSymlinkAllowed(fallbackFilePath) {
+		return "", false
+	}
+
+	return fallbackFilePath, true
+}
+
+// Returns the file system from which the static route serves files, which is the local file system for static routes defined using Static().
+func (route *Route) getFileSystem() fs.FileSystem {
+	if route == nil || route.StaticFS == nil {
+		return fs.OSFileSystem{}
+	}
+
+	return route.StaticFS
+}
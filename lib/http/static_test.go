@@ -0,0 +1,582 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+	"github.com/mkbworks/proteus/lib/fs"
+)
+
+// Test case to validate the directory listing sent for folder requests made to static routes.
+func Test_Server_DirectoryListing(t *testing.T) {
+	testFolder := t.TempDir()
+	os.Mkdir(filepath.Join(testFolder, "docs"), 0755)
+	os.WriteFile(filepath.Join(testFolder, "docs", "guide.txt"), []byte("guide"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "notes.txt"), []byte("notes"), 0644)
+	os.WriteFile(filepath.Join(testFolder, ".secret"), []byte("secret"), 0644)
+	os.Mkdir(filepath.Join(testFolder, "50% off"), 0755)
+	os.WriteFile(filepath.Join(testFolder, "50% off", "a b?#.txt"), []byte("sale"), 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/files", testFolder, StaticOptions{ DirectoryListing: true })
+	testServer.Static("/private", testFolder)
+	testServer.Static("/custom", testFolder, StaticOptions{ DirectoryListing: true, ListingTemplate: template.Must(template.New("custom").Parse(`{{range .Entries}}{{.Name}};{{end}}`)) })
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		ExpStatus int
+		ExpContains []string
+		ExpMissing []string
+	} {
+		{ "Listing of the root folder", "GET", "/files", int(StatusOK), []string{ `<a href="/files/docs">docs/</a>`, `<a href="/files/notes.txt">notes.txt</a>`, "5 B" }, []string{ ".secret", "../" } },
+		{ "Listing of a sub-folder", "GET", "/files/docs", int(StatusOK), []string{ `<a href="/files">../</a>`, `<a href="/files/docs/guide.txt">guide.txt</a>` }, []string{} },
+		{ "Listing with names containing reserved characters", "GET", "/files", int(StatusOK), []string{ `<a href="/files/50%25%20off">50% off/</a>` }, []string{} },
+		{ "Listing of a sub-folder with reserved characters in its path", "GET", "/files/50%25%20off", int(StatusOK), []string{ `<a href="/files/50%25%20off/a%20b%3F%23.txt">a b?#.txt</a>` }, []string{} },
+		{ "Listing requested using HEAD", "HEAD", "/files", int(StatusOK), []string{}, []string{ "notes.txt" } },
+		{ "Listing using a custom template", "GET", "/custom", int(StatusOK), []string{ "docs;notes.txt;" }, []string{} },
+		{ "Listing disabled for the static route", "GET", "/private", int(StatusNotFound), []string{}, []string{ "notes.txt" } },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.isHeadRequest = testCase.Method == "HEAD"
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.end()
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+				return
+			}
+
+			_, body, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			for _, expValue := range testCase.ExpContains {
+				if !strings.Contains(body, expValue) {
+					tt.Errorf("Expected the response body [%s] to contain [%s]", body, expValue)
+				} else {
+					tt.Logf("The response body contains [%s]", expValue)
+				}
+			}
+
+			for _, value := range testCase.ExpMissing {
+				if strings.Contains(body, value) {
+					tt.Errorf("Was not expecting the response body [%s] to contain [%s]", body, value)
+				}
+			}
+		})
+	}
+}
+
+// Test case to validate the resolution of index files for folder requests made to static routes.
+func Test_Server_StaticIndex(t *testing.T) {
+	testFolder := t.TempDir()
+	os.Mkdir(filepath.Join(testFolder, "docs"), 0755)
+	os.WriteFile(filepath.Join(testFolder, "docs", "index.htm"), []byte("<p>docs</p>"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "index.html"), []byte("<p>home</p>"), 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/files", testFolder, StaticOptions{ Index: []string{ "index.html", "index.htm" } })
+	testServer.Static("/listed", testFolder, StaticOptions{ DirectoryListing: true, Index: []string{ "default.html" } })
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "First index file present in the folder", "/files/", int(StatusOK), "<p>home</p>" },
+		{ "Second index file present in the sub-folder", "/files/docs", int(StatusOK), "<p>docs</p>" },
+		{ "Index file not present in the folder", "/listed", int(StatusOK), "Index of /listed" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body [%s] to contain [%s]", string(testResponse.Body), testCase.ExpBody)
+			} else {
+				tt.Logf("The response body contains [%s] as expected", testCase.ExpBody)
+			}
+		})
+	}
+}
+
+// Test case to validate the caching of small static files, along with their invalidation when modified and the eviction of the least recently used files once the cache budget is exceeded.
+func Test_Server_StaticFileCache(t *testing.T) {
+	testFolder := t.TempDir()
+	os.WriteFile(filepath.Join(testFolder, "one.txt"), []byte("first file"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "two.txt"), []byte("second one"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "three.txt"), []byte("third file"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "large.css"), []byte("file larger than entry"), 0644)
+	testCache := NewStaticFileCache(16, 24)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/files", testFolder, StaticOptions{ Cache: testCache })
+	testCases := []struct {
+		Name string
+		FileName string
+		NewContents string
+		ExpBody string
+		ExpCount int
+		ExpSize int64
+	} {
+		{ "Small file cached on the first request", "one.txt", "", "first file", 1, 10 },
+		{ "Cached file served again", "one.txt", "", "first file", 1, 10 },
+		{ "File larger than the maximum entry size", "large.css", "", "file larger than entry", 1, 10 },
+		{ "Cached file reloaded once modified", "one.txt", "first edit", "first edit", 1, 10 },
+		{ "Second small file cached", "two.txt", "", "second one", 2, 20 },
+		{ "Least recently used file evicted", "three.txt", "", "third file", 2, 20 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			targetPath := filepath.Join(testFolder, testCase.FileName)
+			if testCase.NewContents != "" {
+				os.WriteFile(targetPath, []byte(testCase.NewContents), 0644)
+				modifiedAt := time.Now().Add(time.Hour)
+				os.Chtimes(targetPath, modifiedAt, modifiedAt)
+			}
+
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/files/" + testCase.FileName
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			count, size := testCache.Stats()
+			if string(testResponse.Body) != testCase.ExpBody {
+				tt.Errorf("The response body [%s] does not match the expected body [%s]", string(testResponse.Body), testCase.ExpBody)
+			} else if count != testCase.ExpCount || size != testCase.ExpSize {
+				tt.Errorf("The cache contains %d files of %d bytes, but expected %d files of %d bytes", count, size, testCase.ExpCount, testCase.ExpSize)
+			} else {
+				tt.Logf("The response body [%s] has been sent with %d files in the cache as expected", string(testResponse.Body), count)
+			}
+		})
+	}
+
+	if _, found := testCache.entries[filepath.Join(testFolder, "one.txt")]; found {
+		t.Errorf("Expected the least recently used file one.txt to be evicted from the cache")
+	}
+}
+
+// Test case to validate that large files which are not compressed are copied to the response byte stream as they are, while other files are read into the response body.
+func Test_Server_StaticFileStreaming(t *testing.T) {
+	testFolder := t.TempDir()
+	largeContents := bytes.Repeat([]byte("0123456789abcdef"), 8192)
+	os.WriteFile(filepath.Join(testFolder, "large.png"), largeContents, 0644)
+	os.WriteFile(filepath.Join(testFolder, "large.css"), largeContents, 0644)
+	os.WriteFile(filepath.Join(testFolder, "small.png"), largeContents[:1024], 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/files", testFolder)
+	testCases := []struct {
+		Name string
+		Method string
+		FileName string
+		ExpStreamed bool
+		ExpBodySize int
+	} {
+		{ "Large file that is not compressible", "GET", "large.png", true, len(largeContents) },
+		{ "Large file that is compressible", "GET", "large.css", false, len(largeContents) },
+		{ "Small file that is not compressible", "GET", "small.png", false, 1024 },
+		{ "Large file requested using HEAD", "HEAD", "large.png", false, 0 },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = "/files/" + testCase.FileName
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.isHeadRequest = testCase.Method == "HEAD"
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			_, responseBody, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			isStreamed := len(testResponse.Body) == 0 && testResponse.bodySize > 0
+			if isStreamed != testCase.ExpStreamed {
+				tt.Errorf("Expected the file to be streamed [%t], but it was streamed [%t]", testCase.ExpStreamed, isStreamed)
+			} else if testResponse.bodySize != testCase.ExpBodySize || (testCase.ExpBodySize > 0 && len(responseBody) != testCase.ExpBodySize) {
+				tt.Errorf("Expected a response body of %d bytes, but %d bytes were written", testCase.ExpBodySize, testResponse.bodySize)
+			} else if testResponse.bodyFile != nil {
+				tt.Errorf("Expected the body file to be closed once the response has been written")
+			} else {
+				tt.Logf("The response body of %d bytes has been written as expected", testResponse.bodySize)
+			}
+		})
+	}
+}
+
+// Test case to validate the caching headers sent for files served by static routes, as configured in the static options.
+func Test_Server_StaticCacheControl(t *testing.T) {
+	testFolder := t.TempDir()
+	os.WriteFile(filepath.Join(testFolder, "site.css"), []byte("body { margin: 0; }"), 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/assets", testFolder, StaticOptions{ MaxAge: 24 * time.Hour, Immutable: true })
+	testServer.Static("/private", testFolder, StaticOptions{ MaxAge: time.Hour, NoStore: true })
+	testServer.Static("/files", testFolder)
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Revalidate bool
+		ExpStatus int
+		ExpCacheControl string
+		ExpExpires bool
+	} {
+		{ "File with max-age and immutable", "/assets/site.css", false, int(StatusOK), "public, max-age=86400, immutable", true },
+		{ "File revalidated with max-age", "/assets/site.css", true, int(StatusNotModified), "public, max-age=86400, immutable", true },
+		{ "File that must not be stored", "/private/site.css", false, int(StatusOK), "no-store", false },
+		{ "File without cache settings", "/files/site.css", false, int(StatusOK), "", false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			if testCase.Revalidate {
+				fileInfo, _ := os.Stat(filepath.Join(testFolder, "site.css"))
+				testRequest.Headers.Add("If-Modified-Since", fileInfo.ModTime().Add(time.Minute).UTC().Format(HTTP_DATE_FORMAT))
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			cacheControl, _ := testResponse.Headers.Get("Cache-Control")
+			_, hasExpires := testResponse.Headers.Get("Expires")
+			vary, _ := testResponse.Headers.Get("Vary")
+			_, hasLastModified := testResponse.Headers.Get("Last-Modified")
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if cacheControl != testCase.ExpCacheControl || hasExpires != testCase.ExpExpires {
+				tt.Errorf("Expected the Cache-Control header [%s] with Expires sent [%t], but got [%s] with Expires sent [%t]", testCase.ExpCacheControl, testCase.ExpExpires, cacheControl, hasExpires)
+			} else if vary != "Accept-Encoding" || !hasLastModified {
+				tt.Errorf("Expected the Vary header [Accept-Encoding] and the Last-Modified header, but got Vary [%s] with Last-Modified sent [%t]", vary, hasLastModified)
+			} else {
+				tt.Logf("The caching headers have been sent as expected with Cache-Control [%s]", cacheControl)
+			}
+		})
+	}
+}
+
+// Test case to validate that requests made to static routes cannot access files outside the target folder, using plain or percent-encoded dot-segments or symbolic links.
+func Test_Server_StaticPathTraversal(t *testing.T) {
+	baseFolder := t.TempDir()
+	outsideFolder := t.TempDir()
+	publicFolder := filepath.Join(baseFolder, "public")
+	os.Mkdir(publicFolder, 0755)
+	os.WriteFile(filepath.Join(publicFolder, "notes.txt"), []byte("notes"), 0644)
+	os.WriteFile(filepath.Join(publicFolder, "my notes.txt"), []byte("my notes"), 0644)
+	os.WriteFile(filepath.Join(baseFolder, "secret.txt"), []byte("secret"), 0644)
+	os.WriteFile(filepath.Join(outsideFolder, "outside.txt"), []byte("outside"), 0644)
+	symlinks := map[string]string{
+		"inside-link": filepath.Join(publicFolder, "notes.txt"),
+		"outside-link": filepath.Join(outsideFolder, "outside.txt"),
+		"outside-dir": outsideFolder,
+	}
+	for name, target := range symlinks {
+		if err := os.Symlink(target, filepath.Join(publicFolder, name)); err != nil {
+			t.Skipf("Symbolic links cannot be created in this environment - %v", err)
+		}
+	}
+
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/files", publicFolder)
+	testServer.Static("/strict", publicFolder, StaticOptions{ DenySymlinksOutsideRoot: true, Index: []string{ "outside-link" } })
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "File within the target folder", "/files/notes.txt", int(StatusOK), "notes" },
+		{ "Percent-encoded file name", "/files/my%20notes.txt", int(StatusOK), "my notes" },
+		{ "Dot-segments leading outside the target folder", "/files/../secret.txt", int(StatusNotFound), "" },
+		{ "Percent-encoded dot-segments", "/files/%2e%2e/secret.txt", int(StatusNotFound), "" },
+		{ "Percent-encoded dot-segments and slashes", "/files/%2E%2E%2F%2E%2E%2Fsecret.txt", int(StatusNotFound), "" },
+		{ "Percent-encoded backslashes", "/files/..%5C..%5Csecret.txt", int(StatusNotFound), "" },
+		{ "Percent-encoded null byte", "/files/notes.txt%00.png", int(StatusNotFound), "" },
+		{ "Invalid percent-encoding", "/files/notes%zz.txt", int(StatusNotFound), "" },
+		{ "Symbolic link outside the target folder followed by default", "/files/outside-link", int(StatusOK), "outside" },
+		{ "Symbolic link outside the target folder denied", "/strict/outside-link", int(StatusNotFound), "" },
+		{ "File in a symbolic link to a folder outside the target folder", "/strict/outside-dir/outside.txt", int(StatusNotFound), "" },
+		{ "Index file linking outside the target folder", "/strict", int(StatusNotFound), "" },
+		{ "Symbolic link within the target folder", "/strict/inside-link", int(StatusOK), "notes" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.end()
+			_, body, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+			} else if testCase.ExpBody != "" && body != testCase.ExpBody {
+				tt.Errorf("Expected the response body to be [%s], but got [%s]", testCase.ExpBody, body)
+			} else if strings.Contains(body, "secret") || (testCase.ExpBody == "" && strings.Contains(body, "outside")) {
+				tt.Errorf("Was not expecting the response body [%s] to contain a file outside the target folder", body)
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate the files served from a file system (like an embed.FS) by a static route defined using StaticFS(), including index files, listings, byte ranges and revalidation using entity tags.
+func Test_Server_StaticFS(t *testing.T) {
+	modifiedAt := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	testFS := fstest.MapFS{
+		"site.css": &fstest.MapFile{ Data: []byte("body { margin: 0; }") },
+		"docs/index.html": &fstest.MapFile{ Data: []byte("<h1>Docs</h1>"), ModTime: modifiedAt },
+		"notes/readme.txt": &fstest.MapFile{ Data: []byte("0123456789"), ModTime: modifiedAt },
+	}
+	testCache := NewStaticFileCache(1024, 4096)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.StaticFS("/assets", testFS, StaticOptions{ Index: []string{ "index.html" }, DirectoryListing: true, Cache: testCache, MaxAge: time.Hour })
+	siteETag, _ := generateETag(testFS, "site.css", &fs.File{ Size: 19 })
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		HeaderKey string
+		HeaderValue string
+		Range string
+		ExpStatus int
+		ExpBody string
+		ExpHeaders map[string]string
+	} {
+		{ "File without a last modified time", "/assets/site.css", "", "", "", int(StatusOK), "body { margin: 0; }", map[string]string{ "Content-Type": "text/css", "ETag": siteETag, "Last-Modified": "", "Cache-Control": "public, max-age=3600" } },
+		{ "File revalidated using its entity tag", "/assets/site.css", "If-None-Match", siteETag, "", int(StatusNotModified), "", map[string]string{ "ETag": siteETag } },
+		{ "File revalidated using a date only", "/assets/site.css", "If-Modified-Since", "Fri, 01 Mar 2030 10:00:00 GMT", "", int(StatusOK), "body { margin: 0; }", nil },
+		{ "Index file of a folder", "/assets/docs/", "", "", "", int(StatusOK), "<h1>Docs</h1>", map[string]string{ "Content-Type": "text/html", "Last-Modified": "Fri, 01 Mar 2024 10:00:00 GMT" } },
+		{ "Listing of a folder without an index file", "/assets/notes", "", "", "", int(StatusOK), `<a href="/assets/notes/readme.txt">readme.txt</a>`, nil },
+		{ "Byte range of a file", "/assets/notes/readme.txt", "", "", "bytes=2-5", int(StatusPartialContent), "2345", map[string]string{ "Content-Range": "bytes 2-5/10" } },
+		{ "Request path leading outside the file system", "/assets/../site.css", "", "", "", int(StatusOK), "body { margin: 0; }", nil },
+		{ "File which does not exist", "/assets/missing.js", "", "", "", int(StatusNotFound), "", nil },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			if testCase.HeaderKey != "" {
+				testRequest.Headers.Add(testCase.HeaderKey, testCase.HeaderValue)
+			}
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.rangeHeader = testCase.Range
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d]", testResponse.StatusCode, testCase.ExpStatus)
+				return
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body [%s] to contain [%s]", string(testResponse.Body), testCase.ExpBody)
+				return
+			}
+
+			for headerKey, expValue := range testCase.ExpHeaders {
+				if headerValue, _ := testResponse.Headers.Get(headerKey); headerValue != expValue {
+					tt.Errorf("Expected the %s header to be [%s], but got [%s] instead", headerKey, expValue, headerValue)
+					return
+				}
+			}
+
+			tt.Logf("The response status [%d] and headers match the expected values", testResponse.StatusCode)
+		})
+	}
+
+	if count, _ := testCache.Stats(); count != 3 {
+		t.Errorf("Expected the 3 files served to be cached under the route path of the file system, but %d files were cached", count)
+	} else if _, found := testCache.entries["fs:/assets:site.css"]; !found {
+		t.Errorf("Expected site.css to be cached under the route path of the file system")
+	}
+
+	if err := testServer.StaticFS("/empty", fstest.MapFS{}); err != nil {
+		t.Errorf("Was not expecting an error while serving an empty file system, but got this instead - %v", err)
+	} else if err = testServer.StaticFS("/missing", nil); err == nil {
+		t.Errorf("Was expecting an error while serving a nil file system")
+	}
+}
+
+// Test case to validate the media types sniffed from the contents of the files with unknown extensions served by static routes, with and without content sniffing enabled.
+func Test_Server_StaticContentSniffing(t *testing.T) {
+	testFS := fstest.MapFS{
+		"LICENSE": &fstest.MapFile{ Data: []byte("Permission is hereby granted, free of charge") },
+		"logo.pic": &fstest.MapFile{ Data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR") },
+		"page.tmpl": &fstest.MapFile{ Data: []byte("<!DOCTYPE html><html></html>") },
+		"data.bin": &fstest.MapFile{ Data: []byte{ 0x00, 0x01, 0x02, 0x03 } },
+		"site.css": &fstest.MapFile{ Data: []byte("<html></html>") },
+	}
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.StaticFS("/files", testFS)
+	testCases := []struct {
+		Name string
+		FileName string
+		Sniffing string
+		ExpContentType string
+	} {
+		{ "Text file without an extension", "LICENSE", "on", "text/plain; charset=utf-8" },
+		{ "Image with an unknown extension", "logo.pic", "on", "image/png" },
+		{ "HTML with an unknown extension", "page.tmpl", "on", "text/html; charset=utf-8" },
+		{ "Binary contents not matching any media type", "data.bin", "on", "application/octet-stream" },
+		{ "Known extension not overridden by the contents", "site.css", "on", "text/css" },
+		{ "Text file without an extension and sniffing disabled", "LICENSE", "off", "application/octet-stream" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			originalSniffing := ServerDefaults["content_sniffing"]
+			ServerDefaults["content_sniffing"] = testCase.Sniffing
+			defer func() {
+				ServerDefaults["content_sniffing"] = originalSniffing
+			}()
+
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = "/files/" + testCase.FileName
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if contentType, _ := testResponse.Headers.Get("Content-Type"); contentType != testCase.ExpContentType {
+				tt.Errorf("Expected the content type [%s], but got [%s] instead", testCase.ExpContentType, contentType)
+			} else {
+				tt.Logf("The file has been sent with the content type [%s] as expected", contentType)
+			}
+		})
+	}
+}
+
+// Test case to validate the precompressed versions of the files sent by static routes, as per the content encodings accepted by the client.
+func Test_Server_StaticPrecompressed(t *testing.T) {
+	testFS := fstest.MapFS{
+		"app.js": &fstest.MapFile{ Data: []byte("console.log('proteus');") },
+		"app.js.br": &fstest.MapFile{ Data: []byte("brotli contents") },
+		"app.js.gz": &fstest.MapFile{ Data: []byte("gzip contents") },
+		"site.css": &fstest.MapFile{ Data: []byte("body {}") },
+		"site.css.gz": &fstest.MapFile{ Data: []byte("gzip contents") },
+		"logo.png": &fstest.MapFile{ Data: []byte("\x89PNG\r\n\x1a\n") },
+	}
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.StaticFS("/assets", testFS, StaticOptions{ Precompressed: true })
+	testServer.StaticFS("/plain", testFS)
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		AcceptEncoding string
+		ExpBody string
+		ExpEncoding string
+		ExpVary bool
+	} {
+		{ "Brotli preferred over gzip", "/assets/app.js", "gzip, br", "brotli contents", "br", true },
+		{ "Gzip preferred with quality values", "/assets/app.js", "br;q=0.5, gzip", "gzip contents", "gzip", true },
+		{ "No content encoding accepted", "/assets/app.js", "", "console.log('proteus');", "", true },
+		{ "Accepted encoding without a precompressed file", "/assets/site.css", "br", "body {}", "", true },
+		{ "Gzip accepted using the wildcard encoding", "/assets/site.css", "*", "gzip contents", "gzip", true },
+		{ "File without precompressed versions", "/assets/logo.png", "gzip, br", "\x89PNG\r\n\x1a\n", "", false },
+		{ "Precompressed files not enabled", "/plain/app.js", "gzip, br", "console.log('proteus');", "", true },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.acceptEncoding = testCase.AcceptEncoding
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.compress()
+			encoding, _ := testResponse.Headers.Get("Content-Encoding")
+			_, hasVary := testResponse.Headers.Get("Vary")
+			contentType, _ := testResponse.Headers.Get("Content-Type")
+			if string(testResponse.Body) != testCase.ExpBody {
+				tt.Errorf("Expected the response body to be [%q], but got [%q] instead", testCase.ExpBody, string(testResponse.Body))
+			} else if encoding != testCase.ExpEncoding {
+				tt.Errorf("Expected the Content-Encoding header to be [%s], but got [%s] instead", testCase.ExpEncoding, encoding)
+			} else if hasVary != testCase.ExpVary {
+				tt.Errorf("Expected the presence of the Vary header to be %t, but got %t instead", testCase.ExpVary, hasVary)
+			} else if strings.HasSuffix(testCase.ResourcePath, ".js") && !strings.Contains(contentType, "javascript") {
+				tt.Errorf("Expected the media type of the original file to be sent, but got [%s] instead", contentType)
+			} else {
+				tt.Logf("The file has been sent with the Content-Encoding [%s] and the Content-Type [%s] as expected", encoding, contentType)
+			}
+		})
+	}
+}
+
+// Test case to validate the single-page application entry point sent by static routes for the request paths which do not match any file.
+func Test_Server_StaticSPAFallback(t *testing.T) {
+	testFolder := t.TempDir()
+	os.Mkdir(filepath.Join(testFolder, "assets"), 0755)
+	os.WriteFile(filepath.Join(testFolder, "index.html"), []byte("<html>app</html>"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "assets", "app.js"), []byte("console.log('proteus');"), 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Static("/app", testFolder, StaticOptions{ SPAFallback: "index.html" })
+	testServer.Static("/listed", testFolder, StaticOptions{ SPAFallback: "index.html", DirectoryListing: true })
+	testServer.Static("/missing", testFolder, StaticOptions{ SPAFallback: "../main.html" })
+	testServer.Static("/plain", testFolder)
+	testServer.StaticFS("/embedded", fstest.MapFS{ "app/index.html": &fstest.MapFile{ Data: []byte("<html>embedded</html>") } }, StaticOptions{ SPAFallback: "/app/index.html" })
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		ExpStatusCode StatusCode
+		ExpBody string
+	} {
+		{ "Client-side route", "/app/users/42", StatusOK, "<html>app</html>" },
+		{ "Existing file", "/app/assets/app.js", StatusOK, "console.log('proteus');" },
+		{ "Root folder without an index file", "/app/", StatusOK, "<html>app</html>" },
+		{ "Folder without an index file", "/app/assets", StatusOK, "<html>app</html>" },
+		{ "Folder with directory listing enabled", "/listed/assets", StatusOK, "app.js" },
+		{ "Client-side route with directory listing enabled", "/listed/users/42", StatusOK, "<html>app</html>" },
+		{ "Entry point not present in the target folder", "/missing/users/42", StatusNotFound, "" },
+		{ "Entry point not configured", "/plain/users/42", StatusNotFound, "" },
+		{ "Client-side route of an io/fs file system", "/embedded/users/42", StatusOK, "<html>embedded</html>" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != int(testCase.ExpStatusCode) {
+				tt.Errorf("Expected the response status code to be %d, but got %d instead", testCase.ExpStatusCode, testResponse.StatusCode)
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body to contain [%s], but got [%s] instead", testCase.ExpBody, string(testResponse.Body))
+			} else {
+				tt.Logf("The response has been sent with the status code %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
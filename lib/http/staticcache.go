@@ -0,0 +1,93 @@
+package http
+
+import (
+	"container/list"
+	"sync"
+	"github.com/mkbworks/proteus/lib/fs"
+)
+
+// Structure to represent an in-memory cache of the contents of small static files, which can be shared by one or more static routes using StaticOptions. When the total size of the cached files exceeds
+// the budget of the cache, the least recently used files are evicted. A cached file is reloaded from the file system when its last modified time or size changes.
+type StaticFileCache struct {
+	// Maximum size (in bytes) of a file that can be cached. Larger files are always read from the file system.
+	MaxEntrySize int64
+	// Maximum total size (in bytes) of all the files present in the cache.
+	MaxSize int64
+	// Collection of cached files, with the cache key of the file (its complete path for the files of the local file system) as key and the element of the file in the usage order as value.
+	entries map[string]*list.Element
+	// List of cached files ordered by their usage, with the most recently used file at the front.
+	usageOrder *list.List
+	// Total size (in bytes) of all the files present in the cache.
+	size int64
+	// Mutex to synchronize access to the cached files across multiple client connections.
+	mutex sync.Mutex
+}
+
+// Structure to represent a single file present in the static file cache.
+type staticCacheEntry struct {
+	// Cache key of the file.
+	key string
+	// Cached file along with its contents.
+	file *fs.File
+}
+
+// Returns the given file along with its contents, which are taken from the cache (using the given cache key) if the cached copy of the file is still current. Otherwise, the contents are read from the given
+// file system and cached. The boolean value returned is false if the file cannot be cached (as it is larger than the maximum entry size or
+// the budget of the cache) or its contents could not be read.
+func (cache *StaticFileCache) load(CacheKey string, FileSystem fs.FileSystem, CompleteFilePath string, file *fs.File) (*fs.File, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if element, found := cache.entries[CacheKey]; found {
+		entry := element.Value.(*staticCacheEntry)
+		// The content type is compared as well, since it changes when the allowed content types are reloaded.
+		if entry.file.LastModifiedAt.Equal(file.LastModifiedAt) && entry.file.Size == file.Size && entry.file.ContentType == file.ContentType {
+			cache.usageOrder.MoveToFront(element)
+			return entry.file, true
+		}
+
+		cache.remove(element)
+	}
+
+	if file.Size > cache.MaxEntrySize || file.Size > cache.MaxSize {
+		return nil, false
+	}
+
+	fileContents, err := fs.ReadFileContentsFS(FileSystem, CompleteFilePath)
+	if err != nil || int64(len(fileContents)) != file.Size {
+		// The file has either been removed or modified after its metadata was read.
+		return nil, false
+	}
+
+	cachedFile := *file
+	cachedFile.Contents = fileContents
+	cache.entries[CacheKey] = cache.usageOrder.PushFront(&staticCacheEntry{ key: CacheKey, file: &cachedFile })
+	cache.size += cachedFile.Size
+	for cache.size > cache.MaxSize {
+		cache.remove(cache.usageOrder.Back())
+	}
+
+	return &cachedFile, true
+}
+
+// Removes the file at the given element of the usage order from the cache.
+func (cache *StaticFileCache) remove(element *list.Element) {
+	entry := cache.usageOrder.Remove(element).(*staticCacheEntry)
+	delete(cache.entries, entry.key)
+	cache.size -= entry.file.Size
+}
+
+// Removes all the files from the cache.
+func (cache *StaticFileCache) Clear() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.entries = make(map[string]*list.Element)
+	cache.usageOrder.Init()
+	cache.size = 0
+}
+
+// Returns the number of files and the total size (in bytes) of the files present in the cache.
+func (cache *StaticFileCache) Stats() (int, int64) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return len(cache.entries), cache.size
+}
@@ -5,38 +5,74 @@ import (
 	"html/template"
 )
 
+// HTTP response status code. The codes defined in RFC 9110 (along with 429 and 431 from RFC 6585, and 207 and 423 from RFC 4918 for WebDAV) are available as constants, whose reason phrases are returned by StatusText().
 type StatusCode int
 
 const (
+	StatusContinue StatusCode = 100
+	StatusSwitchingProtocols StatusCode = 101
 	StatusOK StatusCode = 200
 	StatusCreated StatusCode = 201
 	StatusAccepted StatusCode = 202
 	StatusNonAuthoritative StatusCode = 203
 	StatusNoContent StatusCode = 204
+	StatusResetContent StatusCode = 205
+	StatusPartialContent StatusCode = 206
+	StatusMultiStatus StatusCode = 207
 	StatusMultipleChoices StatusCode = 300
 	StatusMovedPermanently StatusCode = 301
-	StatusMovedTemporarily StatusCode = 302
+	StatusFound StatusCode = 302
 	StatusSeeOther StatusCode = 303
 	StatusNotModified StatusCode = 304
+	StatusUseProxy StatusCode = 305
+	StatusTemporaryRedirect StatusCode = 307
+	StatusPermanentRedirect StatusCode = 308
 	StatusBadRequest StatusCode = 400
 	StatusUnauthorized StatusCode = 401
 	StatusPaymentRequired StatusCode = 402
 	StatusForbidden StatusCode = 403
 	StatusNotFound StatusCode = 404
 	StatusMethodNotAllowed StatusCode = 405
-	StatusNoneAcceptable StatusCode = 406
-	StatusProxyAuth StatusCode = 407
+	StatusNotAcceptable StatusCode = 406
+	StatusProxyAuthRequired StatusCode = 407
 	StatusRequestTimeout StatusCode = 408
 	StatusConflict StatusCode = 409
 	StatusGone StatusCode = 410
-	StatusLengthMissing StatusCode = 411
+	StatusLengthRequired StatusCode = 411
+	StatusPreconditionFailed StatusCode = 412
+	StatusContentTooLarge StatusCode = 413
+	StatusURITooLong StatusCode = 414
+	StatusUnsupportedMediaType StatusCode = 415
+	StatusRangeNotSatisfiable StatusCode = 416
+	StatusExpectationFailed StatusCode = 417
+	StatusMisdirectedRequest StatusCode = 421
+	StatusUnprocessableContent StatusCode = 422
+	StatusLocked StatusCode = 423
+	StatusUpgradeRequired StatusCode = 426
+	StatusTooManyRequests StatusCode = 429
+	StatusRequestHeaderFieldsTooLarge StatusCode = 431
 	StatusInternalServerError StatusCode = 500
 	StatusNotImplemented StatusCode = 501
 	StatusBadGateway StatusCode = 502
 	StatusServiceUnavailable StatusCode = 503
 	StatusGatewayTimeout StatusCode = 504
+	StatusHTTPVersionNotSupported StatusCode = 505
 )
 
+// Names of the status codes used before they were renamed after their reason phrases in RFC 9110. These are kept so that the existing code using them continues to work.
+const (
+	StatusMovedTemporarily = StatusFound
+	StatusNoneAcceptable = StatusNotAcceptable
+	StatusProxyAuth = StatusProxyAuthRequired
+	StatusLengthMissing = StatusLengthRequired
+	StatusRequestEntityTooLarge = StatusContentTooLarge
+)
+
+// Returns the reason phrase of the given HTTP status code as defined in RFC 9110, like "Not Found" for 404. It returns an empty string if the status code is not known.
+func StatusText(code int) string {
+	return StatusCode(code).GetStatusMessage()
+}
+
 // Gets the minified message assosciated with a HTTP status code.
 func (code StatusCode) GetStatusMessage() string {
 	for _, stat := range ResponseStatusCodes {
@@ -50,13 +86,18 @@ func (code StatusCode) GetStatusMessage() string {
 
 // Gets the default error content for a HTTP status code.
 func (code StatusCode) GetErrorContent() string {
+	return code.getErrorContent("")
+}
+
+// Gets the default error content for a HTTP status code, showing the given message instead of the error description of the status code if it is not empty.
+func (code StatusCode) getErrorContent(message string) string {
 	htmlTemplate := `<html>
 					<head>
-					<title>{{printf "%s - Response" .StatusCode}}</title>
+					<title>{{printf "%d - Response" .Code}}</title>
 					</head>
 					<body>
-					<h1>{{printf "%d - %s" .StatusCode .StatusMessage}}</h1>
-					<p>{{.Description}}</p>
+					<h1>{{printf "%d - %s" .Code .Message}}</h1>
+					<p>{{.ErrorDescription}}</p>
 					</body>
 				</html>`
 	
@@ -67,6 +108,10 @@ func (code StatusCode) GetErrorContent() string {
 				break
 			}
 
+			if message != "" {
+				stat.ErrorDescription = message
+			}
+
 			var tmpBytes bytes.Buffer
 			err = temp.Execute(&tmpBytes, stat)
 			if err != nil {
@@ -78,4 +123,4 @@ func (code StatusCode) GetErrorContent() string {
 	}
 
 	return ""
-}
\ No newline at end of file
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"testing"
+)
+
+// Test case to validate the reason phrases returned for the HTTP status codes, including the codes renamed in RFC 9110 and the codes that are not known.
+func Test_StatusText(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IpCode int
+		ExpText string
+	} {
+		{ "Informational status code", int(StatusContinue), "Continue" },
+		{ "Successful status code", int(StatusNoContent), "No Content" },
+		{ "Redirection status code renamed in RFC 9110", int(StatusFound), "Found" },
+		{ "Client error status code", int(StatusNotFound), "Not Found" },
+		{ "Client error status code renamed in RFC 9110", int(StatusContentTooLarge), "Content Too Large" },
+		{ "Client error status code added in RFC 9110", int(StatusUnprocessableContent), "Unprocessable Content" },
+		{ "Client error status code using its old name", int(StatusRequestEntityTooLarge), "Content Too Large" },
+		{ "Server error status code", int(StatusHTTPVersionNotSupported), "HTTP Version Not Supported" },
+		{ "Unknown status code", 599, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			text := StatusText(testCase.IpCode)
+			if text != testCase.ExpText {
+				tt.Errorf("Expected the reason phrase of %d to be [%s], but got [%s] instead", testCase.IpCode, testCase.ExpText, text)
+			} else {
+				tt.Logf("Reason phrase of %d is [%s] as expected", testCase.IpCode, text)
+			}
+		})
+	}
+}
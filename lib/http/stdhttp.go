@@ -0,0 +1,147 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// Adapter which lets a handler of the net/http package write its response to a HttpResponse instance. The response body is buffered, unless the handler flushes the response, in which case
+// the response is streamed to the client from then on.
+type stdResponseWriter struct {
+	// Response to which the handler writes.
+	response *HttpResponse
+	// Headers set by the handler, which are copied to the response when the handler writes the status code.
+	header nethttp.Header
+	// Boolean value to indicate if the status code has been written by the handler.
+	wroteHeader bool
+}
+
+// Returns the headers to be sent in the response, which can be modified by the handler until it writes the status code.
+func (writer *stdResponseWriter) Header() nethttp.Header {
+	return writer.header
+}
+
+// Sets the status code of the response along with the headers set by the handler. Informational status codes and status codes written after the first one are ignored.
+func (writer *stdResponseWriter) WriteHeader(statusCode int) {
+	if writer.wroteHeader || statusCode < 200 {
+		return
+	}
+
+	writer.wroteHeader = true
+	for key, values := range writer.header {
+		// The values are copied as they are, since a single value can contain commas (like the value of the Expires header).
+		writer.response.Headers[textproto.CanonicalMIMEHeaderKey(key)] = append([]string(nil), values...)
+	}
+	writer.response.Status(StatusCode(statusCode))
+}
+
+// Writes the given data to the response body. The status code defaults to 200 OK and the Content-Type header is detected from the data if the handler has not set it, as done by the net/http package.
+func (writer *stdResponseWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		if writer.header.Get("Content-Type") == "" && writer.header.Get("Content-Encoding") == "" && len(data) > 0 {
+			writer.header.Set("Content-Type", nethttp.DetectContentType(data))
+		}
+		writer.WriteHeader(int(StatusOK))
+	}
+
+	if writer.response.isStreaming {
+		err := writer.response.WriteChunk(data)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		writer.response.Body = append(writer.response.Body, data...)
+	}
+
+	return len(data), nil
+}
+
+// Sends the response written so far to the client, switching the response to streaming mode.
+func (writer *stdResponseWriter) Flush() {
+	if !writer.wroteHeader {
+		writer.WriteHeader(int(StatusOK))
+	}
+	writer.response.Flush()
+}
+
+// Returns the request in the form of a request of the net/http package, which carries the context, headers, body and trailers of the request.
+func (req *HttpRequest) toStdRequest() *nethttp.Request {
+	requestURI := req.getRawPath()
+	if req.RawQuery != "" {
+		requestURI += "?" + req.RawQuery
+	}
+
+	stdRequest := new(nethttp.Request)
+	stdRequest.Method = req.Method
+	stdRequest.RequestURI = requestURI
+	stdRequest.URL, _ = url.ParseRequestURI(requestURI)
+	if stdRequest.URL == nil {
+		stdRequest.URL = &url.URL{ Path: req.ResourcePath }
+	}
+	stdRequest.Proto = "HTTP/" + req.Version
+	stdRequest.ProtoMajor, stdRequest.ProtoMinor, _ = nethttp.ParseHTTPVersion(stdRequest.Proto)
+	stdRequest.Header = make(nethttp.Header)
+	for key, values := range req.Headers {
+		if key != "Host" {
+			stdRequest.Header[key] = []string{ strings.Join(values, ",") }
+		}
+	}
+	stdRequest.Trailer = make(nethttp.Header)
+	for key, values := range req.Trailers {
+		stdRequest.Trailer[key] = []string{ strings.Join(values, ",") }
+	}
+	stdRequest.Host, _ = req.Headers.Get("Host")
+	stdRequest.RemoteAddr = req.ClientAddress
+	stdRequest.TLS = req.tlsInfo.toConnectionState()
+	stdRequest.ContentLength = int64(len(req.Body))
+	stdRequest.Body = nethttp.NoBody
+	if len(req.Body) > 0 {
+		stdRequest.Body = io.NopCloser(bytes.NewReader(req.Body))
+	}
+
+	return stdRequest.WithContext(req.Context())
+}
+
+// Wraps the given handler of the net/http package (like the handlers of net/http/pprof or promhttp) into a handler which can be used for the routes of a web server instance.
+// The request is passed to the handler with its context, headers and body, and the response written by the handler is sent as the response of the route.
+func WrapStdHandler(handler nethttp.Handler) Handler {
+	return func(request *HttpRequest, response *HttpResponse) error {
+		writer := &stdResponseWriter{ response: response, header: make(nethttp.Header) }
+		handler.ServeHTTP(writer, request.toStdRequest())
+		if !writer.wroteHeader {
+			writer.WriteHeader(int(StatusOK))
+		}
+		return nil
+	}
+}
+
+// Returns a handler of the net/http package which processes every request using the given handler, so that the handlers (and middlewares wrapping them) written for proteus can be used with a net/http server.
+// If the handler returns an error without having written the response, a 500 (Internal Server Error) response is sent.
+func ToStdHandler(handler Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		httpRequest, err := newHTTP2Request(request, nil)
+		httpRequest.Version = fmt.Sprintf("%d.%d", request.ProtoMajor, request.ProtoMinor)
+		httpResponse := newHTTP2Response(writer, httpRequest)
+		httpRequest.ctx = request.Context()
+		httpResponse.ctx = request.Context()
+		if err != nil {
+			status := StatusBadRequest
+			if reqError, ok := err.(*RequestParseError); ok && reqError.Status != 0 {
+				status = reqError.Status
+			}
+			httpResponse.Status(status)
+			handleError(httpRequest, httpResponse)
+		} else if err = handler(httpRequest, httpResponse); err != nil && !httpResponse.isWritten {
+			httpResponse.Status(StatusInternalServerError)
+			handleError(httpRequest, httpResponse)
+		}
+
+		httpResponse.end()
+		httpRequest.cleanupMultipartForm()
+	})
+}
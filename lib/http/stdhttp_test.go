@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test case to validate the responses written by handlers of the net/http package, when they are wrapped into handlers of a web server instance.
+func Test_Server_WrapStdHandler(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Post("/std/created", WrapStdHandler(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		body := new(bytes.Buffer)
+		body.ReadFrom(request.Body)
+		writer.Header().Set("Content-Type", "text/plain")
+		writer.Header().Set("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
+		writer.WriteHeader(nethttp.StatusCreated)
+		writer.Write([]byte(request.URL.Query().Get("name") + ":" + body.String()))
+	})))
+	testServer.Get("/std/detected", WrapStdHandler(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		writer.Write([]byte("<html><body>proteus</body></html>"))
+	})))
+	testServer.Get("/std/flushed", WrapStdHandler(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		writer.Write([]byte("first"))
+		writer.(nethttp.Flusher).Flush()
+		writer.Write([]byte("second"))
+	})))
+	testServer.Get("/std/empty", WrapStdHandler(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {})))
+
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		ExpStatus int
+		ExpHeader string
+		ExpResponse string
+	} {
+		{ "Status code, headers and body written by the handler", "POST", "/std/created?name=proteus", int(StatusCreated), "Expires: Thu, 01 Jan 1970 00:00:00 GMT", "proteus:hello" },
+		{ "Content type detected from the body", "GET", "/std/detected", int(StatusOK), "Content-Type: text/html; charset=utf-8", "<html><body>proteus</body></html>" },
+		{ "Response flushed by the handler", "GET", "/std/flushed", int(StatusOK), "Transfer-Encoding: chunked", "5\r\nfirst\r\n6\r\nsecond\r\n0\r\n\r\n" },
+		{ "Handler writing nothing", "GET", "/std/empty", int(StatusOK), "Content-Length: 0", "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath, testRequest.RawQuery, _ = strings.Cut(testCase.ResourcePath, "?")
+			testRequest.Body = []byte("hello")
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.end()
+			responseHead, responseBody, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("Expected status code to be %d, but got %d", testCase.ExpStatus, testResponse.StatusCode)
+			} else if !strings.Contains(responseHead, testCase.ExpHeader) {
+				tt.Errorf("Expected the response to contain the header [%s], but got [%q]", testCase.ExpHeader, responseHead)
+			} else if responseBody != testCase.ExpResponse {
+				tt.Errorf("Expected the response body to be [%q], but got [%q]", testCase.ExpResponse, responseBody)
+			} else {
+				tt.Logf("Received status %d with the expected headers and body", testResponse.StatusCode)
+			}
+		})
+	}
+}
+
+// Test case to validate the processing of requests received by a net/http server using a handler written for proteus.
+func Test_ToStdHandler(t *testing.T) {
+	stdHandler := ToStdHandler(func(req *HttpRequest, res *HttpResponse) error {
+		names, _ := req.Query.Get("name")
+		if len(names) == 0 {
+			return errors.New("name is missing")
+		}
+
+		res.Headers.Add("X-Version", req.Version)
+		return res.JSON(StatusOK, map[string]string{ "name": names[0], "body": string(req.Body) })
+	})
+
+	testCases := []struct {
+		Name string
+		Target string
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "Response written by the handler", "/greet?name=proteus", int(StatusOK), "{\"body\":\"hello\",\"name\":\"proteus\"}" },
+		{ "Error returned by the handler", "/greet", int(StatusInternalServerError), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			recorder := httptest.NewRecorder()
+			stdHandler.ServeHTTP(recorder, httptest.NewRequest("POST", testCase.Target, strings.NewReader("hello")))
+			if recorder.Code != testCase.ExpStatus {
+				tt.Errorf("Expected status code to be %d, but got %d", testCase.ExpStatus, recorder.Code)
+			} else if testCase.ExpBody != "" && (strings.TrimSpace(recorder.Body.String()) != testCase.ExpBody || recorder.Header().Get("X-Version") != "1.1") {
+				tt.Errorf("Expected the response body to be [%s] for version 1.1, but got [%s] for version [%s]", testCase.ExpBody, recorder.Body.String(), recorder.Header().Get("X-Version"))
+			} else {
+				tt.Logf("Received status %d with the expected body", recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"github.com/mkbworks/proteus/lib/fs"
+)
+
+// Content type of the responses rendered from HTML templates.
+const HTML_CONTENT_TYPE = "text/html; charset=utf-8"
+
+// Structure to hold the HTML templates of a web server instance, which are used to render responses using Render().
+type templateSet struct {
+	// Glob pattern matching the template files.
+	glob string
+	// Collection of functions made available to the templates.
+	funcs template.FuncMap
+	// Base name of the template file used as the layout for all the other templates. It is empty if no layout has been set.
+	layout string
+	// Boolean value to indicate if the template files must be parsed again for every response rendered, so that changes made to them are picked up without restarting the server.
+	autoReload bool
+	// Collection of parsed templates, with the base name of the template file as key.
+	views map[string]*template.Template
+	// Mutex to synchronize access to the parsed templates, which can be parsed again while responses are being rendered.
+	mutex sync.RWMutex
+}
+
+// Parses the template files matching the glob pattern. Without a layout, all the template files are parsed into a single set, so that each template can use the others. With a layout, every template file
+// is parsed along with the layout and the partials (template files whose base names start with an underscore, like _header.html) and is executed through the layout, so that the template can override
+// the blocks (like {{block "content" .}}) defined in the layout.
+func (ts *templateSet) load() error {
+	templateFiles, err := filepath.Glob(ts.glob)
+	if err != nil || len(templateFiles) == 0 {
+		resErr := new(ResponseError)
+		resErr.Section = "Template"
+		resErr.Value = ts.glob
+		resErr.Message = "No template files match the given pattern"
+		return resErr
+	}
+
+	views := make(map[string]*template.Template)
+	if ts.layout == "" {
+		allTemplates, err := template.New(filepath.Base(templateFiles[0])).Funcs(ts.funcs).ParseFiles(templateFiles...)
+		if err != nil {
+			return newTemplateError(ts.glob, err)
+		}
+
+		for _, templateFile := range templateFiles {
+			views[filepath.Base(templateFile)] = allTemplates
+		}
+	} else {
+		baseFiles := make([]string, 0)
+		for _, templateFile := range templateFiles {
+			if filepath.Base(templateFile) == ts.layout || strings.HasPrefix(filepath.Base(templateFile), "_") {
+				baseFiles = append(baseFiles, templateFile)
+			}
+		}
+
+		if !slices.ContainsFunc(baseFiles, func(baseFile string) bool { return filepath.Base(baseFile) == ts.layout }) {
+			resErr := new(ResponseError)
+			resErr.Section = "Template"
+			resErr.Value = ts.layout
+			resErr.Message = "Layout template is not among the template files matching the given pattern"
+			return resErr
+		}
+
+		baseTemplate, err := template.New(ts.layout).Funcs(ts.funcs).ParseFiles(baseFiles...)
+		if err != nil {
+			return newTemplateError(ts.layout, err)
+		}
+
+		for _, templateFile := range templateFiles {
+			if slices.Contains(baseFiles, templateFile) {
+				continue
+			}
+
+			viewTemplate, err := baseTemplate.Clone()
+			if err == nil {
+				viewTemplate, err = viewTemplate.ParseFiles(templateFile)
+			}
+
+			if err != nil {
+				return newTemplateError(templateFile, err)
+			}
+			views[filepath.Base(templateFile)] = viewTemplate
+		}
+	}
+
+	ts.mutex.Lock()
+	ts.views = views
+	ts.mutex.Unlock()
+	return nil
+}
+
+// Renders the template with the given name using the given data and returns the rendered content.
+func (ts *templateSet) render(name string, data any) ([]byte, error) {
+	if ts.autoReload {
+		err := ts.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ts.mutex.RLock()
+	viewTemplate, found := ts.views[name]
+	ts.mutex.RUnlock()
+	if !found {
+		resErr := new(ResponseError)
+		resErr.Section = "Template"
+		resErr.Value = name
+		resErr.Message = "Template with the given name could not be found"
+		return nil, resErr
+	}
+
+	templateName := name
+	if ts.layout != "" {
+		templateName = ts.layout
+	}
+
+	var content bytes.Buffer
+	err := viewTemplate.ExecuteTemplate(&content, templateName, data)
+	if err != nil {
+		return nil, newTemplateError(name, err)
+	}
+
+	return content.Bytes(), nil
+}
+
+// Returns the error raised when the template with the given name could not be parsed or executed due to the given error.
+func newTemplateError(Name string, err error) error {
+	resErr := new(ResponseError)
+	resErr.Section = "Template"
+	resErr.Value = Name
+	resErr.Message = fmt.Sprintf("Error while processing the template :: %s", err.Error())
+	return resErr
+}
+
+// Returns the templates of the web server instance, which are created if they have not been created already.
+func (srv *HttpServer) getTemplates() *templateSet {
+	if srv.templates == nil {
+		srv.templates = new(templateSet)
+	}
+	return srv.templates
+}
+
+// Parses the HTML template files matching the given glob pattern (like "views/*.html"), with the given functions made available to the templates. The templates are rendered using Render() on the response,
+// with the base name of the template file as the template name. An error is returned if no files match the pattern or if any of the files could not be parsed.
+func (srv *HttpServer) SetTemplates(glob string, funcs template.FuncMap) error {
+	templates := srv.getTemplates()
+	templates.glob = glob
+	templates.funcs = funcs
+	return templates.load()
+}
+
+// Sets the template file with the given base name (like "layout.html") as the layout, through which all the other templates are rendered. The layout can define blocks which are overridden by the templates.
+// An empty name removes the layout. An error is returned if the templates have already been set and the layout is not among them.
+func (srv *HttpServer) SetTemplateLayout(layout string) error {
+	templates := srv.getTemplates()
+	previousLayout := templates.layout
+	templates.layout = layout
+	if templates.glob == "" {
+		return nil
+	}
+
+	err := templates.load()
+	if err != nil {
+		templates.layout = previousLayout
+		return err
+	}
+
+	return nil
+}
+
+// Enables or disables the parsing of the template files for every response rendered, so that changes made to the templates are picked up without restarting the server. This is meant to be used only
+// during development, as parsing the templates for every response is slow. It is disabled by default.
+func (srv *HttpServer) AutoReloadTemplates(enabled bool) {
+	srv.getTemplates().autoReload = enabled
+}
+
+// Watches the folders containing the template files for changes and parses the templates again once the changes stop, so that changes made to the templates are picked up without restarting the server
+// and without parsing the templates for every response (as done by AutoReloadTemplates()). The folders are scanned once every "watch_interval" and the templates are parsed once no changes have been
+// detected for "watch_debounce" (both server defaults). If the templates cannot be parsed, the error is logged and the previously parsed templates are kept. Watching stops once the server shuts down.
+// An error is returned if the templates have not been set using SetTemplates().
+func (srv *HttpServer) WatchTemplates() error {
+	templates := srv.getTemplates()
+	templateFiles, err := filepath.Glob(templates.glob)
+	if templates.glob == "" || err != nil || len(templateFiles) == 0 {
+		resErr := new(ResponseError)
+		resErr.Section = "Template"
+		resErr.Value = templates.glob
+		resErr.Message = "WatchTemplates: Templates must be set using SetTemplates() before they can be watched"
+		return resErr
+	}
+
+	watcher := fs.NewWatcher(getDefaultDuration("watch_interval"), getDefaultDuration("watch_debounce"), func(ChangedPaths []string) {
+		// Changes to the other files present in the folders (like editor backups) do not require the templates to be parsed again.
+		if !slices.ContainsFunc(ChangedPaths, func(changedPath string) bool { matched, _ := filepath.Match(templates.glob, changedPath); return matched }) {
+			return
+		}
+
+		if err := templates.load(); err != nil {
+			srv.LogError(fmt.Sprintf("Error occurred while reloading the templates: %s", err.Error()))
+			return
+		}
+		srv.LogInfo("Templates have been reloaded", "changes", len(ChangedPaths))
+	})
+
+	for _, templateFile := range templateFiles {
+		if err := watcher.Add(filepath.Dir(templateFile)); err != nil {
+			return err
+		}
+	}
+
+	srv.runWatcher(watcher)
+	return nil
+}
+
+// Renders the HTML template with the given name (the base name of the template file) using the given data and sends it as response back to the client with the given status code.
+// An error is returned if the templates have not been set using SetTemplates() on the web server instance, or if the template could not be rendered.
+func (res *HttpResponse) Render(status StatusCode, name string, data any) error {
+	if res.templates == nil || res.templates.glob == "" {
+		resErr := new(ResponseError)
+		resErr.Section = "Template"
+		resErr.Value = name
+		resErr.Message = "Templates have not been set using SetTemplates() for the web server instance"
+		return resErr
+	}
+
+	responseContent, err := res.templates.render(name, data)
+	if err != nil {
+		return err
+	}
+
+	res.Status(status)
+	res.Headers.Add("Content-Type", HTML_CONTENT_TYPE)
+	res.Headers.Add("Content-Length", strconv.Itoa(len(responseContent)))
+	res.Body = responseContent
+	return res.write()
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the rendering of HTML templates, with and without a layout and partials, along with the reloading of modified templates.
+func Test_Response_Render(t *testing.T) {
+	testFolder := t.TempDir()
+	os.WriteFile(filepath.Join(testFolder, "layout.html"), []byte(`<html><title>{{block "title" .}}Proteus{{end}}</title><body>{{block "content" .}}{{end}}</body></html>`), 0644)
+	os.WriteFile(filepath.Join(testFolder, "user.html"), []byte(`{{define "title"}}{{.Name}}{{end}}{{define "content"}}<p>{{shout .Name}}</p>{{end}}`), 0644)
+	os.WriteFile(filepath.Join(testFolder, "home.html"), []byte(`{{define "content"}}<p>{{.Name}} &amp; {{template "_nav.html"}}</p>{{end}}`), 0644)
+	os.WriteFile(filepath.Join(testFolder, "_nav.html"), []byte(`home`), 0644)
+	funcs := template.FuncMap{ "shout": strings.ToUpper }
+	testCases := []struct {
+		Name string
+		Layout string
+		ViewName string
+		Data any
+		NewContents string
+		ExpError bool
+		ExpBody string
+	} {
+		{ "Template rendered without a layout", "", "user.html", map[string]string{ "Name": "<b>ann</b>" }, "", false, "" },
+		{ "Template rendered through the layout", "layout.html", "user.html", map[string]string{ "Name": "<b>ann</b>" }, "", false, "<html><title>&lt;b&gt;ann&lt;/b&gt;</title><body><p>&lt;B&gt;ANN&lt;/B&gt;</p></body></html>" },
+		{ "Layout block not overridden by the template", "layout.html", "home.html", map[string]string{ "Name": "bob" }, "", false, "<html><title>Proteus</title><body><p>bob &amp; home</p></body></html>" },
+		{ "Modified template reloaded", "layout.html", "home.html", map[string]string{ "Name": "bob" }, `{{define "content"}}<h1>{{.Name}}</h1>{{end}}`, false, "<html><title>Proteus</title><body><h1>bob</h1></body></html>" },
+		{ "Template that does not exist", "", "missing.html", nil, "", true, "" },
+		{ "Partial rendered as a template through the layout", "layout.html", "_nav.html", nil, "", true, "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testServer := NewServer()
+			testServer.AutoReloadTemplates(testCase.NewContents != "")
+			err := testServer.SetTemplates(filepath.Join(testFolder, "*.html"), funcs)
+			if err == nil {
+				err = testServer.SetTemplateLayout(testCase.Layout)
+			}
+
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while setting the templates and yet received one - %v", err)
+			}
+
+			if testCase.NewContents != "" {
+				os.WriteFile(filepath.Join(testFolder, testCase.ViewName), []byte(testCase.NewContents), 0644)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.templates = testServer.templates
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			err = testResponse.Render(StatusOK, testCase.ViewName, testCase.Data)
+			contentType, _ := testResponse.Headers.Get("Content-Type")
+			if testCase.ExpError {
+				if err == nil {
+					tt.Errorf("Expected an error while rendering the template, but got none")
+				} else {
+					tt.Logf("Received the expected error - %v", err)
+				}
+			} else if err != nil {
+				tt.Errorf("Was not expecting an error while rendering the template and yet received one - %v", err)
+			} else if testCase.ExpBody != "" && string(testResponse.Body) != testCase.ExpBody {
+				tt.Errorf("Expected the rendered body [%s], but got [%s]", testCase.ExpBody, string(testResponse.Body))
+			} else if testResponse.StatusCode != int(StatusOK) || contentType != HTML_CONTENT_TYPE {
+				tt.Errorf("Expected status 200 with content type [%s], but got status %d with content type [%s]", HTML_CONTENT_TYPE, testResponse.StatusCode, contentType)
+			} else {
+				tt.Logf("The template has been rendered as expected - %s", string(testResponse.Body))
+			}
+		})
+	}
+}
+
+// Test case to validate that the templates watched using WatchTemplates() are parsed again once a template file changes, and that templates must be set before they can be watched.
+func Test_Server_WatchTemplates(t *testing.T) {
+	originalInterval, originalDebounce := ServerDefaults["watch_interval"], ServerDefaults["watch_debounce"]
+	ServerDefaults["watch_interval"], ServerDefaults["watch_debounce"] = "10ms", "0s"
+	defer func() {
+		ServerDefaults["watch_interval"], ServerDefaults["watch_debounce"] = originalInterval, originalDebounce
+	}()
+
+	testFolder := t.TempDir()
+	os.WriteFile(filepath.Join(testFolder, "home.html"), []byte(`<p>{{.}}</p>`), 0644)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	defer testServer.cancelBaseContext()
+	if err := testServer.WatchTemplates(); err == nil {
+		t.Fatalf("Was expecting an error while watching the templates before they were set")
+	}
+
+	testServer.SetTemplates(filepath.Join(testFolder, "*.html"), nil)
+	if err := testServer.WatchTemplates(); err != nil {
+		t.Fatalf("Was not expecting an error while watching the templates and yet received one - %v", err)
+	}
+
+	os.WriteFile(filepath.Join(testFolder, "home.html"), []byte(`<h1>{{.}}</h1>`), 0644)
+	for attempt := 0; attempt < 500; attempt++ {
+		content, err := testServer.templates.render("home.html", "ann")
+		if err == nil && string(content) == "<h1>ann</h1>" {
+			t.Logf("The modified template has been parsed again as expected")
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Errorf("Expected the modified template to be parsed again once it changed")
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Structure to represent the outcome of a handler executed by the timeout middleware.
+type timeoutResult struct {
+	// Error returned by the handler.
+	err error
+	// Value recovered from the panic raised by the handler. It is nil if the handler did not panic.
+	recovered any
+	// Stack trace of the goroutine at the time of the panic raised by the handler.
+	stack []byte
+}
+
+// Returns a middleware which limits the time taken by the wrapped handler to the given duration. The request context passed to the handler is cancelled once the duration elapses and if the handler
+// has not completed by then, a 503 (Service Unavailable) response is sent without waiting for the handler any further. The handler writes to a buffered copy of the response, which is sent only if the
+// handler completes in time, and hence it must not stream the response (using WriteChunk(), Writer(), Flush(), EventStream() or UpgradeWebSocket()).
+func Timeout(duration time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			ctx, cancel := context.WithTimeout(request.Context(), duration)
+			defer cancel()
+			timedRequest := *request
+			timedRequest.ctx = ctx
+			timedResponse := response.newBufferedCopy(ctx)
+			timedResponse.request = &timedRequest
+			completed := make(chan timeoutResult, 1)
+			go func() {
+				var result timeoutResult
+				defer func() {
+					if recovered := recover(); recovered != nil {
+						result.recovered = recovered
+						result.stack = debug.Stack()
+					}
+					completed <- result
+				}()
+				result.err = next(&timedRequest, timedResponse)
+			}()
+
+			select {
+			case result := <-completed:
+				if result.recovered != nil {
+					panic(result.recovered)
+				}
+
+				response.copyFrom(timedResponse)
+				return result.err
+			case <-ctx.Done():
+				go func() {
+					// The handler keeps running in the background until it returns, and a panic raised by it can only be logged.
+					if result := <-completed; result.recovered != nil {
+						request.Logger().Error("Panic occurred while processing the request after it timed out", "path", request.ResourcePath, "panic", fmt.Sprint(result.recovered), "stack", string(result.stack))
+					}
+				}()
+
+				if ctx.Err() != context.DeadlineExceeded {
+					// The client has disconnected or the server is shutting down, and hence there is no one to send the response to.
+					return ctx.Err()
+				}
+
+				response.Status(StatusServiceUnavailable)
+				return handleError(request, response)
+			}
+		}
+	}
+}
+
+// Returns a copy of the response that is not attached to the response byte stream, so that a handler can populate it in a separate goroutine without affecting the response.
+// The copy has its own headers and uses the given context.
+func (res *HttpResponse) newBufferedCopy(ctx context.Context) *HttpResponse {
+	bufferedResponse := new(HttpResponse)
+	bufferedResponse.StatusCode = res.StatusCode
+	bufferedResponse.StatusMessage = res.StatusMessage
+	bufferedResponse.Version = res.Version
+	bufferedResponse.Headers = make(Headers)
+	for key, values := range res.Headers {
+		bufferedResponse.Headers[key] = append([]string(nil), values...)
+	}
+	bufferedResponse.Body = res.Body
+	bufferedResponse.isTest = res.isTest
+	bufferedResponse.acceptEncoding = res.acceptEncoding
+	bufferedResponse.errorHandlers = res.errorHandlers
+	bufferedResponse.templates = res.templates
+	bufferedResponse.isHeadRequest = res.isHeadRequest
+	bufferedResponse.rangeHeader = res.rangeHeader
+	bufferedResponse.ifRangeHeader = res.ifRangeHeader
+	bufferedResponse.ctx = ctx
+	return bufferedResponse
+}
+
+// Replaces the status, headers and body of the response with those of the given buffered copy, along with the functions registered on the copy to be executed before the response is written.
+func (res *HttpResponse) copyFrom(bufferedResponse *HttpResponse) {
+	res.StatusCode = bufferedResponse.StatusCode
+	res.StatusMessage = bufferedResponse.StatusMessage
+	res.Headers = bufferedResponse.Headers
+	res.Body = bufferedResponse.Body
+	res.closeConnection = res.closeConnection || bufferedResponse.closeConnection
+	res.beforeWriteHooks = append(res.beforeWriteHooks, bufferedResponse.beforeWriteHooks...)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Test case to validate the responses sent by the timeout middleware for handlers completing before and after the timeout.
+func Test_Server_Timeout(t *testing.T) {
+	handlerCancelled := make(chan bool, 1)
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Get("/fast", func(req *HttpRequest, res *HttpResponse) error {
+		res.Headers.Add("X-Handler", "fast")
+		res.Status(StatusCreated)
+		res.Body = []byte("done")
+		return nil
+	}, Timeout(time.Second))
+	testServer.Get("/slow", func(req *HttpRequest, res *HttpResponse) error {
+		select {
+		case <-req.Context().Done():
+			handlerCancelled <- true
+		case <-time.After(time.Second):
+			handlerCancelled <- false
+		}
+		res.Headers.Add("X-Handler", "slow")
+		return nil
+	}, Timeout(20 * time.Millisecond))
+	testServer.Get("/panic", func(req *HttpRequest, res *HttpResponse) error {
+		panic("handler failure")
+	}, Timeout(time.Second))
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		ExpStatus int
+		ExpHandlerHeader string
+	} {
+		{ "Handler completing before the timeout", "/fast", int(StatusCreated), "fast" },
+		{ "Handler completing after the timeout", "/slow", int(StatusServiceUnavailable), "" },
+		{ "Handler panicking before the timeout", "/panic", int(StatusInternalServerError), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "GET"
+			testRequest.ResourcePath = testCase.ResourcePath
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			handlerHeader, _ := testResponse.Headers.Get("X-Handler")
+			if testResponse.StatusCode != testCase.ExpStatus || handlerHeader != testCase.ExpHandlerHeader {
+				tt.Errorf("Received status %d with X-Handler [%s], but expected status %d with X-Handler [%s]", testResponse.StatusCode, handlerHeader, testCase.ExpStatus, testCase.ExpHandlerHeader)
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+
+	if !<-handlerCancelled {
+		t.Errorf("Expected the context of the slow handler to be cancelled once the timeout elapsed")
+	}
+}
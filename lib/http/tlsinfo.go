@@ -0,0 +1,72 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// Details of the TLS connection on which a request has been received, like the certificates presented by the client when client authentication (mutual TLS) has been enabled in the TLS configuration.
+type TLSInfo struct {
+	// TLS version used by the connection, like tls.VersionTLS13.
+	Version uint16
+	// Cipher suite negotiated for the connection, like tls.TLS_AES_128_GCM_SHA256.
+	CipherSuite uint16
+	// Application protocol negotiated using ALPN, like "h2". It is empty if no protocol has been negotiated.
+	NegotiatedProtocol string
+	// Server name sent by the client using SNI. It is empty if the client did not send a server name.
+	ServerName string
+	// Certificates presented by the client, with the certificate of the client first. It is empty if the client did not present a certificate.
+	ClientCertificates []*x509.Certificate
+	// Chains verified from the certificate of the client up to a trusted root certificate, when the client certificates are verified as per the ClientAuth setting of the TLS configuration.
+	// It is empty if the certificates have not been verified.
+	VerifiedChains [][]*x509.Certificate
+}
+
+// Returns the details of the TLS connection on which the request has been received. It returns nil if the request has not been received over TLS.
+// A handler can authorize the client based on its verified certificate, like `req.TLS().ClientCertificates[0].Subject.CommonName`, once client authentication has been enabled in the TLS configuration of the server.
+func (req *HttpRequest) TLS() *TLSInfo {
+	return req.tlsInfo
+}
+
+// Returns the name of the TLS version used by the connection, like "TLS 1.3".
+func (info *TLSInfo) VersionName() string {
+	return tls.VersionName(info.Version)
+}
+
+// Returns the name of the cipher suite negotiated for the connection, like "TLS_AES_128_GCM_SHA256".
+func (info *TLSInfo) CipherSuiteName() string {
+	return tls.CipherSuiteName(info.CipherSuite)
+}
+
+// Creates the details of a TLS connection from the given connection state. It returns nil if the state is nil or the TLS handshake has not been completed.
+func newTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil || !state.HandshakeComplete {
+		return nil
+	}
+
+	info := new(TLSInfo)
+	info.Version = state.Version
+	info.CipherSuite = state.CipherSuite
+	info.NegotiatedProtocol = state.NegotiatedProtocol
+	info.ServerName = state.ServerName
+	info.ClientCertificates = state.PeerCertificates
+	info.VerifiedChains = state.VerifiedChains
+	return info
+}
+
+// Returns the state of the given TLS connection in the form of the state held by the requests of the net/http package. It returns nil if the request has not been received over TLS.
+func (info *TLSInfo) toConnectionState() *tls.ConnectionState {
+	if info == nil {
+		return nil
+	}
+
+	state := new(tls.ConnectionState)
+	state.HandshakeComplete = true
+	state.Version = info.Version
+	state.CipherSuite = info.CipherSuite
+	state.NegotiatedProtocol = info.NegotiatedProtocol
+	state.ServerName = info.ServerName
+	state.PeerCertificates = info.ClientCertificates
+	state.VerifiedChains = info.VerifiedChains
+	return state
+}
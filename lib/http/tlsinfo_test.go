@@ -0,0 +1,116 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Helper function to create a certificate with the given common name, signed by the given parent certificate. The certificate is self-signed if the parent is nil.
+func newTestCertificate(t testing.TB, CommonName string, parent *tls.Certificate, isCA bool) tls.Certificate {
+	t.Helper()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Was not expecting an error while generating the private key, but got this instead - %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{ CommonName: CommonName },
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter: time.Now().Add(time.Hour),
+		IsCA: isCA,
+		BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{ x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth },
+		IPAddresses: []net.IP{ net.ParseIP("127.0.0.1") },
+	}
+
+	signer, signerKey := template, any(privateKey)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, signer, &privateKey.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Was not expecting an error while creating the certificate, but got this instead - %v", err)
+	}
+
+	leaf, _ := x509.ParseCertificate(certificateBytes)
+	return tls.Certificate{ Certificate: [][]byte{ certificateBytes }, PrivateKey: privateKey, Leaf: leaf }
+}
+
+// Test case to validate the details of the TLS connection exposed on the requests, including the verified certificate presented by the client.
+func Test_Request_TLS(t *testing.T) {
+	authority := newTestCertificate(t, "Test CA", nil, true)
+	serverCertificate := newTestCertificate(t, "127.0.0.1", &authority, false)
+	clientCertificate := newTestCertificate(t, "billing-service", &authority, false)
+	certificatePool := x509.NewCertPool()
+	certificatePool.AddCert(authority.Leaf)
+
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(lockedBuffer), LevelDebug, TextLogFormat))
+	testServer.Get("/whoami", func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		info := req.TLS()
+		if info == nil {
+			res.Body = []byte("plain")
+		} else if len(info.VerifiedChains) == 0 {
+			res.Body = []byte("anonymous " + info.VersionName())
+		} else {
+			res.Body = []byte(info.ClientCertificates[0].Subject.CommonName + " " + info.VersionName() + " " + info.ServerName)
+		}
+		return nil
+	})
+
+	socket, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Was not expecting an error while creating the server socket, but got this instead - %v", err)
+	}
+	serverConfig := &tls.Config{ Certificates: []tls.Certificate{ serverCertificate }, ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: certificatePool, MinVersion: tls.VersionTLS13 }
+	go testServer.Serve(tls.NewListener(socket, serverConfig))
+	defer testServer.Shutdown()
+
+	testCases := []struct {
+		Name string
+		ClientCertificates []tls.Certificate
+		ExpBody string
+	} {
+		{ "Client presenting a verified certificate", []tls.Certificate{ clientCertificate }, "billing-service TLS 1.3 localhost" },
+		{ "Client without a certificate", nil, "anonymous TLS 1.3" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			clientConfig := &tls.Config{ RootCAs: certificatePool, Certificates: testCase.ClientCertificates, ServerName: "localhost", InsecureSkipVerify: true }
+			connection, err := tls.Dial("tcp", socket.Addr().String(), clientConfig)
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while connecting to the server, but got this instead - %v", err)
+			}
+			defer connection.Close()
+			connection.SetDeadline(time.Now().Add(2 * time.Second))
+			connection.Write([]byte("GET /whoami HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"))
+			response, _ := io.ReadAll(connection)
+			_, body, _ := strings.Cut(string(response), "\r\n\r\n")
+			if body != testCase.ExpBody {
+				tt.Errorf("Expected the response body [%s], but got this instead - %s", testCase.ExpBody, string(response))
+			} else {
+				tt.Logf("Received the response body [%s] as expected", body)
+			}
+		})
+	}
+
+	testRequest := newTestRequest(t)
+	if testRequest.TLS() != nil {
+		t.Errorf("Expected the TLS details to be nil for a request not received over TLS")
+	}
+}
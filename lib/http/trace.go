@@ -0,0 +1,47 @@
+package http
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Collection of request headers which are not echoed back in the response to a TRACE request, as they may contain credentials that must not be exposed to scripts reading the response.
+var traceExcludedHeaders = []string{ "Authorization", "Proxy-Authorization", "Cookie" }
+
+// Creates the response for a TRACE request by echoing the request line and the request headers received, as the body of the response with the "message/http" media type, so that the client
+// can see what has been received at the end of the request chain. The request body is not echoed and the headers which may contain credentials are left out.
+func (srv *HttpServer) handleTrace(httpRequest *HttpRequest, httpResponse *HttpResponse) {
+	requestTarget := httpRequest.RequestURI
+	if requestTarget == "" {
+		requestTarget = httpRequest.getRawPath()
+		if httpRequest.RawQuery != "" {
+			requestTarget += "?" + httpRequest.RawQuery
+		}
+	}
+
+	var message strings.Builder
+	message.WriteString(httpRequest.Method + REQUEST_LINE_SEPERATOR + requestTarget + REQUEST_LINE_SEPERATOR + "HTTP/" + httpRequest.Version + HEADER_LINE_SEPERATOR)
+	headerNames := make([]string, 0, len(httpRequest.Headers))
+	for headerName := range httpRequest.Headers {
+		if !slices.Contains(traceExcludedHeaders, headerName) {
+			headerNames = append(headerNames, headerName)
+		}
+	}
+
+	slices.Sort(headerNames)
+	for _, headerName := range headerNames {
+		headerValue, _ := httpRequest.Headers.Get(headerName)
+		message.WriteString(headerName + ": " + headerValue + HEADER_LINE_SEPERATOR)
+	}
+
+	message.WriteString(HEADER_LINE_SEPERATOR)
+	httpResponse.Status(StatusOK)
+	httpResponse.Headers.Add("Content-Type", "message/http")
+	httpResponse.Headers.Add("Content-Length", strconv.Itoa(message.Len()))
+	httpResponse.Body = []byte(message.String())
+	err := httpResponse.write()
+	if err != nil {
+		srv.getRequestLogger(httpRequest).Error(err.Error())
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Test case to validate the echo sent in response to TRACE requests, when it has been enabled in the server settings.
+func Test_Server_Trace(t *testing.T) {
+	handler := func(req *HttpRequest, res *HttpResponse) error {
+		res.Status(StatusOK)
+		return res.SendError("Handled by the route")
+	}
+
+	testCases := []struct {
+		Name string
+		EnableTrace bool
+		InputRequest string
+		ExpStatus int
+		ExpBody string
+	} {
+		{ "Echo of the request", true, "TRACE /users/abc?page=2 HTTP/1.1\r\nHost: example.com\r\nVia: 1.1 proxy\r\nMax-Forwards: 0\r\n\r\n", int(StatusOK), "TRACE /users/abc?page=2 HTTP/1.1\r\nHost: example.com\r\nMax-Forwards: 0\r\nVia: 1.1 proxy\r\n\r\n" },
+		{ "Echo without the credentials", true, "TRACE /orders HTTP/1.1\r\nHost: example.com\r\nAuthorization: Basic c2VjcmV0\r\nCookie: session=abc\r\nAccept: text/html, */*\r\n\r\n", int(StatusOK), "TRACE /orders HTTP/1.1\r\nAccept: text/html, */*\r\nHost: example.com\r\n\r\n" },
+		{ "Route defined for TRACE", true, "TRACE /traced HTTP/1.1\r\nHost: example.com\r\n\r\n", int(StatusOK), "Handled by the route" },
+		{ "Echo disabled for an existing route", false, "TRACE /users/abc HTTP/1.1\r\nHost: example.com\r\n\r\n", int(StatusMethodNotAllowed), "" },
+		{ "Echo disabled for an unknown route", false, "TRACE /unknown HTTP/1.1\r\nHost: example.com\r\n\r\n", int(StatusNotFound), "" },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testServer := NewServer()
+			testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+			testServer.Config.EnableTrace = testCase.EnableTrace
+			testServer.Get("/users/:name", handler)
+			testServer.Trace("/traced", handler)
+			testRequest := newTestRequest(tt)
+			testRequest.setReader(bufio.NewReader(strings.NewReader(testCase.InputRequest)))
+			err := testRequest.read()
+			if err != nil {
+				tt.Fatalf("Was not expecting an error while parsing the request and yet received one - %v", err)
+			}
+
+			testResponse := newTestResponse(tt, "1.1")
+			var opBuffer bytes.Buffer
+			testResponse.setWriter(bufio.NewWriter(&opBuffer))
+			testServer.processRequest(testRequest, testResponse)
+			testResponse.end()
+			responseHead, responseBody, _ := strings.Cut(opBuffer.String(), "\r\n\r\n")
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("Expected status %d, but got status %d", testCase.ExpStatus, testResponse.StatusCode)
+			} else if testCase.ExpBody != "" && responseBody != testCase.ExpBody {
+				tt.Errorf("Expected the response body to be [%q], but got [%q]", testCase.ExpBody, responseBody)
+			} else if testCase.ExpStatus == int(StatusMethodNotAllowed) && strings.Contains(responseHead, "TRACE") {
+				tt.Errorf("Was not expecting TRACE to be allowed when the echo is disabled, but got [%q]", responseHead)
+			} else {
+				tt.Logf("Received status %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+}
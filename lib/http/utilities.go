@@ -1,47 +1,252 @@
 package http
 
 import (
+	"container/list"
 	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"mime"
 	"net"
+	nethttp "net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"github.com/mkbworks/proteus/lib/config"
 	"github.com/mkbworks/proteus/lib/fs"
 )
 
-// Returns the file media type for the given file path.
-func getContentType(CompleteFilePath string) (string, bool) {
+// Returns the file media type for the given file path in the local file system. The given content types, registered in the web server instance sending the file, take precedence over the configured content types
+// and the given default content type (if not empty) replaces the "content_type" server default.
+func getContentType(CompleteFilePath string, contentTypes map[string]string, defaultContentType string) (string, bool) {
 	pathType, err := fs.GetPathType(CompleteFilePath)
-	if err == nil {
-		if pathType == fs.FILE_TYPE_PATH {
-			fileExtension := filepath.Ext(CompleteFilePath)
-			fileExtension = strings.TrimSpace(fileExtension)
-			fileExtension = strings.ToLower(fileExtension)
-			contentType, exists := AllowedContentTypes[fileExtension]
-			if exists {
-				return contentType, exists
-			} else {
-				return strings.TrimSpace(ServerDefaults["content_type"]), true
-			}
+	if err == nil && pathType == fs.FILE_TYPE_PATH {
+		return getFileContentType(fs.OSFileSystem{}, CompleteFilePath, contentTypes, defaultContentType), true
+	}
+	return "", false
+}
+
+// Returns the media type of the file available at the given path in the given file system, without checking if the path points to a file. The media type is resolved from the extension of the file
+// in the same order as in getContentType(). For extensions which are not known, the given default content type (if not empty) is used. Otherwise, the media type is determined by inspecting the contents
+// of the file (unless the "content_sniffing" server default is off), before falling back to the "content_type" server default.
+func getFileContentType(FileSystem fs.FileSystem, FilePath string, contentTypes map[string]string, defaultContentType string) string {
+	if contentType, found := getExtensionContentType(FilePath, contentTypes); found {
+		return contentType
+	} else if defaultContentType != "" {
+		return defaultContentType
+	}
+
+	if !strings.EqualFold(getServerDefaults("content_sniffing"), "off") {
+		// Contents which do not match any of the known media types are sent with the "content_type" server default instead of application/octet-stream.
+		if contentType, err := fs.SniffContentType(FileSystem, FilePath); err == nil && contentType != "application/octet-stream" {
+			return contentType
 		}
 	}
+
+	return getServerDefaults("content_type")
+}
+
+// Returns the media type registered for the extension of the given file path, either in the given content types (which take precedence), in the configured content types or in the mime package.
+// The boolean value returned is false if the extension is not known.
+func getExtensionContentType(FilePath string, contentTypes map[string]string) (string, bool) {
+	fileExtension := getFileExtension(filepath.Ext(FilePath))
+	if contentType, exists := contentTypes[fileExtension]; exists {
+		return contentType, true
+	}
+
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	if contentType, exists := AllowedContentTypes[fileExtension]; exists {
+		return contentType, true
+	} else if contentType = mime.TypeByExtension("." + fileExtension); fileExtension != "" && contentType != "" {
+		// Extensions which have not been configured are resolved using the media types known to the mime package, which includes the media types registered in the operating system.
+		return contentType, true
+	}
+
 	return "", false
 }
 
+// Joins the given folder path and file name, using the separator of the operating system for the local file system or '/' for the other file systems (as required by io/fs).
+func joinFilePath(FileSystem fs.FileSystem, FolderPath string, FileName string) string {
+	if _, isLocal := FileSystem.(fs.OSFileSystem); isLocal {
+		return filepath.Join(FolderPath, FileName)
+	}
+	return path.Join(FolderPath, FileName)
+}
+
+// Returns the given file extension in lower case, without the leading '.'.
+func getFileExtension(Extension string) string {
+	Extension = strings.TrimSpace(Extension)
+	Extension = strings.ToLower(Extension)
+	return strings.TrimPrefix(Extension, ".")
+}
+
+// Generates an entity tag for the given file. By default, a weak entity tag is generated from the size and the last modified time of the file.
+// If the "etag_mode" server default is set to "strong", or the file has no last modified time (like the files of an embed.FS), a strong entity tag is generated from the SHA-256 hash of the file contents instead.
+func generateETag(FileSystem fs.FileSystem, CompleteFilePath string, file *fs.File) (string, error) {
+	if strings.EqualFold(getServerDefaults("etag_mode"), "strong") || file.LastModifiedAt.IsZero() {
+		fileContents, err := fs.ReadFileContentsFS(FileSystem, CompleteFilePath)
+		if err != nil {
+			return "", err
+		}
+
+		contentHash := sha256.Sum256(fileContents)
+		return fmt.Sprintf("\"%x\"", contentHash[:16]), nil
+	}
+
+	return fmt.Sprintf("W/\"%x-%x\"", file.Size, file.LastModifiedAt.UnixNano()), nil
+}
+
+// Checks if the given entity tag matches any of the entity tags present in the given If-None-Match header value. As per RFC 9110, weak comparison is used for If-None-Match.
+func isETagMatch(IfNoneMatch string, ETag string) bool {
+	IfNoneMatch = strings.TrimSpace(IfNoneMatch)
+	if IfNoneMatch == "*" {
+		return true
+	}
+
+	ETag = strings.TrimPrefix(strings.TrimSpace(ETag), "W/")
+	for _, candidate := range strings.Split(IfNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate != "" && candidate == ETag {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Returns the default port number from the list of default configuration values.
 func getDefaultPort() int {
-	portNumberValue := ServerDefaults["port"]
+	portNumberValue := getServerDefaults("port")
 	portNumber, _ := strconv.Atoi(portNumberValue)
 	return portNumber
 }
 
+// Returns the maximum size (in bytes) allowed for a request body from the list of default configuration values. A value of zero or less means that the request body size is not limited.
+func getMaxBodySize() int64 {
+	maxBodySizeValue := getServerDefaults("max_body_size")
+	maxBodySize, err := strconv.ParseInt(maxBodySizeValue, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return maxBodySize
+}
+
+// Returns the integer value for the given key from the list of default configuration values. Zero is returned if the value is not a valid integer.
+func getDefaultInt(key string) int {
+	intValue, err := strconv.Atoi(getServerDefaults(key))
+	if err != nil {
+		return 0
+	}
+
+	return intValue
+}
+
+// Returns the duration value for the given key from the list of default configuration values. A zero duration is returned if the value is not a valid duration string.
+func getDefaultDuration(key string) time.Duration {
+	durationValue := getServerDefaults(key)
+	duration, err := time.ParseDuration(durationValue)
+	if err != nil {
+		return 0
+	}
+
+	return duration
+}
+
+// Returns the time at which an operation started at the given time must time out. The zero time, which means no deadline, is returned if the given timeout is zero.
+func getDeadline(start time.Time, timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+
+	return start.Add(timeout)
+}
+
+// Checks if the given error was raised because a network operation timed out.
+func isTimeoutError(err error) bool {
+	var netError net.Error
+	return errors.As(err, &netError) && netError.Timeout()
+}
+
+// Returns the normalized form of the given request path, which is used for routing. Percent-encoded characters are decoded, except the characters that would change the meaning of the path once
+// decoded ('/', '%' and control characters), which are kept encoded in upper case. Empty segments are removed and dot-segments are resolved as per RFC 3986, without going above the root.
+// Request targets which are not absolute paths (like "*") are returned as they are. An error is returned if the path contains an invalid percent-encoding.
+func normalizePath(RequestPath string) (string, error) {
+	if !strings.HasPrefix(RequestPath, "/") {
+		return RequestPath, nil
+	}
+
+	var decodedPath strings.Builder
+	for index := 0; index < len(RequestPath); index++ {
+		if RequestPath[index] != '%' {
+			decodedPath.WriteByte(RequestPath[index])
+			continue
+		}
+
+		if index + 2 >= len(RequestPath) || !isHexDigit(RequestPath[index + 1]) || !isHexDigit(RequestPath[index + 2]) {
+			return "", fmt.Errorf("Request path contains an invalid percent-encoding at position %d", index)
+		}
+
+		decodedByte, _ := strconv.ParseUint(RequestPath[index + 1: index + 3], 16, 8)
+		if decodedByte == '/' || decodedByte == '%' || decodedByte < 0x20 || decodedByte == 0x7F {
+			decodedPath.WriteString(strings.ToUpper(RequestPath[index: index + 3]))
+		} else {
+			decodedPath.WriteByte(byte(decodedByte))
+		}
+		index += 2
+	}
+
+	pathSegments := strings.Split(decodedPath.String(), "/")
+	normalizedSegments := make([]string, 0, len(pathSegments))
+	for _, pathSegment := range pathSegments {
+		switch pathSegment {
+		case "", ".":
+		case "..":
+			if len(normalizedSegments) > 0 {
+				normalizedSegments = normalizedSegments[:len(normalizedSegments) - 1]
+			}
+		default:
+			normalizedSegments = append(normalizedSegments, pathSegment)
+		}
+	}
+
+	normalizedPath := "/" + strings.Join(normalizedSegments, "/")
+	lastSegment := pathSegments[len(pathSegments) - 1]
+	if len(normalizedSegments) > 0 && (lastSegment == "" || lastSegment == "." || lastSegment == "..") {
+		// The trailing '/' is retained, as is the one implied by a trailing dot-segment.
+		normalizedPath += "/"
+	}
+
+	return normalizedPath, nil
+}
+
+// Returns the given normalized request path with the characters which are not allowed in a request path percent-encoded, so that it can be sent back to the client (like in the Location header).
+func escapePath(NormalizedPath string) string {
+	var escapedPath strings.Builder
+	for index := 0; index < len(NormalizedPath); index++ {
+		char := NormalizedPath[index]
+		if isUnreservedChar(char) || strings.IndexByte("!$&'()*+,;=:@/%", char) != -1 {
+			escapedPath.WriteByte(char)
+		} else {
+			escapedPath.WriteString(fmt.Sprintf("%%%02X", char))
+		}
+	}
+
+	return escapedPath.String()
+}
+
 // Returns the value for the given key from server default configuration values.
 func getServerDefaults(key string) string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
 	value := ServerDefaults[strings.TrimSpace(key)]
 	value = strings.TrimSpace(value)
 	return value
@@ -52,7 +257,8 @@ func getHighestVersion() string {
 	var maxVersion float64 = 0.0
 	for versionNo := range Versions {
 		currentVersion, err := strconv.ParseFloat(versionNo, 64)
-		if err == nil {
+		// HTTP/2 is never spoken over the textual HTTP/1.x byte stream and hence is not considered here.
+		if err == nil && currentVersion < 2 {
 			if currentVersion > maxVersion {
 				maxVersion = currentVersion
 			}
@@ -100,7 +306,7 @@ func getResponseVersion(requestVersion string) string {
 	isCompatible := false
 
 	for _, version := range getAllVersions() {
-		if strings.EqualFold(version, requestVersion) {
+		if strings.EqualFold(version, requestVersion) && version != HTTP2_VERSION {
 			isCompatible = true
 			break
 		}
@@ -113,13 +319,44 @@ func getResponseVersion(requestVersion string) string {
 	}
 }
 
-// Creates and returns pointer to a new instance of HTTP request.
-func newRequest(Connection net.Conn) *HttpRequest {
+// Returns the host name present in the given value of the Host header in lower case, without the port number and without the brackets enclosing an IPv6 address.
+func getHostname(Host string) string {
+	hostname, _, err := net.SplitHostPort(Host)
+	if err != nil {
+		hostname = strings.TrimSuffix(strings.TrimPrefix(Host, "["), "]")
+	}
+
+	return strings.ToLower(hostname)
+}
+
+// Creates and returns pointer to a new HttpError with the given status code and message, which can be returned by a handler to send an error response like "return http.NewError(404, "user not found")".
+func NewError(Status StatusCode, Message string) *HttpError {
+	httpError := new(HttpError)
+	httpError.Status = Status
+	httpError.Message = Message
+	return httpError
+}
+
+// Creates and returns pointer to a new instance of StaticFileCache, which caches files of size up to the given maximum entry size (in bytes) with the given total budget (in bytes).
+func NewStaticFileCache(MaxEntrySize int64, MaxSize int64) *StaticFileCache {
+	cache := new(StaticFileCache)
+	cache.MaxEntrySize = MaxEntrySize
+	cache.MaxSize = MaxSize
+	cache.entries = make(map[string]*list.Element)
+	cache.usageOrder = list.New()
+	return cache
+}
+
+// Creates and returns pointer to a new instance of HTTP request. The given reader is shared by all the requests received over the same client connection.
+func newRequest(Connection net.Conn, reader *bufio.Reader) *HttpRequest {
 	var httpRequest HttpRequest
 	httpRequest.initialize()
-	reader := bufio.NewReader(Connection)
 	httpRequest.setReader(reader)
 	httpRequest.ClientAddress = Connection.RemoteAddr().String()
+	if tlsConnection, ok := Connection.(*tls.Conn); ok {
+		state := tlsConnection.ConnectionState()
+		httpRequest.tlsInfo = newTLSInfo(&state)
+	}
 	return &httpRequest
 }
 
@@ -127,23 +364,156 @@ func newRequest(Connection net.Conn) *HttpRequest {
 func newResponse(Connection net.Conn, request *HttpRequest) *HttpResponse {
 	var httpResponse HttpResponse
 	httpResponse.initialize(getResponseVersion(request.Version), false)
+	httpResponse.acceptEncoding, _ = request.Headers.Get("Accept-Encoding")
+	httpResponse.isHeadRequest = strings.EqualFold(request.Method, "HEAD")
+	if strings.EqualFold(request.Method, "GET") {
+		httpResponse.rangeHeader, _ = request.Headers.Get("Range")
+		httpResponse.ifRangeHeader, _ = request.Headers.Get("If-Range")
+	}
+	httpResponse.connection = Connection
 	writer := bufio.NewWriter(Connection)
 	httpResponse.setWriter(writer)
 	return &httpResponse
 }
 
+// Returns the given connection wrapped so that the writes to it are aborted if the client reads them slower than the given minimum rate (in bytes per second), after the given grace period.
+// The connection is returned as is if the minimum rate is not positive.
+func newMinRateConn(Connection net.Conn, MinRate int64, GracePeriod time.Duration) net.Conn {
+	if MinRate <= 0 {
+		return Connection
+	}
+
+	conn := new(minRateConn)
+	conn.Conn = Connection
+	conn.minRate = MinRate
+	conn.gracePeriod = GracePeriod
+	return conn
+}
+
+// Creates and returns pointer to a new instance of HttpRequest from the given request received as a HTTP/2 stream. The request body is read completely, as done for HTTP/1.x requests.
+// If a router is given, the request body is limited to the maximum body size set for the route matching the request.
+func newHTTP2Request(request *nethttp.Request, router *Router) (*HttpRequest, error) {
+	var httpRequest HttpRequest
+	httpRequest.initialize()
+	httpRequest.Method = request.Method
+	httpRequest.ResourcePath = request.RequestURI
+	httpRequest.Version = HTTP2_VERSION
+	httpRequest.ClientAddress = request.RemoteAddr
+	httpRequest.tlsInfo = newTLSInfo(request.TLS)
+	httpRequest.Headers.Add("Host", request.Host)
+	for key, values := range request.Header {
+		for _, value := range values {
+			err := httpRequest.addHeader(key, value)
+			if err != nil {
+				return &httpRequest, err
+			}
+		}
+	}
+
+	err := httpRequest.parseQueryParams()
+	if err != nil {
+		return &httpRequest, err
+	}
+
+	if router != nil {
+		httpRequest.maxBodySize = router.getMaxBodySize(httpRequest.Method, httpRequest.ResourcePath)
+	}
+
+	maxBodySize := httpRequest.getMaxBodySize()
+	bodyReader := io.Reader(request.Body)
+	if maxBodySize > 0 {
+		bodyReader = io.LimitReader(request.Body, maxBodySize + 1)
+	}
+
+	httpRequest.Body, err = io.ReadAll(bodyReader)
+	if err != nil {
+		reqError := new(RequestParseError)
+		reqError.Section = "Body"
+		reqError.Value = "Request Body"
+		reqError.Message = err.Error()
+		reqError.Status = StatusBadRequest
+		return &httpRequest, reqError
+	}
+
+	if maxBodySize > 0 && int64(len(httpRequest.Body)) > maxBodySize {
+		reqError := new(RequestParseError)
+		reqError.Section = "Body"
+		reqError.Value = "Request Body"
+		reqError.Message = fmt.Sprintf("Request body size exceeds the maximum allowed size of %d bytes", maxBodySize)
+		reqError.Status = StatusContentTooLarge
+		return &httpRequest, reqError
+	}
+
+	for key, values := range request.Trailer {
+		for _, value := range values {
+			httpRequest.Trailers.Add(key, value)
+		}
+	}
+
+	httpRequest.ContentLength = len(httpRequest.Body)
+	return &httpRequest, nil
+}
+
+// Creates and returns pointer to a new instance of HTTP response, which is sent on the HTTP/2 stream of the given writer.
+func newHTTP2Response(writer nethttp.ResponseWriter, request *HttpRequest) *HttpResponse {
+	var httpResponse HttpResponse
+	httpResponse.initialize(HTTP2_VERSION, false)
+	httpResponse.acceptEncoding, _ = request.Headers.Get("Accept-Encoding")
+	httpResponse.isHeadRequest = strings.EqualFold(request.Method, "HEAD")
+	if strings.EqualFold(request.Method, "GET") {
+		httpResponse.rangeHeader, _ = request.Headers.Get("Range")
+		httpResponse.ifRangeHeader, _ = request.Headers.Get("If-Range")
+	}
+	httpResponse.http2Writer = writer
+	httpResponse.request = request
+	httpResponse.setWriter(bufio.NewWriter(writer))
+	return &httpResponse
+}
+
+// Creates and returns pointer to a new instance of WebSocket, which exchanges messages over the given connection. The maximum message size is read from the default configuration values.
+func newWebSocket(Connection net.Conn, reader *bufio.Reader, writer *bufio.Writer, writeTimeout time.Duration) *WebSocket {
+	ws := new(WebSocket)
+	ws.connection = Connection
+	ws.reader = reader
+	ws.writer = writer
+	ws.writeTimeout = writeTimeout
+	ws.MaxMessageSize, _ = strconv.ParseInt(getServerDefaults("websocket_max_message_size"), 10, 64)
+	return ws
+}
+
 // Creates and returns pointer to a new instance of Router.
 func newRouter() *Router {
 	router := new(Router)
 	router.Routes = make([]Route, 0)
 	router.RouteTree = createTree()
+	router.disabledRoutes = make(map[string]bool)
 	return router
 }
 
-// Returns the current UTC time in RFC 1123 format.
+// Creates and returns pointer to a new instance of Router, which can be used to define routes separately from a web server instance (like in another package) and attach them to the server using Mount().
+func NewRouter() *Router {
+	return newRouter()
+}
+
+// Creates and returns pointer to a new instance of RouteGroup.
+func newRouteGroup(router *Router, parent *RouteGroup, Prefix string, middlewares []Middleware) *RouteGroup {
+	group := new(RouteGroup)
+	group.Prefix = cleanRoute(Prefix)
+	group.router = router
+	group.parent = parent
+	group.middlewares = make([]Middleware, 0)
+	group.middlewares = append(group.middlewares, middlewares...)
+	return group
+}
+
+// Returns the current time in the RFC 1123 format used for HTTP dates, which is always expressed in GMT.
 func getRfc1123Time() string {
-	currentTime := time.Now().UTC()
-	return currentTime.Format(time.RFC1123)
+	return formatHttpDate(time.Now())
+}
+
+// Returns the given time in the RFC 1123 format used for HTTP dates (like "Sun, 06 Nov 1994 08:49:37 GMT"), after converting it to UTC.
+func formatHttpDate(value time.Time) string {
+	return value.UTC().Format(HTTP_DATE_FORMAT)
 }
 
 // Checks if the given date time value corresponds to a valid HTTP date and returns two values.
@@ -168,27 +538,168 @@ func isHttpDate(value string) (bool, time.Time) {
 // Removes all but one leading '/' and all the trailing '/' from the given route path and returns the cleaned value.
 func cleanRoute(RoutePath string) string {
 	RoutePath = strings.TrimSpace(RoutePath)
-	RoutePath = strings.ToLower(RoutePath)
+	RoutePath = lowerRoute(RoutePath)
 	RoutePath = strings.TrimRight(RoutePath, "/")
 	RoutePath = strings.TrimLeft(RoutePath, "/")
 	RoutePath = "/" + RoutePath
 	return RoutePath
 }
 
-// Returns a pointer to a newly created instance of Logger.
-func newLogger() *logger {
+// Joins the given route prefix and route path into a single route path.
+func joinRoute(Prefix string, RoutePath string) string {
+	Prefix = strings.TrimRight(cleanRoute(Prefix), "/")
+	RoutePath = strings.Trim(strings.TrimSpace(RoutePath), "/")
+	if RoutePath == "" {
+		return cleanRoute(Prefix)
+	}
+
+	return cleanRoute(Prefix + "/" + RoutePath)
+}
+
+// Creates and returns pointer to a new in-memory session store, where sessions expire once they have not been saved for the given time to live.
+// If the given time to live is not positive, the "session_ttl" server default is used instead.
+func NewMemorySessionStore(TTL time.Duration) *MemorySessionStore {
+	store := new(MemorySessionStore)
+	if TTL <= 0 {
+		TTL = getDefaultDuration("session_ttl")
+	}
+
+	store.TTL = TTL
+	store.sessions = make(map[string]memorySessionEntry)
+	store.lastEvictedAt = time.Now()
+	return store
+}
+
+// Creates and returns pointer to a new in-memory rate limit store. Expired states are evicted once every interval configured in the "rate_limit_eviction_interval" server default.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	store := new(MemoryRateLimitStore)
+	store.EvictionInterval = getDefaultDuration("rate_limit_eviction_interval")
+	store.entries = make(map[string]memoryRateLimitEntry)
+	store.lastEvictedAt = time.Now()
+	return store
+}
+
+// Creates and returns a new Logger which writes log entries with at least the given level to the given writer, in the given format.
+func NewLogger(Output io.Writer, Level LogLevel, Format LogFormat) Logger {
 	eventLogger := new(logger)
-	eventLogger.srvLogger = log.New(os.Stdout, "", log.LstdFlags)
+	eventLogger.output = Output
+	eventLogger.level = Level
+	eventLogger.format = Format
 	eventLogger.serverName = getServerDefaults("server_name")
 	return eventLogger
 }
 
+// Creates and returns pointer to a new access logger which writes the access log entries to the given writer in the given format.
+func NewAccessLogger(Output io.Writer, Format AccessLogFormat) *AccessLogger {
+	accessLogger := new(AccessLogger)
+	accessLogger.Format = Format
+	accessLogger.output = Output
+	return accessLogger
+}
+
+// Returns the security headers configuration initialized from the server defaults, which can be modified before being passed to SecureHeaders().
+func NewSecureHeadersConfig() SecureHeadersConfig {
+	var config SecureHeadersConfig
+	config.HSTSMaxAge = getDefaultDuration("hsts_max_age")
+	config.HSTSIncludeSubdomains = strings.EqualFold(getServerDefaults("hsts_include_subdomains"), "on")
+	config.ContentTypeNosniff = strings.EqualFold(getServerDefaults("content_type_nosniff"), "on")
+	config.FrameOptions = getServerDefaults("frame_options")
+	config.ReferrerPolicy = getServerDefaults("referrer_policy")
+	config.ContentSecurityPolicy = getServerDefaults("content_security_policy")
+	return config
+}
+
+// Returns a new Logger which writes to stdout, with the level and format taken from the "log_level" and "log_format" server defaults.
+func newLogger() Logger {
+	return NewLogger(os.Stdout, parseLogLevel(getServerDefaults("log_level")), LogFormat(strings.ToLower(getServerDefaults("log_format"))))
+}
+
+// Creates and returns pointer to a new instance of ServerConfig initialized from the server default configuration values.
+func newServerConfig() *ServerConfig {
+	config := new(ServerConfig)
+	config.ReadTimeout = getDefaultDuration("read_timeout")
+	config.WriteTimeout = getDefaultDuration("write_timeout")
+	config.IdleTimeout = getDefaultDuration("idle_timeout")
+	config.HeaderTimeout = getDefaultDuration("header_timeout")
+	config.MinWriteRate = int64(getDefaultInt("min_write_rate"))
+	config.MinWriteRateGracePeriod = getDefaultDuration("min_write_rate_grace_period")
+	config.ResponseBufferSize = getDefaultInt("response_buffer_size")
+	config.HTTP2 = strings.EqualFold(getServerDefaults("http2"), "on")
+	config.MaxConcurrentConnections = getDefaultInt("max_concurrent_connections")
+	config.ConnectionLimitPolicy = ConnectionLimitPolicy(strings.ToLower(getServerDefaults("connection_limit_policy")))
+	config.ConnectionRetryAfter = getDefaultDuration("connection_retry_after")
+	config.MaxRequestsPerConnection = getDefaultInt("max_requests_per_connection")
+	config.MaxHeaderBytes = getDefaultInt("max_header_bytes")
+	config.MaxHeaderCount = getDefaultInt("max_header_count")
+	config.MaxURILength = getDefaultInt("max_uri_length")
+	config.PrintRoutes = strings.EqualFold(getServerDefaults("print_routes"), "on")
+	config.EnableTrace = strings.EqualFold(getServerDefaults("enable_trace"), "on")
+	config.DisabledRouteStatus = StatusCode(getDefaultInt("disabled_route_status"))
+	config.ProxyProtocol = strings.EqualFold(getServerDefaults("proxy_protocol"), "on")
+	config.ACMEDirectoryURL = getServerDefaults("acme_directory_url")
+	config.ACMEEmail = getServerDefaults("acme_email")
+	config.ACMERenewBefore = getDefaultDuration("acme_renew_before")
+	return config
+}
+
+// Creates and returns a new instance of autoTLSManager for the given domains, which obtains the certificates from the ACME server given in the server configuration and caches them in the given directory.
+// An error is returned if no valid domain is given or if the cache directory could not be created.
+func newAutoTLSManager(domains []string, cacheDir string, serverConfig *ServerConfig, eventLogger Logger) (*autoTLSManager, error) {
+	manager := new(autoTLSManager)
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" || strings.ContainsAny(domain, "/:*") {
+			ce := new(config.ConfigError)
+			ce.Message = fmt.Sprintf("ListenAutoTLS: Domain [%s] is not valid", domain)
+			return nil, ce
+		} else if !slices.Contains(manager.domains, domain) {
+			manager.domains = append(manager.domains, domain)
+		}
+	}
+
+	if len(manager.domains) == 0 {
+		ce := new(config.ConfigError)
+		ce.Message = "ListenAutoTLS: At least one domain must be given"
+		return nil, ce
+	}
+
+	cacheDir = strings.TrimSpace(cacheDir)
+	if cacheDir == "" {
+		ce := new(config.ConfigError)
+		ce.Message = "ListenAutoTLS: Cache directory must be given"
+		return nil, ce
+	} else if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		ce := new(config.ConfigError)
+		ce.Message = fmt.Sprintf("ListenAutoTLS: Cache directory could not be created :: %s", err.Error())
+		return nil, ce
+	}
+
+	manager.cacheDir = cacheDir
+	manager.email = strings.TrimSpace(serverConfig.ACMEEmail)
+	manager.renewBefore = serverConfig.ACMERenewBefore
+	manager.logger = eventLogger
+	manager.certificates = make(map[string]*tls.Certificate)
+	manager.httpTokens = make(map[string]string)
+	manager.alpnCertificates = make(map[string]*tls.Certificate)
+	manager.client = new(acmeClient)
+	manager.client.directoryURL = serverConfig.ACMEDirectoryURL
+	manager.client.pollInterval = time.Second
+	manager.client.httpClient = &nethttp.Client{ Timeout: 30 * time.Second }
+	return manager, nil
+}
+
 // Returns an instance of HTTP web server.
 func NewServer() *HttpServer {
 	var server HttpServer
 	server.HostAddress = "";
 	server.PortNumber = 0
+	server.Config = newServerConfig()
 	server.innerRouter = newRouter()
 	server.eventLogger = newLogger()
+	server.errorHandlers = make(map[StatusCode]Handler)
+	server.contentTypes = make(map[string]string)
+	server.idleConnections = make(map[net.Conn]struct{})
+	server.drainCompleted = make(chan struct{})
+	server.baseContext, server.cancelBaseContext = context.WithCancel(context.Background())
 	return &server
-}
\ No newline at end of file
+}
@@ -0,0 +1,412 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Structure to represent a field of the request which violates a validation rule.
+type Violation struct {
+	// Name of the field as given in its tag, like the name of the query parameter or of the JSON field.
+	Field string `json:"field"`
+	// Part of the request from which the field is read - "query", "path", "header" or "body".
+	Source string `json:"source"`
+	// Name of the rule violated by the field - "required", "min", "max", "pattern", "enum" or "type".
+	Rule string `json:"rule"`
+	// Message describing the violation.
+	Message string `json:"message"`
+}
+
+// Structure to represent the body of the 422 (Unprocessable Content) response sent by the Validate middleware.
+type validationResponse struct {
+	// Message describing the error.
+	Message string `json:"message"`
+	// Violations of the validation rules found in the request.
+	Violations []Violation `json:"violations"`
+}
+
+// Structure to represent a field of the struct bound by the Validate middleware, along with its validation rules.
+type validationField struct {
+	// Index of the field in the struct.
+	index int
+	// Name of the field in the request.
+	name string
+	// Part of the request from which the field is read - "query", "path", "header" or "body".
+	source string
+	// Boolean value to indicate if the field must be sent in the request.
+	required bool
+	// Minimum value of a numeric field, or the minimum length of a string or a slice field. It is nil if there is no minimum.
+	min *float64
+	// Maximum value of a numeric field, or the maximum length of a string or a slice field. It is nil if there is no maximum.
+	max *float64
+	// Regular expression to be matched by a string field, or by every element of a slice of strings. It is nil if there is no pattern.
+	pattern *regexp.Regexp
+	// List of values allowed for the field, or for every element of a slice field. It is empty if all values are allowed.
+	enum []string
+}
+
+// Type of the key against which the value bound by the Validate middleware is stored in the request context.
+type validatedContextKey struct{}
+
+// Returns the pointer to the value bound and validated by the Validate() middleware, which has the type of the target given to the middleware, like "input := req.Validated().(*CreateUserInput)".
+// It returns nil if the Validate() middleware has not been added to the route matched.
+func (req *HttpRequest) Validated() any {
+	return req.GetValue(validatedContextKey{})
+}
+
+// Returns a middleware which binds the request to a new value of the type of the given struct (or pointer to a struct), and validates its fields against the rules in their tags before invoking the handler.
+// Fields tagged with "query", "path" or "header" are read from the query parameters, the path segments or the request headers with the name given in the tag, while the rest of the fields are decoded from
+// the JSON request body. The rules are given in the "validate" tag, separated by commas - "required", "min=N" and "max=N" (the value of a number, or the length of a string or a slice), "enum=a|b|c" and
+// "pattern=regexp", which must be the last rule as the regular expression can contain commas. Requests violating the rules are rejected with a 422 (Unprocessable Content) JSON response listing the violations.
+// If the value implements the Validator interface, its Validate() method is invoked once the rules have been checked and the error returned by it, if any, is returned as the error of the handler.
+// The bound value is returned by the Validated() method of the request. If the target or its tags are not valid, the middleware returns the error for every request.
+func Validate(target any) Middleware {
+	targetType := reflect.TypeOf(target)
+	if targetType != nil && targetType.Kind() == reflect.Pointer {
+		targetType = targetType.Elem()
+	}
+
+	fields, err := getValidationFields(targetType)
+	hasBody := slices.ContainsFunc(fields, func(field validationField) bool { return field.source == "body" })
+	return func(next Handler) Handler {
+		return func(request *HttpRequest, response *HttpResponse) error {
+			if err != nil {
+				return err
+			}
+
+			value := reflect.New(targetType)
+			violations, bindErr := bindRequest(request, value.Elem(), fields, hasBody)
+			if bindErr != nil {
+				// The request body could not be read as JSON, and hence none of its fields can be validated.
+				response.Status(bindErr.Status)
+				return handleError(request, response)
+			}
+
+			if len(violations) > 0 {
+				return response.JSON(StatusUnprocessableContent, validationResponse{ Message: "Request contains fields which are not valid", Violations: violations })
+			}
+
+			if validator, ok := value.Interface().(Validator); ok {
+				if err := validator.Validate(); err != nil {
+					return err
+				}
+			}
+
+			request.SetValue(validatedContextKey{}, value.Interface())
+			return next(request, response)
+		}
+	}
+}
+
+// Returns the fields of the given struct type along with their validation rules. An error is returned if the type is not a struct, or if a tag of the struct is not valid.
+func getValidationFields(targetType reflect.Type) ([]validationField, error) {
+	if targetType == nil || targetType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Validate: Target must be a struct or a pointer to a struct, but got %v instead", targetType)
+	}
+
+	fields := make([]validationField, 0)
+	for index := 0; index < targetType.NumField(); index++ {
+		structField := targetType.Field(index)
+		if !structField.IsExported() {
+			continue
+		}
+
+		field := validationField{ index: index }
+		for _, source := range []string{ "query", "path", "header" } {
+			if name, found := structField.Tag.Lookup(source); found {
+				field.name = name
+				field.source = source
+				break
+			}
+		}
+
+		if field.source == "" {
+			jsonName, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+			if jsonName == "-" {
+				continue
+			} else if jsonName == "" {
+				jsonName = structField.Name
+			}
+			field.name = jsonName
+			field.source = "body"
+		} else if !isBindableType(structField.Type) {
+			return nil, fmt.Errorf("Validate: Field %s of type %v cannot be read from the request %s", structField.Name, structField.Type, field.source)
+		}
+
+		err := field.parseRules(structField)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Parses the rules given in the "validate" tag of the given struct field into the validation field. An error is returned if a rule is not known, or if its value is not valid for the type of the field.
+func (field *validationField) parseRules(structField reflect.StructField) error {
+	rules := structField.Tag.Get("validate")
+	for rules != "" {
+		var rule string
+		rules = strings.TrimSpace(rules)
+		if strings.HasPrefix(rules, "pattern=") {
+			rule, rules = rules, ""
+		} else {
+			rule, rules, _ = strings.Cut(rules, ",")
+		}
+
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		elemType := structField.Type
+		if elemType.Kind() == reflect.Slice {
+			elemType = elemType.Elem()
+		}
+
+		switch name {
+		case "required":
+			field.required = true
+		case "min", "max":
+			limit, err := strconv.ParseFloat(value, 64)
+			isSized := structField.Type.Kind() == reflect.Slice || (isBindableType(structField.Type) && structField.Type.Kind() != reflect.Bool)
+			if err != nil || !isSized {
+				return fmt.Errorf("Validate: Rule %s of field %s must be a number and the field must be a number, a string or a slice", name, structField.Name)
+			}
+			if name == "min" {
+				field.min = &limit
+			} else {
+				field.max = &limit
+			}
+		case "pattern":
+			pattern, err := regexp.Compile(value)
+			if err != nil || elemType.Kind() != reflect.String {
+				return fmt.Errorf("Validate: Rule pattern of field %s must be a valid regular expression and the field must be a string or a slice of strings", structField.Name)
+			}
+			field.pattern = pattern
+		case "enum":
+			if value == "" || !isBindableType(structField.Type) {
+				return fmt.Errorf("Validate: Rule enum of field %s must contain at least one value and the field must be a boolean, a number, a string or a slice", structField.Name)
+			}
+			field.enum = strings.Split(value, "|")
+		case "":
+		default:
+			return fmt.Errorf("Validate: Rule %s of field %s is not a known validation rule", name, structField.Name)
+		}
+	}
+
+	return nil
+}
+
+// Checks if a value of the given type can be read from the query parameters, the path segments or the request headers, i.e., it is a boolean, a number, a string, or a slice of them.
+func isBindableType(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Slice {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Binds the given request to the given struct value and validates its fields against their rules. It returns the violations found, or an error with the status of the response to be sent
+// if the request body could not be read as JSON.
+func bindRequest(request *HttpRequest, value reflect.Value, fields []validationField, hasBody bool) ([]Violation, *RequestParseError) {
+	violations := make([]Violation, 0)
+	bodyFields := make(map[string]json.RawMessage)
+	if hasBody && len(request.Body) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(request.Header("Content-Type"))
+		if !strings.EqualFold(mediaType, JSON_CONTENT_TYPE) {
+			reqError := new(RequestParseError)
+			reqError.Section = "Header"
+			reqError.Value = request.Header("Content-Type")
+			reqError.Message = "Request body can be validated only if its content type is application/json"
+			reqError.Status = StatusUnsupportedMediaType
+			return nil, reqError
+		}
+
+		err := json.Unmarshal(request.Body, value.Addr().Interface())
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			// The field of the wrong type is left out of the body fields, so that it is not checked against its other rules.
+			violations = append(violations, Violation{ Field: typeErr.Field, Source: "body", Rule: "type", Message: fmt.Sprintf("Field must be of type %v", typeErr.Type) })
+			err = json.Unmarshal(request.Body, &bodyFields)
+			delete(bodyFields, typeErr.Field)
+		} else if err == nil {
+			err = json.Unmarshal(request.Body, &bodyFields)
+		}
+
+		if err != nil {
+			reqError := new(RequestParseError)
+			reqError.Section = "Body"
+			reqError.Value = "Request Body"
+			reqError.Message = fmt.Sprintf("Error while decoding request body as JSON :: %s", err.Error())
+			reqError.Status = StatusBadRequest
+			return nil, reqError
+		}
+	}
+
+	for _, field := range fields {
+		var values []string
+		isSent := false
+		switch field.source {
+		case "query":
+			values, isSent = request.Query.Get(field.name)
+		case "path":
+			values, isSent = request.Segments.Get(field.name)
+		case "header":
+			if value.Field(field.index).Kind() == reflect.Slice {
+				values = request.HeaderValues(field.name)
+			} else if header, found := request.Headers.Get(field.name); found {
+				values = []string{ strings.TrimSpace(header) }
+			}
+			isSent = len(values) > 0
+		case "body":
+			rawValue, found := bodyFields[field.name]
+			for name, fieldValue := range bodyFields {
+				// The JSON field names are matched case-insensitively while decoding the body, and hence while checking if a field has been sent as well.
+				if !found && strings.EqualFold(name, field.name) {
+					rawValue, found = fieldValue, true
+				}
+			}
+			isSent = found && string(rawValue) != "null"
+			if !found && slices.ContainsFunc(violations, func(violation Violation) bool { return violation.Source == "body" && violation.Field == field.name }) {
+				continue
+			}
+		}
+
+		if field.source != "body" {
+			// A field decoded from the request body with the name of a field read from elsewhere is discarded, so that the body cannot set a field like a request header.
+			value.Field(field.index).SetZero()
+			isSent = slices.ContainsFunc(values, func(value string) bool { return value != "" })
+		}
+
+		if field.source != "body" && isSent {
+			if err := setFieldValue(value.Field(field.index), values); err != nil {
+				violations = append(violations, Violation{ Field: field.name, Source: field.source, Rule: "type", Message: err.Error() })
+				continue
+			}
+		}
+
+		if !isSent {
+			if field.required {
+				violations = append(violations, Violation{ Field: field.name, Source: field.source, Rule: "required", Message: "Field is required" })
+			}
+			continue
+		}
+
+		violations = append(violations, field.validate(value.Field(field.index))...)
+	}
+
+	return violations, nil
+}
+
+// Sets the given struct field to the given values read from the request, converting them to the type of the field. A field which is not a slice is set to the first value.
+// An error is returned if a value cannot be converted to the type of the field.
+func setFieldValue(fieldValue reflect.Value, values []string) error {
+	if fieldValue.Kind() != reflect.Slice {
+		return setScalarValue(fieldValue, values[0])
+	}
+
+	sliceValue := reflect.MakeSlice(fieldValue.Type(), len(values), len(values))
+	for index, value := range values {
+		if err := setScalarValue(sliceValue.Index(index), value); err != nil {
+			return err
+		}
+	}
+	fieldValue.Set(sliceValue)
+	return nil
+}
+
+// Sets the given value of a boolean, a number or a string to the given text, converting it to the type of the value. An error is returned if the text cannot be converted.
+func setScalarValue(fieldValue reflect.Value, text string) error {
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(text)
+	case reflect.Bool:
+		boolValue, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("Field must be a boolean, but got [%s] instead", text)
+		}
+		fieldValue.SetBool(boolValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(text, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("Field must be an integer, but got [%s] instead", text)
+		}
+		fieldValue.SetInt(intValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, err := strconv.ParseUint(text, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("Field must be a non-negative integer, but got [%s] instead", text)
+		}
+		fieldValue.SetUint(uintValue)
+	case reflect.Float32, reflect.Float64:
+		floatValue, err := strconv.ParseFloat(text, fieldValue.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("Field must be a number, but got [%s] instead", text)
+		}
+		fieldValue.SetFloat(floatValue)
+	}
+
+	return nil
+}
+
+// Validates the given value of the field against the min, max, pattern and enum rules of the field, and returns the violations found.
+func (field *validationField) validate(fieldValue reflect.Value) []Violation {
+	violations := make([]Violation, 0)
+	addViolation := func(rule string, message string) {
+		violations = append(violations, Violation{ Field: field.name, Source: field.source, Rule: rule, Message: message })
+	}
+
+	var size float64
+	unit := ""
+	switch fieldValue.Kind() {
+	case reflect.String:
+		size, unit = float64(utf8.RuneCountInString(fieldValue.String())), " characters"
+	case reflect.Slice:
+		size, unit = float64(fieldValue.Len()), " elements"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(fieldValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(fieldValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = fieldValue.Float()
+	}
+
+	if field.min != nil && size < *field.min {
+		addViolation("min", fmt.Sprintf("Field must have at least %v%s", *field.min, unit))
+	}
+
+	if field.max != nil && size > *field.max {
+		addViolation("max", fmt.Sprintf("Field must have at most %v%s", *field.max, unit))
+	}
+
+	elements := []reflect.Value{ fieldValue }
+	if fieldValue.Kind() == reflect.Slice {
+		elements = make([]reflect.Value, fieldValue.Len())
+		for index := range elements {
+			elements[index] = fieldValue.Index(index)
+		}
+	}
+
+	for _, element := range elements {
+		if field.pattern != nil && !field.pattern.MatchString(element.String()) {
+			addViolation("pattern", fmt.Sprintf("Field must match the pattern [%s], but got [%s] instead", field.pattern.String(), element.String()))
+		}
+
+		if len(field.enum) > 0 && !slices.Contains(field.enum, fmt.Sprint(element.Interface())) {
+			addViolation("enum", fmt.Sprintf("Field must be one of [%s], but got [%v] instead", strings.Join(field.enum, ", "), element.Interface()))
+		}
+	}
+
+	return violations
+}
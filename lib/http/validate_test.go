@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Input bound by the Validate middleware in the test cases.
+type testOrderInput struct {
+	Store string `path:"store" validate:"required,pattern=^[a-z]+$"`
+	Quantity int `query:"qty" validate:"required,min=1,max=10"`
+	Tags []string `query:"tag" validate:"max=2,enum=gift|express"`
+	Channel string `header:"X-Channel" validate:"required,enum=web|mobile"`
+	Item string `json:"item" validate:"required,min=3,max=20"`
+	Note string `json:"note"`
+}
+
+// Rejects the orders for an item which is sold out, once the rules in the tags have been checked.
+func (input *testOrderInput) Validate() error {
+	if input.Item == "sold-out" {
+		return NewError(StatusConflict, "item is sold out")
+	}
+	return nil
+}
+
+// Test case to validate the binding of the query parameters, path segments, headers and JSON body fields by the Validate middleware, and the violations listed in its 422 (Unprocessable Content) response.
+func Test_Server_Validate(t *testing.T) {
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.Post("/stores/:store/orders", func(req *HttpRequest, res *HttpResponse) error {
+		input := req.Validated().(*testOrderInput)
+		res.Status(StatusCreated)
+		res.Body = []byte(fmt.Sprintf("%s|%d|%s|%s|%s|%s", input.Store, input.Quantity, strings.Join(input.Tags, ","), input.Channel, input.Item, input.Note))
+		return nil
+	}, Validate(testOrderInput{}))
+	testServer.Post("/invalid", func(req *HttpRequest, res *HttpResponse) error {
+		return nil
+	}, Validate("not a struct"))
+
+	testCases := []struct {
+		Name string
+		ResourcePath string
+		Channel string
+		ContentType string
+		Body string
+		ExpStatus int
+		ExpBody string
+		ExpViolations []string
+	} {
+		{ "Request with valid fields", "/stores/north/orders?qty=2&tag=gift&tag=express", "web", JSON_CONTENT_TYPE, `{"item":"book","note":"wrap it"}`, int(StatusCreated), "north|2|gift,express|web|book|wrap it", nil },
+		{ "Request without the required fields", "/stores/north/orders?qty=", "", JSON_CONTENT_TYPE, `{"note":"wrap it"}`, int(StatusUnprocessableContent), "", []string{ "qty:required", "X-Channel:required", "item:required" } },
+		{ "Request with values outside the limits", "/stores/north/orders?qty=11&tag=gift&tag=gift&tag=gift", "web", JSON_CONTENT_TYPE, `{"item":"cd"}`, int(StatusUnprocessableContent), "", []string{ "qty:max", "tag:max", "item:min" } },
+		{ "Request with values not matching the pattern or the allowed values", "/stores/North/orders?qty=1&tag=slow", "fax", JSON_CONTENT_TYPE, `{"item":"book"}`, int(StatusUnprocessableContent), "", []string{ "store:pattern", "tag:enum", "X-Channel:enum" } },
+		{ "Request with values of the wrong type", "/stores/north/orders?qty=two", "web", JSON_CONTENT_TYPE, `{"item":42}`, int(StatusUnprocessableContent), "", []string{ "item:type", "qty:type" } },
+		{ "Request body setting a field read from a header", "/stores/north/orders?qty=1", "", JSON_CONTENT_TYPE, `{"item":"book","Channel":"web"}`, int(StatusUnprocessableContent), "", []string{ "X-Channel:required" } },
+		{ "Request rejected by the Validate() method of the input", "/stores/north/orders?qty=1", "web", JSON_CONTENT_TYPE, `{"item":"sold-out"}`, int(StatusConflict), "item is sold out", nil },
+		{ "Request body which is not valid JSON", "/stores/north/orders?qty=1", "web", JSON_CONTENT_TYPE, `{"item":`, int(StatusBadRequest), "", nil },
+		{ "Request body which is not JSON", "/stores/north/orders?qty=1", "web", "text/plain", "book", int(StatusUnsupportedMediaType), "", nil },
+		{ "Middleware created for a target which is not a struct", "/invalid", "web", JSON_CONTENT_TYPE, "", int(StatusInternalServerError), "", nil },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = "POST"
+			testRequest.ResourcePath = testCase.ResourcePath
+			if err := testRequest.parseQueryParams(); err != nil {
+				tt.Fatalf("Was not expecting an error while parsing the query parameters, but got this instead - %v", err)
+			}
+			if testCase.Channel != "" {
+				testRequest.Headers.Add("X-Channel", testCase.Channel)
+			}
+			testRequest.Headers.Add("Content-Type", testCase.ContentType)
+			testRequest.Body = []byte(testCase.Body)
+
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != testCase.ExpStatus {
+				tt.Errorf("The response status [%d] does not match the expected status [%d] for the body [%s]", testResponse.StatusCode, testCase.ExpStatus, string(testResponse.Body))
+				return
+			} else if !strings.Contains(string(testResponse.Body), testCase.ExpBody) {
+				tt.Errorf("Expected the response body [%s] to contain [%s]", string(testResponse.Body), testCase.ExpBody)
+				return
+			}
+
+			if testCase.ExpViolations != nil {
+				var response validationResponse
+				if err := json.Unmarshal(testResponse.Body, &response); err != nil {
+					tt.Fatalf("Was not expecting an error while decoding the response body, but got this instead - %v", err)
+				}
+
+				violations := make([]string, 0)
+				for _, violation := range response.Violations {
+					violations = append(violations, violation.Field + ":" + violation.Rule)
+				}
+				slices.Sort(violations)
+				slices.Sort(testCase.ExpViolations)
+				if !slices.Equal(violations, testCase.ExpViolations) {
+					tt.Errorf("Expected the violations %v, but got %v instead", testCase.ExpViolations, violations)
+					return
+				}
+			}
+
+			tt.Logf("The response status [%d] and body match the expected values", testResponse.StatusCode)
+		})
+	}
+}
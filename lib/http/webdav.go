@@ -0,0 +1,774 @@
+package http
+
+import (
+	"encoding/xml"
+	"io"
+	iofs "io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/mkbworks/proteus/lib/fs"
+)
+
+// Collection of the HTTP methods handled by a WebDAV endpoint, which are sent in the Allow header of the responses to OPTIONS requests.
+var webDAVMethods = []string{ "OPTIONS", "GET", "HEAD", "PUT", "DELETE", "PROPFIND", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK" }
+
+// Collection of the live properties (in the DAV: namespace) returned for the files and folders of a WebDAV endpoint, in the order in which they are returned.
+var webDAVPropertyNames = []string{ "displayname", "resourcetype", "getcontentlength", "getcontenttype", "getlastmodified", "getetag", "supportedlock", "lockdiscovery" }
+
+// Structure to represent a WebDAV endpoint, which serves the files and folders of a folder in the local file system along with the locks held on them.
+type webDAVHandler struct {
+	// Route prefix at which the endpoint is served, without the trailing '/'.
+	prefix string
+	// Complete path of the folder served by the endpoint.
+	rootPath string
+	// Collection of the locks held on the files and folders of the endpoint.
+	locks *webDAVLocks
+}
+
+// Structure to represent the propfind XML element sent in the body of a PROPFIND request.
+type webDAVPropfind struct {
+	// Name of the root XML element.
+	XMLName xml.Name
+	// Present if all the properties are requested.
+	AllProp *struct{} `xml:"allprop"`
+	// Present if only the names of the properties are requested.
+	PropName *struct{} `xml:"propname"`
+	// Collection of the names of the requested properties, if specific properties are requested.
+	Prop *struct {
+		// Names of the requested properties.
+		Names []struct {
+			// Name of the property, along with its namespace.
+			XMLName xml.Name
+		} `xml:",any"`
+	} `xml:"prop"`
+}
+
+// Structure to represent the lockinfo XML element sent in the body of a LOCK request.
+type webDAVLockInfo struct {
+	// Name of the root XML element.
+	XMLName xml.Name
+	// Present if an exclusive lock is requested.
+	Exclusive *struct{} `xml:"lockscope>exclusive"`
+	// Present if a shared lock is requested.
+	Shared *struct{} `xml:"lockscope>shared"`
+	// Present if a write lock is requested.
+	Write *struct{} `xml:"locktype>write"`
+	// Description of the owner of the lock.
+	Owner struct {
+		// XML content of the owner element, as sent by the client.
+		Content string `xml:",innerxml"`
+	} `xml:"owner"`
+}
+
+// Adds a WebDAV (RFC 4918) endpoint at the given route prefix (like "/dav"), serving the files and folders of the folder available at the given absolute path, so that they can be browsed, uploaded, moved,
+// copied and removed using WebDAV clients (like the file managers of the operating systems). The PROPFIND, MKCOL, COPY, MOVE, DELETE, PUT, LOCK and UNLOCK methods are supported, along with GET and HEAD
+// to download the files. Only exclusive write locks are supported, which are held in memory. Symbolic links are followed only if they lead to files or folders within the target folder.
+// The WebDAV methods missing from the allowed methods of the server configuration are allowed for the server instance once the endpoint has been added, for the HTTP versions allowing the PUT method. The given middlewares (like BasicAuth) are invoked for every request made to the endpoint.
+func (srv *HttpServer) WebDAV(Route string, TargetPath string, middlewares ...Middleware) error {
+	TargetPath = strings.TrimSpace(TargetPath)
+	if !filepath.IsAbs(TargetPath) {
+		reError := new(RoutingError)
+		reError.RoutePath = TargetPath
+		reError.Message = "WebDAV: Given target folder path is not an absolute path"
+		return reError
+	}
+
+	PathType, err := fs.GetPathType(TargetPath)
+	if err != nil {
+		return err
+	} else if PathType != fs.FOLDER_TYPE_PATH {
+		reError := new(RoutingError)
+		reError.RoutePath = TargetPath
+		reError.Message = "WebDAV: Target path given should point to a directory not a file"
+		return reError
+	}
+
+	dav := &webDAVHandler{ prefix: strings.TrimSuffix(cleanRoute(Route), "/"), rootPath: filepath.Clean(TargetPath), locks: newWebDAVLocks() }
+	for _, method := range webDAVMethods {
+		// The route prefix serves the target folder itself, while the wildcard route serves the files and folders present in it.
+		for _, routePath := range []string{ Route, joinRoute(Route, "*path") } {
+			err = srv.innerRouter.addDynamicRoute(method, routePath, dav.handle, middlewares...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// The WebDAV methods are allowed only once a WebDAV endpoint has been added, so that other servers keep rejecting them with a 405 (Method Not Allowed) response.
+	srv.enableMethods(webDAVMethods...)
+	return nil
+}
+
+// Handles the requests made to the WebDAV endpoint, by invoking the handler of the request method.
+func (dav *webDAVHandler) handle(request *HttpRequest, response *HttpResponse) error {
+	FilePath, found := dav.getFilePath(request.ResourcePath)
+	if !found || !dav.isWithinRoot(FilePath) {
+		return dav.sendStatus(request, response, StatusNotFound)
+	}
+
+	switch strings.ToUpper(request.Method) {
+	case "OPTIONS":
+		response.Headers.Add("DAV", "1, 2")
+		response.Headers.Add("Allow", strings.Join(webDAVMethods, ", "))
+		response.Headers.Add("MS-Author-Via", "DAV")
+		response.Status(StatusNoContent)
+		return nil
+	case "GET", "HEAD":
+		return dav.handleGet(request, response, FilePath)
+	case "PUT":
+		return dav.handlePut(request, response, FilePath)
+	case "DELETE":
+		return dav.handleDelete(request, response, FilePath)
+	case "PROPFIND":
+		return dav.handlePropfind(request, response, FilePath)
+	case "MKCOL":
+		return dav.handleMkcol(request, response, FilePath)
+	case "COPY", "MOVE":
+		return dav.handleCopyMove(request, response, FilePath, strings.EqualFold(request.Method, "MOVE"))
+	case "LOCK":
+		return dav.handleLock(request, response, FilePath)
+	case "UNLOCK":
+		return dav.handleUnlock(request, response, FilePath)
+	}
+
+	return dav.sendStatus(request, response, StatusMethodNotAllowed)
+}
+
+// Sends the error response for the given status code, using the error handler registered for the status code.
+func (dav *webDAVHandler) sendStatus(request *HttpRequest, response *HttpResponse, status StatusCode) error {
+	response.Status(status)
+	return handleError(request, response)
+}
+
+// Returns the path of the file or folder in the local file system requested using the given request path. The request path is percent-decoded and its dot-segments are resolved within the
+// target folder of the endpoint. The boolean value returned is false if the request path does not lie under the route prefix of the endpoint or cannot be decoded.
+func (dav *webDAVHandler) getFilePath(RequestPath string) (string, bool) {
+	// Route paths are matched case-insensitively, and hence the route prefix is removed irrespective of its case.
+	if len(RequestPath) < len(dav.prefix) || !strings.EqualFold(RequestPath[:len(dav.prefix)], dav.prefix) {
+		return "", false
+	}
+
+	relativePath := RequestPath[len(dav.prefix):]
+	if relativePath != "" && !strings.HasPrefix(relativePath, "/") {
+		return "", false
+	}
+
+	relativePath, err := url.PathUnescape(relativePath)
+	if err != nil || strings.ContainsRune(relativePath, 0) {
+		return "", false
+	}
+
+	FilePath := filepath.Join(dav.rootPath, filepath.FromSlash(path.Clean("/" + relativePath)))
+	if !isWithinFolder(dav.rootPath, FilePath) {
+		return "", false
+	}
+
+	return FilePath, true
+}
+
+// Checks if the given path still lies within the target folder of the endpoint once its symbolic links have been resolved, so that symbolic links present in the target folder cannot be used to read or
+// change the files outside it. Paths which do not exist yet (like the target of a PUT or MKCOL request) are checked using their nearest existing parent folder. Paths which exist but cannot be resolved
+// (like broken symbolic links) are not allowed, as writing to them would create the target of the link.
+func (dav *webDAVHandler) isWithinRoot(FilePath string) bool {
+	resolvedRoot, err := filepath.EvalSymlinks(dav.rootPath)
+	if err != nil {
+		return false
+	}
+
+	existingPath, remainingPath := FilePath, ""
+	for {
+		resolvedPath, err := filepath.EvalSymlinks(existingPath)
+		if err == nil {
+			return isWithinFolder(resolvedRoot, filepath.Join(resolvedPath, remainingPath))
+		} else if _, lstatErr := os.Lstat(existingPath); lstatErr == nil || !os.IsNotExist(err) {
+			return false
+		}
+
+		parentPath := filepath.Dir(existingPath)
+		if parentPath == existingPath {
+			return false
+		}
+
+		remainingPath = filepath.Join(filepath.Base(existingPath), remainingPath)
+		existingPath = parentPath
+	}
+}
+
+// Returns the percent-encoded request path of the file or folder available at the given path in the local file system, which ends with a '/' for folders.
+func (dav *webDAVHandler) getHref(FilePath string, IsDir bool) string {
+	href := dav.prefix
+	if relativePath, err := filepath.Rel(dav.rootPath, FilePath); err == nil && relativePath != "." {
+		for _, segment := range strings.Split(filepath.ToSlash(relativePath), "/") {
+			href += "/" + url.PathEscape(segment)
+		}
+	}
+
+	if IsDir || href == "" {
+		href += "/"
+	}
+
+	return href
+}
+
+// Returns the path in the local file system of the destination given in the Destination header of a COPY or MOVE request, along with the status code of the error response to be sent if the
+// destination is not valid. A 400 (Bad Request) status is returned if the header is missing or cannot be parsed, and a 502 (Bad Gateway) status if the destination lies outside the endpoint.
+func (dav *webDAVHandler) getDestination(request *HttpRequest) (string, StatusCode) {
+	destination := request.Header("Destination")
+	if destination == "" {
+		return "", StatusBadRequest
+	}
+
+	destinationURL, err := url.Parse(destination)
+	if err != nil {
+		return "", StatusBadRequest
+	} else if destinationURL.Host != "" && !strings.EqualFold(destinationURL.Host, request.Header("Host")) {
+		return "", StatusBadGateway
+	}
+
+	destinationPath, err := normalizePath(destinationURL.EscapedPath())
+	if err != nil {
+		return "", StatusBadRequest
+	}
+
+	FilePath, found := dav.getFilePath(destinationPath)
+	if !found {
+		return "", StatusBadGateway
+	} else if !dav.isWithinRoot(FilePath) {
+		return "", StatusForbidden
+	}
+
+	return FilePath, StatusOK
+}
+
+// Returns the value of the Depth header of the request ("0", "1" or "infinity"), or the given default value if the header has not been sent. The boolean value returned is false if the header has an invalid value.
+func getDepth(request *HttpRequest, Default string) (string, bool) {
+	depth := strings.ToLower(request.Header("Depth"))
+	if depth == "" {
+		return Default, true
+	}
+
+	return depth, depth == "0" || depth == "1" || depth == "infinity"
+}
+
+// Returns the lock tokens submitted in the If header of the request. Only the state tokens present in the lists of conditions (enclosed in parentheses) are returned, and the conditions are not
+// evaluated otherwise. Entity tags (enclosed in square brackets) and the resource tags preceding the lists are skipped.
+func getIfLockTokens(request *HttpRequest) []string {
+	tokens := make([]string, 0)
+	ifHeader := request.Header("If")
+	isInList := false
+	for index := 0; index < len(ifHeader); index++ {
+		switch ifHeader[index] {
+		case '(':
+			isInList = true
+		case ')':
+			isInList = false
+		case '[', '<':
+			closingChar := byte(']')
+			if ifHeader[index] == '<' {
+				closingChar = '>'
+			}
+
+			endIndex := strings.IndexByte(ifHeader[index:], closingChar)
+			if endIndex == -1 {
+				return tokens
+			}
+
+			if isInList && closingChar == '>' {
+				tokens = append(tokens, ifHeader[index + 1: index + endIndex])
+			}
+			index += endIndex
+		}
+	}
+
+	return tokens
+}
+
+// Returns the duration requested for a lock in the Timeout header of the request, as the number of seconds of the first "Second-<n>" value. The duration is capped at the "webdav_lock_timeout" server default,
+// which is also returned if the header has not been sent or if an infinite timeout has been requested.
+func getLockTimeout(request *HttpRequest) time.Duration {
+	maxTimeout := getDefaultDuration("webdav_lock_timeout")
+	for _, value := range request.HeaderValues("Timeout") {
+		if seconds, found := strings.CutPrefix(value, "Second-"); found {
+			if timeout, err := strconv.ParseInt(seconds, 10, 64); err == nil && timeout > 0 {
+				// The number of seconds is compared before it is converted to a duration, as the conversion of a large number of seconds overflows.
+				if timeout >= int64(maxTimeout / time.Second) {
+					return maxTimeout
+				}
+
+				return time.Duration(timeout) * time.Second
+			}
+		}
+	}
+
+	return maxTimeout
+}
+
+// Returns the given text with the characters that are special in XML escaped.
+func escapeXML(Text string) string {
+	var escapedText strings.Builder
+	xml.EscapeText(&escapedText, []byte(Text))
+	return escapedText.String()
+}
+
+// Sends the given XML content as the response body, with the given status code.
+func sendXML(response *HttpResponse, status StatusCode, Content string) error {
+	response.Status(status)
+	response.Headers.Add("Content-Type", "application/xml; charset=utf-8")
+	response.Body = []byte(xml.Header + Content)
+	return nil
+}
+
+// Sends the requested file, or the HTML listing of the requested folder, as response.
+func (dav *webDAVHandler) handleGet(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	PathType, err := fs.GetPathType(FilePath)
+	if err != nil {
+		return dav.sendStatus(request, response, StatusNotFound)
+	}
+
+	if PathType == fs.FOLDER_TYPE_PATH {
+		listingContent, err := renderDirectoryListing(fs.OSFileSystem{}, FilePath, request.ResourcePath, FilePath == dav.rootPath, nil)
+		if err != nil {
+			return err
+		}
+
+		response.Status(StatusOK)
+		response.Headers.Add("Content-Type", "text/html; charset=utf-8")
+		response.Body = listingContent
+		return nil
+	}
+
+	file, err := fs.GetFile(FilePath, "", true)
+	if err != nil {
+		return dav.sendStatus(request, response, StatusNotFound)
+	}
+
+	file.ContentType = getFileContentType(fs.OSFileSystem{}, FilePath, response.contentTypes, response.defaultContentType)
+	ETag, err := generateETag(fs.OSFileSystem{}, FilePath, file)
+	if err != nil {
+		return err
+	}
+
+	response.Headers.Add("ETag", ETag)
+	if request.isNotModified(file, ETag) {
+		response.Status(StatusNotModified)
+		return response.serveFile(fs.OSFileSystem{}, FilePath, file, true)
+	}
+
+	response.Status(StatusOK)
+	return response.serveFile(fs.OSFileSystem{}, FilePath, file, false)
+}
+
+// Writes the request body to the requested file, creating the file if it does not exist. A 409 (Conflict) response is sent if the folder containing the file does not exist.
+func (dav *webDAVHandler) handlePut(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	PathType, err := fs.GetPathType(FilePath)
+	if err == nil && PathType == fs.FOLDER_TYPE_PATH {
+		return dav.sendStatus(request, response, StatusMethodNotAllowed)
+	}
+
+	if ParentType, err := fs.GetPathType(filepath.Dir(FilePath)); err != nil || ParentType != fs.FOLDER_TYPE_PATH {
+		return dav.sendStatus(request, response, StatusConflict)
+	}
+
+	if dav.locks.isLocked(FilePath, false, getIfLockTokens(request)) {
+		return dav.sendStatus(request, response, StatusLocked)
+	}
+
+	isCreated := err != nil
+	err = os.WriteFile(FilePath, request.Body, 0644)
+	if err != nil {
+		return err
+	}
+
+	if isCreated {
+		response.Status(StatusCreated)
+	} else {
+		response.Status(StatusNoContent)
+	}
+	return nil
+}
+
+// Removes the requested file, or the requested folder along with its contents. The target folder of the endpoint cannot be removed.
+func (dav *webDAVHandler) handleDelete(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	if FilePath == dav.rootPath {
+		return dav.sendStatus(request, response, StatusForbidden)
+	}
+
+	if _, err := fs.GetPathType(FilePath); err != nil {
+		return dav.sendStatus(request, response, StatusNotFound)
+	}
+
+	if dav.locks.isLocked(FilePath, true, getIfLockTokens(request)) {
+		return dav.sendStatus(request, response, StatusLocked)
+	}
+
+	err := os.RemoveAll(FilePath)
+	if err != nil {
+		return err
+	}
+
+	dav.locks.removeWithin(FilePath)
+	response.Status(StatusNoContent)
+	return nil
+}
+
+// Creates the requested folder. A 405 (Method Not Allowed) response is sent if the folder already exists and a 409 (Conflict) response is sent if the folder containing it does not exist.
+func (dav *webDAVHandler) handleMkcol(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	if len(request.Body) > 0 {
+		return dav.sendStatus(request, response, StatusUnsupportedMediaType)
+	}
+
+	if _, err := fs.GetPathType(FilePath); err == nil {
+		return dav.sendStatus(request, response, StatusMethodNotAllowed)
+	}
+
+	if ParentType, err := fs.GetPathType(filepath.Dir(FilePath)); err != nil || ParentType != fs.FOLDER_TYPE_PATH {
+		return dav.sendStatus(request, response, StatusConflict)
+	}
+
+	if dav.locks.isLocked(FilePath, false, getIfLockTokens(request)) {
+		return dav.sendStatus(request, response, StatusLocked)
+	}
+
+	err := os.Mkdir(FilePath, 0755)
+	if err != nil {
+		return err
+	}
+
+	response.Status(StatusCreated)
+	return nil
+}
+
+// Copies or moves the requested file or folder to the destination given in the Destination header. An existing destination is replaced unless the Overwrite header is "F", in which case a 412 (Precondition Failed)
+// response is sent. Folders are copied along with their contents, unless the Depth header is "0".
+func (dav *webDAVHandler) handleCopyMove(request *HttpRequest, response *HttpResponse, FilePath string, IsMove bool) error {
+	depth, isValid := getDepth(request, "infinity")
+	if !isValid || depth == "1" || (IsMove && depth != "infinity") {
+		return dav.sendStatus(request, response, StatusBadRequest)
+	}
+
+	DestinationPath, status := dav.getDestination(request)
+	if status != StatusOK {
+		return dav.sendStatus(request, response, status)
+	}
+
+	PathType, err := fs.GetPathType(FilePath)
+	if err != nil {
+		return dav.sendStatus(request, response, StatusNotFound)
+	}
+
+	// Copying or moving a folder into itself would never end, and the target folder of the endpoint can neither be moved nor replaced.
+	if DestinationPath == FilePath || DestinationPath == dav.rootPath || (IsMove && FilePath == dav.rootPath) || (PathType == fs.FOLDER_TYPE_PATH && isWithinFolder(FilePath, DestinationPath)) {
+		return dav.sendStatus(request, response, StatusForbidden)
+	}
+
+	_, err = fs.GetPathType(DestinationPath)
+	destinationExists := err == nil
+	if destinationExists && strings.EqualFold(request.Header("Overwrite"), "F") {
+		return dav.sendStatus(request, response, StatusPreconditionFailed)
+	}
+
+	if ParentType, err := fs.GetPathType(filepath.Dir(DestinationPath)); err != nil || ParentType != fs.FOLDER_TYPE_PATH {
+		return dav.sendStatus(request, response, StatusConflict)
+	}
+
+	lockTokens := getIfLockTokens(request)
+	if (IsMove && dav.locks.isLocked(FilePath, true, lockTokens)) || dav.locks.isLocked(DestinationPath, true, lockTokens) {
+		return dav.sendStatus(request, response, StatusLocked)
+	}
+
+	if destinationExists {
+		err = os.RemoveAll(DestinationPath)
+		if err != nil {
+			return err
+		}
+		dav.locks.removeWithin(DestinationPath)
+	}
+
+	if IsMove {
+		err = os.Rename(FilePath, DestinationPath)
+		// The locks held on the moved files and folders are not moved along with them.
+		dav.locks.removeWithin(FilePath)
+	} else {
+		err = copyResource(FilePath, DestinationPath, depth == "infinity")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if destinationExists {
+		response.Status(StatusNoContent)
+	} else {
+		response.Status(StatusCreated)
+	}
+	return nil
+}
+
+// Copies the file or folder available at the given source path to the given destination path. Folders are copied along with their contents if Recursive is true, or else only an empty folder is created.
+// Symbolic links and other special files present in the copied folders are left out.
+func copyResource(SourcePath string, DestinationPath string, Recursive bool) error {
+	sourceInfo, err := os.Stat(SourcePath)
+	if err != nil {
+		return err
+	}
+
+	if !sourceInfo.IsDir() {
+		return copyRegularFile(SourcePath, DestinationPath, sourceInfo.Mode().Perm())
+	} else if !Recursive {
+		return os.Mkdir(DestinationPath, sourceInfo.Mode().Perm())
+	}
+
+	return filepath.WalkDir(SourcePath, func(entryPath string, dirEntry iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(SourcePath, entryPath)
+		if err != nil {
+			return err
+		}
+
+		entryInfo, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(DestinationPath, relativePath)
+		if dirEntry.IsDir() {
+			return os.Mkdir(targetPath, entryInfo.Mode().Perm())
+		} else if !entryInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyRegularFile(entryPath, targetPath, entryInfo.Mode().Perm())
+	})
+}
+
+// Copies the contents of the file available at the given source path to a new file at the given destination path, which is created with the given permissions.
+func copyRegularFile(SourcePath string, DestinationPath string, Permissions iofs.FileMode) error {
+	sourceFile, err := os.Open(SourcePath)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destinationFile, err := os.OpenFile(DestinationPath, os.O_WRONLY | os.O_CREATE | os.O_TRUNC, Permissions)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(destinationFile, sourceFile)
+	if closeErr := destinationFile.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Sends the properties of the requested file or folder (and of the entries present in the folder if the Depth header is "1") as a 207 (Multi-Status) response. All the live properties are sent unless
+// specific properties or only the property names are requested in the request body. Requests with the Depth header "infinity" (the default) are answered with a 403 (Forbidden) response.
+func (dav *webDAVHandler) handlePropfind(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	depth, isValid := getDepth(request, "infinity")
+	if !isValid {
+		return dav.sendStatus(request, response, StatusBadRequest)
+	} else if depth == "infinity" {
+		return dav.sendStatus(request, response, StatusForbidden)
+	}
+
+	propfind := new(webDAVPropfind)
+	if len(strings.TrimSpace(string(request.Body))) > 0 {
+		err := xml.Unmarshal(request.Body, propfind)
+		if err != nil || propfind.XMLName.Space != "DAV:" || propfind.XMLName.Local != "propfind" {
+			return dav.sendStatus(request, response, StatusBadRequest)
+		}
+	}
+
+	fileInfo, err := os.Stat(FilePath)
+	if err != nil {
+		return dav.sendStatus(request, response, StatusNotFound)
+	}
+
+	var content strings.Builder
+	content.WriteString(`<D:multistatus xmlns:D="DAV:">`)
+	content.WriteString(dav.renderPropertyResponse(FilePath, fileInfo, propfind, response))
+	if depth == "1" && fileInfo.IsDir() {
+		dirEntries, err := os.ReadDir(FilePath)
+		if err != nil {
+			return err
+		}
+
+		for _, dirEntry := range dirEntries {
+			entryPath := filepath.Join(FilePath, dirEntry.Name())
+			// Entries which have been removed or cannot be read (like broken symbolic links), and symbolic links leading outside the target folder, are left out.
+			if entryInfo, err := os.Stat(entryPath); err == nil && dav.isWithinRoot(entryPath) {
+				content.WriteString(dav.renderPropertyResponse(entryPath, entryInfo, propfind, response))
+			}
+		}
+	}
+
+	content.WriteString("</D:multistatus>")
+	return sendXML(response, StatusMultiStatus, content.String())
+}
+
+// Returns the values of the live properties of the file or folder available at the given path, as XML content with the names of the properties as keys. The properties which do not apply to folders
+// (like getcontentlength) are not returned for folders.
+func (dav *webDAVHandler) getProperties(FilePath string, fileInfo os.FileInfo, response *HttpResponse) map[string]string {
+	properties := map[string]string{
+		"displayname": escapeXML(fileInfo.Name()),
+		"resourcetype": "",
+		"getlastmodified": fileInfo.ModTime().UTC().Format(HTTP_DATE_FORMAT),
+		"supportedlock": "<D:lockentry><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype></D:lockentry>",
+		"lockdiscovery": dav.renderActiveLocks(FilePath),
+	}
+
+	if fileInfo.IsDir() {
+		properties["resourcetype"] = "<D:collection/>"
+		return properties
+	}
+
+	properties["getcontentlength"] = strconv.FormatInt(fileInfo.Size(), 10)
+	properties["getcontenttype"] = escapeXML(getFileContentType(fs.OSFileSystem{}, FilePath, response.contentTypes, response.defaultContentType))
+	file := &fs.File{ Name: fileInfo.Name(), Size: fileInfo.Size(), LastModifiedAt: fileInfo.ModTime() }
+	if ETag, err := generateETag(fs.OSFileSystem{}, FilePath, file); err == nil {
+		properties["getetag"] = escapeXML(ETag)
+	}
+
+	return properties
+}
+
+// Returns the response XML element listing the properties of the file or folder available at the given path, as requested in the given propfind element. The requested properties which are not
+// available are listed with the status 404 (Not Found).
+func (dav *webDAVHandler) renderPropertyResponse(FilePath string, fileInfo os.FileInfo, propfind *webDAVPropfind, response *HttpResponse) string {
+	properties := dav.getProperties(FilePath, fileInfo, response)
+	var foundContent, missingContent strings.Builder
+	if propfind.Prop != nil {
+		for _, propertyName := range propfind.Prop.Names {
+			if value, found := properties[propertyName.XMLName.Local]; found && propertyName.XMLName.Space == "DAV:" {
+				foundContent.WriteString("<D:" + propertyName.XMLName.Local + ">" + value + "</D:" + propertyName.XMLName.Local + ">")
+			} else {
+				missingContent.WriteString("<" + propertyName.XMLName.Local + ` xmlns="` + escapeXML(propertyName.XMLName.Space) + `"/>`)
+			}
+		}
+	} else {
+		for _, propertyName := range webDAVPropertyNames {
+			if value, found := properties[propertyName]; !found {
+				continue
+			} else if propfind.PropName != nil {
+				foundContent.WriteString("<D:" + propertyName + "/>")
+			} else {
+				foundContent.WriteString("<D:" + propertyName + ">" + value + "</D:" + propertyName + ">")
+			}
+		}
+	}
+
+	content := "<D:response><D:href>" + escapeXML(dav.getHref(FilePath, fileInfo.IsDir())) + "</D:href>"
+	if foundContent.Len() > 0 {
+		content += "<D:propstat><D:prop>" + foundContent.String() + "</D:prop><D:status>HTTP/1.1 200 " + StatusOK.GetStatusMessage() + "</D:status></D:propstat>"
+	}
+	if missingContent.Len() > 0 {
+		content += "<D:propstat><D:prop>" + missingContent.String() + "</D:prop><D:status>HTTP/1.1 404 " + StatusNotFound.GetStatusMessage() + "</D:status></D:propstat>"
+	}
+
+	return content + "</D:response>"
+}
+
+// Returns the activelock XML elements describing the locks applying to the file or folder available at the given path.
+func (dav *webDAVHandler) renderActiveLocks(FilePath string) string {
+	var content strings.Builder
+	for _, lock := range dav.locks.getLocks(FilePath) {
+		depth := "0"
+		if lock.isRecursive {
+			depth = "infinity"
+		}
+
+		lockRootInfo, err := os.Stat(lock.filePath)
+		content.WriteString("<D:activelock><D:locktype><D:write/></D:locktype><D:lockscope><D:exclusive/></D:lockscope><D:depth>" + depth + "</D:depth>")
+		if lock.owner != "" {
+			content.WriteString("<D:owner>" + lock.owner + "</D:owner>")
+		}
+		content.WriteString("<D:timeout>" + lock.getTimeout() + "</D:timeout><D:locktoken><D:href>" + escapeXML(lock.token) + "</D:href></D:locktoken>")
+		content.WriteString("<D:lockroot><D:href>" + escapeXML(dav.getHref(lock.filePath, err == nil && lockRootInfo.IsDir())) + "</D:href></D:lockroot></D:activelock>")
+	}
+
+	return content.String()
+}
+
+// Creates an exclusive write lock on the requested file or folder, or refreshes an existing lock if the request body is empty and the lock token is given in the If header. The lock token of a new lock
+// is sent in the Lock-Token header. A missing file is created as an empty file before it is locked, with a 201 (Created) response. A 423 (Locked) response is sent if the lock conflicts with an existing lock.
+func (dav *webDAVHandler) handleLock(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	depth, isValid := getDepth(request, "infinity")
+	if !isValid || depth == "1" {
+		return dav.sendStatus(request, response, StatusBadRequest)
+	}
+
+	timeout := getLockTimeout(request)
+	if len(strings.TrimSpace(string(request.Body))) == 0 {
+		lockTokens := getIfLockTokens(request)
+		if len(lockTokens) == 0 {
+			return dav.sendStatus(request, response, StatusBadRequest)
+		}
+
+		if _, found := dav.locks.refresh(FilePath, lockTokens, timeout); !found {
+			return dav.sendStatus(request, response, StatusPreconditionFailed)
+		}
+
+		return sendXML(response, StatusOK, `<D:prop xmlns:D="DAV:"><D:lockdiscovery>` + dav.renderActiveLocks(FilePath) + "</D:lockdiscovery></D:prop>")
+	}
+
+	lockInfo := new(webDAVLockInfo)
+	err := xml.Unmarshal(request.Body, lockInfo)
+	if err != nil || lockInfo.XMLName.Space != "DAV:" || lockInfo.XMLName.Local != "lockinfo" || lockInfo.Write == nil {
+		return dav.sendStatus(request, response, StatusBadRequest)
+	} else if lockInfo.Exclusive == nil {
+		// Shared locks are not supported, as they would not prevent other clients from modifying the locked files.
+		return dav.sendStatus(request, response, StatusNotImplemented)
+	}
+
+	PathType, err := fs.GetPathType(FilePath)
+	isCreated := err != nil
+	if isCreated {
+		if ParentType, err := fs.GetPathType(filepath.Dir(FilePath)); err != nil || ParentType != fs.FOLDER_TYPE_PATH {
+			return dav.sendStatus(request, response, StatusConflict)
+		}
+	}
+
+	lock, found := dav.locks.create(FilePath, depth == "infinity" && PathType == fs.FOLDER_TYPE_PATH, strings.TrimSpace(lockInfo.Owner.Content), timeout)
+	if !found {
+		return dav.sendStatus(request, response, StatusLocked)
+	}
+
+	if isCreated {
+		err = os.WriteFile(FilePath, nil, 0644)
+		if err != nil {
+			dav.locks.remove(lock.token, FilePath)
+			return err
+		}
+	}
+
+	status := StatusOK
+	if isCreated {
+		status = StatusCreated
+	}
+
+	response.Headers.Add("Lock-Token", "<" + lock.token + ">")
+	return sendXML(response, status, `<D:prop xmlns:D="DAV:"><D:lockdiscovery>` + dav.renderActiveLocks(FilePath) + "</D:lockdiscovery></D:prop>")
+}
+
+// Releases the lock identified by the token given in the Lock-Token header, if it applies to the requested file or folder. A 409 (Conflict) response is sent otherwise.
+func (dav *webDAVHandler) handleUnlock(request *HttpRequest, response *HttpResponse, FilePath string) error {
+	lockToken := strings.TrimSuffix(strings.TrimPrefix(request.Header("Lock-Token"), "<"), ">")
+	if lockToken == "" {
+		return dav.sendStatus(request, response, StatusBadRequest)
+	}
+
+	if !dav.locks.remove(lockToken, FilePath) {
+		return dav.sendStatus(request, response, StatusConflict)
+	}
+
+	response.Status(StatusNoContent)
+	return nil
+}
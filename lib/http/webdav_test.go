@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test case to validate the requests made to a WebDAV endpoint, which are sent in order as each request depends on the files, folders and locks left by the previous requests.
+// The lock token sent in the response to the first LOCK request replaces the "{token}" placeholder in the headers of the later requests.
+func Test_Server_WebDAV(t *testing.T) {
+	testFolder := t.TempDir()
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	if err := testServer.WebDAV("/dav", testFolder); err != nil {
+		t.Fatalf("Was not expecting an error while defining the WebDAV endpoint, but got this instead - %v", err)
+	}
+
+	lockInfo := `<?xml version="1.0" encoding="utf-8"?><D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype><D:owner><D:href>mailto:owner@example.com</D:href></D:owner></D:lockinfo>`
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		Headers map[string]string
+		Body string
+		ExpStatusCode StatusCode
+		ExpContents []string
+	} {
+		{ "Options of the endpoint", "OPTIONS", "/dav", nil, "", StatusNoContent, nil },
+		{ "Folder created", "MKCOL", "/dav/docs", nil, "", StatusCreated, nil },
+		{ "Folder which already exists", "MKCOL", "/dav/docs", nil, "", StatusMethodNotAllowed, nil },
+		{ "Folder whose parent does not exist", "MKCOL", "/dav/missing/docs", nil, "", StatusConflict, nil },
+		{ "File uploaded", "PUT", "/dav/docs/notes.txt", nil, "proteus", StatusCreated, nil },
+		{ "File replaced", "PUT", "/dav/docs/notes.txt", nil, "proteus notes", StatusNoContent, nil },
+		{ "File whose folder does not exist", "PUT", "/dav/missing/notes.txt", nil, "proteus", StatusConflict, nil },
+		{ "File downloaded", "GET", "/dav/docs/notes.txt", nil, "", StatusOK, []string{ "proteus notes" } },
+		{ "Properties of a folder and its entries", "PROPFIND", "/dav/docs", map[string]string{ "Depth": "1" }, "", StatusMultiStatus, []string{ "<D:href>/dav/docs/</D:href>", "<D:resourcetype><D:collection/></D:resourcetype>", "<D:href>/dav/docs/notes.txt</D:href>", "<D:getcontentlength>13</D:getcontentlength>" } },
+		{ "Requested properties", "PROPFIND", "/dav/docs/notes.txt", map[string]string{ "Depth": "0" }, `<D:propfind xmlns:D="DAV:"><D:prop><D:getcontentlength/><E:color xmlns:E="urn:example"/></D:prop></D:propfind>`, StatusMultiStatus, []string{ "<D:getcontentlength>13</D:getcontentlength>", `<color xmlns="urn:example"/>`, "HTTP/1.1 404 Not Found" } },
+		{ "Properties with an infinite depth", "PROPFIND", "/dav", nil, "", StatusForbidden, nil },
+		{ "Properties of a missing file", "PROPFIND", "/dav/missing.txt", map[string]string{ "Depth": "0" }, "", StatusNotFound, nil },
+		{ "File copied", "COPY", "/dav/docs/notes.txt", map[string]string{ "Destination": "/dav/docs/copy.txt" }, "", StatusCreated, nil },
+		{ "Existing destination not overwritten", "COPY", "/dav/docs/notes.txt", map[string]string{ "Destination": "/dav/docs/copy.txt", "Overwrite": "F" }, "", StatusPreconditionFailed, nil },
+		{ "File moved", "MOVE", "/dav/docs/copy.txt", map[string]string{ "Destination": "/dav/moved.txt" }, "", StatusCreated, nil },
+		{ "Moved file downloaded", "GET", "/dav/moved.txt", nil, "", StatusOK, []string{ "proteus notes" } },
+		{ "Destination on another server", "MOVE", "/dav/moved.txt", map[string]string{ "Destination": "http://other.example.com/dav/docs/moved.txt" }, "", StatusBadGateway, nil },
+		{ "Destination outside the endpoint", "COPY", "/dav/moved.txt", map[string]string{ "Destination": "/files/moved.txt" }, "", StatusBadGateway, nil },
+		{ "Folder copied into itself", "COPY", "/dav/docs", map[string]string{ "Destination": "/dav/docs/nested" }, "", StatusForbidden, nil },
+		{ "Folder copied with its contents", "COPY", "/dav/docs", map[string]string{ "Destination": "/dav/backup" }, "", StatusCreated, nil },
+		{ "Copied folder contents downloaded", "GET", "/dav/backup/notes.txt", nil, "", StatusOK, []string{ "proteus notes" } },
+		{ "File locked", "LOCK", "/dav/docs/notes.txt", map[string]string{ "Timeout": "Second-60" }, lockInfo, StatusOK, []string{ "<D:lockroot><D:href>/dav/docs/notes.txt</D:href></D:lockroot>", "<D:timeout>Second-60</D:timeout>", "mailto:owner@example.com" } },
+		{ "Locked file already locked", "LOCK", "/dav/docs/notes.txt", nil, lockInfo, StatusLocked, nil },
+		{ "Lock refreshed", "LOCK", "/dav/docs/notes.txt", map[string]string{ "If": "(<{token}>)", "Timeout": "Second-120" }, "", StatusOK, []string{ "<D:timeout>Second-120</D:timeout>" } },
+		{ "Lock discovered", "PROPFIND", "/dav/docs/notes.txt", map[string]string{ "Depth": "0" }, "", StatusMultiStatus, []string{ "<D:locktoken><D:href>{token}</D:href></D:locktoken>" } },
+		{ "Locked file replaced without the lock token", "PUT", "/dav/docs/notes.txt", nil, "changed", StatusLocked, nil },
+		{ "Locked file replaced with the lock token", "PUT", "/dav/docs/notes.txt", map[string]string{ "If": "</dav/docs/notes.txt> (<{token}>)" }, "changed", StatusNoContent, nil },
+		{ "Folder containing a locked file removed", "DELETE", "/dav/docs", nil, "", StatusLocked, nil },
+		{ "Unlocked using another token", "UNLOCK", "/dav/docs/notes.txt", map[string]string{ "Lock-Token": "<urn:uuid:00000000-0000-4000-8000-000000000000>" }, "", StatusConflict, nil },
+		{ "File unlocked", "UNLOCK", "/dav/docs/notes.txt", map[string]string{ "Lock-Token": "<{token}>" }, "", StatusNoContent, nil },
+		{ "Folder removed", "DELETE", "/dav/docs", nil, "", StatusNoContent, nil },
+		{ "Removed file downloaded", "GET", "/dav/docs/notes.txt", nil, "", StatusNotFound, nil },
+		{ "Missing file locked", "LOCK", "/dav/draft.txt", nil, lockInfo, StatusCreated, nil },
+		{ "Target folder removed", "DELETE", "/dav", nil, "", StatusForbidden, nil },
+	}
+
+	lockToken := ""
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			for name, value := range testCase.Headers {
+				testRequest.Headers.Add(name, strings.ReplaceAll(value, "{token}", lockToken))
+			}
+			testRequest.Body = []byte(testCase.Body)
+			testRequest.ContentLength = len(testCase.Body)
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if headerValue, exists := testResponse.Headers.Get("Lock-Token"); exists && lockToken == "" {
+				lockToken = strings.Trim(headerValue, "<>")
+			}
+
+			if testResponse.StatusCode != int(testCase.ExpStatusCode) {
+				tt.Fatalf("Expected the response status code to be %d, but got %d instead", testCase.ExpStatusCode, testResponse.StatusCode)
+			}
+
+			for _, expContent := range testCase.ExpContents {
+				expContent = strings.ReplaceAll(expContent, "{token}", lockToken)
+				if !strings.Contains(string(testResponse.Body), expContent) {
+					tt.Errorf("Expected the response body to contain [%s], but got [%s] instead", expContent, string(testResponse.Body))
+				}
+			}
+			tt.Logf("The response has been sent with the status code %d as expected", testResponse.StatusCode)
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(testFolder, "draft.txt")); err != nil {
+		t.Errorf("Expected the missing file to be created when it was locked, but got this error instead - %v", err)
+	}
+}
+
+// Test case to validate that the symbolic links present in the target folder of a WebDAV endpoint cannot be used to read or change the files outside the target folder.
+func Test_Server_WebDAVSymlinks(t *testing.T) {
+	testFolder := t.TempDir()
+	outsideFolder := t.TempDir()
+	os.WriteFile(filepath.Join(outsideFolder, "secret.txt"), []byte("secret"), 0644)
+	os.WriteFile(filepath.Join(testFolder, "notes.txt"), []byte("proteus"), 0644)
+	os.Mkdir(filepath.Join(testFolder, "docs"), 0755)
+	if err := os.Symlink(outsideFolder, filepath.Join(testFolder, "outside")); err != nil {
+		t.Skipf("Symbolic links cannot be created in this environment - %v", err)
+	}
+	os.Symlink(filepath.Join(outsideFolder, "created.txt"), filepath.Join(testFolder, "dangling.txt"))
+	os.Symlink(filepath.Join(testFolder, "docs"), filepath.Join(testFolder, "inside"))
+
+	testServer := NewServer()
+	testServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	testServer.WebDAV("/dav", testFolder)
+	testCases := []struct {
+		Name string
+		Method string
+		ResourcePath string
+		Headers map[string]string
+		ExpStatusCode StatusCode
+	} {
+		{ "File outside the target folder downloaded", "GET", "/dav/outside/secret.txt", nil, StatusNotFound },
+		{ "File outside the target folder replaced", "PUT", "/dav/outside/secret.txt", nil, StatusNotFound },
+		{ "File created outside the target folder", "PUT", "/dav/outside/new.txt", nil, StatusNotFound },
+		{ "File created through a broken symbolic link", "PUT", "/dav/dangling.txt", nil, StatusNotFound },
+		{ "Folder created outside the target folder", "MKCOL", "/dav/outside/docs", nil, StatusNotFound },
+		{ "File copied outside the target folder", "COPY", "/dav/notes.txt", map[string]string{ "Destination": "/dav/outside/notes.txt" }, StatusForbidden },
+		{ "File moved from outside the target folder", "MOVE", "/dav/outside/secret.txt", map[string]string{ "Destination": "/dav/secret.txt" }, StatusNotFound },
+		{ "Folder outside the target folder removed", "DELETE", "/dav/outside", nil, StatusNotFound },
+		{ "Properties of the folder outside the target folder", "PROPFIND", "/dav/outside", map[string]string{ "Depth": "0" }, StatusNotFound },
+		{ "File created through a symbolic link within the target folder", "PUT", "/dav/inside/new.txt", nil, StatusCreated },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			for name, value := range testCase.Headers {
+				testRequest.Headers.Add(name, value)
+			}
+			testRequest.Body = []byte("changed")
+			testRequest.ContentLength = len(testRequest.Body)
+			testResponse := newTestResponse(tt, "1.1")
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testServer.processRequest(testRequest, testResponse)
+			if testResponse.StatusCode != int(testCase.ExpStatusCode) {
+				tt.Errorf("Expected the response status code to be %d, but got %d instead", testCase.ExpStatusCode, testResponse.StatusCode)
+			} else {
+				tt.Logf("The response has been sent with the status code %d as expected", testResponse.StatusCode)
+			}
+		})
+	}
+
+	outsideEntries, _ := os.ReadDir(outsideFolder)
+	if secret, err := os.ReadFile(filepath.Join(outsideFolder, "secret.txt")); err != nil || string(secret) != "secret" || len(outsideEntries) != 1 {
+		t.Errorf("Expected the folder outside the target folder to be left unchanged, but found %d entries in it", len(outsideEntries))
+	}
+}
+// Test case to validate if the WebDAV methods are allowed only by a server with a WebDAV endpoint, for the HTTP versions allowing the PUT method.
+func Test_Server_WebDAVMethods(t *testing.T) {
+	plainServer := NewServer()
+	plainServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	davServer := NewServer()
+	davServer.SetLogger(NewLogger(new(bytes.Buffer), LevelDebug, TextLogFormat))
+	if err := davServer.WebDAV("/dav", t.TempDir()); err != nil {
+		t.Fatalf("Was not expecting an error while defining the WebDAV endpoint, but got this instead - %v", err)
+	}
+
+	testCases := []struct {
+		Name string
+		Server *HttpServer
+		Version string
+		Method string
+		ResourcePath string
+		ExpStatusCode StatusCode
+		ExpAllowsPropfind bool
+	} {
+		{ "PROPFIND request to a server without a WebDAV endpoint", plainServer, "1.1", "PROPFIND", "/dav", StatusMethodNotAllowed, false },
+		{ "Options of a server without a WebDAV endpoint", plainServer, "1.1", "OPTIONS", "*", StatusNoContent, false },
+		{ "PROPFIND request to a server with a WebDAV endpoint", davServer, "1.1", "PROPFIND", "/dav", StatusMultiStatus, false },
+		{ "Options of a server with a WebDAV endpoint", davServer, "1.1", "OPTIONS", "*", StatusNoContent, true },
+		{ "HTTP/1.0 PROPFIND request to a server with a WebDAV endpoint", davServer, "1.0", "PROPFIND", "/dav", StatusMethodNotAllowed, false },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			testRequest.Method = testCase.Method
+			testRequest.ResourcePath = testCase.ResourcePath
+			testRequest.Headers.Add("Depth", "0")
+			testResponse := newTestResponse(tt, testCase.Version)
+			testResponse.setWriter(bufio.NewWriter(new(bytes.Buffer)))
+			testCase.Server.processRequest(testRequest, testResponse)
+			allowedMethods, _ := testResponse.Headers.Get("Allow")
+			if testResponse.StatusCode != int(testCase.ExpStatusCode) {
+				tt.Errorf("Expected the response status code to be %d, but got %d instead", testCase.ExpStatusCode, testResponse.StatusCode)
+			} else if strings.Contains(allowedMethods, "PROPFIND") != testCase.ExpAllowsPropfind {
+				tt.Errorf("Was not expecting the allowed methods [%s] to contain PROPFIND to be %t", allowedMethods, !testCase.ExpAllowsPropfind)
+			} else {
+				tt.Logf("The response has been sent with the status code %d and the allowed methods [%s] as expected", testResponse.StatusCode, allowedMethods)
+			}
+		})
+	}
+}
+// Test case to validate the lock timeout read from the Timeout header of the LOCK requests, which is capped at the "webdav_lock_timeout" server default.
+func Test_WebDAV_LockTimeout(t *testing.T) {
+	maxTimeout := getDefaultDuration("webdav_lock_timeout")
+	testCases := []struct {
+		Name string
+		TimeoutHeader string
+		ExpTimeout time.Duration
+	} {
+		{ "No Timeout header", "", maxTimeout },
+		{ "Timeout in seconds", "Second-60", time.Minute },
+		{ "Infinite timeout followed by a timeout in seconds", "Infinite, Second-120", 2 * time.Minute },
+		{ "Timeout beyond the maximum timeout", "Second-86400", maxTimeout },
+		{ "Timeout overflowing a duration", "Second-9223372036854775807", maxTimeout },
+		{ "Timeout which is not a number", "Second-abc", maxTimeout },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			if testCase.TimeoutHeader != "" {
+				testRequest.Headers.Add("Timeout", testCase.TimeoutHeader)
+			}
+
+			timeout := getLockTimeout(testRequest)
+			if timeout != testCase.ExpTimeout {
+				tt.Errorf("Expected the lock timeout %v, but got %v instead", testCase.ExpTimeout, timeout)
+			} else {
+				tt.Logf("The lock timeout %v has been read as expected", timeout)
+			}
+		})
+	}
+}
+
+// Test case to validate the lock tokens read from the If header of the requests made to a WebDAV endpoint.
+func Test_WebDAV_IfLockTokens(t *testing.T) {
+	testCases := []struct {
+		Name string
+		IfHeader string
+		ExpTokens []string
+	} {
+		{ "No If header", "", []string{} },
+		{ "Untagged list", "(<urn:uuid:first>)", []string{ "urn:uuid:first" } },
+		{ "Tagged lists with entity tags", `</dav/a.txt> (<urn:uuid:first> ["etag"]) (Not <urn:uuid:second>)`, []string{ "urn:uuid:first", "urn:uuid:second" } },
+		{ "Unterminated state token", "(<urn:uuid:first", []string{} },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestRequest(tt)
+			if testCase.IfHeader != "" {
+				testRequest.Headers.Add("If", testCase.IfHeader)
+			}
+
+			tokens := getIfLockTokens(testRequest)
+			if strings.Join(tokens, " ") != strings.Join(testCase.ExpTokens, " ") {
+				tt.Errorf("Expected the lock tokens %v, but got %v instead", testCase.ExpTokens, tokens)
+			} else {
+				tt.Logf("The lock tokens %v have been read as expected", tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Structure to represent an exclusive write lock (RFC 4918) held on a file or folder served by a WebDAV endpoint.
+type webDAVLock struct {
+	// Token identifying the lock, which is a URI of the form "urn:uuid:<uuid>".
+	token string
+	// Complete path of the locked file or folder in the local file system.
+	filePath string
+	// Is true if the lock also applies to all the files and folders present in the locked folder (a lock with the depth "infinity").
+	isRecursive bool
+	// XML content describing the owner of the lock, as sent by the client in the LOCK request.
+	owner string
+	// Duration for which the lock is held, unless it is refreshed.
+	timeout time.Duration
+	// Time at which the lock expires.
+	expiresAt time.Time
+}
+
+// Checks if the lock applies to the file or folder available at the given path.
+func (lock *webDAVLock) covers(FilePath string) bool {
+	return lock.filePath == FilePath || (lock.isRecursive && isWithinFolder(lock.filePath, FilePath))
+}
+
+// Returns the value of the timeout of the lock, as sent in the Timeout header and in the lock discovery property.
+func (lock *webDAVLock) getTimeout() string {
+	return "Second-" + strconv.FormatInt(int64(lock.timeout / time.Second), 10)
+}
+
+// Structure to represent the collection of locks held on the files and folders served by a WebDAV endpoint. Locks are held in memory and are released once they expire.
+type webDAVLocks struct {
+	// Collection of locks, with the lock tokens as keys.
+	locks map[string]*webDAVLock
+	// Mutex to synchronize access to the locks across multiple client connections.
+	mutex sync.Mutex
+}
+
+// Creates and returns pointer to a new collection of WebDAV locks, without any locks.
+func newWebDAVLocks() *webDAVLocks {
+	return &webDAVLocks{ locks: make(map[string]*webDAVLock) }
+}
+
+// Removes the locks which have expired. The caller must hold the lock of the collection.
+func (locks *webDAVLocks) removeExpired() {
+	now := time.Now()
+	for token, lock := range locks.locks {
+		if now.After(lock.expiresAt) {
+			delete(locks.locks, token)
+		}
+	}
+}
+
+// Creates a new lock on the file or folder available at the given path and returns a copy of it. The boolean value returned is false if the lock conflicts with an existing lock, which is the case
+// when an existing lock applies to the given path or when a recursive lock is requested on a folder containing a locked file or folder.
+func (locks *webDAVLocks) create(FilePath string, IsRecursive bool, Owner string, Timeout time.Duration) (webDAVLock, bool) {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	locks.removeExpired()
+	for _, lock := range locks.locks {
+		if lock.covers(FilePath) || (IsRecursive && isWithinFolder(FilePath, lock.filePath)) {
+			return webDAVLock{}, false
+		}
+	}
+
+	lock := &webDAVLock{ token: "urn:uuid:" + generateRequestID(), filePath: FilePath, isRecursive: IsRecursive, owner: Owner, timeout: Timeout, expiresAt: time.Now().Add(Timeout) }
+	locks.locks[lock.token] = lock
+	return *lock, true
+}
+
+// Extends the lock applying to the file or folder available at the given path, whose token is one of the given tokens, by the given timeout and returns a copy of it.
+// The boolean value returned is false if none of the given tokens identifies such a lock.
+func (locks *webDAVLocks) refresh(FilePath string, Tokens []string, Timeout time.Duration) (webDAVLock, bool) {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	locks.removeExpired()
+	for _, token := range Tokens {
+		if lock, found := locks.locks[token]; found && lock.covers(FilePath) {
+			lock.timeout = Timeout
+			lock.expiresAt = time.Now().Add(Timeout)
+			return *lock, true
+		}
+	}
+
+	return webDAVLock{}, false
+}
+
+// Releases the lock identified by the given token, if it applies to the file or folder available at the given path. The boolean value returned is false if there is no such lock.
+func (locks *webDAVLocks) remove(Token string, FilePath string) bool {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	locks.removeExpired()
+	if lock, found := locks.locks[Token]; found && lock.covers(FilePath) {
+		delete(locks.locks, Token)
+		return true
+	}
+
+	return false
+}
+
+// Releases the locks held on the file or folder available at the given path and on the files and folders present in it, once they have been removed or moved.
+func (locks *webDAVLocks) removeWithin(FilePath string) {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	for token, lock := range locks.locks {
+		if isWithinFolder(FilePath, lock.filePath) {
+			delete(locks.locks, token)
+		}
+	}
+}
+
+// Checks if the file or folder available at the given path cannot be modified using the given lock tokens, as a lock applies to it whose token has not been given.
+// If Recursive is true, the locks held on the files and folders present in the given folder are checked as well.
+func (locks *webDAVLocks) isLocked(FilePath string, Recursive bool, Tokens []string) bool {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	locks.removeExpired()
+	for token, lock := range locks.locks {
+		if (lock.covers(FilePath) || (Recursive && isWithinFolder(FilePath, lock.filePath))) && !slices.Contains(Tokens, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns copies of the locks applying to the file or folder available at the given path, sorted by the paths of the locked files and folders.
+func (locks *webDAVLocks) getLocks(FilePath string) []webDAVLock {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	locks.removeExpired()
+	activeLocks := make([]webDAVLock, 0)
+	for _, lock := range locks.locks {
+		if lock.covers(FilePath) {
+			activeLocks = append(activeLocks, *lock)
+		}
+	}
+
+	slices.SortFunc(activeLocks, func(first webDAVLock, second webDAVLock) int {
+		return strings.Compare(first.filePath, second.filePath)
+	})
+	return activeLocks
+}
@@ -0,0 +1,504 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// GUID appended to the Sec-WebSocket-Key header value to compute the Sec-WebSocket-Accept header value, as per RFC 6455.
+const WEBSOCKET_ACCEPT_GUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket protocol version supported by the web server.
+const WEBSOCKET_VERSION = "13"
+
+// Represents the type of a WebSocket data message.
+type WebSocketMessageType int
+
+const (
+	// Message containing UTF-8 encoded text.
+	TextMessage WebSocketMessageType = 1
+	// Message containing binary data.
+	BinaryMessage WebSocketMessageType = 2
+)
+
+// Opcodes of the WebSocket frames, as per RFC 6455.
+const (
+	wsContinuationFrame byte = 0x0
+	wsTextFrame byte = 0x1
+	wsBinaryFrame byte = 0x2
+	wsCloseFrame byte = 0x8
+	wsPingFrame byte = 0x9
+	wsPongFrame byte = 0xA
+)
+
+// Status codes sent in WebSocket close frames, as per RFC 6455.
+const (
+	CloseNormalClosure = 1000
+	CloseGoingAway = 1001
+	CloseProtocolError = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatusReceived = 1005
+	CloseInvalidPayload = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig = 1009
+	CloseInternalServerError = 1011
+)
+
+// Maximum length of the payload of a WebSocket control frame (close, ping and pong).
+const wsMaxControlPayloadLength = 125
+
+// Structure to represent a WebSocket connection established with a client.
+type WebSocket struct {
+	// Maximum size (in bytes) of a message that can be received from the client. A message exceeding this size causes the connection to be closed with status 1009 (Message Too Big). A value of zero or less means that the message size is not limited.
+	MaxMessageSize int64
+	// Function invoked with the payload of every pong frame received from the client. It can be used to check if the client is still alive after sending a ping.
+	PongHandler func(Data []byte)
+	// Network connection on which the WebSocket messages are exchanged.
+	connection net.Conn
+	// Streamed reader instance to read the WebSocket frames from the network stream.
+	reader *bufio.Reader
+	// Streamed writer instance to write the WebSocket frames to the network stream.
+	writer *bufio.Writer
+	// Maximum duration allowed for writing the close frame once the handler returns. A zero duration means no timeout.
+	writeTimeout time.Duration
+	// Boolean value to indicate if a close frame has been sent to the client.
+	isCloseSent bool
+	// Close frame received from the client (or raised due to a protocol violation), after which no more messages can be read.
+	closeErr *WebSocketCloseError
+	// Mutex to synchronize the writing of frames, since control frames can be written while a message is being read.
+	writeMutex sync.Mutex
+}
+
+// Upgrades the connection of the request to the WebSocket protocol, by validating the opening handshake sent by the client and sending the 101 (Switching Protocols) response.
+// Once upgraded, the response must not be used any further and the connection is closed when the handler returns. The read and write timeouts of the server do not apply to the WebSocket connection.
+// If the handshake is not valid, the error response (400 or 426) is set in the given response and a RequestParseError is returned.
+func (req *HttpRequest) UpgradeWebSocket(res *HttpResponse) (*WebSocket, error) {
+	webSocketKey, err := req.validateWebSocketHandshake()
+	if err != nil {
+		reqErr := err.(*RequestParseError)
+		if reqErr.Status == StatusUpgradeRequired {
+			res.Headers["Sec-Websocket-Version"] = []string{ WEBSOCKET_VERSION }
+			res.Headers["Upgrade"] = []string{ "websocket" }
+			res.Headers.Add("Connection", "Upgrade")
+		}
+		res.Status(reqErr.Status)
+		handleError(req, res)
+		return nil, err
+	}
+
+	if res.writer == nil || res.isWritten {
+		resErr := new(ResponseError)
+		resErr.Section = "RespWrite"
+		resErr.Value = ""
+		resErr.Message = "Response has already been written and cannot be upgraded to a WebSocket connection"
+		return nil, resErr
+	}
+
+	// The connection watcher must be stopped before the WebSocket frames can be read from the request byte stream.
+	if req.stopWatching != nil {
+		req.stopWatching()
+	}
+
+	res.isWritten = true
+	res.closeConnection = true
+	res.runBeforeWriteHooks()
+	res.Status(StatusSwitchingProtocols)
+	delete(res.Headers, "Content-Length")
+	res.Headers["Connection"] = []string{ "Upgrade" }
+	res.Headers["Upgrade"] = []string{ "websocket" }
+	res.Headers["Sec-Websocket-Accept"] = []string{ computeWebSocketAccept(webSocketKey) }
+	err = res.writeStatusLine()
+	if err == nil {
+		err = res.writeHeaders()
+	}
+	if err == nil {
+		err = res.writer.Flush()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if res.connection != nil {
+		res.connection.SetDeadline(time.Time{})
+	}
+
+	ws := newWebSocket(res.connection, req.reader, res.writer, res.writeTimeout)
+	res.webSocket = ws
+	return ws, nil
+}
+
+// Validates the WebSocket opening handshake sent in the request and returns the value of the Sec-WebSocket-Key header.
+func (req *HttpRequest) validateWebSocketHandshake() (string, error) {
+	reqError := new(RequestParseError)
+	reqError.Section = "Header"
+	reqError.Status = StatusBadRequest
+	if !strings.EqualFold(req.Method, "GET") || !strings.EqualFold(strings.TrimSpace(req.Version), "1.1") {
+		reqError.Value = req.Method + " HTTP/" + req.Version
+		reqError.Message = "WebSocket connections can be established only using GET requests made with HTTP/1.1"
+		return "", reqError
+	}
+
+	if !hasHeaderToken(req.Headers, "Upgrade", "websocket") || !hasHeaderToken(req.Headers, "Connection", "upgrade") {
+		reqError.Value = "Upgrade"
+		reqError.Message = "Request does not ask for the connection to be upgraded to the WebSocket protocol"
+		reqError.Status = StatusUpgradeRequired
+		return "", reqError
+	}
+
+	webSocketVersion, _ := req.Headers.Get("Sec-WebSocket-Version")
+	if strings.TrimSpace(webSocketVersion) != WEBSOCKET_VERSION {
+		reqError.Value = webSocketVersion
+		reqError.Message = "WebSocket protocol version requested is not supported"
+		reqError.Status = StatusUpgradeRequired
+		return "", reqError
+	}
+
+	webSocketKey, _ := req.Headers.Get("Sec-WebSocket-Key")
+	webSocketKey = strings.TrimSpace(webSocketKey)
+	decodedKey, err := base64.StdEncoding.DecodeString(webSocketKey)
+	if err != nil || len(decodedKey) != 16 {
+		reqError.Value = webSocketKey
+		reqError.Message = "Sec-WebSocket-Key header must be a base64 encoded 16 byte value"
+		return "", reqError
+	}
+
+	return webSocketKey, nil
+}
+
+// Checks if the given comma-separated header contains the given token, ignoring case.
+func hasHeaderToken(headers Headers, key string, token string) bool {
+	headerValue, ok := headers.Get(key)
+	if !ok {
+		return false
+	}
+
+	return slices.ContainsFunc(strings.Split(headerValue, ","), func(value string) bool {
+		return strings.EqualFold(strings.TrimSpace(value), token)
+	})
+}
+
+// Returns the value of the Sec-WebSocket-Accept header computed for the given Sec-WebSocket-Key header value.
+func computeWebSocketAccept(webSocketKey string) string {
+	hash := sha1.Sum([]byte(webSocketKey + WEBSOCKET_ACCEPT_GUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// Reads the next data message sent by the client, reassembling fragmented messages. Ping frames received meanwhile are answered automatically with pong frames.
+// When the client closes the connection, a close frame is sent back and a WebSocketCloseError containing the status code sent by the client is returned.
+// A WebSocketCloseError is also returned if the client violates the protocol, in which case the connection is closed with the appropriate status code.
+// It must not be called concurrently from multiple goroutines.
+func (ws *WebSocket) ReadMessage() (WebSocketMessageType, []byte, error) {
+	if ws.closeErr != nil {
+		return 0, nil, ws.closeErr
+	}
+
+	var messageType WebSocketMessageType
+	message := make([]byte, 0)
+	for {
+		isFinal, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case wsPingFrame:
+			err = ws.writeFrame(wsPongFrame, payload)
+			if err != nil {
+				return 0, nil, err
+			}
+		case wsPongFrame:
+			if ws.PongHandler != nil {
+				ws.PongHandler(payload)
+			}
+		case wsCloseFrame:
+			return 0, nil, ws.handleCloseFrame(payload)
+		case wsTextFrame, wsBinaryFrame, wsContinuationFrame:
+			if opcode == wsContinuationFrame && messageType == 0 {
+				return 0, nil, ws.fail(CloseProtocolError, "Continuation frame received without a message to continue")
+			}
+
+			if opcode != wsContinuationFrame {
+				if messageType != 0 {
+					return 0, nil, ws.fail(CloseProtocolError, "New message received before the fragmented message was completed")
+				}
+				messageType = WebSocketMessageType(opcode)
+			}
+
+			if ws.MaxMessageSize > 0 && int64(len(message) + len(payload)) > ws.MaxMessageSize {
+				return 0, nil, ws.fail(CloseMessageTooBig, "Message exceeds the maximum message size allowed")
+			}
+
+			message = append(message, payload...)
+			if isFinal {
+				if messageType == TextMessage && !utf8.Valid(message) {
+					return 0, nil, ws.fail(CloseInvalidPayload, "Text message is not valid UTF-8")
+				}
+
+				return messageType, message, nil
+			}
+		default:
+			return 0, nil, ws.fail(CloseProtocolError, fmt.Sprintf("Unknown opcode %d received", opcode))
+		}
+	}
+}
+
+// Sends the given data to the client as a single message of the given type.
+func (ws *WebSocket) WriteMessage(messageType WebSocketMessageType, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		resErr := new(ResponseError)
+		resErr.Section = "WebSocket"
+		resErr.Value = fmt.Sprintf("%d", messageType)
+		resErr.Message = "WebSocket message type must be either TextMessage or BinaryMessage"
+		return resErr
+	}
+
+	if messageType == TextMessage && !utf8.Valid(data) {
+		resErr := new(ResponseError)
+		resErr.Section = "WebSocket"
+		resErr.Value = "Text Message"
+		resErr.Message = "Text message must be valid UTF-8"
+		return resErr
+	}
+
+	return ws.writeFrame(byte(messageType), data)
+}
+
+// Sends a ping frame with the given data to the client, to which the client must respond with a pong frame containing the same data.
+func (ws *WebSocket) Ping(data []byte) error {
+	return ws.writeFrame(wsPingFrame, data)
+}
+
+// Sends a close frame with the given status code and reason to the client. No more messages can be sent once the close frame has been sent, but the messages already sent by the client can still be read until its close frame is received.
+func (ws *WebSocket) Close(code int, reason string) error {
+	payload := make([]byte, 2, 2 + len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > wsMaxControlPayloadLength {
+		payload = payload[:wsMaxControlPayloadLength]
+	}
+
+	return ws.writeFrame(wsCloseFrame, payload)
+}
+
+// Sets the deadline for reading the next message from the client. A zero value means that reads do not time out.
+func (ws *WebSocket) SetReadDeadline(deadline time.Time) error {
+	if ws.connection == nil {
+		return nil
+	}
+
+	return ws.connection.SetReadDeadline(deadline)
+}
+
+// Sets the deadline for writing the next message to the client. A zero value means that writes do not time out.
+func (ws *WebSocket) SetWriteDeadline(deadline time.Time) error {
+	if ws.connection == nil {
+		return nil
+	}
+
+	return ws.connection.SetWriteDeadline(deadline)
+}
+
+// Sends a close frame to the client, if not done already, once the handler has returned.
+func (ws *WebSocket) end() {
+	ws.writeMutex.Lock()
+	isCloseSent := ws.isCloseSent
+	ws.writeMutex.Unlock()
+	if isCloseSent {
+		return
+	}
+
+	if ws.connection != nil {
+		ws.connection.SetWriteDeadline(getDeadline(time.Now(), ws.writeTimeout))
+	}
+	ws.Close(CloseNormalClosure, "")
+}
+
+// Reads a single frame sent by the client and returns whether it is the final fragment of the message, its opcode and its unmasked payload.
+func (ws *WebSocket) readFrame() (bool, byte, []byte, error) {
+	frameHeader := make([]byte, 2)
+	_, err := io.ReadFull(ws.reader, frameHeader)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	isFinal := frameHeader[0] & 0x80 != 0
+	opcode := frameHeader[0] & 0x0F
+	isMasked := frameHeader[1] & 0x80 != 0
+	payloadLength := uint64(frameHeader[1] & 0x7F)
+	if frameHeader[0] & 0x70 != 0 {
+		return false, 0, nil, ws.fail(CloseProtocolError, "Reserved bits must not be set in the absence of negotiated extensions")
+	}
+
+	if !isMasked {
+		return false, 0, nil, ws.fail(CloseProtocolError, "Frames sent by the client must be masked")
+	}
+
+	switch payloadLength {
+	case 126:
+		extendedLength := make([]byte, 2)
+		_, err = io.ReadFull(ws.reader, extendedLength)
+		payloadLength = uint64(binary.BigEndian.Uint16(extendedLength))
+	case 127:
+		extendedLength := make([]byte, 8)
+		_, err = io.ReadFull(ws.reader, extendedLength)
+		payloadLength = binary.BigEndian.Uint64(extendedLength)
+	}
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if opcode & 0x08 != 0 && (!isFinal || payloadLength > wsMaxControlPayloadLength) {
+		return false, 0, nil, ws.fail(CloseProtocolError, "Control frames must not be fragmented and must not exceed 125 bytes")
+	}
+
+	if payloadLength > math.MaxInt64 {
+		return false, 0, nil, ws.fail(CloseProtocolError, "Most significant bit of the payload length must not be set")
+	} else if ws.MaxMessageSize > 0 && payloadLength > uint64(ws.MaxMessageSize) {
+		return false, 0, nil, ws.fail(CloseMessageTooBig, "Message exceeds the maximum message size allowed")
+	}
+
+	maskingKey := make([]byte, 4)
+	_, err = io.ReadFull(ws.reader, maskingKey)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	// The payload is read as it is received rather than being allocated upfront, so that a frame announcing a large payload (which is possible when the message size is not limited)
+	// only takes as much memory as the data sent by the client.
+	var payloadBuffer bytes.Buffer
+	_, err = io.CopyN(&payloadBuffer, ws.reader, int64(payloadLength))
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := payloadBuffer.Bytes()
+	for index := range payload {
+		payload[index] ^= maskingKey[index % 4]
+	}
+
+	return isFinal, opcode, payload, nil
+}
+
+// Writes a single unfragmented frame with the given opcode and payload to the client and flushes it.
+func (ws *WebSocket) writeFrame(opcode byte, payload []byte) error {
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+	if ws.isCloseSent {
+		resErr := new(ResponseError)
+		resErr.Section = "WebSocket"
+		resErr.Value = ""
+		resErr.Message = "Close frame has already been sent on the WebSocket connection"
+		return resErr
+	}
+
+	if opcode & 0x08 != 0 && len(payload) > wsMaxControlPayloadLength {
+		resErr := new(ResponseError)
+		resErr.Section = "WebSocket"
+		resErr.Value = fmt.Sprintf("%d", len(payload))
+		resErr.Message = "Payload of a control frame must not exceed 125 bytes"
+		return resErr
+	}
+
+	frameHeader := []byte{ 0x80 | opcode }
+	switch {
+	case len(payload) < 126:
+		frameHeader = append(frameHeader, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frameHeader = append(frameHeader, 126)
+		frameHeader = binary.BigEndian.AppendUint16(frameHeader, uint16(len(payload)))
+	default:
+		frameHeader = append(frameHeader, 127)
+		frameHeader = binary.BigEndian.AppendUint64(frameHeader, uint64(len(payload)))
+	}
+
+	_, err := ws.writer.Write(frameHeader)
+	if err == nil {
+		_, err = ws.writer.Write(payload)
+	}
+	if err == nil {
+		err = ws.writer.Flush()
+	}
+	if err != nil {
+		resErr := new(ResponseError)
+		resErr.Section = "WebSocket"
+		resErr.Value = ""
+		resErr.Message = fmt.Sprintf("Error while writing WebSocket frame :: %s", err.Error())
+		return resErr
+	}
+
+	if opcode == wsCloseFrame {
+		ws.isCloseSent = true
+	}
+
+	return nil
+}
+
+// Handles the close frame received from the client by sending back a close frame with the same status code and returns the WebSocketCloseError to be reported to the handler.
+func (ws *WebSocket) handleCloseFrame(payload []byte) error {
+	closeErr := &WebSocketCloseError{ Code: CloseNoStatusReceived }
+	switch {
+	case len(payload) == 1:
+		return ws.fail(CloseProtocolError, "Close frame payload must contain a status code")
+	case len(payload) >= 2:
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+		if !isValidCloseCode(closeErr.Code) {
+			return ws.fail(CloseProtocolError, fmt.Sprintf("Invalid close status code %d received", closeErr.Code))
+		}
+
+		if !utf8.Valid(payload[2:]) {
+			return ws.fail(CloseInvalidPayload, "Close frame reason is not valid UTF-8")
+		}
+	}
+
+	ws.closeErr = closeErr
+	replyCode := closeErr.Code
+	if replyCode == CloseNoStatusReceived {
+		replyCode = CloseNormalClosure
+	}
+
+	ws.writeMutex.Lock()
+	isCloseSent := ws.isCloseSent
+	ws.writeMutex.Unlock()
+	if !isCloseSent {
+		ws.Close(replyCode, "")
+	}
+
+	return closeErr
+}
+
+// Closes the connection with the given status code due to a protocol violation by the client and returns the corresponding WebSocketCloseError.
+func (ws *WebSocket) fail(code int, reason string) error {
+	ws.closeErr = &WebSocketCloseError{ Code: code, Reason: reason }
+	ws.Close(code, reason)
+	return ws.closeErr
+}
+
+// Checks if the given status code can be sent by an endpoint in a close frame.
+func isValidCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003:
+		return true
+	case code >= 1007 && code <= 1011:
+		return true
+	case code >= 3000 && code <= 4999:
+		return true
+	default:
+		return false
+	}
+}
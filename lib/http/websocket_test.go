@@ -0,0 +1,239 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Helper function to create a request containing a valid WebSocket opening handshake.
+func newTestWebSocketRequest(t testing.TB) *HttpRequest {
+	t.Helper()
+	testRequest := newTestRequest(t)
+	testRequest.Method = "GET"
+	testRequest.Version = "1.1"
+	testRequest.Headers.Add("Upgrade", "websocket")
+	testRequest.Headers.Add("Connection", "keep-alive, Upgrade")
+	testRequest.Headers.Add("Sec-WebSocket-Version", "13")
+	testRequest.Headers.Add("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	return testRequest
+}
+
+// Helper function to write a masked WebSocket frame to the given writer, as sent by a client.
+func writeTestFrame(t testing.TB, writer io.Writer, isFinal bool, opcode byte, payload []byte) {
+	t.Helper()
+	firstByte := opcode
+	if isFinal {
+		firstByte |= 0x80
+	}
+
+	frame := []byte{ firstByte, 0x80 | byte(len(payload)) }
+	maskingKey := []byte{ 0x12, 0x34, 0x56, 0x78 }
+	frame = append(frame, maskingKey...)
+	for index, value := range payload {
+		frame = append(frame, value ^ maskingKey[index % 4])
+	}
+
+	if _, err := writer.Write(frame); err != nil {
+		t.Fatalf("Error occurred while writing the test frame - %v", err)
+	}
+}
+
+// Helper function to read an unmasked WebSocket frame sent by the server and return its opcode and payload.
+func readTestFrame(t testing.TB, reader io.Reader) (byte, []byte) {
+	t.Helper()
+	frameHeader := make([]byte, 2)
+	if _, err := io.ReadFull(reader, frameHeader); err != nil {
+		t.Fatalf("Error occurred while reading the frame header - %v", err)
+	}
+
+	payload := make([]byte, frameHeader[1] & 0x7F)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("Error occurred while reading the frame payload - %v", err)
+	}
+
+	return frameHeader[0] & 0x0F, payload
+}
+
+// Test case to validate the WebSocket opening handshake sent by the client.
+func Test_Request_ValidateWebSocketHandshake(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Method string
+		HeaderKey string
+		HeaderValue string
+		ExpStatus StatusCode
+	} {
+		{ "Valid opening handshake", "GET", "", "", 0 },
+		{ "Opening handshake sent using POST", "POST", "", "", StatusBadRequest },
+		{ "Upgrade header missing", "GET", "Upgrade", "", StatusUpgradeRequired },
+		{ "Unsupported WebSocket version", "GET", "Sec-WebSocket-Version", "8", StatusUpgradeRequired },
+		{ "Invalid WebSocket key", "GET", "Sec-WebSocket-Key", "c2hvcnQ=", StatusBadRequest },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			testRequest := newTestWebSocketRequest(tt)
+			testRequest.Method = testCase.Method
+			if testCase.HeaderKey != "" {
+				delete(testRequest.Headers, textproto.CanonicalMIMEHeaderKey(testCase.HeaderKey))
+				if testCase.HeaderValue != "" {
+					testRequest.Headers.Add(testCase.HeaderKey, testCase.HeaderValue)
+				}
+			}
+
+			_, err := testRequest.validateWebSocketHandshake()
+			if testCase.ExpStatus == 0 {
+				if err != nil {
+					tt.Errorf("Was not expecting an error and yet received one - %v", err)
+				}
+				return
+			}
+
+			reqErr, ok := err.(*RequestParseError)
+			if !ok || reqErr.Status != testCase.ExpStatus {
+				tt.Errorf("Expected a request parse error with status %d, but got this instead - %v", testCase.ExpStatus, err)
+			} else {
+				tt.Logf("Received a request parse error as expected - %v", reqErr)
+			}
+		})
+	}
+}
+
+// Test case to validate the WebSocket upgrade and the exchange of messages and control frames with the client.
+func Test_Request_UpgradeWebSocket(t *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+	defer clientConnection.Close()
+	clientConnection.SetDeadline(time.Now().Add(5 * time.Second))
+	testRequest := newTestWebSocketRequest(t)
+	testRequest.setReader(bufio.NewReader(serverConnection))
+	testResponse := newTestResponse(t, "1.1")
+	testResponse.connection = serverConnection
+	testResponse.setWriter(bufio.NewWriter(serverConnection))
+	serverErrors := make(chan error, 1)
+	go func() {
+		defer serverConnection.Close()
+		ws, err := testRequest.UpgradeWebSocket(testResponse)
+		if err != nil {
+			serverErrors <- err
+			return
+		}
+
+		for {
+			messageType, message, err := ws.ReadMessage()
+			if err != nil {
+				serverErrors <- err
+				return
+			}
+
+			ws.WriteMessage(messageType, bytes.ToUpper(message))
+		}
+	}()
+
+	clientReader := bufio.NewReader(clientConnection)
+	statusLine, _ := clientReader.ReadString('\n')
+	if !strings.HasPrefix(statusLine, "HTTP/1.1 101") {
+		t.Fatalf("Expected the status line to be 101 (Switching Protocols), but got [%s]", strings.TrimSpace(statusLine))
+	}
+
+	var handshake strings.Builder
+	for {
+		headerLine, err := clientReader.ReadString('\n')
+		if err != nil || headerLine == "\r\n" {
+			break
+		}
+		handshake.WriteString(headerLine)
+	}
+
+	if !strings.Contains(handshake.String(), "Sec-Websocket-Accept: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=") {
+		t.Errorf("The handshake response [%s] does not contain the expected Sec-WebSocket-Accept header", handshake.String())
+	}
+
+	writeTestFrame(t, clientConnection, true, wsPingFrame, []byte("are you there"))
+	opcode, payload := readTestFrame(t, clientReader)
+	if opcode != wsPongFrame || string(payload) != "are you there" {
+		t.Errorf("Expected a pong frame with the ping payload, but got opcode %d with payload [%s]", opcode, string(payload))
+	}
+
+	writeTestFrame(t, clientConnection, false, wsTextFrame, []byte("hello "))
+	writeTestFrame(t, clientConnection, true, wsContinuationFrame, []byte("world"))
+	opcode, payload = readTestFrame(t, clientReader)
+	if opcode != wsTextFrame || string(payload) != "HELLO WORLD" {
+		t.Errorf("Expected the fragmented message to be echoed in upper case, but got opcode %d with payload [%s]", opcode, string(payload))
+	} else {
+		t.Logf("The fragmented message was reassembled and echoed as expected")
+	}
+
+	closePayload := binary.BigEndian.AppendUint16(nil, CloseGoingAway)
+	writeTestFrame(t, clientConnection, true, wsCloseFrame, append(closePayload, "bye"...))
+	opcode, payload = readTestFrame(t, clientReader)
+	if opcode != wsCloseFrame || len(payload) < 2 || binary.BigEndian.Uint16(payload) != CloseGoingAway {
+		t.Errorf("Expected a close frame echoing the status code %d, but got opcode %d with payload %v", CloseGoingAway, opcode, payload)
+	}
+
+	err := <-serverErrors
+	closeErr, ok := err.(*WebSocketCloseError)
+	if !ok || closeErr.Code != CloseGoingAway || closeErr.Reason != "bye" {
+		t.Errorf("Expected a WebSocket close error with code %d, but got this instead - %v", CloseGoingAway, err)
+	}
+}
+
+// Test case to validate that a frame announcing a payload larger than the data sent by the client is rejected without allocating the announced payload, when the message size is not limited.
+func Test_WebSocket_LargePayloadLength(t *testing.T) {
+	testCases := []struct {
+		Name string
+		PayloadLength uint64
+		ExpCloseCode int
+	} {
+		{ "Payload length larger than the data sent", 1 << 62, 0 },
+		{ "Payload length with the most significant bit set", 1 << 63, CloseProtocolError },
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(tt *testing.T) {
+			var clientFrames bytes.Buffer
+			clientFrames.Write([]byte{ 0x82, 0x80 | 127 })
+			clientFrames.Write(binary.BigEndian.AppendUint64(nil, testCase.PayloadLength))
+			clientFrames.Write([]byte{ 0x12, 0x34, 0x56, 0x78, 'h', 'i' })
+			ws := newWebSocket(nil, bufio.NewReader(&clientFrames), bufio.NewWriter(new(bytes.Buffer)), 0)
+			ws.MaxMessageSize = 0
+			_, _, err := ws.ReadMessage()
+			closeErr, isCloseErr := err.(*WebSocketCloseError)
+			if testCase.ExpCloseCode == 0 && err != io.ErrUnexpectedEOF {
+				tt.Errorf("Expected the frame to be rejected as it ends before the payload, but got this instead - %v", err)
+			} else if testCase.ExpCloseCode != 0 && (!isCloseErr || closeErr.Code != testCase.ExpCloseCode) {
+				tt.Errorf("Expected a WebSocket close error with code %d, but got this instead - %v", testCase.ExpCloseCode, err)
+			} else {
+				tt.Logf("The frame was rejected as expected - %v", err)
+			}
+		})
+	}
+}
+
+// Test case to validate that the connection is closed with a protocol error when the client sends an unmasked frame.
+func Test_WebSocket_UnmaskedFrame(t *testing.T) {
+	var clientFrames bytes.Buffer
+	clientFrames.Write([]byte{ 0x81, 0x02, 'h', 'i' })
+	var serverOutput bytes.Buffer
+	ws := newWebSocket(nil, bufio.NewReader(&clientFrames), bufio.NewWriter(&serverOutput), 0)
+	_, _, err := ws.ReadMessage()
+	closeErr, ok := err.(*WebSocketCloseError)
+	if !ok || closeErr.Code != CloseProtocolError {
+		t.Fatalf("Expected a WebSocket close error with code %d, but got this instead - %v", CloseProtocolError, err)
+	}
+
+	opcode, payload := readTestFrame(t, &serverOutput)
+	if opcode != wsCloseFrame || binary.BigEndian.Uint16(payload) != CloseProtocolError {
+		t.Errorf("Expected a close frame with the status code %d to be sent, but got opcode %d with payload %v", CloseProtocolError, opcode, payload)
+	}
+
+	if err = ws.WriteMessage(TextMessage, []byte("late")); err == nil {
+		t.Errorf("Expected an error while writing a message after the close frame was sent")
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Maximum number of bytes written to the connection at once by a minRateConn, each of which must be read by the client within the time allowed by the minimum write rate.
+const MIN_WRITE_RATE_PART_SIZE = 64 << 10
+
+// Connection wrapper which aborts the writes to a client reading the response slower than the minimum write rate. Every write must complete within the grace period, plus the time needed to send
+// the bytes written at the minimum rate, or else it fails with a timeout error upon which the connection is closed. The write deadline set on the connection (like the one for the write timeout) still applies if it is earlier.
+type minRateConn struct {
+	net.Conn
+	// Minimum rate (in bytes per second) at which the client must read the bytes written to the connection.
+	minRate int64
+	// Duration added to the time allowed for every write, so that short pauses of the client (like while its receive window is full) do not abort the connection.
+	gracePeriod time.Duration
+	// Mutex to synchronize access to the write deadline, which can be set by a handler (like while streaming events) while the response is being written.
+	mutex sync.Mutex
+	// Write deadline set on the connection. It is the zero time if no write deadline has been set.
+	deadline time.Time
+}
+
+// Writes the given bytes to the connection, which must be read by the client at the minimum write rate. The bytes are written in parts of MIN_WRITE_RATE_PART_SIZE, so that the rate is enforced
+// while a large response body is being written rather than only once it has been written completely. An error is returned if the client does not read the bytes in time.
+func (conn *minRateConn) Write(data []byte) (int, error) {
+	conn.mutex.Lock()
+	deadline := conn.deadline
+	conn.mutex.Unlock()
+	writeStart := time.Now()
+	written := 0
+	for written < len(data) {
+		part := data[written:min(len(data), written + MIN_WRITE_RATE_PART_SIZE)]
+		partDeadline := writeStart.Add(conn.gracePeriod + time.Duration(int64(written + len(part)) * int64(time.Second) / conn.minRate))
+		isRateDeadline := deadline.IsZero() || partDeadline.Before(deadline)
+		if !isRateDeadline {
+			partDeadline = deadline
+		}
+
+		conn.Conn.SetWriteDeadline(partDeadline)
+		partWritten, err := conn.Conn.Write(part)
+		written += partWritten
+		if err != nil {
+			if isRateDeadline && isTimeoutError(err) {
+				return written, fmt.Errorf("client is reading the response slower than the minimum write rate of %d bytes per second: %w", conn.minRate, err)
+			}
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// Sets the write deadline of the connection, which applies along with the deadline of every write based on the minimum write rate.
+func (conn *minRateConn) SetWriteDeadline(deadline time.Time) error {
+	conn.mutex.Lock()
+	conn.deadline = deadline
+	conn.mutex.Unlock()
+	return conn.Conn.SetWriteDeadline(deadline)
+}
+
+// Sets both the read and the write deadlines of the connection.
+func (conn *minRateConn) SetDeadline(deadline time.Time) error {
+	conn.mutex.Lock()
+	conn.deadline = deadline
+	conn.mutex.Unlock()
+	return conn.Conn.SetDeadline(deadline)
+}
@@ -24,5 +24,9 @@ func main() {
 		return nil
 	})
 	
-	server.Listen(8080, "localhost")
+	err = server.Listen(8080, "localhost")
+	if err != nil {
+		fmt.Println("Error occurred while starting the web server: " + err.Error())
+		os.Exit(1)
+	}
 }
\ No newline at end of file